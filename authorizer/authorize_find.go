@@ -300,3 +300,21 @@ func AuthorizeFindUserResourceMappings(ctx context.Context, os OrganizationServi
 	}
 	return rrs, len(rrs), nil
 }
+
+// AuthorizeFindQuotas takes the given items and returns only the ones that the user is authorized to read.
+func AuthorizeFindQuotas(ctx context.Context, rs []*influxdb.Quota) ([]*influxdb.Quota, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeReadOrg(ctx, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
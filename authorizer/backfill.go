@@ -0,0 +1,92 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.BackfillService = (*BackfillService)(nil)
+
+// BackfillService wraps a influxdb.BackfillService and authorizes actions
+// against it appropriately. Backfills are visible to those allowed to read
+// their task, and created or canceled by those allowed to write it.
+type BackfillService struct {
+	s influxdb.BackfillService
+}
+
+// NewBackfillService constructs an instance of an authorizing backfill service.
+func NewBackfillService(s influxdb.BackfillService) *BackfillService {
+	return &BackfillService{
+		s: s,
+	}
+}
+
+// CreateBackfill checks to see if the authorizer on context has write access to the task of the backfill.
+func (s *BackfillService) CreateBackfill(ctx context.Context, b *influxdb.Backfill) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, b.TaskID, b.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateBackfill(ctx, b)
+}
+
+// FindBackfillByID checks to see if the authorizer on context has read access to the task of the backfill.
+func (s *BackfillService) FindBackfillByID(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.s.FindBackfillByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, b.TaskID, b.OrgID); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// FindBackfills retrieves all backfills that match the provided filter and then filters the list down to only the
+// backfills of tasks that are authorized.
+func (s *BackfillService) FindBackfills(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	backfills, err := s.s.FindBackfills(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	authorized := backfills[:0]
+	for _, b := range backfills {
+		_, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, b.TaskID, b.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		authorized = append(authorized, b)
+	}
+	return authorized, nil
+}
+
+// CancelBackfill checks to see if the authorizer on context has write access to the task of the backfill.
+func (s *BackfillService) CancelBackfill(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	b, err := s.s.FindBackfillByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, b.TaskID, b.OrgID); err != nil {
+		return err
+	}
+	return s.s.CancelBackfill(ctx, id)
+}
@@ -0,0 +1,170 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestBackfillService_FindBackfills(t *testing.T) {
+	type fields struct {
+		BackfillService influxdb.BackfillService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err       error
+		backfills []*influxdb.Backfill
+	}
+
+	backfills := func(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+		return []*influxdb.Backfill{
+			{ID: 1, OrgID: 10, TaskID: 100},
+			{ID: 2, OrgID: 10, TaskID: 200},
+			{ID: 3, OrgID: 11, TaskID: 300},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all backfills",
+			fields: fields{
+				BackfillService: &mock.BackfillService{FindBackfillsF: backfills},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+					},
+				},
+			},
+			wants: wants{
+				backfills: []*influxdb.Backfill{
+					{ID: 1, OrgID: 10, TaskID: 100},
+					{ID: 2, OrgID: 10, TaskID: 200},
+					{ID: 3, OrgID: 11, TaskID: 300},
+				},
+			},
+		},
+		{
+			name: "authorized to see the backfills of one task",
+			fields: fields{
+				BackfillService: &mock.BackfillService{FindBackfillsF: backfills},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(200),
+					},
+				},
+			},
+			wants: wants{
+				backfills: []*influxdb.Backfill{
+					{ID: 2, OrgID: 10, TaskID: 200},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBackfillService(tt.fields.BackfillService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			backfills, err := s.FindBackfills(ctx, influxdb.BackfillFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(backfills, tt.wants.backfills); diff != "" {
+				t.Errorf("backfills are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestBackfillService_CreateBackfill(t *testing.T) {
+	type fields struct {
+		BackfillService influxdb.BackfillService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to backfill task",
+			fields: fields{
+				BackfillService: mock.NewBackfillService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(100),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to backfill task",
+			fields: fields{
+				BackfillService: mock.NewBackfillService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(100),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/tasks/0000000000000064 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewBackfillService(tt.fields.BackfillService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.CreateBackfill(ctx, &influxdb.Backfill{OrgID: 10, TaskID: 100})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
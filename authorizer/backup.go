@@ -23,14 +23,14 @@ func NewBackupService(s influxdb.BackupService) *BackupService {
 	}
 }
 
-func (b BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+func (b BackupService) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.BackupManifest, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
 	if err := IsAllowedAll(ctx, influxdb.ReadAllPermissions()); err != nil {
-		return 0, nil, err
+		return nil, err
 	}
-	return b.s.CreateBackup(ctx)
+	return b.s.CreateBackup(ctx, filter)
 }
 
 func (b BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
@@ -0,0 +1,92 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.DeleteJobService = (*DeleteJobService)(nil)
+
+// DeleteJobService wraps a influxdb.DeleteJobService and authorizes actions
+// against it appropriately. Jobs are visible to those allowed to read their
+// bucket, and created or canceled by those allowed to write it.
+type DeleteJobService struct {
+	s influxdb.DeleteJobService
+}
+
+// NewDeleteJobService constructs an instance of an authorizing delete job service.
+func NewDeleteJobService(s influxdb.DeleteJobService) *DeleteJobService {
+	return &DeleteJobService{
+		s: s,
+	}
+}
+
+// CreateDeleteJob checks to see if the authorizer on context has write access to the bucket of the job.
+func (s *DeleteJobService) CreateDeleteJob(ctx context.Context, job *influxdb.DeleteJob) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, job.BucketID, job.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateDeleteJob(ctx, job)
+}
+
+// FindDeleteJobByID checks to see if the authorizer on context has read access to the bucket of the job.
+func (s *DeleteJobService) FindDeleteJobByID(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	job, err := s.s.FindDeleteJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, job.BucketID, job.OrgID); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// FindDeleteJobs retrieves all delete jobs that match the provided filter and then filters the list down to only the
+// jobs of buckets that are authorized.
+func (s *DeleteJobService) FindDeleteJobs(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	jobs, err := s.s.FindDeleteJobs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	authorized := jobs[:0]
+	for _, job := range jobs {
+		_, _, err := AuthorizeRead(ctx, influxdb.BucketsResourceType, job.BucketID, job.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		authorized = append(authorized, job)
+	}
+	return authorized, nil
+}
+
+// CancelDeleteJob checks to see if the authorizer on context has write access to the bucket of the job.
+func (s *DeleteJobService) CancelDeleteJob(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	job, err := s.s.FindDeleteJobByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, job.BucketID, job.OrgID); err != nil {
+		return err
+	}
+	return s.s.CancelDeleteJob(ctx, id)
+}
@@ -0,0 +1,178 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestDeleteJobService_FindDeleteJobs(t *testing.T) {
+	type fields struct {
+		DeleteJobService influxdb.DeleteJobService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err  error
+		jobs []*influxdb.DeleteJob
+	}
+
+	jobs := func(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+		return []*influxdb.DeleteJob{
+			{ID: 1, OrgID: 10, BucketID: 100},
+			{ID: 2, OrgID: 10, BucketID: 200},
+			{ID: 3, OrgID: 11, BucketID: 300},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all jobs",
+			fields: fields{
+				DeleteJobService: &mock.DeleteJobService{FindDeleteJobsF: jobs},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				jobs: []*influxdb.DeleteJob{
+					{ID: 1, OrgID: 10, BucketID: 100},
+					{ID: 2, OrgID: 10, BucketID: 200},
+					{ID: 3, OrgID: 11, BucketID: 300},
+				},
+			},
+		},
+		{
+			name: "authorized to see the jobs of one bucket",
+			fields: fields{
+				DeleteJobService: &mock.DeleteJobService{FindDeleteJobsF: jobs},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(200),
+					},
+				},
+			},
+			wants: wants{
+				jobs: []*influxdb.DeleteJob{
+					{ID: 2, OrgID: 10, BucketID: 200},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDeleteJobService(tt.fields.DeleteJobService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			jobs, err := s.FindDeleteJobs(ctx, influxdb.DeleteJobFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(jobs, tt.wants.jobs); diff != "" {
+				t.Errorf("delete jobs are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteJobService_CancelDeleteJob(t *testing.T) {
+	type fields struct {
+		DeleteJobService influxdb.DeleteJobService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	svc := func() *mock.DeleteJobService {
+		s := mock.NewDeleteJobService()
+		s.FindDeleteJobByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+			return &influxdb.DeleteJob{ID: id, OrgID: 10, BucketID: 100}, nil
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to cancel job",
+			fields: fields{
+				DeleteJobService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(100),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to cancel job",
+			fields: fields{
+				DeleteJobService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.BucketsResourceType,
+						ID:   influxdbtesting.IDPtr(100),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/buckets/0000000000000064 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDeleteJobService(tt.fields.DeleteJobService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.CancelDeleteJob(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.EscalationPolicyService = (*EscalationPolicyService)(nil)
+
+// EscalationPolicyService wraps a influxdb.EscalationPolicyService and
+// authorizes actions against it appropriately. Escalation policies change what
+// the notification rules of an organization send, so they are visible to those
+// allowed to read its notification rules and changed by those allowed to write
+// them.
+type EscalationPolicyService struct {
+	s influxdb.EscalationPolicyService
+}
+
+// NewEscalationPolicyService constructs an instance of an authorizing escalation policy service.
+func NewEscalationPolicyService(s influxdb.EscalationPolicyService) *EscalationPolicyService {
+	return &EscalationPolicyService{
+		s: s,
+	}
+}
+
+// FindEscalationPolicyByID checks to see if the authorizer on context has read access to the notification rules of the
+// escalation policy's organization.
+func (s *EscalationPolicyService) FindEscalationPolicyByID(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := s.s.FindEscalationPolicyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, p.OrgID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FindEscalationPolicies retrieves all escalation policies that match the provided filter and then filters the list down to only the
+// escalation policies of organizations whose notification rules are readable.
+func (s *EscalationPolicyService) FindEscalationPolicies(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ps, err := s.s.FindEscalationPolicies(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	authorized := ps[:0]
+	for _, p := range ps {
+		_, _, err := AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, p.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		authorized = append(authorized, p)
+	}
+	return authorized, nil
+}
+
+// CreateEscalationPolicy checks to see if the authorizer on context has write access to the notification rules of the
+// escalation policy's organization.
+func (s *EscalationPolicyService) CreateEscalationPolicy(ctx context.Context, p *influxdb.EscalationPolicy) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, p.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateEscalationPolicy(ctx, p)
+}
+
+// UpdateEscalationPolicy checks to see if the authorizer on context has write access to the notification rules of the
+// escalation policy's organization.
+func (s *EscalationPolicyService) UpdateEscalationPolicy(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := s.s.FindEscalationPolicyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, p.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateEscalationPolicy(ctx, id, upd)
+}
+
+// DeleteEscalationPolicy checks to see if the authorizer on context has write access to the notification rules of the
+// escalation policy's organization.
+func (s *EscalationPolicyService) DeleteEscalationPolicy(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := s.s.FindEscalationPolicyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, p.OrgID); err != nil {
+		return err
+	}
+	return s.s.DeleteEscalationPolicy(ctx, id)
+}
+
+var _ influxdb.AcknowledgementService = (*AcknowledgementService)(nil)
+
+// AcknowledgementService wraps a influxdb.AcknowledgementService and
+// authorizes actions against it appropriately. Acknowledging stops the
+// escalation of the statuses of a notification rule, so it is allowed to
+// those allowed to write the notification rules of the organization.
+type AcknowledgementService struct {
+	s influxdb.AcknowledgementService
+}
+
+// NewAcknowledgementService constructs an instance of an authorizing acknowledgement service.
+func NewAcknowledgementService(s influxdb.AcknowledgementService) *AcknowledgementService {
+	return &AcknowledgementService{
+		s: s,
+	}
+}
+
+// Acknowledge checks to see if the authorizer on context has write access to the notification rules of the
+// acknowledgement's organization.
+func (s *AcknowledgementService) Acknowledge(ctx context.Context, a *influxdb.Acknowledgement) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, a.OrgID); err != nil {
+		return err
+	}
+	return s.s.Acknowledge(ctx, a)
+}
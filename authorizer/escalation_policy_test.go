@@ -0,0 +1,320 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestEscalationPolicyService_FindEscalationPolicies(t *testing.T) {
+	type fields struct {
+		EscalationPolicyService influxdb.EscalationPolicyService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err      error
+		policies []*influxdb.EscalationPolicy
+	}
+
+	policies := func(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+		return []*influxdb.EscalationPolicy{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 11},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all escalation policies",
+			fields: fields{
+				EscalationPolicyService: &mock.EscalationPolicyService{FindEscalationPoliciesF: policies},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.NotificationRuleResourceType,
+					},
+				},
+			},
+			wants: wants{
+				policies: []*influxdb.EscalationPolicy{
+					{ID: 1, OrgID: 10},
+					{ID: 2, OrgID: 10},
+					{ID: 3, OrgID: 11},
+				},
+			},
+		},
+		{
+			name: "authorized to see the escalation policies of one org",
+			fields: fields{
+				EscalationPolicyService: &mock.EscalationPolicyService{FindEscalationPoliciesF: policies},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				policies: []*influxdb.EscalationPolicy{
+					{ID: 3, OrgID: 11},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewEscalationPolicyService(tt.fields.EscalationPolicyService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			policies, err := s.FindEscalationPolicies(ctx, influxdb.EscalationPolicyFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(policies, tt.wants.policies); diff != "" {
+				t.Errorf("escalation policies are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestEscalationPolicyService_CreateEscalationPolicy(t *testing.T) {
+	type fields struct {
+		EscalationPolicyService influxdb.EscalationPolicyService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to create escalation policy",
+			fields: fields{
+				EscalationPolicyService: mock.NewEscalationPolicyService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to create escalation policy",
+			fields: fields{
+				EscalationPolicyService: mock.NewEscalationPolicyService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/notificationRules is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewEscalationPolicyService(tt.fields.EscalationPolicyService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.CreateEscalationPolicy(ctx, &influxdb.EscalationPolicy{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestEscalationPolicyService_DeleteEscalationPolicy(t *testing.T) {
+	type fields struct {
+		EscalationPolicyService influxdb.EscalationPolicyService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	svc := func() *mock.EscalationPolicyService {
+		s := mock.NewEscalationPolicyService()
+		s.FindEscalationPolicyByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+			return &influxdb.EscalationPolicy{ID: id, OrgID: 10}, nil
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to delete escalation policy",
+			fields: fields{
+				EscalationPolicyService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to delete escalation policy of another org",
+			fields: fields{
+				EscalationPolicyService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/notificationRules is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewEscalationPolicyService(tt.fields.EscalationPolicyService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.DeleteEscalationPolicy(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestAcknowledgementService_Acknowledge(t *testing.T) {
+	type fields struct {
+		AcknowledgementService influxdb.AcknowledgementService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to acknowledge",
+			fields: fields{
+				AcknowledgementService: mock.NewAcknowledgementService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to acknowledge",
+			fields: fields{
+				AcknowledgementService: mock.NewAcknowledgementService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/notificationRules is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewAcknowledgementService(tt.fields.AcknowledgementService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.Acknowledge(ctx, &influxdb.Acknowledgement{OrgID: 10, NotificationRuleID: 1})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
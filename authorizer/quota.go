@@ -0,0 +1,72 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.QuotaService = (*QuotaService)(nil)
+
+// QuotaService wraps a influxdb.QuotaService and authorizes actions
+// against it appropriately. Members of an organization may read its quotas;
+// changing them requires write access to all organizations, so a tenant
+// cannot lift its own quotas.
+type QuotaService struct {
+	s influxdb.QuotaService
+}
+
+// NewQuotaService constructs an instance of an authorizing quota service.
+func NewQuotaService(s influxdb.QuotaService) *QuotaService {
+	return &QuotaService{
+		s: s,
+	}
+}
+
+// FindQuotaByID checks to see if the authorizer on context has read access to the quota's organization.
+func (s *QuotaService) FindQuotaByID(ctx context.Context, id influxdb.ID) (*influxdb.Quota, error) {
+	q, err := s.s.FindQuotaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeReadOrg(ctx, q.OrgID); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// FindQuotas retrieves all quotas that match the provided filter and then filters the list down to
+// the quotas of organizations the authorizer on context has read access to.
+func (s *QuotaService) FindQuotas(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+	qs, err := s.s.FindQuotas(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	qs, _, err = AuthorizeFindQuotas(ctx, qs)
+	return qs, err
+}
+
+// CreateQuota checks to see if the authorizer on context has write access to all organizations.
+func (s *QuotaService) CreateQuota(ctx context.Context, q *influxdb.Quota) error {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.CreateQuota(ctx, q)
+}
+
+// UpdateQuota checks to see if the authorizer on context has write access to all organizations.
+func (s *QuotaService) UpdateQuota(ctx context.Context, id influxdb.ID, upd influxdb.QuotaUpdate) (*influxdb.Quota, error) {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateQuota(ctx, id, upd)
+}
+
+// DeleteQuota checks to see if the authorizer on context has write access to all organizations.
+func (s *QuotaService) DeleteQuota(ctx context.Context, id influxdb.ID) error {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	return s.s.DeleteQuota(ctx, id)
+}
@@ -0,0 +1,167 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestQuotaService_FindQuotas(t *testing.T) {
+	type fields struct {
+		QuotaService influxdb.QuotaService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err    error
+		quotas []*influxdb.Quota
+	}
+
+	quotas := func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+		return []*influxdb.Quota{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 11},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all quotas",
+			fields: fields{
+				QuotaService: &mock.QuotaService{FindQuotasF: quotas},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				quotas: []*influxdb.Quota{
+					{ID: 1, OrgID: 10},
+					{ID: 2, OrgID: 11},
+				},
+			},
+		},
+		{
+			name: "authorized to see the quotas of one org",
+			fields: fields{
+				QuotaService: &mock.QuotaService{FindQuotasF: quotas},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				quotas: []*influxdb.Quota{
+					{ID: 2, OrgID: 11},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewQuotaService(tt.fields.QuotaService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			quotas, err := s.FindQuotas(ctx, influxdb.QuotaFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(quotas, tt.wants.quotas); diff != "" {
+				t.Errorf("quotas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestQuotaService_CreateQuota(t *testing.T) {
+	type fields struct {
+		QuotaService influxdb.QuotaService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to create quota",
+			fields: fields{
+				QuotaService: mock.NewQuotaService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to create quota of own org",
+			fields: fields{
+				QuotaService: mock.NewQuotaService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewQuotaService(tt.fields.QuotaService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.CreateQuota(ctx, &influxdb.Quota{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.RestoreService = (*RestoreService)(nil)
+
+// RestoreService wraps a influxdb.RestoreService and authorizes actions
+// against it appropriately.
+type RestoreService struct {
+	s influxdb.RestoreService
+}
+
+// NewRestoreService constructs an instance of an authorizing restore service.
+func NewRestoreService(s influxdb.RestoreService) *RestoreService {
+	return &RestoreService{
+		s: s,
+	}
+}
+
+// RestoreBucket checks to see if the authorizer on context has write access to the restored bucket.
+func (b RestoreService) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, src influxdb.BackupManifestBucket, r io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, bucketID, orgID); err != nil {
+		return err
+	}
+	return b.s.RestoreBucket(ctx, orgID, bucketID, src, r)
+}
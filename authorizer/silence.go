@@ -0,0 +1,112 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService wraps a influxdb.SilenceService and authorizes actions
+// against it appropriately. Silences change what the notification rules of an
+// organization send, so they are visible to those allowed to read its
+// notification rules and changed by those allowed to write them.
+type SilenceService struct {
+	s influxdb.SilenceService
+}
+
+// NewSilenceService constructs an instance of an authorizing silence service.
+func NewSilenceService(s influxdb.SilenceService) *SilenceService {
+	return &SilenceService{
+		s: s,
+	}
+}
+
+// FindSilenceByID checks to see if the authorizer on context has read access to the notification rules of the
+// silence's organization.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// FindSilences retrieves all silences that match the provided filter and then filters the list down to only the
+// silences of organizations whose notification rules are readable.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, err := s.s.FindSilences(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	authorized := ss[:0]
+	for _, sl := range ss {
+		_, _, err := AuthorizeOrgReadResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		authorized = append(authorized, sl)
+	}
+	return authorized, nil
+}
+
+// CreateSilence checks to see if the authorizer on context has write access to the notification rules of the
+// silence's organization.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return err
+	}
+	return s.s.CreateSilence(ctx, sl)
+}
+
+// UpdateSilence checks to see if the authorizer on context has write access to the notification rules of the
+// silence's organization.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateSilence(ctx, id, upd)
+}
+
+// DeleteSilence checks to see if the authorizer on context has write access to the notification rules of the
+// silence's organization.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeOrgWriteResource(ctx, influxdb.NotificationRuleResourceType, sl.OrgID); err != nil {
+		return err
+	}
+	return s.s.DeleteSilence(ctx, id)
+}
@@ -0,0 +1,249 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestSilenceService_FindSilences(t *testing.T) {
+	type fields struct {
+		SilenceService influxdb.SilenceService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err      error
+		silences []*influxdb.Silence
+	}
+
+	silences := func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+		return []*influxdb.Silence{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 11},
+		}, nil
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to see all silences",
+			fields: fields{
+				SilenceService: &mock.SilenceService{FindSilencesF: silences},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.NotificationRuleResourceType,
+					},
+				},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					{ID: 1, OrgID: 10},
+					{ID: 2, OrgID: 10},
+					{ID: 3, OrgID: 11},
+				},
+			},
+		},
+		{
+			name: "authorized to see the silences of one org",
+			fields: fields{
+				SilenceService: &mock.SilenceService{FindSilencesF: silences},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					{ID: 3, OrgID: 11},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSilenceService(tt.fields.SilenceService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			silences, err := s.FindSilences(ctx, influxdb.SilenceFilter{})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(silences, tt.wants.silences); diff != "" {
+				t.Errorf("silences are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestSilenceService_CreateSilence(t *testing.T) {
+	type fields struct {
+		SilenceService influxdb.SilenceService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to create silence",
+			fields: fields{
+				SilenceService: mock.NewSilenceService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to create silence",
+			fields: fields{
+				SilenceService: mock.NewSilenceService(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/notificationRules is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSilenceService(tt.fields.SilenceService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.CreateSilence(ctx, &influxdb.Silence{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestSilenceService_DeleteSilence(t *testing.T) {
+	type fields struct {
+		SilenceService influxdb.SilenceService
+	}
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	svc := func() *mock.SilenceService {
+		s := mock.NewSilenceService()
+		s.FindSilenceByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+			return &influxdb.Silence{ID: id, OrgID: 10}, nil
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to delete silence",
+			fields: fields{
+				SilenceService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to delete silence of another org",
+			fields: fields{
+				SilenceService: svc(),
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.NotificationRuleResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/notificationRules is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSilenceService(tt.fields.SilenceService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			err := s.DeleteSilence(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -229,7 +229,7 @@ func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID infl
 	return ts.TaskService.RetryRun(ctx, taskID, runID)
 }
 
-func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -248,5 +248,5 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
-	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor, params)
 }
@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.TaskDryRunService = (*TaskDryRunService)(nil)
+
+// TaskDryRunService wraps a influxdb.TaskDryRunService and authorizes actions
+// against it appropriately. A task is dry run by those allowed to write it,
+// like a manual run of the task.
+type TaskDryRunService struct {
+	s influxdb.TaskDryRunService
+}
+
+// NewTaskDryRunService constructs an instance of an authorizing task dry run service.
+func NewTaskDryRunService(s influxdb.TaskDryRunService) *TaskDryRunService {
+	return &TaskDryRunService{
+		s: s,
+	}
+}
+
+// DryRunTask checks to see if the authorizer on context has write access to the task of the dry run.
+func (s *TaskDryRunService) DryRunTask(ctx context.Context, d *influxdb.TaskDryRun) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if _, _, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, d.TaskID, d.OrgID); err != nil {
+		return err
+	}
+	return s.s.DryRunTask(ctx, d)
+}
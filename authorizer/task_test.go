@@ -107,7 +107,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 		RetryRunFn: func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error) {
 			return &run, nil
 		},
-		ForceRunFn: func(context.Context, influxdb.ID, int64) (*influxdb.Run, error) {
+		ForceRunFn: func(context.Context, influxdb.ID, int64, map[string]interface{}) (*influxdb.Run, error) {
 			return &run, nil
 		},
 	}
@@ -534,7 +534,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`
 			name: "ForceRun with bad auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: wrongOrgReadAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, nil)
 				if err == nil {
 					return errors.New("returned no error with a invalid auth")
 				}
@@ -545,7 +545,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`
 			name: "ForceRun with org auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, nil)
 				return err
 			},
 		},
@@ -553,7 +553,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`
 			name: "ForceRun with task auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, nil)
 				return err
 			},
 		},
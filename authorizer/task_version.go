@@ -0,0 +1,56 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.TaskVersionService = (*TaskVersionService)(nil)
+
+// TaskVersionService wraps a influxdb.TaskVersionService and authorizes actions
+// against it appropriately. The versions of a task are visible to those allowed
+// to read the task.
+type TaskVersionService struct {
+	s influxdb.TaskVersionService
+}
+
+// NewTaskVersionService constructs an instance of an authorizing task version service.
+func NewTaskVersionService(s influxdb.TaskVersionService) *TaskVersionService {
+	return &TaskVersionService{
+		s: s,
+	}
+}
+
+// FindTaskVersions checks to see if the authorizer on context has read access to the task of the versions.
+func (s *TaskVersionService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	versions, err := s.s.FindTaskVersions(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) > 0 {
+		if _, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, taskID, versions[0].OrgID); err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
+// FindTaskVersion checks to see if the authorizer on context has read access to the task of the version.
+func (s *TaskVersionService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	v, err := s.s.FindTaskVersion(ctx, taskID, version)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, v.TaskID, v.OrgID); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,151 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestTaskVersionService_FindTaskVersions(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err      error
+		versions []*influxdb.TaskVersion
+	}
+
+	versions := func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+		return []*influxdb.TaskVersion{
+			{TaskID: taskID, OrgID: 10, Version: 1},
+			{TaskID: taskID, OrgID: 10, Version: 2},
+		}, nil
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to read task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(100),
+					},
+				},
+			},
+			wants: wants{
+				versions: []*influxdb.TaskVersion{
+					{TaskID: 100, OrgID: 10, Version: 1},
+					{TaskID: 100, OrgID: 10, Version: 2},
+				},
+			},
+		},
+		{
+			name: "unauthorized to read task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type: influxdb.TasksResourceType,
+						ID:   influxdbtesting.IDPtr(200),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a/tasks/0000000000000064 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTaskVersionService(&mock.TaskVersionService{FindTaskVersionsF: versions})
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			got, err := s.FindTaskVersions(ctx, 100)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(got, tt.wants.versions); diff != "" {
+				t.Errorf("versions are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestTaskVersionService_FindTaskVersion(t *testing.T) {
+	type args struct {
+		permission influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	version := func(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+		return &influxdb.TaskVersion{TaskID: taskID, OrgID: 10, Version: version}, nil
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to read task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.TasksResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized to read task",
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.TasksResourceType,
+						OrgID: influxdbtesting.IDPtr(11),
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000a/tasks/0000000000000064 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTaskVersionService(&mock.TaskVersionService{FindTaskVersionF: version})
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, mock.NewMockAuthorizer(false, []influxdb.Permission{tt.args.permission}))
+
+			_, err := s.FindTaskVersion(ctx, 100, 2)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
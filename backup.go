@@ -3,13 +3,14 @@ package influxdb
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // BackupService represents the data backup functions of InfluxDB.
 type BackupService interface {
-	// CreateBackup creates a local copy (hard links) of the TSM data for all orgs and buckets.
-	// The return values are used to download each backup file.
-	CreateBackup(context.Context) (backupID int, backupFiles []string, err error)
+	// CreateBackup creates a local copy (hard links) of the TSM data matching filter.
+	// The returned manifest is used to download each backup file.
+	CreateBackup(ctx context.Context, filter BackupFilter) (*BackupManifest, error)
 	// FetchBackupFile downloads one backup file, data or metadata.
 	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
 	// InternalBackupPath is a utility to determine the on-disk location of a backup fileset.
@@ -21,3 +22,55 @@ type KVBackupService interface {
 	// Backup creates a live backup copy of the metadata database.
 	Backup(ctx context.Context, w io.Writer) error
 }
+
+// BackupFilter restricts the data included in a backup.
+type BackupFilter struct {
+	// OrgID restricts the backup to the buckets of an organization.
+	OrgID *ID `json:"orgID,omitempty"`
+	// BucketID restricts the backup to a single bucket of OrgID.
+	BucketID *ID `json:"bucketID,omitempty"`
+	// Since restricts the backup to the TSM files written after the given time,
+	// typically the creation time of a previous backup.
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// Partial returns true if the backup is restricted to an organization or bucket.
+// Partial backups do not include the metadata database.
+func (f BackupFilter) Partial() bool {
+	return f.OrgID != nil || f.BucketID != nil
+}
+
+// BackupManifest describes the files of a backup.
+type BackupManifest struct {
+	// ID identifies the backup and is referenced by incremental backups.
+	ID ID `json:"id"`
+	// BackupID is the handle used to fetch the backup files from the server
+	// that created the backup. It is only valid until the server restarts.
+	BackupID  int          `json:"backupID"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Filter    BackupFilter `json:"filter"`
+	// KV is the snapshot of the metadata database, not included in partial backups.
+	KV *BackupManifestFile `json:"kv,omitempty"`
+	// Configs are the CLI configs of the server, not included in partial backups.
+	Configs *BackupManifestFile `json:"configs,omitempty"`
+	// Files are the TSM and tombstone files of the backup.
+	Files []BackupManifestFile `json:"files"`
+	// Buckets are the buckets included in the backup, used to recreate them on restore.
+	Buckets []*Bucket `json:"buckets,omitempty"`
+}
+
+// BackupManifestFile describes a single file of a backup.
+type BackupManifestFile struct {
+	FileName string `json:"fileName"`
+	Size     int64  `json:"size"`
+	// Checksum is the hex-encoded SHA-256 digest of the file.
+	Checksum string `json:"checksum"`
+	// Buckets are the buckets with data in a TSM file.
+	Buckets []BackupManifestBucket `json:"buckets,omitempty"`
+}
+
+// BackupManifestBucket identifies a bucket with data in a backup file.
+type BackupManifestBucket struct {
+	OrgID    ID `json:"orgID"`
+	BucketID ID `json:"bucketID"`
+}
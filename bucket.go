@@ -34,13 +34,14 @@ const InfiniteRetention = 0
 
 // Bucket is a bucket. 🎉
 type Bucket struct {
-	ID                  ID            `json:"id,omitempty"`
-	OrgID               ID            `json:"orgID,omitempty"`
-	Type                BucketType    `json:"type"`
-	Name                string        `json:"name"`
-	Description         string        `json:"description"`
-	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
-	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	ID                  ID                 `json:"id,omitempty"`
+	OrgID               ID                 `json:"orgID,omitempty"`
+	Type                BucketType         `json:"type"`
+	Name                string             `json:"name"`
+	Description         string             `json:"description"`
+	RetentionPolicyName string             `json:"rp,omitempty"` // This to support v1 sources
+	RetentionPeriod     time.Duration      `json:"retentionPeriod"`
+	DownsamplePolicies  []DownsamplePolicy `json:"downsamplePolicies,omitempty"`
 	CRUDLog
 }
 
@@ -104,6 +105,9 @@ type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	Description     *string        `json:"description,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+
+	// DownsamplePolicies replaces every downsample policy of the bucket when set.
+	DownsamplePolicies *[]DownsamplePolicy `json:"downsamplePolicies,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/bolt"
 	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
 )
@@ -20,10 +24,18 @@ func cmdBackup(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 		`Backs up data and meta data for the running InfluxDB instance.
 Downloaded files are written to the directory indicated by --path.
 The target directory, and any parent directories, are created automatically.
-Data file have extension .tsm; meta data is written to %s in the same directory.`,
+Data file have extension .tsm; meta data is written to %s in the same directory.
+The files of the backup are described by <backup ID>.manifest.
+
+Backups can be restricted to an organization (--org or --org-id) or a single
+bucket (--bucket or --bucket-id); such backups do not include the meta data.
+
+With --since, only the data files written after the given time, or after the
+backup with the given ID in --path, are downloaded.`,
 		bolt.DefaultFilename)
 
 	f.registerFlags(cmd)
+	backupFlags.org.register(cmd, false)
 
 	opts := flagOpts{
 		{
@@ -34,6 +46,22 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 			Desc:     "directory path to write backup files to",
 			Required: true,
 		},
+		{
+			DestP: &backupFlags.Bucket,
+			Flag:  "bucket",
+			Short: 'b',
+			Desc:  "The name of the bucket to back up",
+		},
+		{
+			DestP: &backupFlags.BucketID,
+			Flag:  "bucket-id",
+			Desc:  "The ID of the bucket to back up",
+		},
+		{
+			DestP: &backupFlags.Since,
+			Flag:  "since",
+			Desc:  "Only back up data written after an RFC3339 time or after the backup with the given ID",
+		},
 	}
 	opts.mustRegister(cmd)
 
@@ -41,7 +69,11 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 }
 
 var backupFlags struct {
-	Path string
+	Path     string
+	Bucket   string
+	BucketID string
+	Since    string
+	org      organization
 }
 
 func newBackupService() (influxdb.BackupService, error) {
@@ -63,34 +95,198 @@ func backupF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	filter, err := backupFilter(ctx)
+	if err != nil {
+		return err
+	}
+
 	backupService, err := newBackupService()
 	if err != nil {
 		return err
 	}
 
-	id, backupFilenames, err := backupService.CreateBackup(ctx)
+	manifest, err := backupService.CreateBackup(ctx, filter)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Backup ID %d contains %d files\n", id, len(backupFilenames))
+	files := manifest.Files
+	if manifest.KV != nil {
+		files = append(files, *manifest.KV)
+	}
+	if manifest.Configs != nil {
+		files = append(files, *manifest.Configs)
+	}
+
+	fmt.Printf("Backup ID %s contains %d files\n", manifest.ID, len(files))
 
-	for _, backupFilename := range backupFilenames {
-		dest := filepath.Join(backupFlags.Path, backupFilename)
-		w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-		if err != nil {
+	for _, file := range files {
+		if err := fetchBackupFile(ctx, backupService, manifest.BackupID, file); err != nil {
 			return err
 		}
-		err = backupService.FetchBackupFile(ctx, id, backupFilename, w)
+	}
+
+	if err := writeBackupManifest(backupFlags.Path, manifest); err != nil {
+		return err
+	}
+
+	fmt.Println("Backup complete")
+
+	return nil
+}
+
+// backupFilter builds the backup filter from the flags, resolving the
+// bucket, organization and previous backup they reference.
+func backupFilter(ctx context.Context) (influxdb.BackupFilter, error) {
+	var filter influxdb.BackupFilter
+
+	if backupFlags.Bucket != "" && backupFlags.BucketID != "" {
+		return filter, fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+	if backupFlags.org.id != "" && backupFlags.org.name != "" {
+		return filter, fmt.Errorf("must specify org-id, or org name not both")
+	}
+
+	switch {
+	case backupFlags.Bucket != "" || backupFlags.BucketID != "":
+		bucket, err := findBackupBucket(ctx)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID, filter.BucketID = &bucket.OrgID, &bucket.ID
+	case backupFlags.org.id != "" || backupFlags.org.name != "":
+		orgSvc, err := newOrganizationService()
 		if err != nil {
-			return multierr.Append(fmt.Errorf("error fetching file %s: %v", backupFilename, err), w.Close())
+			return filter, err
 		}
-		if err = w.Close(); err != nil {
-			return err
+		orgID, err := backupFlags.org.getID(orgSvc)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &orgID
+	}
+
+	if backupFlags.Since != "" {
+		since, err := parseBackupSince(backupFlags.Path, backupFlags.Since)
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = &since
+	}
+	return filter, nil
+}
+
+func findBackupBucket(ctx context.Context) (*influxdb.Bucket, error) {
+	var (
+		filter influxdb.BucketFilter
+		err    error
+	)
+	if backupFlags.BucketID != "" {
+		filter.ID, err = influxdb.IDFromString(backupFlags.BucketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+	} else {
+		filter.Name = &backupFlags.Bucket
+		if backupFlags.org.id == "" && backupFlags.org.name == "" {
+			backupFlags.org.name = flags.Org
+		}
+	}
+
+	if backupFlags.org.id != "" {
+		filter.OrganizationID, err = influxdb.IDFromString(backupFlags.org.id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id id: %v", err)
+		}
+	}
+	if backupFlags.org.name != "" {
+		filter.Org = &backupFlags.org.name
+	}
+
+	bs, err := newBucketService()
+	if err != nil {
+		return nil, err
+	}
+	buckets, _, err := bs.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve buckets: %v", err)
+	}
+	if len(buckets) == 0 {
+		if backupFlags.Bucket != "" {
+			return nil, fmt.Errorf("bucket %q was not found", backupFlags.Bucket)
 		}
+		return nil, fmt.Errorf("bucket with id %q does not exist", backupFlags.BucketID)
+	}
+	return buckets[0], nil
+}
+
+// parseBackupSince parses since as an RFC3339 time, or as the ID of a backup
+// whose manifest is in dir.
+func parseBackupSince(dir, since string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+
+	id, err := influxdb.IDFromString(since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be an RFC3339 time or a backup ID: %q", since)
+	}
+	m, err := readBackupManifest(dir, *id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return m.CreatedAt, nil
+}
+
+func backupManifestPath(dir string, id influxdb.ID) string {
+	return filepath.Join(dir, id.String()+".manifest")
+}
+
+func readBackupManifest(dir string, id influxdb.ID) (*influxdb.BackupManifest, error) {
+	b, err := ioutil.ReadFile(backupManifestPath(dir, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest of backup %s: %v", id, err)
 	}
+	var m influxdb.BackupManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of backup %s: %v", id, err)
+	}
+	return &m, nil
+}
+
+func writeBackupManifest(dir string, m *influxdb.BackupManifest) error {
+	f, err := os.OpenFile(backupManifestPath(dir, m.ID), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(f, m); err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	return f.Close()
+}
 
-	fmt.Printf("Backup complete")
+// fetchBackupFile downloads a file of the backup into the backup path and
+// verifies it against the manifest.
+func fetchBackupFile(ctx context.Context, backupService influxdb.BackupService, backupID int, file influxdb.BackupManifestFile) error {
+	dest := filepath.Join(backupFlags.Path, file.FileName)
+	w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	err = backupService.FetchBackupFile(ctx, backupID, file.FileName, w)
+	if err != nil {
+		return multierr.Append(fmt.Errorf("error fetching file %s: %v", file.FileName, err), w.Close())
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
 
+	got, err := storage.NewBackupManifestFile(dest)
+	if err != nil {
+		return err
+	}
+	if got.Size != file.Size || got.Checksum != file.Checksum {
+		return fmt.Errorf("file %s does not match the backup manifest", file.FileName)
+	}
 	return nil
 }
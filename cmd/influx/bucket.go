@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/http"
@@ -29,6 +30,11 @@ type cmdBucketBuilder struct {
 	description string
 	org         organization
 	retention   string
+
+	destID     string
+	every      string
+	lag        string
+	aggregates []string
 }
 
 func newCmdBucketBuilder(svcsFn bucketSVCsFn, f *globalFlags, opts genericCLIOpts) *cmdBucketBuilder {
@@ -47,6 +53,7 @@ func (b *cmdBucketBuilder) cmd() *cobra.Command {
 	cmd.AddCommand(
 		b.cmdCreate(),
 		b.cmdDelete(),
+		b.cmdDownsample(),
 		b.cmdList(),
 		b.cmdUpdate(),
 	)
@@ -289,6 +296,268 @@ func (b *cmdBucketBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) er
 	return b.printBuckets(bucketPrintOpt{bucket: bkt})
 }
 
+func (b *cmdBucketBuilder) cmdDownsample() *cobra.Command {
+	cmd := b.newCmd("downsample", nil)
+	cmd.Short = "Bucket downsample policy management commands"
+	cmd.Long = `Manage the downsample policies of a bucket. Each policy continuously
+aggregates the data written to the bucket into a destination bucket using a
+task managed by the server.
+
+Aggregates are given as <field type>=<function>[:<field>,...]. Only one
+aggregate may omit its fields; it applies to all fields not listed by another
+aggregate whatever their type, so its function must be count, first or last.
+For example:
+
+	influx bucket downsample set -i <id> --dest-id <id> --every 1h --lag 5m \
+		--aggregate float=mean:usage_user,usage_system --aggregate float=last`
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdDownsampleList(),
+		b.cmdDownsampleRemove(),
+		b.cmdDownsampleSet(),
+	)
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleList() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdDownsampleListRunEFn)
+	cmd.Short = "List the downsample policies of a bucket and their status"
+	cmd.Aliases = []string{"find", "ls"}
+
+	b.registerPrintFlags(cmd)
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The bucket ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleListRunEFn(cmd *cobra.Command, args []string) error {
+	bktSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", b.id, err)
+	}
+
+	bkt, err := bktSVC.FindBucketByID(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("failed to find bucket with id %q: %v", id, err)
+	}
+
+	return b.printDownsamplePolicies(bkt.DownsamplePolicies)
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleSet() *cobra.Command {
+	cmd := b.newCmd("set", b.cmdDownsampleSetRunEFn)
+	cmd.Short = "Create or replace the downsample policy of a bucket for a destination bucket"
+
+	b.registerPrintFlags(cmd)
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The bucket ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&b.destID, "dest-id", "", "The destination bucket ID (required)")
+	cmd.MarkFlagRequired("dest-id")
+	cmd.Flags().StringVar(&b.every, "every", "", "Window the data is aggregated over (required)")
+	cmd.MarkFlagRequired("every")
+	cmd.Flags().StringVar(&b.lag, "lag", "", "Duration to wait for late data before aggregating a window")
+	cmd.Flags().StringArrayVar(&b.aggregates, "aggregate", []string{"float=last"}, "Aggregate applied to fields as <field type>=<function>[:<field>,...]")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleSetRunEFn(cmd *cobra.Command, args []string) error {
+	bktSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id, destID influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", b.id, err)
+	}
+	if err := destID.DecodeFromString(b.destID); err != nil {
+		return fmt.Errorf("failed to decode destination bucket id %q: %v", b.destID, err)
+	}
+
+	every, err := rawDurationToTimeDuration(b.every)
+	if err != nil {
+		return err
+	}
+	lag, err := rawDurationToTimeDuration(b.lag)
+	if err != nil {
+		return err
+	}
+
+	policy := influxdb.DownsamplePolicy{
+		DestinationBucketID: destID,
+		Every:               influxdb.Duration{Duration: every},
+		Lag:                 influxdb.Duration{Duration: lag},
+	}
+	for _, raw := range b.aggregates {
+		agg, err := parseDownsampleAggregate(raw)
+		if err != nil {
+			return err
+		}
+		policy.Aggregates = append(policy.Aggregates, agg)
+	}
+
+	ctx := context.Background()
+	bkt, err := bktSVC.FindBucketByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find bucket with id %q: %v", id, err)
+	}
+
+	policies := make([]influxdb.DownsamplePolicy, 0, len(bkt.DownsamplePolicies)+1)
+	for _, p := range bkt.DownsamplePolicies {
+		if p.DestinationBucketID != destID {
+			policies = append(policies, p)
+		}
+	}
+	policies = append(policies, policy)
+
+	bkt, err = bktSVC.UpdateBucket(ctx, id, downsampleUpdate(bkt, policies))
+	if err != nil {
+		return fmt.Errorf("failed to update bucket: %v", err)
+	}
+
+	return b.printDownsamplePolicies(bkt.DownsamplePolicies)
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleRemove() *cobra.Command {
+	cmd := b.newCmd("remove", b.cmdDownsampleRemoveRunEFn)
+	cmd.Short = "Remove the downsample policy of a bucket for a destination bucket"
+	cmd.Aliases = []string{"delete", "rm"}
+
+	b.registerPrintFlags(cmd)
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The bucket ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&b.destID, "dest-id", "", "The destination bucket ID (required)")
+	cmd.MarkFlagRequired("dest-id")
+
+	return cmd
+}
+
+func (b *cmdBucketBuilder) cmdDownsampleRemoveRunEFn(cmd *cobra.Command, args []string) error {
+	bktSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id, destID influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", b.id, err)
+	}
+	if err := destID.DecodeFromString(b.destID); err != nil {
+		return fmt.Errorf("failed to decode destination bucket id %q: %v", b.destID, err)
+	}
+
+	ctx := context.Background()
+	bkt, err := bktSVC.FindBucketByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find bucket with id %q: %v", id, err)
+	}
+
+	policies := make([]influxdb.DownsamplePolicy, 0, len(bkt.DownsamplePolicies))
+	for _, p := range bkt.DownsamplePolicies {
+		if p.DestinationBucketID != destID {
+			policies = append(policies, p)
+		}
+	}
+	if len(policies) == len(bkt.DownsamplePolicies) {
+		return fmt.Errorf("bucket %q does not downsample into %q", id, destID)
+	}
+
+	bkt, err = bktSVC.UpdateBucket(ctx, id, downsampleUpdate(bkt, policies))
+	if err != nil {
+		return fmt.Errorf("failed to update bucket: %v", err)
+	}
+
+	return b.printDownsamplePolicies(bkt.DownsamplePolicies)
+}
+
+func (b *cmdBucketBuilder) printDownsamplePolicies(policies []influxdb.DownsamplePolicy) error {
+	if b.json {
+		return b.writeJSON(policies)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	w.WriteHeaders(
+		"Destination ID",
+		"Every",
+		"Lag",
+		"Aggregates",
+		"Task ID",
+		"Task Status",
+		"Latest Completed",
+		"Last Run Status",
+		"Last Run Error",
+	)
+
+	for _, p := range policies {
+		aggs := make([]string, 0, len(p.Aggregates))
+		for _, a := range p.Aggregates {
+			agg := string(a.FieldType) + "=" + a.Function
+			if len(a.Fields) > 0 {
+				agg += ":" + strings.Join(a.Fields, ",")
+			}
+			aggs = append(aggs, agg)
+		}
+
+		m := map[string]interface{}{
+			"Destination ID": p.DestinationBucketID.String(),
+			"Every":          p.Every.Duration,
+			"Lag":            p.Lag.Duration,
+			"Aggregates":     strings.Join(aggs, " "),
+			"Task ID":        p.TaskID.String(),
+		}
+		if p.Status != nil {
+			m["Task Status"] = p.Status.TaskStatus
+			m["Latest Completed"] = p.Status.LatestCompleted
+			m["Last Run Status"] = p.Status.LastRunStatus
+			m["Last Run Error"] = p.Status.LastRunError
+		}
+		w.Write(m)
+	}
+
+	return nil
+}
+
+// downsampleUpdate returns an update replacing the downsample policies of bkt.
+// The retention period is sent along as the server resets it when omitted.
+func downsampleUpdate(bkt *influxdb.Bucket, policies []influxdb.DownsamplePolicy) influxdb.BucketUpdate {
+	upd := influxdb.BucketUpdate{DownsamplePolicies: &policies}
+	if bkt.RetentionPeriod > 0 {
+		upd.RetentionPeriod = &bkt.RetentionPeriod
+	}
+	return upd
+}
+
+// parseDownsampleAggregate parses an aggregate given as <field type>=<function>[:<field>,...].
+func parseDownsampleAggregate(raw string) (influxdb.DownsampleAggregate, error) {
+	parts := strings.SplitN(raw, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return influxdb.DownsampleAggregate{}, fmt.Errorf("invalid aggregate %q: expected <field type>=<function>[:<field>,...]", raw)
+	}
+
+	agg := influxdb.DownsampleAggregate{FieldType: influxdb.DownsampleFieldType(parts[0])}
+	fn := strings.SplitN(parts[1], ":", 2)
+	agg.Function = fn[0]
+	if len(fn) == 2 && fn[1] != "" {
+		agg.Fields = strings.Split(fn[1], ",")
+	}
+	if err := agg.Valid(); err != nil {
+		return influxdb.DownsampleAggregate{}, fmt.Errorf("invalid aggregate %q: %v", raw, err)
+	}
+	return agg, nil
+}
+
 func (b *cmdBucketBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
 	cmd := b.genericCLIOpts.newCmd(use, runE, true)
 	b.globalFlags.registerFlags(cmd)
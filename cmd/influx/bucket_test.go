@@ -95,7 +95,7 @@ func TestCmdBucket(t *testing.T) {
 		cmdFn := func(expectedBkt influxdb.Bucket) func(*globalFlags, genericCLIOpts) *cobra.Command {
 			svc := mock.NewBucketService()
 			svc.CreateBucketFn = func(ctx context.Context, bucket *influxdb.Bucket) error {
-				if expectedBkt != *bucket {
+				if !reflect.DeepEqual(expectedBkt, *bucket) {
 					return fmt.Errorf("unexpected bucket;\n\twant= %+v\n\tgot=  %+v", expectedBkt, *bucket)
 				}
 				return nil
@@ -445,6 +445,99 @@ func TestCmdBucket(t *testing.T) {
 			t.Run(tt.name, fn)
 		}
 	})
+
+	t.Run("downsample set", func(t *testing.T) {
+		existing := influxdb.DownsamplePolicy{
+			DestinationBucketID: 4,
+			Every:               influxdb.Duration{Duration: time.Hour},
+			Aggregates:          []influxdb.DownsampleAggregate{{FieldType: "float", Function: "last"}},
+			TaskID:              7,
+		}
+
+		tests := []struct {
+			name     string
+			flags    []string
+			expected []influxdb.DownsamplePolicy
+		}{
+			{
+				name: "default aggregate",
+				flags: []string{
+					"--id=" + influxdb.ID(3).String(),
+					"--dest-id=" + influxdb.ID(5).String(),
+					"--every=1d",
+				},
+				expected: []influxdb.DownsamplePolicy{
+					existing,
+					{
+						DestinationBucketID: 5,
+						Every:               influxdb.Duration{Duration: 24 * time.Hour},
+						Aggregates:          []influxdb.DownsampleAggregate{{FieldType: "float", Function: "last"}},
+					},
+				},
+			},
+			{
+				name: "replaces policy of destination",
+				flags: []string{
+					"-i=" + influxdb.ID(3).String(),
+					"--dest-id=" + influxdb.ID(4).String(),
+					"--every=5m",
+					"--lag=30s",
+					"--aggregate=integer=count",
+					"--aggregate=float=mean:usage",
+				},
+				expected: []influxdb.DownsamplePolicy{
+					{
+						DestinationBucketID: 4,
+						Every:               influxdb.Duration{Duration: 5 * time.Minute},
+						Lag:                 influxdb.Duration{Duration: 30 * time.Second},
+						Aggregates: []influxdb.DownsampleAggregate{
+							{FieldType: "integer", Function: "count"},
+							{FieldType: "float", Function: "mean", Fields: []string{"usage"}},
+						},
+					},
+				},
+			},
+		}
+
+		cmdFn := func(expected []influxdb.DownsamplePolicy) func(*globalFlags, genericCLIOpts) *cobra.Command {
+			svc := mock.NewBucketService()
+			svc.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				return &influxdb.Bucket{ID: id, RetentionPeriod: time.Hour, DownsamplePolicies: []influxdb.DownsamplePolicy{existing}}, nil
+			}
+			svc.UpdateBucketFn = func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+				if id != 3 {
+					return nil, fmt.Errorf("unexpecte id:\n\twant= %s\n\tgot=  %s", influxdb.ID(3), id)
+				}
+				if upd.RetentionPeriod == nil || *upd.RetentionPeriod != time.Hour {
+					return nil, fmt.Errorf("expected retention period to be kept")
+				}
+				if upd.DownsamplePolicies == nil || !reflect.DeepEqual(expected, *upd.DownsamplePolicies) {
+					return nil, fmt.Errorf("unexpected downsample policies;\n\twant= %+v\n\tgot=  %+v", expected, upd.DownsamplePolicies)
+				}
+				return &influxdb.Bucket{}, nil
+			}
+
+			return func(g *globalFlags, opt genericCLIOpts) *cobra.Command {
+				return newCmdBucketBuilder(fakeSVCFn(svc), g, opt).cmd()
+			}
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				builder := newInfluxCmdBuilder(
+					in(new(bytes.Buffer)),
+					out(ioutil.Discard),
+				)
+
+				cmd := builder.cmd(cmdFn(tt.expected))
+
+				cmd.SetArgs(append([]string{"bucket", "downsample", "set"}, tt.flags...))
+				require.NoError(t, cmd.Execute())
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
 }
 
 func strPtr(s string) *string {
@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/http"
 	"github.com/influxdata/influxdb/v2/kit/signals"
 	"github.com/spf13/cobra"
@@ -22,13 +24,33 @@ type cmdDeleteBuilder struct {
 	*globalFlags
 
 	flags http.DeleteRequest
+
+	id          string
+	status      string
+	wait        bool
+	hideHeaders bool
+	json        bool
 }
 
 func (b *cmdDeleteBuilder) cmd() *cobra.Command {
 	cmd := b.newCmd("delete", b.fluxDeleteF)
 	cmd.Short = "Delete points from influxDB"
 	cmd.Long = `Delete points from influxDB, by specify start, end time
-	and a sql like predicate string.`
+	and a sql like predicate string.
+
+	The predicate compares tags, _measurement and _field with =, !=, =~ and !~,
+	combined with and, or and parentheses, exp
+	'_measurement="cpu" and (host=~/^web-/ or _field="usage_typo")'.
+
+	The delete runs in the background as a delete job, use --wait to wait for
+	it to finish. Delete jobs are listed, inspected and canceled with the
+	list, status and cancel commands.`
+	cmd.TraverseChildren = true
+	cmd.AddCommand(
+		b.cmdList(),
+		b.cmdStatus(),
+		b.cmdCancel(),
+	)
 
 	opts := flagOpts{
 		{
@@ -62,8 +84,39 @@ func (b *cmdDeleteBuilder) cmd() *cobra.Command {
 
 	cmd.PersistentFlags().StringVar(&b.flags.Start, "start", "", "the start time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
 	cmd.PersistentFlags().StringVar(&b.flags.Stop, "stop", "", "the stop time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
-	cmd.PersistentFlags().StringVarP(&b.flags.Predicate, "predicate", "p", "", "sql like predicate string, exp 'tag1=\"v1\" and (tag2=123)'")
+	cmd.PersistentFlags().StringVarP(&b.flags.Predicate, "predicate", "p", "", "sql like predicate string, exp 'tag1=\"v1\" and (tag2=123 or tag3=~/^v3/)'")
+	cmd.Flags().BoolVar(&b.wait, "wait", false, "Wait for the delete job to finish")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdDeleteBuilder) cmdList() *cobra.Command {
+	cmd := b.newCmd("list", b.listF)
+	cmd.Short = "List delete jobs"
+	cmd.Long = "List the delete jobs of the organization and bucket, if given, oldest first"
+	cmd.Aliases = []string{"find", "ls"}
+	cmd.Flags().StringVar(&b.status, "status", "", "Only list jobs with the status, one of queued, running, canceling, success, failed or canceled")
+	b.registerPrintFlags(cmd)
+	return cmd
+}
 
+func (b *cmdDeleteBuilder) cmdStatus() *cobra.Command {
+	cmd := b.newCmd("status", b.statusF)
+	cmd.Short = "Show the status and progress of a delete job"
+	b.registerJobIDFlag(cmd)
+	cmd.Flags().BoolVar(&b.wait, "wait", false, "Wait for the delete job to finish")
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdDeleteBuilder) cmdCancel() *cobra.Command {
+	cmd := b.newCmd("cancel", b.cancelF)
+	cmd.Short = "Cancel a queued or running delete job"
+	cmd.Long = `Cancel a queued or running delete job. A running job stops between
+	TSM files, points it already deleted are not restored.`
+	b.registerJobIDFlag(cmd)
+	b.registerPrintFlags(cmd)
 	return cmd
 }
 
@@ -84,18 +137,183 @@ func (b *cmdDeleteBuilder) fluxDeleteF(cmd *cobra.Command, args []string) error
 		return fmt.Errorf("both start and stop are required")
 	}
 
-	s := &http.DeleteService{
+	s := b.newDeleteService()
+
+	ctx := signals.WithStandardSignals(context.Background())
+	job, err := s.DeleteBucketRangePredicate(ctx, b.flags)
+	if err != nil && err != context.Canceled {
+		return fmt.Errorf("failed to delete data: %v", err)
+	} else if err != nil {
+		return nil
+	}
+
+	if b.wait {
+		return b.waitForJob(ctx, s, job)
+	}
+	return b.printJobs(deleteJobPrintOpt{job: job})
+}
+
+func (b *cmdDeleteBuilder) listF(cmd *cobra.Command, args []string) error {
+	if b.flags.Org == "" {
+		b.flags.Org = b.globalFlags.Org
+	}
+
+	jobs, err := b.newDeleteService().FindDeleteJobs(context.Background(), b.flags, b.status)
+	if err != nil {
+		return fmt.Errorf("failed to list delete jobs: %v", err)
+	}
+	return b.printJobs(deleteJobPrintOpt{jobs: jobs})
+}
+
+func (b *cmdDeleteBuilder) statusF(cmd *cobra.Command, args []string) error {
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode delete job id %q: %v", b.id, err)
+	}
+
+	s := b.newDeleteService()
+	ctx := signals.WithStandardSignals(context.Background())
+	job, err := s.FindDeleteJobByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find delete job: %v", err)
+	}
+
+	if b.wait {
+		return b.waitForJob(ctx, s, job)
+	}
+	return b.printJobs(deleteJobPrintOpt{job: job})
+}
+
+func (b *cmdDeleteBuilder) cancelF(cmd *cobra.Command, args []string) error {
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode delete job id %q: %v", b.id, err)
+	}
+
+	s := b.newDeleteService()
+	ctx := context.Background()
+	if err := s.CancelDeleteJob(ctx, *id); err != nil {
+		return fmt.Errorf("failed to cancel delete job: %v", err)
+	}
+
+	job, err := s.FindDeleteJobByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find delete job: %v", err)
+	}
+	return b.printJobs(deleteJobPrintOpt{job: job})
+}
+
+// waitForJob polls the delete job until it finished and prints it, returning
+// an error if the job failed. It stops waiting when ctx is done.
+func (b *cmdDeleteBuilder) waitForJob(ctx context.Context, s *http.DeleteService, job *influxdb.DeleteJob) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for !job.Status.Finished() {
+		select {
+		case <-ctx.Done():
+			return b.printJobs(deleteJobPrintOpt{job: job})
+		case <-ticker.C:
+		}
+
+		var err error
+		if job, err = s.FindDeleteJobByID(ctx, job.ID); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to find delete job: %v", err)
+		} else if err != nil {
+			return nil
+		}
+	}
+
+	if err := b.printJobs(deleteJobPrintOpt{job: job}); err != nil {
+		return err
+	}
+	if job.Status == influxdb.DeleteJobFailed {
+		return fmt.Errorf("delete job %s failed: %s", job.ID, job.Error)
+	}
+	return nil
+}
+
+type deleteJobPrintOpt struct {
+	job  *influxdb.DeleteJob
+	jobs []*influxdb.DeleteJob
+}
+
+func (b *cmdDeleteBuilder) printJobs(printOpt deleteJobPrintOpt) error {
+	if b.json {
+		var v interface{} = printOpt.jobs
+		if printOpt.jobs == nil {
+			v = printOpt.job
+		}
+		return b.writeJSON(v)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	w.WriteHeaders(
+		"ID",
+		"Status",
+		"Organization ID",
+		"Bucket ID",
+		"Start",
+		"Stop",
+		"Predicate",
+		"Files Scanned",
+		"Files Rewritten",
+		"Series Removed",
+		"Created At",
+		"Error",
+	)
+
+	if printOpt.job != nil {
+		printOpt.jobs = append(printOpt.jobs, printOpt.job)
+	}
+
+	for _, j := range printOpt.jobs {
+		w.Write(map[string]interface{}{
+			"ID":              j.ID.String(),
+			"Status":          j.Status,
+			"Organization ID": j.OrgID.String(),
+			"Bucket ID":       j.BucketID.String(),
+			"Start":           j.Start.Format(time.RFC3339Nano),
+			"Stop":            j.Stop.Format(time.RFC3339Nano),
+			"Predicate":       j.Predicate,
+			"Files Scanned":   j.Progress.FilesScanned,
+			"Files Rewritten": j.Progress.FilesRewritten,
+			"Series Removed":  j.Progress.SeriesRemoved,
+			"Created At":      j.CreatedAt.Format(time.RFC3339),
+			"Error":           j.Error,
+		})
+	}
+
+	return nil
+}
+
+func (b *cmdDeleteBuilder) newDeleteService() *http.DeleteService {
+	return &http.DeleteService{
 		Addr:               flags.Host,
 		Token:              flags.Token,
 		InsecureSkipVerify: flags.skipVerify,
 	}
+}
 
-	ctx := signals.WithStandardSignals(context.Background())
-	if err := s.DeleteBucketRangePredicate(ctx, b.flags); err != nil && err != context.Canceled {
-		return fmt.Errorf("failed to delete data: %v", err)
+func (b *cmdDeleteBuilder) registerJobIDFlag(cmd *cobra.Command) {
+	opts := flagOpts{
+		{
+			DestP:    &b.id,
+			Flag:     "id",
+			Short:    'i',
+			Desc:     "The delete job ID",
+			Required: true,
+		},
 	}
+	opts.mustRegister(cmd)
+}
 
-	return nil
+func (b *cmdDeleteBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
 }
 
 func (b *cmdDeleteBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
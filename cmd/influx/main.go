@@ -285,8 +285,10 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdPing,
 		cmdQuery,
 		cmdREPL,
+		cmdRestore,
 		cmdSecret,
 		cmdSetup,
+		cmdSilence,
 		cmdStack,
 		cmdTask,
 		cmdTemplate,
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+)
+
+func cmdRestore(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("restore", restoreF, false)
+	cmd.Short = "Restore a bucket into a running InfluxDB"
+	cmd.Long = `Restores a single bucket from the backups in the directory indicated by --path
+into the running InfluxDB instance, leaving other buckets untouched.
+
+The bucket is created from the backed up bucket, optionally under a new name
+(--new-bucket) or in another organization (--new-org or --new-org-id), and must
+not exist yet. The data of every backup in --path holding data of the bucket,
+including incremental backups, is loaded in the order the backups were taken.
+
+To replace all data and meta data of a stopped instance, use "influxd restore".`
+
+	f.registerFlags(cmd)
+
+	opts := flagOpts{
+		{
+			DestP:    &restoreFlags.Path,
+			Flag:     "path",
+			Short:    'p',
+			EnvVar:   "PATH",
+			Desc:     "directory path to read backup files from",
+			Required: true,
+		},
+		{
+			DestP: &restoreFlags.Bucket,
+			Flag:  "bucket",
+			Short: 'b',
+			Desc:  "The name of the backed up bucket to restore",
+		},
+		{
+			DestP: &restoreFlags.BucketID,
+			Flag:  "bucket-id",
+			Desc:  "The ID of the backed up bucket to restore",
+		},
+		{
+			DestP: &restoreFlags.NewBucket,
+			Flag:  "new-bucket",
+			Desc:  "The name of the restored bucket, defaults to the name of the backed up bucket",
+		},
+		{
+			DestP: &restoreFlags.newOrg.name,
+			Flag:  "new-org",
+			Desc:  "The name of the organization to restore the bucket into, defaults to the organization of the backed up bucket",
+		},
+		{
+			DestP: &restoreFlags.newOrg.id,
+			Flag:  "new-org-id",
+			Desc:  "The ID of the organization to restore the bucket into",
+		},
+	}
+	opts.mustRegister(cmd)
+
+	return cmd
+}
+
+var restoreFlags struct {
+	Path      string
+	Bucket    string
+	BucketID  string
+	NewBucket string
+	newOrg    organization
+}
+
+func newRestoreService() (influxdb.RestoreService, error) {
+	return &http.RestoreService{
+		Addr:               flags.Host,
+		Token:              flags.Token,
+		InsecureSkipVerify: flags.skipVerify,
+	}, nil
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if restoreFlags.Bucket == "" && restoreFlags.BucketID == "" {
+		return fmt.Errorf("must specify bucket or bucket-id")
+	} else if restoreFlags.Bucket != "" && restoreFlags.BucketID != "" {
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+	if restoreFlags.newOrg.id != "" && restoreFlags.newOrg.name != "" {
+		return fmt.Errorf("must specify new-org-id, or new-org not both")
+	}
+
+	manifests, err := readBackupManifests(restoreFlags.Path)
+	if err != nil {
+		return err
+	}
+
+	src, err := findRestoreBucket(manifests, restoreFlags.Bucket, restoreFlags.BucketID)
+	if err != nil {
+		return err
+	}
+
+	bucket := &influxdb.Bucket{
+		OrgID:           src.OrgID,
+		Name:            src.Name,
+		Description:     src.Description,
+		RetentionPeriod: src.RetentionPeriod,
+	}
+	if restoreFlags.NewBucket != "" {
+		bucket.Name = restoreFlags.NewBucket
+	}
+	if restoreFlags.newOrg.id != "" || restoreFlags.newOrg.name != "" {
+		orgSvc, err := newOrganizationService()
+		if err != nil {
+			return err
+		}
+		if bucket.OrgID, err = restoreFlags.newOrg.getID(orgSvc); err != nil {
+			return err
+		}
+	}
+
+	bucketSvc, err := newBucketService()
+	if err != nil {
+		return err
+	}
+	if err := bucketSvc.CreateBucket(ctx, bucket); err != nil {
+		if influxdb.ErrorCode(err) == influxdb.EConflict {
+			return fmt.Errorf("bucket %q already exists, use --new-bucket to restore it under another name", bucket.Name)
+		}
+		return fmt.Errorf("failed to create bucket: %v", err)
+	}
+
+	restoreSvc, err := newRestoreService()
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "influx-restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	from := influxdb.BackupManifestBucket{OrgID: src.OrgID, BucketID: src.ID}
+	var n int
+	for _, m := range manifests {
+		for _, file := range m.Files {
+			if !backupFileHoldsBucket(file, from) {
+				continue
+			}
+			if err := restoreBackupFile(ctx, restoreSvc, tmpDir, file.FileName, bucket, from); err != nil {
+				return err
+			}
+			n++
+		}
+	}
+
+	fmt.Printf("Restored %d files of bucket %s into bucket %s (%s)\n", n, src.ID, bucket.Name, bucket.ID)
+	return nil
+}
+
+// readBackupManifests reads the manifests of the backups in dir, oldest first.
+func readBackupManifests(dir string) ([]*influxdb.BackupManifest, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.manifest"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no backup manifest found in %s", dir)
+	}
+
+	manifests := make([]*influxdb.BackupManifest, 0, len(paths))
+	for _, path := range paths {
+		id, err := influxdb.IDFromString(strings.TrimSuffix(filepath.Base(path), ".manifest"))
+		if err != nil {
+			continue
+		}
+		m, err := readBackupManifest(dir, *id)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.Before(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// findRestoreBucket returns the most recent metadata of the backed up bucket
+// with the given name or ID.
+func findRestoreBucket(manifests []*influxdb.BackupManifest, name, id string) (*influxdb.Bucket, error) {
+	var bucketID influxdb.ID
+	if id != "" {
+		if err := bucketID.DecodeFromString(id); err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+	}
+
+	var found *influxdb.Bucket
+	for i := len(manifests) - 1; i >= 0 && found == nil; i-- {
+		for _, b := range manifests[i].Buckets {
+			if (id != "" && b.ID != bucketID) || (name != "" && b.Name != name) {
+				continue
+			}
+			if found != nil && found.ID != b.ID {
+				return nil, fmt.Errorf("found several buckets named %q, please specify bucket-id", name)
+			}
+			found = b
+		}
+	}
+
+	if found == nil {
+		if name != "" {
+			return nil, fmt.Errorf("bucket %q was not found in the backups", name)
+		}
+		return nil, fmt.Errorf("bucket with id %q was not found in the backups", id)
+	}
+	return found, nil
+}
+
+func backupFileHoldsBucket(file influxdb.BackupManifestFile, b influxdb.BackupManifestBucket) bool {
+	if filepath.Ext(file.FileName) != "."+tsm1.TSMFileExtension {
+		return false
+	}
+	for _, fb := range file.Buckets {
+		if fb == b {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreBackupFile uploads the data of the bucket from held by the backed up
+// TSM file into bucket. The file and its tombstone are copied to tmpDir, where
+// the data of other buckets and deleted data are dropped before uploading.
+func restoreBackupFile(ctx context.Context, restoreSvc influxdb.RestoreService, tmpDir, fileName string, bucket *influxdb.Bucket, from influxdb.BackupManifestBucket) error {
+	path := filepath.Join(tmpDir, fileName)
+	if err := linkOrCopyFile(filepath.Join(restoreFlags.Path, fileName), path); err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	tombstone := strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".tombstone"
+	if _, err := os.Stat(filepath.Join(restoreFlags.Path, tombstone)); err == nil {
+		if err := linkOrCopyFile(filepath.Join(restoreFlags.Path, tombstone), filepath.Join(tmpDir, tombstone)); err != nil {
+			return err
+		}
+		defer os.Remove(filepath.Join(tmpDir, tombstone))
+	}
+
+	if err := storage.FilterBackupFile(path, from); err != nil {
+		return fmt.Errorf("failed to read backup file %s: %v", fileName, err)
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// All data of the bucket in the file was deleted.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := restoreSvc.RestoreBucket(ctx, bucket.OrgID, bucket.ID, from, f); err != nil {
+		return fmt.Errorf("failed to restore backup file %s: %v", fileName, err)
+	}
+	return nil
+}
+
+// linkOrCopyFile hard links src to dst, falling back to a copy across file systems.
+func linkOrCopyFile(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		return multierr.Append(err, out.Close())
+	}
+	return out.Close()
+}
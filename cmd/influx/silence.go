@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/spf13/cobra"
+)
+
+type silenceSVCsFn func() (influxdb.SilenceService, influxdb.OrganizationService, error)
+
+func cmdSilence(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdSilenceBuilder(newSilenceSVCs, f, opt)
+	return builder.cmd()
+}
+
+type cmdSilenceBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn silenceSVCsFn
+	now   func() time.Time
+
+	json        bool
+	hideHeaders bool
+	id          string
+	matchers    []string
+	start       string
+	end         string
+	duration    time.Duration
+	comment     string
+	expired     bool
+	org         organization
+}
+
+func newCmdSilenceBuilder(svcsFn silenceSVCsFn, f *globalFlags, opt genericCLIOpts) *cmdSilenceBuilder {
+	return &cmdSilenceBuilder{
+		genericCLIOpts: opt,
+		globalFlags:    f,
+		svcFn:          svcsFn,
+		now:            time.Now,
+	}
+}
+
+func (b *cmdSilenceBuilder) cmd() *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd("silence", nil, false)
+	cmd.Short = "Silence management commands"
+	cmd.Long = `Silences mute the notifications of the statuses matching all of their
+matchers between a start and an end time, e.g. during a planned maintenance.
+The muted notifications are logged as suppressed instead of being sent.`
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdCreate(),
+		b.cmdDelete(),
+		b.cmdFind(),
+		b.cmdUpdate(),
+	)
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdCreateRunEFn)
+	cmd.Short = "Create silence"
+
+	cmd.Flags().StringArrayVarP(&b.matchers, "matcher", "m", nil, "Tag the statuses to silence must match, as key=value, key!=value, key=~regex or key!~regex (required)")
+	cmd.Flags().StringVar(&b.start, "start", "", "The start time in RFC3339Nano format, exp 2009-01-02T23:00:00Z; defaults to now")
+	cmd.Flags().StringVar(&b.end, "end", "", "The end time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
+	cmd.Flags().DurationVarP(&b.duration, "duration", "d", 0, "Duration of the silence from its start; used when end is not set")
+	cmd.Flags().StringVarP(&b.comment, "comment", "c", "", "Comment on the reason of the silence")
+	cmd.MarkFlagRequired("matcher")
+	b.org.register(cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdCreateRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+	orgID, err := b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+
+	matchers, err := parseSilenceMatchers(b.matchers)
+	if err != nil {
+		return err
+	}
+
+	start := b.now()
+	if b.start != "" {
+		if start, err = time.Parse(time.RFC3339Nano, b.start); err != nil {
+			return fmt.Errorf("failed to parse start time %q: %v", b.start, err)
+		}
+	}
+	var end time.Time
+	switch {
+	case b.end != "":
+		if end, err = time.Parse(time.RFC3339Nano, b.end); err != nil {
+			return fmt.Errorf("failed to parse end time %q: %v", b.end, err)
+		}
+	case b.duration > 0:
+		end = start.Add(b.duration)
+	default:
+		return fmt.Errorf("one of end or duration is required")
+	}
+
+	s := &influxdb.Silence{
+		OrgID:    orgID,
+		Matchers: matchers,
+		StartsAt: start,
+		EndsAt:   end,
+		Comment:  b.comment,
+	}
+	if err := silenceSVC.CreateSilence(context.Background(), s); err != nil {
+		return fmt.Errorf("failed to create silence: %v", err)
+	}
+
+	return b.printSilences(silencePrintOpt{silence: s})
+}
+
+func (b *cmdSilenceBuilder) cmdUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdUpdateRunEFn)
+	cmd.Short = "Update silence, e.g. to end it early"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID (required)")
+	cmd.Flags().StringArrayVarP(&b.matchers, "matcher", "m", nil, "Replace the matchers of the silence, as key=value, key!=value, key=~regex or key!~regex")
+	cmd.Flags().StringVar(&b.start, "start", "", "The new start time in RFC3339Nano format, exp 2009-01-02T23:00:00Z")
+	cmd.Flags().StringVar(&b.end, "end", "", "The new end time in RFC3339Nano format, exp 2009-01-02T23:00:00Z, or now to end the silence")
+	cmd.Flags().StringVarP(&b.comment, "comment", "c", "", "The new comment")
+	cmd.MarkFlagRequired("id")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	var upd influxdb.SilenceUpdate
+	if len(b.matchers) > 0 {
+		if upd.Matchers, err = parseSilenceMatchers(b.matchers); err != nil {
+			return err
+		}
+	}
+	if b.start != "" {
+		start, err := time.Parse(time.RFC3339Nano, b.start)
+		if err != nil {
+			return fmt.Errorf("failed to parse start time %q: %v", b.start, err)
+		}
+		upd.StartsAt = &start
+	}
+	if b.end != "" {
+		end := b.now()
+		if b.end != "now" {
+			if end, err = time.Parse(time.RFC3339Nano, b.end); err != nil {
+				return fmt.Errorf("failed to parse end time %q: %v", b.end, err)
+			}
+		}
+		upd.EndsAt = &end
+	}
+	if cmd.Flags().Changed("comment") {
+		upd.Comment = &b.comment
+	}
+
+	s, err := silenceSVC.UpdateSilence(context.Background(), id, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update silence: %v", err)
+	}
+
+	return b.printSilences(silencePrintOpt{silence: s})
+}
+
+func (b *cmdSilenceBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete silence"
+
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID (required)")
+	cmd.MarkFlagRequired("id")
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdDeleteRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(b.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	ctx := context.Background()
+	s, err := silenceSVC.FindSilenceByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find silence with id %q: %v", id, err)
+	}
+	if err := silenceSVC.DeleteSilence(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete silence with id %q: %v", id, err)
+	}
+
+	return b.printSilences(silencePrintOpt{
+		deleted: true,
+		silence: s,
+	})
+}
+
+func (b *cmdSilenceBuilder) cmdFind() *cobra.Command {
+	cmd := b.newCmd("list", b.cmdFindRunEFn)
+	cmd.Short = "List pending and active silences"
+	cmd.Aliases = []string{"find", "ls"}
+
+	cmd.Flags().BoolVar(&b.expired, "expired", false, "List the expired silences instead")
+	b.org.register(cmd, false)
+	b.registerPrintFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdFindRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	orgID, err := b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+
+	silences, err := silenceSVC.FindSilences(context.Background(), influxdb.SilenceFilter{
+		OrgID:   &orgID,
+		Expired: &b.expired,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve silences: %s", err)
+	}
+
+	return b.printSilences(silencePrintOpt{
+		silences: silences,
+	})
+}
+
+func (b *cmdSilenceBuilder) newCmd(use string, runE func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := b.genericCLIOpts.newCmd(use, runE, true)
+	b.globalFlags.registerFlags(cmd)
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+}
+
+func (b *cmdSilenceBuilder) printSilences(opt silencePrintOpt) error {
+	if b.json {
+		var v interface{} = opt.silences
+		if opt.silences == nil {
+			v = opt.silence
+		}
+		return b.writeJSON(v)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+
+	headers := []string{"ID", "Organization ID", "Matchers", "Starts At", "Ends At", "State", "Comment"}
+	if opt.deleted {
+		headers = append(headers, "Deleted")
+	}
+	w.WriteHeaders(headers...)
+
+	if opt.silences == nil && opt.silence != nil {
+		opt.silences = append(opt.silences, opt.silence)
+	}
+
+	now := b.now()
+	for _, s := range opt.silences {
+		m := map[string]interface{}{
+			"ID":              s.ID.String(),
+			"Organization ID": s.OrgID.String(),
+			"Matchers":        formatSilenceMatchers(s.Matchers),
+			"Starts At":       s.StartsAt.Format(time.RFC3339),
+			"Ends At":         s.EndsAt.Format(time.RFC3339),
+			"State":           s.State(now),
+			"Comment":         s.Comment,
+		}
+		if opt.deleted {
+			m["Deleted"] = true
+		}
+		w.Write(m)
+	}
+
+	return nil
+}
+
+type silencePrintOpt struct {
+	deleted  bool
+	silence  *influxdb.Silence
+	silences []*influxdb.Silence
+}
+
+// silenceMatcherOperators maps the operators of matcher flags to tag rule
+// operators. Two character operators come first so that they are matched
+// before =.
+var silenceMatcherOperators = []struct {
+	op       string
+	operator influxdb.Operator
+}{
+	{"!=", influxdb.NotEqual},
+	{"=~", influxdb.RegexEqual},
+	{"!~", influxdb.NotRegexEqual},
+	{"=", influxdb.Equal},
+}
+
+// parseSilenceMatchers parses matcher flags such as host=db01 or
+// region=~us-.* into tag rules.
+func parseSilenceMatchers(matchers []string) ([]influxdb.TagRule, error) {
+	rules := make([]influxdb.TagRule, 0, len(matchers))
+	for _, m := range matchers {
+		i := strings.IndexAny(m, "=!")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid matcher %q: expected key=value, key!=value, key=~regex or key!~regex", m)
+		}
+
+		key, rest := m[:i], m[i:]
+		var rule *influxdb.TagRule
+		for _, o := range silenceMatcherOperators {
+			if strings.HasPrefix(rest, o.op) {
+				rule = &influxdb.TagRule{
+					Tag:      influxdb.Tag{Key: key, Value: rest[len(o.op):]},
+					Operator: o.operator,
+				}
+				break
+			}
+		}
+		if rule == nil || rule.Value == "" {
+			return nil, fmt.Errorf("invalid matcher %q: expected key=value, key!=value, key=~regex or key!~regex", m)
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+func formatSilenceMatchers(rules []influxdb.TagRule) string {
+	ms := make([]string, 0, len(rules))
+	for _, r := range rules {
+		for _, o := range silenceMatcherOperators {
+			if o.operator == r.Operator {
+				ms = append(ms, r.Key+o.op+r.Value)
+				break
+			}
+		}
+	}
+	return strings.Join(ms, ",")
+}
+
+func newSilenceSVCs() (influxdb.SilenceService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	orgSvc := &http.OrganizationService{Client: httpClient}
+
+	return &http.SilenceService{Client: httpClient}, orgSvc, nil
+}
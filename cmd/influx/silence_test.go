@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdSilence(t *testing.T) {
+	orgID := influxdb.ID(9000)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	fakeSVCFn := func(svc influxdb.SilenceService) silenceSVCsFn {
+		return func() (influxdb.SilenceService, influxdb.OrganizationService, error) {
+			return svc, &mock.OrganizationService{
+				FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+					return &influxdb.Organization{ID: orgID, Name: "influxdata"}, nil
+				},
+			}, nil
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			flags    []string
+			expected *influxdb.Silence
+			wantErr  bool
+		}{
+			{
+				name: "with duration",
+				flags: []string{
+					"--org=influxdata", "--matcher=host=db01", "-m=region=~us-.*", "--duration=2h", "--comment=maintenance",
+				},
+				expected: &influxdb.Silence{
+					OrgID: orgID,
+					Matchers: []influxdb.TagRule{
+						{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal},
+						{Tag: influxdb.Tag{Key: "region", Value: "us-.*"}, Operator: influxdb.RegexEqual},
+					},
+					StartsAt: now,
+					EndsAt:   now.Add(2 * time.Hour),
+					Comment:  "maintenance",
+				},
+			},
+			{
+				name: "with start and end",
+				flags: []string{
+					"--org-id=" + orgID.String(), "--matcher=host!=db01",
+					"--start=2020-06-02T00:00:00Z", "--end=2020-06-02T01:00:00Z",
+				},
+				expected: &influxdb.Silence{
+					OrgID:    orgID,
+					Matchers: []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.NotEqual}},
+					StartsAt: now.Add(24 * time.Hour),
+					EndsAt:   now.Add(25 * time.Hour),
+				},
+			},
+			{
+				name:    "without end",
+				flags:   []string{"--org=influxdata", "--matcher=host=db01"},
+				wantErr: true,
+			},
+			{
+				name:    "invalid matcher",
+				flags:   []string{"--org=influxdata", "--matcher=host", "--duration=1h"},
+				wantErr: true,
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				var created *influxdb.Silence
+				svc := mock.NewSilenceService()
+				svc.CreateSilenceF = func(ctx context.Context, s *influxdb.Silence) error {
+					created = s
+					return nil
+				}
+
+				builder := newInfluxCmdBuilder(
+					in(new(bytes.Buffer)),
+					out(ioutil.Discard),
+				)
+				cmd := builder.cmd(func(g *globalFlags, opt genericCLIOpts) *cobra.Command {
+					b := newCmdSilenceBuilder(fakeSVCFn(svc), g, opt)
+					b.now = func() time.Time { return now }
+					return b.cmd()
+				})
+				cmd.SetArgs(append([]string{"silence", "create"}, tt.flags...))
+
+				err := cmd.Execute()
+				if tt.wantErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, created)
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var (
+			gotID  influxdb.ID
+			gotUpd influxdb.SilenceUpdate
+		)
+		svc := mock.NewSilenceService()
+		svc.UpdateSilenceF = func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+			gotID, gotUpd = id, upd
+			return &influxdb.Silence{ID: id, OrgID: orgID, EndsAt: *upd.EndsAt}, nil
+		}
+
+		builder := newInfluxCmdBuilder(
+			in(new(bytes.Buffer)),
+			out(ioutil.Discard),
+		)
+		cmd := builder.cmd(func(g *globalFlags, opt genericCLIOpts) *cobra.Command {
+			b := newCmdSilenceBuilder(fakeSVCFn(svc), g, opt)
+			b.now = func() time.Time { return now }
+			return b.cmd()
+		})
+		cmd.SetArgs([]string{"silence", "update", "--id=" + influxdb.ID(1).String(), "--end=now"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, influxdb.ID(1), gotID)
+		assert.Equal(t, influxdb.SilenceUpdate{EndsAt: &now}, gotUpd)
+	})
+}
+
+func TestParseSilenceMatchers(t *testing.T) {
+	rules, err := parseSilenceMatchers([]string{"a=1", "b!=2", "c=~3.*", "d!~4", "e==5"})
+	require.NoError(t, err)
+	assert.Equal(t, []influxdb.TagRule{
+		{Tag: influxdb.Tag{Key: "a", Value: "1"}, Operator: influxdb.Equal},
+		{Tag: influxdb.Tag{Key: "b", Value: "2"}, Operator: influxdb.NotEqual},
+		{Tag: influxdb.Tag{Key: "c", Value: "3.*"}, Operator: influxdb.RegexEqual},
+		{Tag: influxdb.Tag{Key: "d", Value: "4"}, Operator: influxdb.NotRegexEqual},
+		{Tag: influxdb.Tag{Key: "e", Value: "=5"}, Operator: influxdb.Equal},
+	}, rules)
+	assert.Equal(t, "a=1,b!=2,c=~3.*,d!~4,e==5", formatSilenceMatchers(rules))
+
+	for _, m := range []string{"a", "=1", "a=", "a!1"} {
+		_, err := parseSilenceMatchers([]string{m})
+		assert.Error(t, err, m)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/andreyvit/diff"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/cmd/influx/internal"
 	"github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/kit/signals"
 	"github.com/spf13/cobra"
 )
 
@@ -25,10 +30,14 @@ func cmdTask(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	cmd.AddCommand(
 		taskLogCmd(f, opt),
 		taskRunCmd(f, opt),
+		taskBackfillCmd(f, opt),
 		taskCreateCmd(f, opt),
 		taskDeleteCmd(f, opt),
+		taskDependenciesCmd(f, opt),
 		taskFindCmd(f, opt),
+		taskTestCmd(f, opt),
 		taskUpdateCmd(f, opt),
+		taskVersionCmd(f, opt),
 	)
 
 	return cmd
@@ -40,8 +49,9 @@ var taskPrintFlags struct {
 }
 
 var taskCreateFlags struct {
-	org  organization
-	file string
+	org       organization
+	file      string
+	dependsOn []string
 }
 
 func taskCreateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -52,6 +62,7 @@ func taskCreateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 
 	f.registerFlags(cmd)
 	cmd.Flags().StringVarP(&taskCreateFlags.file, "file", "f", "", "Path to Flux script file")
+	cmd.Flags().StringSliceVar(&taskCreateFlags.dependsOn, "depends-on", nil, "IDs of the upstream tasks the task depends on")
 	taskCreateFlags.org.register(cmd, false)
 	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
 
@@ -77,9 +88,15 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error parsing flux script: %s", err)
 	}
 
+	dependsOn, err := parseTaskIDs(taskCreateFlags.dependsOn)
+	if err != nil {
+		return err
+	}
+
 	tc := influxdb.TaskCreate{
 		Flux:         flux,
 		Organization: taskCreateFlags.org.name,
+		DependsOn:    dependsOn,
 	}
 	if taskCreateFlags.org.id != "" || taskCreateFlags.org.name != "" {
 		svc, err := newOrganizationService()
@@ -203,9 +220,10 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 }
 
 var taskUpdateFlags struct {
-	id     string
-	status string
-	file   string
+	id        string
+	status    string
+	file      string
+	dependsOn []string
 }
 
 func taskUpdateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -218,6 +236,7 @@ func taskUpdateCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	cmd.Flags().StringVarP(&taskUpdateFlags.id, "id", "i", "", "task ID (required)")
 	cmd.Flags().StringVarP(&taskUpdateFlags.status, "status", "", "", "update task status")
 	cmd.Flags().StringVarP(&taskUpdateFlags.file, "file", "f", "", "Path to Flux script file")
+	cmd.Flags().StringSliceVar(&taskUpdateFlags.dependsOn, "depends-on", nil, "IDs of the upstream tasks the task depends on, replacing the current ones; empty to remove them")
 	cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -243,6 +262,17 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		update.Status = &taskUpdateFlags.status
 	}
 
+	if cmd.Flags().Changed("depends-on") {
+		dependsOn, err := parseTaskIDs(taskUpdateFlags.dependsOn)
+		if err != nil {
+			return err
+		}
+		if dependsOn == nil {
+			dependsOn = []influxdb.ID{}
+		}
+		update.DependsOn = &dependsOn
+	}
+
 	// update flux script only if first arg or file is supplied
 	if (len(args) > 0 && len(args[0]) > 0) || len(taskUpdateFlags.file) > 0 {
 		flux, err := readFluxQuery(args, taskUpdateFlags.file)
@@ -319,6 +349,94 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 	)
 }
 
+// parseTaskIDs decodes the task IDs of a flag.
+func parseTaskIDs(ids []string) ([]influxdb.ID, error) {
+	var taskIDs []influxdb.ID
+	for _, s := range ids {
+		id, err := influxdb.IDFromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task ID %q: %s", s, err)
+		}
+		taskIDs = append(taskIDs, *id)
+	}
+	return taskIDs, nil
+}
+
+var taskDependenciesFlags struct {
+	id string
+}
+
+func taskDependenciesCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("dependencies", taskDependenciesF, true)
+	cmd.Short = "List the upstream and downstream tasks of a task"
+	cmd.Aliases = []string{"deps"}
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskDependenciesFlags.id, "id", "i", "", "task ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func taskDependenciesF(cmd *cobra.Command, args []string) error {
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	s := &http.TaskService{
+		Client: client,
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(taskDependenciesFlags.id); err != nil {
+		return err
+	}
+
+	deps, err := s.FindTaskDependencies(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	if taskPrintFlags.json {
+		return writeJSON(cmd.OutOrStdout(), deps)
+	}
+
+	tabW := internal.NewTabWriter(cmd.OutOrStdout())
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"Direction",
+		"ID",
+		"Name",
+		"Status",
+		"LatestCompleted",
+		"LastRunStatus",
+		"DependsOn",
+	)
+
+	write := func(direction string, deps []http.TaskDependency) {
+		for _, d := range deps {
+			tabW.Write(map[string]interface{}{
+				"Direction":       direction,
+				"ID":              d.ID,
+				"Name":            d.Name,
+				"Status":          d.Status,
+				"LatestCompleted": d.LatestCompleted,
+				"LastRunStatus":   d.LastRunStatus,
+				"DependsOn":       d.DependsOn,
+			})
+		}
+	}
+	write("upstream", deps.Upstream)
+	write("downstream", deps.Downstream)
+
+	return nil
+}
+
 type taskPrintOpts struct {
 	hideHeaders bool
 	json        bool
@@ -348,6 +466,7 @@ func printTasks(w io.Writer, opts taskPrintOpts) error {
 		"Status",
 		"Every",
 		"Cron",
+		"Timezone",
 	)
 
 	if opts.task != nil {
@@ -363,6 +482,7 @@ func printTasks(w io.Writer, opts taskPrintOpts) error {
 			"Status":          t.Status,
 			"Every":           t.Every,
 			"Cron":            t.Cron,
+			"Timezone":        t.Timezone,
 		})
 	}
 
@@ -384,6 +504,7 @@ func taskLogCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 var taskLogFindFlags struct {
 	taskID string
 	runID  string
+	level  string
 }
 
 func taskLogFindCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -395,6 +516,7 @@ func taskLogFindCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
 	cmd.Flags().StringVarP(&taskLogFindFlags.taskID, "task-id", "", "", "task id (required)")
 	cmd.Flags().StringVarP(&taskLogFindFlags.runID, "run-id", "", "", "run id")
+	cmd.Flags().StringVarP(&taskLogFindFlags.level, "level", "", "", "minimum level of the logs to list: debug, info, warn or error")
 	cmd.MarkFlagRequired("task-id")
 
 	return cmd
@@ -425,12 +547,26 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 		filter.Run = id
 	}
 
+	minLevel := 0
+	if taskLogFindFlags.level != "" {
+		if minLevel = logLevelRank(taskLogFindFlags.level); minLevel < 0 {
+			return fmt.Errorf("invalid log level %q", taskLogFindFlags.level)
+		}
+	}
+
 	ctx := context.TODO()
-	logs, _, err := s.FindLogs(ctx, filter)
+	all, _, err := s.FindLogs(ctx, filter)
 	if err != nil {
 		return err
 	}
 
+	logs := all[:0]
+	for _, log := range all {
+		if logLevelRank(log.Level) >= minLevel {
+			logs = append(logs, log)
+		}
+	}
+
 	w := cmd.OutOrStdout()
 	if taskPrintFlags.json {
 		return writeJSON(w, logs)
@@ -441,18 +577,57 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 
 	tabW.HideHeaders(taskPrintFlags.hideHeaders)
 
-	tabW.WriteHeaders("RunID", "Time", "Message")
+	tabW.WriteHeaders("RunID", "Time", "Level", "Message", "Fields")
 	for _, log := range logs {
+		level := log.Level
+		if level == "" {
+			level = influxdb.LogLevelInfo
+		}
 		tabW.Write(map[string]interface{}{
 			"RunID":   log.RunID,
 			"Time":    log.Time,
+			"Level":   level,
 			"Message": log.Message,
+			"Fields":  formatLogFields(log.Fields),
 		})
 	}
 
 	return nil
 }
 
+// logLevelRank orders the levels of run logs, logs without level are info.
+// It returns -1 for an unknown level.
+func logLevelRank(level string) int {
+	switch level {
+	case influxdb.LogLevelDebug:
+		return 0
+	case influxdb.LogLevelInfo, "":
+		return 1
+	case influxdb.LogLevelWarn:
+		return 2
+	case influxdb.LogLevelError:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// formatLogFields formats the fields of a run log, or the tags and fields of a
+// tested task, as key=value pairs sorted by key.
+func formatLogFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + fields[k]
+	}
+	return strings.Join(pairs, " ")
+}
+
 func taskRunCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 	cmd := opt.newCmd("run", nil, false)
 	cmd.Run = seeHelp
@@ -552,6 +727,8 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		"StartedAt",
 		"FinishedAt",
 		"RequestedAt",
+		"RetryOf",
+		"Retry",
 	)
 
 	for _, r := range runs {
@@ -559,6 +736,10 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		startedAt := r.StartedAt.Format(time.RFC3339Nano)
 		finishedAt := r.FinishedAt.Format(time.RFC3339Nano)
 		requestedAt := r.RequestedAt.Format(time.RFC3339Nano)
+		retryOf := ""
+		if r.RetryOf.Valid() {
+			retryOf = r.RetryOf.String()
+		}
 
 		tabW.Write(map[string]interface{}{
 			"ID":           r.ID,
@@ -568,6 +749,8 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			"StartedAt":    startedAt,
 			"FinishedAt":   finishedAt,
 			"RequestedAt":  requestedAt,
+			"RetryOf":      retryOf,
+			"Retry":        r.Retry,
 		})
 	}
 
@@ -619,3 +802,582 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var taskBackfillFlags struct {
+	taskID      string
+	id          string
+	start       string
+	stop        string
+	concurrency int
+	status      string
+	wait        bool
+}
+
+func taskBackfillCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("backfill", taskBackfillF, true)
+	cmd.Short = "Run a task over a past time range"
+	cmd.Long = `Run a task for every time it is scheduled for between start and stop,
+according to its every or cron option. The runs are executed in the background,
+at most concurrency at a time. Backfills are listed, inspected and canceled
+with the list and cancel commands.`
+	cmd.TraverseChildren = true
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.PersistentFlags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVar(&taskBackfillFlags.start, "start", "", "the start time in RFC3339Nano format, exp 2009-01-02T23:00:00Z (required)")
+	cmd.Flags().StringVar(&taskBackfillFlags.stop, "stop", "", "the stop time in RFC3339Nano format, exp 2009-01-02T23:00:00Z (required)")
+	cmd.Flags().IntVar(&taskBackfillFlags.concurrency, "concurrency", 1, "maximum number of runs executed at a time")
+	cmd.Flags().BoolVar(&taskBackfillFlags.wait, "wait", false, "Wait for the backfill to finish")
+	cmd.MarkPersistentFlagRequired("task-id")
+
+	cmd.AddCommand(
+		taskBackfillListCmd(f, opt),
+		taskBackfillCancelCmd(f, opt),
+	)
+
+	return cmd
+}
+
+func taskBackfillF(cmd *cobra.Command, args []string) error {
+	if taskBackfillFlags.start == "" || taskBackfillFlags.stop == "" {
+		return fmt.Errorf("both start and stop are required")
+	}
+	start, err := time.Parse(time.RFC3339Nano, taskBackfillFlags.start)
+	if err != nil {
+		return fmt.Errorf("failed to parse start time %q: %v", taskBackfillFlags.start, err)
+	}
+	stop, err := time.Parse(time.RFC3339Nano, taskBackfillFlags.stop)
+	if err != nil {
+		return fmt.Errorf("failed to parse stop time %q: %v", taskBackfillFlags.stop, err)
+	}
+
+	s, taskID, err := newTaskBackfillService()
+	if err != nil {
+		return err
+	}
+
+	ctx := signals.WithStandardSignals(context.Background())
+	b, err := s.Backfill(ctx, taskID, start, stop, taskBackfillFlags.concurrency)
+	if err != nil {
+		return fmt.Errorf("failed to backfill task: %v", err)
+	}
+
+	if taskBackfillFlags.wait {
+		return waitForBackfill(ctx, cmd.OutOrStdout(), s, b)
+	}
+	return printBackfills(cmd.OutOrStdout(), backfillPrintOpt{backfill: b})
+}
+
+func taskBackfillListCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("list", taskBackfillListF, true)
+	cmd.Short = "List the backfills of a task"
+	cmd.Aliases = []string{"find", "ls"}
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVar(&taskBackfillFlags.id, "id", "", "backfill id")
+	cmd.Flags().StringVar(&taskBackfillFlags.status, "status", "", "Only list backfills with the status, one of queued, running, canceling, success, failed or canceled")
+
+	return cmd
+}
+
+func taskBackfillListF(cmd *cobra.Command, args []string) error {
+	s, taskID, err := newTaskBackfillService()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if taskBackfillFlags.id != "" {
+		id, err := influxdb.IDFromString(taskBackfillFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode backfill id %q: %v", taskBackfillFlags.id, err)
+		}
+		b, err := s.FindBackfillByID(ctx, taskID, *id)
+		if err != nil {
+			return fmt.Errorf("failed to find backfill: %v", err)
+		}
+		return printBackfills(cmd.OutOrStdout(), backfillPrintOpt{backfill: b})
+	}
+
+	backfills, err := s.FindBackfills(ctx, taskID, taskBackfillFlags.status)
+	if err != nil {
+		return fmt.Errorf("failed to list backfills: %v", err)
+	}
+	return printBackfills(cmd.OutOrStdout(), backfillPrintOpt{backfills: backfills})
+}
+
+func taskBackfillCancelCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("cancel", taskBackfillCancelF, true)
+	cmd.Short = "Cancel a queued or running backfill"
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVar(&taskBackfillFlags.id, "id", "", "backfill id (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) error {
+	s, taskID, err := newTaskBackfillService()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(taskBackfillFlags.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode backfill id %q: %v", taskBackfillFlags.id, err)
+	}
+
+	ctx := context.Background()
+	if err := s.CancelBackfill(ctx, taskID, *id); err != nil {
+		return fmt.Errorf("failed to cancel backfill: %v", err)
+	}
+
+	b, err := s.FindBackfillByID(ctx, taskID, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find backfill: %v", err)
+	}
+	return printBackfills(cmd.OutOrStdout(), backfillPrintOpt{backfill: b})
+}
+
+func newTaskBackfillService() (*http.TaskService, influxdb.ID, error) {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode task id %q: %v", taskBackfillFlags.taskID, err)
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, 0, err
+	}
+	return &http.TaskService{Client: client}, taskID, nil
+}
+
+// waitForBackfill polls the backfill until it finished and prints it,
+// returning an error if it failed. It stops waiting when ctx is done.
+func waitForBackfill(ctx context.Context, w io.Writer, s *http.TaskService, b *influxdb.Backfill) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for !b.Status.Finished() {
+		select {
+		case <-ctx.Done():
+			return printBackfills(w, backfillPrintOpt{backfill: b})
+		case <-ticker.C:
+		}
+
+		next, err := s.FindBackfillByID(ctx, b.TaskID, b.ID)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to find backfill: %v", err)
+		} else if err != nil {
+			return nil
+		}
+		b = next
+	}
+
+	if err := printBackfills(w, backfillPrintOpt{backfill: b}); err != nil {
+		return err
+	}
+	if b.Status == influxdb.BackfillFailed {
+		return fmt.Errorf("backfill %s failed: %s", b.ID, b.Error)
+	}
+	return nil
+}
+
+type backfillPrintOpt struct {
+	backfill  *influxdb.Backfill
+	backfills []*influxdb.Backfill
+}
+
+func printBackfills(w io.Writer, printOpt backfillPrintOpt) error {
+	if taskPrintFlags.json {
+		var v interface{} = printOpt.backfills
+		if printOpt.backfill != nil {
+			v = printOpt.backfill
+		} else if printOpt.backfills == nil {
+			// guarantee we never return a null value from CLI
+			v = make([]*influxdb.Backfill, 0)
+		}
+		return writeJSON(w, v)
+	}
+
+	tabW := internal.NewTabWriter(w)
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"ID",
+		"TaskID",
+		"Status",
+		"Start",
+		"Stop",
+		"Concurrency",
+		"Total",
+		"Succeeded",
+		"Failed",
+		"Completed",
+		"CreatedAt",
+		"Error",
+	)
+
+	if printOpt.backfill != nil {
+		printOpt.backfills = append(printOpt.backfills, printOpt.backfill)
+	}
+
+	for _, b := range printOpt.backfills {
+		completed := ""
+		if b.Progress.Completed != nil {
+			completed = b.Progress.Completed.Format(time.RFC3339)
+		}
+
+		tabW.Write(map[string]interface{}{
+			"ID":          b.ID,
+			"TaskID":      b.TaskID,
+			"Status":      b.Status,
+			"Start":       b.Start.Format(time.RFC3339Nano),
+			"Stop":        b.Stop.Format(time.RFC3339Nano),
+			"Concurrency": b.Concurrency,
+			"Total":       b.Progress.Total,
+			"Succeeded":   b.Progress.Succeeded,
+			"Failed":      b.Progress.Failed,
+			"Completed":   completed,
+			"CreatedAt":   b.CreatedAt.Format(time.RFC3339),
+			"Error":       b.Error,
+		})
+	}
+
+	return nil
+}
+
+var taskTestFlags struct {
+	id           string
+	scheduledFor string
+}
+
+func taskTestCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("test", taskTestF, true)
+	cmd.Short = "Test a task without writing data"
+	cmd.Long = `Execute a task for a time it is scheduled for, printing the data its script
+writes with to() or experimental.to() instead of writing it. No run is created,
+and the task does not need to be active.`
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().StringVarP(&taskTestFlags.id, "id", "i", "", "task ID (required)")
+	cmd.Flags().StringVar(&taskTestFlags.scheduledFor, "scheduled-for", "", "the time to execute the task for in RFC3339Nano format, exp 2009-01-02T23:00:00Z, defaults to now")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func taskTestF(cmd *cobra.Command, args []string) error {
+	var id influxdb.ID
+	if err := id.DecodeFromString(taskTestFlags.id); err != nil {
+		return fmt.Errorf("failed to decode task id %q: %v", taskTestFlags.id, err)
+	}
+
+	var scheduledFor time.Time
+	if taskTestFlags.scheduledFor != "" {
+		var err error
+		scheduledFor, err = time.Parse(time.RFC3339Nano, taskTestFlags.scheduledFor)
+		if err != nil {
+			return fmt.Errorf("failed to parse scheduled for time %q: %v", taskTestFlags.scheduledFor, err)
+		}
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	s := &http.TaskService{Client: client}
+
+	d, err := s.DryRunTask(context.Background(), id, scheduledFor)
+	if err != nil {
+		return fmt.Errorf("failed to test task: %v", err)
+	}
+
+	if taskPrintFlags.json {
+		return writeJSON(cmd.OutOrStdout(), d)
+	}
+
+	tabW := internal.NewTabWriter(cmd.OutOrStdout())
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"BucketID",
+		"Measurement",
+		"Tags",
+		"Time",
+		"Fields",
+	)
+
+	for _, tbl := range d.Tables {
+		for _, row := range tbl.Rows {
+			fields := make(map[string]string, len(row.Fields))
+			for k, v := range row.Fields {
+				fields[k] = fmt.Sprint(v)
+			}
+
+			tabW.Write(map[string]interface{}{
+				"BucketID":    tbl.BucketID,
+				"Measurement": tbl.Measurement,
+				"Tags":        formatLogFields(tbl.Tags),
+				"Time":        row.Time.Format(time.RFC3339Nano),
+				"Fields":      formatLogFields(fields),
+			})
+		}
+	}
+
+	if d.Truncated {
+		fmt.Fprintln(cmd.ErrOrStderr(), "The task wrote more data than shown.")
+	}
+	return nil
+}
+
+var taskVersionFlags struct {
+	taskID  string
+	version int
+	from    int
+	to      int
+}
+
+func taskVersionCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("version", taskVersionListF, true)
+	cmd.Short = "List the versions of a task"
+	cmd.Long = `List the versions of the script of a task, oldest first. A task gets a new
+version every time its script changes. Versions are compared with the diff
+command, and a task is rolled back to a version with the rollback command.`
+	cmd.Aliases = []string{"versions"}
+	cmd.TraverseChildren = true
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.PersistentFlags().StringVarP(&taskVersionFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVar(&taskVersionFlags.version, "version", 0, "Only show the version, with its script")
+	cmd.MarkPersistentFlagRequired("task-id")
+
+	cmd.AddCommand(
+		taskVersionDiffCmd(f, opt),
+		taskVersionRollbackCmd(f, opt),
+	)
+
+	return cmd
+}
+
+func taskVersionListF(cmd *cobra.Command, args []string) error {
+	s, taskID, err := newTaskVersionService()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if taskVersionFlags.version != 0 {
+		v, err := s.FindTaskVersion(ctx, taskID, taskVersionFlags.version)
+		if err != nil {
+			return fmt.Errorf("failed to find task version: %v", err)
+		}
+		if err := printTaskVersions(cmd.OutOrStdout(), taskVersionPrintOpt{version: v}); err != nil {
+			return err
+		}
+		if !taskPrintFlags.json {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s\n", v.Flux)
+		}
+		return nil
+	}
+
+	versions, err := s.FindTaskVersions(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to list task versions: %v", err)
+	}
+	return printTaskVersions(cmd.OutOrStdout(), taskVersionPrintOpt{versions: versions})
+}
+
+func taskVersionDiffCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("diff", taskVersionDiffF, true)
+	cmd.Short = "Show the changes between two versions of a task"
+
+	f.registerFlags(cmd)
+	cmd.Flags().IntVar(&taskVersionFlags.from, "from", 0, "version to compare from (required)")
+	cmd.Flags().IntVar(&taskVersionFlags.to, "to", 0, "version to compare to, defaults to the latest version")
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func taskVersionDiffF(cmd *cobra.Command, args []string) error {
+	s, taskID, err := newTaskVersionService()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	from, err := s.FindTaskVersion(ctx, taskID, taskVersionFlags.from)
+	if err != nil {
+		return fmt.Errorf("failed to find task version %d: %v", taskVersionFlags.from, err)
+	}
+
+	var to *influxdb.TaskVersion
+	if taskVersionFlags.to != 0 {
+		to, err = s.FindTaskVersion(ctx, taskID, taskVersionFlags.to)
+		if err != nil {
+			return fmt.Errorf("failed to find task version %d: %v", taskVersionFlags.to, err)
+		}
+	} else {
+		versions, err := s.FindTaskVersions(ctx, taskID)
+		if err != nil {
+			return fmt.Errorf("failed to list task versions: %v", err)
+		}
+		if len(versions) == 0 {
+			return fmt.Errorf("task %s has no versions", taskID)
+		}
+		to = versions[len(versions)-1]
+	}
+
+	writeTaskVersionDiff(cmd.OutOrStdout(), from, to)
+	return nil
+}
+
+// writeTaskVersionDiff writes the options changed between the versions from
+// and to, then the diff of their scripts.
+func writeTaskVersionDiff(w io.Writer, from, to *influxdb.TaskVersion) {
+	fmt.Fprintf(w, "--- version %d\n+++ version %d\n", from.Version, to.Version)
+
+	options := []struct {
+		name     string
+		from, to string
+	}{
+		{"name", from.Name, to.Name},
+		{"every", from.Every, to.Every},
+		{"cron", from.Cron, to.Cron},
+		{"timezone", from.Timezone, to.Timezone},
+		{"offset", from.Offset.String(), to.Offset.String()},
+		{"retry", strconv.FormatInt(from.Retry, 10), strconv.FormatInt(to.Retry, 10)},
+		{"catchUp", from.CatchUp, to.CatchUp},
+		{"maxMissedRuns", strconv.FormatInt(from.MaxMissedRuns, 10), strconv.FormatInt(to.MaxMissedRuns, 10)},
+	}
+	for _, o := range options {
+		if o.from != o.to {
+			fmt.Fprintf(w, "option %s: %q -> %q\n", o.name, o.from, o.to)
+		}
+	}
+
+	if from.Flux == to.Flux {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, diff.LineDiff(from.Flux, to.Flux))
+}
+
+func taskVersionRollbackCmd(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	cmd := opt.newCmd("rollback", taskVersionRollbackF, true)
+	cmd.Short = "Roll a task back to a version"
+	cmd.Long = `Update a task to the script of one of its versions. The task gets a new
+version, so that the rollback can itself be undone.`
+
+	f.registerFlags(cmd)
+	registerPrintOptions(cmd, &taskPrintFlags.hideHeaders, &taskPrintFlags.json)
+	cmd.Flags().IntVar(&taskVersionFlags.version, "version", 0, "version to roll back to (required)")
+	cmd.MarkFlagRequired("version")
+
+	return cmd
+}
+
+func taskVersionRollbackF(cmd *cobra.Command, args []string) error {
+	s, taskID, err := newTaskVersionService()
+	if err != nil {
+		return err
+	}
+
+	t, err := s.RollbackTask(context.Background(), taskID, taskVersionFlags.version)
+	if err != nil {
+		return fmt.Errorf("failed to roll back task: %v", err)
+	}
+
+	return printTasks(
+		cmd.OutOrStdout(),
+		taskPrintOpts{
+			hideHeaders: taskPrintFlags.hideHeaders,
+			json:        taskPrintFlags.json,
+			task:        t,
+		},
+	)
+}
+
+func newTaskVersionService() (*http.TaskService, influxdb.ID, error) {
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(taskVersionFlags.taskID); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode task id %q: %v", taskVersionFlags.taskID, err)
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, 0, err
+	}
+	return &http.TaskService{Client: client}, taskID, nil
+}
+
+type taskVersionPrintOpt struct {
+	version  *influxdb.TaskVersion
+	versions []*influxdb.TaskVersion
+}
+
+func printTaskVersions(w io.Writer, printOpt taskVersionPrintOpt) error {
+	if taskPrintFlags.json {
+		var v interface{} = printOpt.versions
+		if printOpt.version != nil {
+			v = printOpt.version
+		} else if printOpt.versions == nil {
+			// guarantee we never return a null value from CLI
+			v = make([]*influxdb.TaskVersion, 0)
+		}
+		return writeJSON(w, v)
+	}
+
+	tabW := internal.NewTabWriter(w)
+	defer tabW.Flush()
+
+	tabW.HideHeaders(taskPrintFlags.hideHeaders)
+
+	tabW.WriteHeaders(
+		"Version",
+		"TaskID",
+		"Name",
+		"Every",
+		"Cron",
+		"Timezone",
+		"Offset",
+		"CreatedBy",
+		"CreatedAt",
+	)
+
+	if printOpt.version != nil {
+		printOpt.versions = append(printOpt.versions, printOpt.version)
+	}
+
+	for _, v := range printOpt.versions {
+		createdBy := ""
+		if v.CreatedBy.Valid() {
+			createdBy = v.CreatedBy.String()
+		}
+
+		tabW.Write(map[string]interface{}{
+			"Version":   v.Version,
+			"TaskID":    v.TaskID,
+			"Name":      v.Name,
+			"Every":     v.Every,
+			"Cron":      v.Cron,
+			"Timezone":  v.Timezone,
+			"Offset":    v.Offset.String(),
+			"CreatedBy": createdBy,
+			"CreatedAt": v.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return nil
+}
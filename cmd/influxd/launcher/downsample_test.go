@@ -0,0 +1,50 @@
+package launcher_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/cmd/influxd/launcher"
+	"github.com/influxdata/influxdb/v2/task/backend/downsample"
+)
+
+func TestLauncher_Downsample_FieldTypes(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx, nil)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	dst := &influxdb.Bucket{OrgID: l.Org.ID, Name: "downsampled"}
+	if err := l.BucketService(t).CreateBucket(ctx, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bucket has a string field that the mean of the float fields must not see.
+	now := time.Now()
+	l.WritePointsOrFail(t, fmt.Sprintf(`cpu,host=a usage=1.0,status="ok" %d
+cpu,host=a usage=3.0,status="down" %d`, now.Add(-2*time.Second).UnixNano(), now.Add(-time.Second).UnixNano()))
+
+	p := influxdb.DownsamplePolicy{
+		DestinationBucketID: dst.ID,
+		Every:               influxdb.Duration{Duration: time.Hour},
+		Aggregates: []influxdb.DownsampleAggregate{
+			{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean", Fields: []string{"usage"}},
+			{FieldType: influxdb.DownsampleFieldTypeString, Function: "last"},
+		},
+	}
+	if err := p.Valid(); err != nil {
+		t.Fatal(err)
+	}
+	l.FluxQueryOrFail(t, l.Org, l.Auth.Token, downsample.GenerateFlux(l.Bucket, dst, p))
+
+	got := l.FluxQueryOrFail(t, l.Org, l.Auth.Token, fmt.Sprintf(`from(bucket: "%s")
+	|> range(start: -2h)
+	|> keep(columns: ["_field", "_value"])`, dst.Name))
+	for _, want := range []string{",usage", ",down,status"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("downsampled data does not contain %q:\n%s", want, got)
+		}
+	}
+}
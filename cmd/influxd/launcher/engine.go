@@ -30,6 +30,7 @@ type Engine interface {
 	storage.BucketDeleter
 	prom.PrometheusCollector
 	influxdb.BackupService
+	influxdb.RestoreService
 
 	SeriesCardinality() int64
 
@@ -167,14 +168,18 @@ func (t *TemporaryEngine) Flush(ctx context.Context) {
 	}
 }
 
-func (t *TemporaryEngine) CreateBackup(ctx context.Context) (int, []string, error) {
-	return t.engine.CreateBackup(ctx)
+func (t *TemporaryEngine) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.BackupManifest, error) {
+	return t.engine.CreateBackup(ctx, filter)
 }
 
 func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
 	return t.engine.FetchBackupFile(ctx, backupID, backupFile, w)
 }
 
+func (t *TemporaryEngine) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, src influxdb.BackupManifestBucket, r io.Reader) error {
+	return t.engine.RestoreBucket(ctx, orgID, bucketID, src, r)
+}
+
 func (t *TemporaryEngine) InternalBackupPath(backupID int) string {
 	return t.engine.InternalBackupPath(backupID)
 }
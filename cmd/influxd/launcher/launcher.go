@@ -12,6 +12,7 @@ import (
 	_ "net/http/pprof" // needed to add pprof to our binary.
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/influxdata/influxdb/v2/chronograf/server"
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect"
 	"github.com/influxdata/influxdb/v2/dbrp"
+	"github.com/influxdata/influxdb/v2/deletejob"
 	"github.com/influxdata/influxdb/v2/endpoints"
 	"github.com/influxdata/influxdb/v2/gather"
 	"github.com/influxdata/influxdb/v2/http"
@@ -43,24 +45,30 @@ import (
 	"github.com/influxdata/influxdb/v2/label"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
 	"github.com/influxdata/influxdb/v2/nats"
+	"github.com/influxdata/influxdb/v2/notification/escalation"
 	"github.com/influxdata/influxdb/v2/pkger"
 	infprom "github.com/influxdata/influxdb/v2/prometheus"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/control"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/v2/ratelimit"
 	"github.com/influxdata/influxdb/v2/secret"
 	"github.com/influxdata/influxdb/v2/session"
 	"github.com/influxdata/influxdb/v2/snowflake"
 	"github.com/influxdata/influxdb/v2/source"
+	"github.com/influxdata/influxdb/v2/sqlite"
 	"github.com/influxdata/influxdb/v2/storage"
 	storageflux "github.com/influxdata/influxdb/v2/storage/flux"
 	"github.com/influxdata/influxdb/v2/storage/readservice"
 	taskbackend "github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/coordinator"
+	"github.com/influxdata/influxdb/v2/task/backend/downsample"
 	"github.com/influxdata/influxdb/v2/task/backend/executor"
+	"github.com/influxdata/influxdb/v2/task/backend/lease"
 	"github.com/influxdata/influxdb/v2/task/backend/middleware"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/backfill"
 	"github.com/influxdata/influxdb/v2/telemetry"
 	"github.com/influxdata/influxdb/v2/tenant"
 	_ "github.com/influxdata/influxdb/v2/tsdb/tsi1" // needed for tsi1
@@ -80,6 +88,8 @@ const (
 	BoltStore = "bolt"
 	// MemoryStore stores all REST resources in memory (useful for testing).
 	MemoryStore = "memory"
+	// SQLiteStore stores all REST resources in an sqlite database.
+	SQLiteStore = "sqlite"
 
 	// LogTracing enables tracing via zap logs
 	LogTracing = "log"
@@ -196,6 +206,12 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: filepath.Join(dir, bolt.DefaultFilename),
 			Desc:    "path to boltdb database",
 		},
+		{
+			DestP:   &l.sqlitePath,
+			Flag:    "sqlite-path",
+			Default: filepath.Join(dir, sqlite.DefaultFilename),
+			Desc:    "path to sqlite database used when --store is sqlite",
+		},
 		{
 			DestP: &l.assetsPath,
 			Flag:  "assets-path",
@@ -205,7 +221,7 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			DestP:   &l.storeType,
 			Flag:    "store",
 			Default: "bolt",
-			Desc:    "backing store for REST resources (bolt or memory)",
+			Desc:    "backing store for REST resources (bolt, memory or sqlite)",
 		},
 		{
 			DestP:   &l.testing,
@@ -323,6 +339,12 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: false,
 			Desc:    "disables the task scheduler",
 		},
+		{
+			DestP:   &l.taskLeaseTTL,
+			Flag:    "task-lease-ttl",
+			Default: time.Duration(0),
+			Desc:    "shares the tasks to schedule with the other instances using the same metadata store, taking over the tasks of an instance not renewing its leases within this ttl (0 disables leasing)",
+		},
 		{
 			DestP:   &l.concurrencyQuota,
 			Flag:    "query-concurrency",
@@ -353,6 +375,21 @@ func launcherOpts(l *Launcher) []cli.Opt {
 			Default: 10,
 			Desc:    "the number of queries that are allowed to be awaiting execution before new queries are rejected",
 		},
+		{
+			DestP: &l.reservedConcurrency,
+			Flag:  "query-reserved-concurrency",
+			Desc:  "the number of the query-concurrency slots reserved for the queries of a priority class (interactive, task, check or system), e.g. interactive=4,check=2",
+		},
+		{
+			DestP: &l.reservedMemoryBytes,
+			Flag:  "query-reserved-memory-bytes",
+			Desc:  "the number of the query-max-memory-bytes reserved for the queries of a priority class, e.g. interactive=1073741824",
+		},
+		{
+			DestP: &l.classQueueSize,
+			Flag:  "query-class-queue-size",
+			Desc:  "the number of queries of a priority class that are allowed to be awaiting execution, e.g. task=100. Classes which are not set use query-queue-size",
+		},
 		{
 			DestP: &l.featureFlags,
 			Flag:  "feature-flags",
@@ -379,6 +416,7 @@ type Launcher struct {
 
 	httpBindAddress string
 	boltPath        string
+	sqlitePath      string
 	enginePath      string
 	secretStore     string
 
@@ -391,8 +429,12 @@ type Launcher struct {
 	memoryBytesQuotaPerQuery        int
 	maxMemoryBytes                  int
 	queueSize                       int
+	reservedConcurrency             map[string]string
+	reservedMemoryBytes             map[string]string
+	classQueueSize                  map[string]string
 
 	boltClient    *bolt.Client
+	sqliteStore   *sqlite.KVStore
 	kvStore       kv.SchemaStore
 	kvService     *kv.Service
 	engine        Engine
@@ -411,6 +453,7 @@ type Launcher struct {
 	natsPort   int
 
 	noTasks            bool
+	taskLeaseTTL       time.Duration
 	scheduler          stoppingScheduler
 	executor           *executor.Executor
 	taskControlService taskbackend.TaskControlService
@@ -483,6 +526,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.log.Info("Stopping", zap.String("service", "task"))
 
 	m.scheduler.Stop()
+	m.executor.Close()
 
 	m.log.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
@@ -492,6 +536,13 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.log.Info("Failed closing bolt", zap.Error(err))
 	}
 
+	if m.sqliteStore != nil {
+		m.log.Info("Stopping", zap.String("service", "sqlite"))
+		if err := m.sqliteStore.Close(); err != nil {
+			m.log.Info("Failed closing sqlite", zap.Error(err))
+		}
+	}
+
 	m.log.Info("Stopping", zap.String("service", "query"))
 	if err := m.queryController.Shutdown(ctx); err != nil && err != context.Canceled {
 		m.log.Info("Failed closing query service", zap.Error(err))
@@ -613,8 +664,20 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		if m.testing {
 			flushers = append(flushers, store)
 		}
+	case SQLiteStore:
+		store := sqlite.NewKVStore(m.log.With(zap.String("service", "kvstore-sqlite")), m.sqlitePath)
+		if err := store.Open(ctx); err != nil {
+			m.log.Error("Failed opening sqlite", zap.Error(err))
+			return err
+		}
+		m.sqliteStore = store
+		m.kvStore = store
+		m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), store, serviceConfig)
+		if m.testing {
+			flushers = append(flushers, store)
+		}
 	default:
-		err := fmt.Errorf("unknown store type %s; expected bolt or memory", m.storeType)
+		err := fmt.Errorf("unknown store type %s; expected bolt, memory or sqlite", m.storeType)
 		m.log.Error("Failed opening bolt", zap.Error(err))
 		return err
 	}
@@ -709,9 +772,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
 	var (
-		deleteService platform.DeleteService = m.engine
-		pointsWriter  storage.PointsWriter   = m.engine
-		backupService platform.BackupService = m.engine
+		deleteService  platform.DeleteService  = m.engine
+		pointsWriter   storage.PointsWriter    = m.engine
+		backupService  platform.BackupService  = m.engine
+		restoreService platform.RestoreService = m.engine
 	)
 
 	deps, err := influxdb.NewDependencies(
@@ -727,12 +791,19 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	priorityClasses, err := m.priorityClasses()
+	if err != nil {
+		m.log.Error("Failed to configure query priority classes", zap.Error(err))
+		return err
+	}
+
 	m.queryController, err = control.New(control.Config{
 		ConcurrencyQuota:                m.concurrencyQuota,
 		InitialMemoryBytesQuotaPerQuery: int64(m.initialMemoryBytesQuotaPerQuery),
 		MemoryBytesQuotaPerQuery:        int64(m.memoryBytesQuotaPerQuery),
 		MaxMemoryBytes:                  int64(m.maxMemoryBytes),
 		QueueSize:                       m.queueSize,
+		PriorityClasses:                 priorityClasses,
 		Logger:                          m.log.With(zap.String("service", "storage-reads")),
 		ExecutorDependencies:            []flux.Dependency{deps},
 	})
@@ -762,13 +833,28 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
 		var sch stoppingScheduler = &scheduler.NoopScheduler{}
+		leasing := !m.noTasks && m.taskLeaseTTL > 0
 		if !m.noTasks {
 			var (
 				sm  *scheduler.SchedulerMetrics
 				err error
 			)
+
+			// when leasing, the scheduler only executes the runs claimed
+			// with the leases of their tasks.
+			var (
+				schExecutor scheduler.Executor = executor
+				leaseStore  *lease.Store
+				leaseOwner  platform.ID
+			)
+			if leasing {
+				leaseStore = lease.NewStore(m.kvStore)
+				leaseOwner = snowflake.NewIDGenerator().ID()
+				schExecutor = lease.NewExecutor(m.log.With(zap.String("service", "task-lease")), leaseStore, leaseOwner, executor)
+			}
+
 			sch, sm, err = scheduler.NewScheduler(
-				executor,
+				schExecutor,
 				taskbackend.NewSchedulableTaskService(m.kvService),
 				scheduler.WithOnErrorFn(func(ctx context.Context, taskID scheduler.ID, scheduledAt time.Time, err error) {
 					schLogger.Info(
@@ -782,6 +868,39 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				m.log.Fatal("could not start task scheduler", zap.Error(err))
 			}
 			m.reg.MustRegister(sm.PrometheusCollectors()...)
+
+			if leasing {
+				leaseLogger := m.log.With(zap.String("service", "task-lease"), zap.String("owner", leaseOwner.String()))
+				leaseManager := lease.NewManager(leaseLogger, leaseStore, leaseOwner, sch, m.kvService)
+				leaseManager.TTL = m.taskLeaseTTL
+				leaseManager.RenewInterval = m.taskLeaseTTL / 3
+				leaseManager.ResumeRuns = func(ctx context.Context, taskID platform.ID) error {
+					err := taskbackend.ResumeRunning(ctx, combinedTaskService, taskID,
+						func(ctx context.Context, taskID platform.ID, runID platform.ID) error {
+							_, err := executor.ResumeCurrentRun(ctx, taskID, runID)
+							if err == platform.ErrRunNotFound {
+								// the run is still executing here, or finished since.
+								return nil
+							}
+							return err
+						},
+						leaseLogger)
+					if err != nil {
+						return err
+					}
+					return executor.ResumeRetries(ctx, taskID)
+				}
+				sch = leaseManager
+
+				m.wg.Add(1)
+				go func(log *zap.Logger) {
+					defer m.wg.Done()
+					if err := leaseManager.Run(ctx); err != nil {
+						log.Error("Failed task lease manager", zap.Error(err))
+					}
+					log.Info("Stopping")
+				}(leaseLogger)
+			}
 		}
 
 		m.scheduler = sch
@@ -794,22 +913,28 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		taskSvc = middleware.New(combinedTaskService, taskCoord)
 		m.taskControlService = combinedTaskService
-		if err := taskbackend.TaskNotifyCoordinatorOfExisting(
-			ctx,
-			taskSvc,
-			combinedTaskService,
-			taskCoord,
-			func(ctx context.Context, taskID platform.ID, runID platform.ID) error {
-				_, err := executor.ResumeCurrentRun(ctx, taskID, runID)
-				return err
-			},
-			coordLogger); err != nil {
-			m.log.Error("Failed to resume existing tasks", zap.Error(err))
+		// when leasing, the lease manager schedules the existing tasks it
+		// acquires, and resumes the runs left running and the queued retries
+		// of those it takes over.
+		if !leasing {
+			if err := taskbackend.TaskNotifyCoordinatorOfExisting(
+				ctx,
+				taskSvc,
+				combinedTaskService,
+				taskCoord,
+				func(ctx context.Context, taskID platform.ID, runID platform.ID) error {
+					_, err := executor.ResumeCurrentRun(ctx, taskID, runID)
+					return err
+				},
+				executor.ResumeRetries,
+				coordLogger); err != nil {
+				m.log.Error("Failed to resume existing tasks", zap.Error(err))
+			}
 		}
 	}
 
-	dbrpSvc := dbrp.NewService(ctx, authorizer.NewBucketService(ts.BucketSvc, ts.UrmSvc), m.kvStore)
-	dbrpSvc = dbrp.NewAuthorizedService(dbrpSvc)
+	legacyDBRPSvc := dbrp.NewService(ctx, authorizer.NewBucketService(ts.BucketSvc, ts.UrmSvc), m.kvStore)
+	dbrpSvc := dbrp.NewAuthorizedService(legacyDBRPSvc)
 
 	var checkSvc platform.CheckService
 	{
@@ -940,8 +1065,37 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		labelSvc = label.NewLabelController(m.flagger, m.kvService, ls)
 	}
 
+	deleteJobSvc := deletejob.NewService(m.kvStore)
+	deleteJobWorker := deletejob.NewWorker(m.log.With(zap.String("service", "delete-job")), deleteJobSvc, deleteService)
+	m.wg.Add(1)
+	go func(log *zap.Logger) {
+		defer m.wg.Done()
+		log = log.With(zap.String("service", "delete-job"))
+		if err := deleteJobWorker.Run(ctx); err != nil {
+			log.Error("Failed delete job worker", zap.Error(err))
+		}
+		log.Info("Stopping")
+	}(m.log)
+
+	// backfill runs are forced through the coordinating task service, which
+	// returns once the run is done.
+	backfillSvc := backfill.NewService(m.kvStore, taskSvc)
+	backfillWorker := backfill.NewWorker(m.log.With(zap.String("service", "task-backfill")), backfillSvc, taskSvc)
+	m.wg.Add(1)
+	go func(log *zap.Logger) {
+		defer m.wg.Done()
+		log = log.With(zap.String("service", "task-backfill"))
+		if err := backfillWorker.Run(ctx); err != nil {
+			log.Error("Failed task backfill worker", zap.Error(err))
+		}
+		log.Info("Stopping")
+	}(m.log)
+
 	ts.BucketSvc = storage.NewBucketService(ts.BucketSvc, m.engine)
 	ts.BucketSvc = dbrp.NewBucketService(m.log, ts.BucketSvc, dbrpSvc)
+	ts.BucketSvc = downsample.NewBucketService(m.log.With(zap.String("service", "downsample")), ts.BucketSvc, taskSvc)
+
+	rateLimiter := ratelimit.NewLimiter(m.log.With(zap.String("service", "ratelimit")), m.kvService)
 
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
@@ -950,14 +1104,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SessionRenewDisabled: m.sessionRenewDisabled,
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
+		RateLimiter:          rateLimiter,
 		PointsWriter: &storage.LoggingPointsWriter{
-			Underlying:    pointsWriter,
+			Underlying:    ratelimit.NewPointsWriter(pointsWriter),
 			BucketFinder:  ts.BucketSvc,
 			LogBucketName: platform.MonitoringSystemBucketName,
 		},
-		DeleteService:        deleteService,
+		DeleteJobService:     deleteJobSvc,
 		BackupService:        backupService,
 		KVBackupService:      m.kvService,
+		RestoreService:       restoreService,
 		AuthorizationService: authSvc,
 		AlgoWProxy:           &http.NoopProxyHandler{},
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
@@ -965,6 +1121,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SessionService:                  sessionSvc,
 		UserService:                     ts.UserSvc,
 		DBRPService:                     dbrpSvc,
+		LegacyDBRPService:               legacyDBRPSvc,
 		OrganizationService:             ts.OrgSvc,
 		UserResourceMappingService:      ts.UrmSvc,
 		LabelService:                    labelSvc,
@@ -980,8 +1137,14 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		FluxService:                     storageQueryService,
 		FluxLanguageService:             fluxlang.DefaultService,
 		TaskService:                     taskSvc,
+		BackfillService:                 backfillSvc,
+		TaskVersionService:              m.kvService,
+		TaskDryRunService:               m.executor,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
+		SilenceService:                  m.kvService,
+		EscalationPolicyService:         m.kvService,
+		AcknowledgementService:          escalation.NewAcknowledgementService(ts.BucketSvc, pointsWriter),
 		NotificationEndpointService:     endpoints.NewService(notificationEndpointStore, secretSvc, ts.UrmSvc, ts.OrgSvc),
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
@@ -1118,6 +1281,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			http.WithResourceHandler(userHTTPServer.UserResourceHandler()),
 			http.WithResourceHandler(orgHTTPServer),
 			http.WithResourceHandler(bucketHTTPServer),
+			http.WithResourceHandler(ratelimit.NewHTTPQuotaHandler(m.log.With(zap.String("handler", "quota")), authorizer.NewQuotaService(m.kvService))),
 		)
 
 		httpLogger := m.log.With(zap.String("service", "http"))
@@ -1223,6 +1387,46 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 // isAddressPortAvailable checks whether the address:port is available to listen,
 // by using net.Listen to verify that the port opens successfully, then closes the listener.
+// priorityClasses returns the configuration of the query priority classes
+// set with the query-reserved-concurrency, query-reserved-memory-bytes and
+// query-class-queue-size flags.
+func (m *Launcher) priorityClasses() (map[query.Priority]control.ClassConfig, error) {
+	classes := make(map[query.Priority]control.ClassConfig)
+	set := func(flag string, values map[string]string, fn func(*control.ClassConfig, int64)) error {
+		for class, value := range values {
+			p := query.Priority(class)
+			if !p.Valid() {
+				return fmt.Errorf("%s: unknown priority class %q", flag, class)
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: invalid value for priority class %q: %v", flag, class, err)
+			}
+			cc := classes[p]
+			fn(&cc, n)
+			classes[p] = cc
+		}
+		return nil
+	}
+
+	if err := set("query-reserved-concurrency", m.reservedConcurrency, func(cc *control.ClassConfig, n int64) {
+		cc.ReservedConcurrency = int(n)
+	}); err != nil {
+		return nil, err
+	}
+	if err := set("query-reserved-memory-bytes", m.reservedMemoryBytes, func(cc *control.ClassConfig, n int64) {
+		cc.ReservedMemoryBytes = n
+	}); err != nil {
+		return nil, err
+	}
+	if err := set("query-class-queue-size", m.classQueueSize, func(cc *control.ClassConfig, n int64) {
+		cc.QueueSize = int(n)
+	}); err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
 func isAddressPortAvailable(address string, port int) (bool, error) {
 	if l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", address, port)); err == nil {
 		if err := l.Close(); err != nil {
@@ -34,6 +34,7 @@ NOTES:
 
 * The influxd server should not be running when using the restore tool
   as it replaces all data and metadata.
+* To restore a single bucket into a running server, use "influx restore".
 `,
 	Args: cobra.ExactArgs(0),
 	RunE: restoreE,
@@ -0,0 +1,113 @@
+package dbrp
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.DBRPMappingService = (*LegacyService)(nil)
+
+// LegacyService exposes the mappings of a single organization through the
+// cluster based influxdb.DBRPMappingService interface that is consumed by the
+// InfluxQL transpiler. The cluster is ignored since mappings are org-scoped.
+type LegacyService struct {
+	svc   influxdb.DBRPMappingServiceV2
+	orgID influxdb.ID
+}
+
+// NewLegacyService returns a LegacyService that resolves mappings for orgID.
+func NewLegacyService(svc influxdb.DBRPMappingServiceV2, orgID influxdb.ID) *LegacyService {
+	return &LegacyService{
+		svc:   svc,
+		orgID: orgID,
+	}
+}
+
+// FindBy returns the mapping for the database and retention policy.
+// If rp is empty the default mapping for the database is returned.
+func (s *LegacyService) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	filter := influxdb.DBRPMappingFilter{
+		Cluster:  &cluster,
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		def := true
+		filter.Default = &def
+	}
+	return s.Find(ctx, filter)
+}
+
+// Find returns the first mapping that matches filter.
+func (s *LegacyService) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	ms, _, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, ErrDBRPNotFound
+	}
+	return ms[0], nil
+}
+
+// FindMany returns the mappings of the organization that match filter.
+func (s *LegacyService) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opts ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	ms, _, err := s.svc.FindMany(ctx, influxdb.DBRPMappingFilterV2{
+		OrgID:           &s.orgID,
+		Database:        filter.Database,
+		RetentionPolicy: filter.RetentionPolicy,
+		Default:         filter.Default,
+	}, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cluster := ""
+	if filter.Cluster != nil {
+		cluster = *filter.Cluster
+	}
+	legacy := make([]*influxdb.DBRPMapping, 0, len(ms))
+	for _, m := range ms {
+		legacy = append(legacy, &influxdb.DBRPMapping{
+			Cluster:         cluster,
+			Database:        m.Database,
+			RetentionPolicy: m.RetentionPolicy,
+			Default:         m.Default,
+			OrganizationID:  m.OrganizationID,
+			BucketID:        m.BucketID,
+		})
+	}
+	return legacy, len(legacy), nil
+}
+
+// Create creates a new mapping in the organization.
+func (s *LegacyService) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	return s.svc.Create(ctx, &influxdb.DBRPMappingV2{
+		Database:        m.Database,
+		RetentionPolicy: m.RetentionPolicy,
+		Default:         m.Default,
+		OrganizationID:  s.orgID,
+		BucketID:        m.BucketID,
+	})
+}
+
+// Delete removes the mapping for the database and retention policy.
+// Deleting a mapping that does not exist is not an error.
+func (s *LegacyService) Delete(ctx context.Context, cluster, db, rp string) error {
+	ms, _, err := s.svc.FindMany(ctx, influxdb.DBRPMappingFilterV2{
+		OrgID:           &s.orgID,
+		Database:        &db,
+		RetentionPolicy: &rp,
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := s.svc.Delete(ctx, s.orgID, m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package dbrp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/dbrp"
+	itesting "github.com/influxdata/influxdb/v2/testing"
+)
+
+func TestLegacyService_FindBy(t *testing.T) {
+	svc, done := initDBRPMappingService(itesting.DBRPMappingFieldsV2{
+		DBRPMappingsV2: []*influxdb.DBRPMappingV2{
+			{
+				ID:              100,
+				Database:        "telegraf",
+				RetentionPolicy: "autogen",
+				Default:         true,
+				OrganizationID:  1,
+				BucketID:        10,
+			},
+			{
+				ID:              200,
+				Database:        "telegraf",
+				RetentionPolicy: "1week",
+				OrganizationID:  1,
+				BucketID:        20,
+			},
+			{
+				ID:              300,
+				Database:        "telegraf",
+				RetentionPolicy: "autogen",
+				Default:         true,
+				OrganizationID:  2,
+				BucketID:        30,
+			},
+		},
+	}, t)
+	defer done()
+
+	ctx := context.Background()
+	legacy := dbrp.NewLegacyService(svc, 1)
+
+	for _, tt := range []struct {
+		name    string
+		db, rp  string
+		want    *influxdb.DBRPMapping
+		wantErr bool
+	}{
+		{
+			name: "default retention policy",
+			db:   "telegraf",
+			want: &influxdb.DBRPMapping{
+				Database:        "telegraf",
+				RetentionPolicy: "autogen",
+				Default:         true,
+				OrganizationID:  1,
+				BucketID:        10,
+			},
+		},
+		{
+			name: "explicit retention policy",
+			db:   "telegraf",
+			rp:   "1week",
+			want: &influxdb.DBRPMapping{
+				Database:        "telegraf",
+				RetentionPolicy: "1week",
+				OrganizationID:  1,
+				BucketID:        20,
+			},
+		},
+		{
+			name:    "missing database",
+			db:      "missing",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := legacy.FindBy(ctx, "", tt.db, tt.rp)
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.ENotFound {
+					t.Fatalf("expected not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected mapping -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
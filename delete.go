@@ -1,6 +1,10 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
 
 // Predicate is something that can match on a series key.
 type Predicate interface {
@@ -13,3 +17,95 @@ type Predicate interface {
 type DeleteService interface {
 	DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID ID, min, max int64, pred Predicate) error
 }
+
+// DeleteJobStatus is the status of a delete job.
+type DeleteJobStatus string
+
+// Statuses of a delete job. A job is queued until the worker picks it up and
+// ends in one of success, failed or canceled.
+const (
+	DeleteJobQueued    DeleteJobStatus = "queued"
+	DeleteJobRunning   DeleteJobStatus = "running"
+	DeleteJobCanceling DeleteJobStatus = "canceling"
+	DeleteJobSuccess   DeleteJobStatus = "success"
+	DeleteJobFailed    DeleteJobStatus = "failed"
+	DeleteJobCanceled  DeleteJobStatus = "canceled"
+)
+
+// Finished returns true if the job will not run anymore.
+func (s DeleteJobStatus) Finished() bool {
+	return s == DeleteJobSuccess || s == DeleteJobFailed || s == DeleteJobCanceled
+}
+
+// DeleteJob is a delete of the points of a bucket in [Start, Stop] matching
+// Predicate, run in the background.
+type DeleteJob struct {
+	ID         ID              `json:"id"`
+	OrgID      ID              `json:"orgID"`
+	BucketID   ID              `json:"bucketID"`
+	Start      time.Time       `json:"start"`
+	Stop       time.Time       `json:"stop"`
+	Predicate  string          `json:"predicate,omitempty"`
+	Status     DeleteJobStatus `json:"status"`
+	Progress   DeleteProgress  `json:"progress"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	StartedAt  *time.Time      `json:"startedAt,omitempty"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// DeleteJobFilter represents a set of filters that restrict the returned delete jobs.
+type DeleteJobFilter struct {
+	OrgID    *ID
+	BucketID *ID
+	Status   *DeleteJobStatus
+}
+
+// DeleteJobService manages delete jobs.
+type DeleteJobService interface {
+	// CreateDeleteJob queues a delete job, setting its ID, status and creation time.
+	CreateDeleteJob(ctx context.Context, job *DeleteJob) error
+
+	// FindDeleteJobByID returns a single delete job by ID.
+	FindDeleteJobByID(ctx context.Context, id ID) (*DeleteJob, error)
+
+	// FindDeleteJobs returns the delete jobs matching filter, oldest first.
+	FindDeleteJobs(ctx context.Context, filter DeleteJobFilter) ([]*DeleteJob, error)
+
+	// CancelDeleteJob cancels a queued or running delete job. A running job
+	// stops between TSM files; points it already deleted are not restored.
+	CancelDeleteJob(ctx context.Context, id ID) error
+}
+
+// DeleteProgress counts the work done by a delete. The counters are updated
+// atomically by the storage engine while the delete runs.
+type DeleteProgress struct {
+	// FilesScanned is the number of TSM files examined.
+	FilesScanned int64 `json:"filesScanned"`
+	// FilesRewritten is the number of TSM files tombstones were written for.
+	FilesRewritten int64 `json:"filesRewritten"`
+	// SeriesRemoved is the number of series removed from the index.
+	SeriesRemoved int64 `json:"seriesRemoved"`
+}
+
+// Load returns a copy of p safe to read while the delete runs.
+func (p *DeleteProgress) Load() DeleteProgress {
+	return DeleteProgress{
+		FilesScanned:   atomic.LoadInt64(&p.FilesScanned),
+		FilesRewritten: atomic.LoadInt64(&p.FilesRewritten),
+		SeriesRemoved:  atomic.LoadInt64(&p.SeriesRemoved),
+	}
+}
+
+const deleteProgressKey contextKey = "deleteProgress"
+
+// NewContextWithDeleteProgress returns a context in which deletes report their progress to p.
+func NewContextWithDeleteProgress(ctx context.Context, p *DeleteProgress) context.Context {
+	return context.WithValue(ctx, deleteProgressKey, p)
+}
+
+// DeleteProgressFromContext returns the progress deletes report to in ctx, or nil.
+func DeleteProgressFromContext(ctx context.Context) *DeleteProgress {
+	p, _ := ctx.Value(deleteProgressKey).(*DeleteProgress)
+	return p
+}
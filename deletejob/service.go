@@ -0,0 +1,290 @@
+// Package deletejob runs deletes of points in the background. Delete jobs are
+// persisted in the kv store and executed one at a time by a Worker, which
+// reports their progress and stops them when they are canceled.
+package deletejob
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+var jobBucket = []byte("deletejobsv1")
+
+var (
+	// ErrJobNotFound is used when the delete job cannot be found.
+	ErrJobNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "delete job not found",
+	}
+
+	// ErrJobFinished is used when canceling a delete job that already finished.
+	ErrJobFinished = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "delete job has already finished",
+	}
+)
+
+var _ influxdb.DeleteJobService = (*Service)(nil)
+
+// Service persists delete jobs in a kv store.
+type Service struct {
+	store kv.Store
+	IDGen influxdb.IDGenerator
+	Now   func() time.Time
+
+	// queued is signaled when a job is created, to wake up the worker.
+	queued chan struct{}
+}
+
+// NewService constructs a delete job service backed by st.
+func NewService(st kv.Store) *Service {
+	return &Service{
+		store:  st,
+		IDGen:  snowflake.NewDefaultIDGenerator(),
+		Now:    func() time.Time { return time.Now().UTC() },
+		queued: make(chan struct{}, 1),
+	}
+}
+
+// CreateDeleteJob queues job, setting its ID, status and creation time.
+func (s *Service) CreateDeleteJob(ctx context.Context, job *influxdb.DeleteJob) error {
+	job.ID = s.IDGen.ID()
+	job.Status = influxdb.DeleteJobQueued
+	job.Progress = influxdb.DeleteProgress{}
+	job.Error = ""
+	job.CreatedAt = s.Now()
+	job.StartedAt, job.FinishedAt = nil, nil
+
+	if err := s.store.Update(ctx, func(tx kv.Tx) error {
+		return putJob(tx, job)
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// FindDeleteJobByID returns a single delete job by ID.
+func (s *Service) FindDeleteJobByID(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+	var job *influxdb.DeleteJob
+	err := s.store.View(ctx, func(tx kv.Tx) (err error) {
+		job, err = findJobByID(tx, id)
+		return err
+	})
+	return job, err
+}
+
+// FindDeleteJobs returns the delete jobs matching filter, oldest first.
+func (s *Service) FindDeleteJobs(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+	jobs := []*influxdb.DeleteJob{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		return walkJobs(ctx, tx, func(job *influxdb.DeleteJob) error {
+			if filterJob(job, filter) {
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CancelDeleteJob cancels a queued job right away. A running job is marked as
+// canceling and canceled by the worker running it.
+func (s *Service) CancelDeleteJob(ctx context.Context, id influxdb.ID) error {
+	_, err := s.update(ctx, id, func(job *influxdb.DeleteJob) error {
+		switch job.Status {
+		case influxdb.DeleteJobQueued:
+			now := s.Now()
+			job.Status = influxdb.DeleteJobCanceled
+			job.FinishedAt = &now
+		case influxdb.DeleteJobRunning:
+			job.Status = influxdb.DeleteJobCanceling
+		case influxdb.DeleteJobCanceling:
+		default:
+			return ErrJobFinished
+		}
+		return nil
+	})
+	return err
+}
+
+// update applies fn to the job id and stores the result.
+func (s *Service) update(ctx context.Context, id influxdb.ID, fn func(job *influxdb.DeleteJob) error) (*influxdb.DeleteJob, error) {
+	var job *influxdb.DeleteJob
+	err := s.store.Update(ctx, func(tx kv.Tx) (err error) {
+		if job, err = findJobByID(tx, id); err != nil {
+			return err
+		}
+		if err := fn(job); err != nil {
+			return err
+		}
+		return putJob(tx, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// start marks the oldest queued job as running and returns it, or nil if no
+// job is queued.
+func (s *Service) start(ctx context.Context) (*influxdb.DeleteJob, error) {
+	var next *influxdb.DeleteJob
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		err := walkJobs(ctx, tx, func(job *influxdb.DeleteJob) error {
+			if next == nil && job.Status == influxdb.DeleteJobQueued {
+				next = job
+			}
+			return nil
+		})
+		if err != nil || next == nil {
+			return err
+		}
+
+		now := s.Now()
+		next.Status = influxdb.DeleteJobRunning
+		next.StartedAt = &now
+		return putJob(tx, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// requeue queues the jobs left running by a previous process again and
+// cancels the ones being canceled.
+func (s *Service) requeue(ctx context.Context) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		var jobs []*influxdb.DeleteJob
+		err := walkJobs(ctx, tx, func(job *influxdb.DeleteJob) error {
+			if job.Status == influxdb.DeleteJobRunning || job.Status == influxdb.DeleteJobCanceling {
+				jobs = append(jobs, job)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			if job.Status == influxdb.DeleteJobCanceling {
+				now := s.Now()
+				job.Status = influxdb.DeleteJobCanceled
+				job.FinishedAt = &now
+			} else {
+				job.Status = influxdb.DeleteJobQueued
+				job.Progress = influxdb.DeleteProgress{}
+				job.StartedAt = nil
+			}
+			if err := putJob(tx, job); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func filterJob(job *influxdb.DeleteJob, filter influxdb.DeleteJobFilter) bool {
+	if filter.OrgID != nil && job.OrgID != *filter.OrgID {
+		return false
+	}
+	if filter.BucketID != nil && job.BucketID != *filter.BucketID {
+		return false
+	}
+	if filter.Status != nil && job.Status != *filter.Status {
+		return false
+	}
+	return true
+}
+
+func findJobByID(tx kv.Tx, id influxdb.ID) (*influxdb.DeleteJob, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(jobBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeJob(v)
+}
+
+func walkJobs(ctx context.Context, tx kv.Tx, fn func(job *influxdb.DeleteJob) error) error {
+	b, err := tx.Bucket(jobBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+
+	return kv.WalkCursor(ctx, cur, func(_, v []byte) error {
+		job, err := decodeJob(v)
+		if err != nil {
+			return err
+		}
+		return fn(job)
+	})
+}
+
+func putJob(tx kv.Tx, job *influxdb.DeleteJob) error {
+	encodedID, err := job.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(job)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(jobBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+func decodeJob(v []byte) (*influxdb.DeleteJob, error) {
+	job := new(influxdb.DeleteJob)
+	if err := json.Unmarshal(v, job); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "failed to decode delete job",
+			Err:  err,
+		}
+	}
+	return job, nil
+}
@@ -0,0 +1,128 @@
+package deletejob_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/deletejob"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+var (
+	orgID     = influxdb.ID(0x1000)
+	bucketID  = influxdb.ID(0x2000)
+	otherID   = influxdb.ID(0x3000)
+	createdAt = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newService(t *testing.T) *deletejob.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+
+	s := deletejob.NewService(store)
+	s.IDGen = &mock.MockIDGenerator{Count: 1}
+	s.Now = func() time.Time { return createdAt }
+	return s
+}
+
+func TestService_FindDeleteJobs(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t)
+
+	for _, job := range []*influxdb.DeleteJob{
+		{OrgID: orgID, BucketID: bucketID, Predicate: `host="a"`},
+		{OrgID: orgID, BucketID: otherID},
+		{OrgID: otherID, BucketID: bucketID},
+	} {
+		if err := s.CreateDeleteJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CancelDeleteJob(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := s.FindDeleteJobByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &influxdb.DeleteJob{
+		ID:        1,
+		OrgID:     orgID,
+		BucketID:  bucketID,
+		Predicate: `host="a"`,
+		Status:    influxdb.DeleteJobQueued,
+		CreatedAt: createdAt,
+	}
+	if diff := cmp.Diff(exp, job); diff != "" {
+		t.Fatalf("unexpected job -want/+got:\n%s", diff)
+	}
+
+	queued, canceled := influxdb.DeleteJobQueued, influxdb.DeleteJobCanceled
+	tests := []struct {
+		name   string
+		filter influxdb.DeleteJobFilter
+		ids    []influxdb.ID
+	}{
+		{name: "all", ids: []influxdb.ID{1, 2, 3}},
+		{name: "org", filter: influxdb.DeleteJobFilter{OrgID: &orgID}, ids: []influxdb.ID{1, 2}},
+		{name: "bucket", filter: influxdb.DeleteJobFilter{BucketID: &bucketID}, ids: []influxdb.ID{1, 3}},
+		{name: "queued", filter: influxdb.DeleteJobFilter{OrgID: &orgID, Status: &queued}, ids: []influxdb.ID{1}},
+		{name: "canceled", filter: influxdb.DeleteJobFilter{Status: &canceled}, ids: []influxdb.ID{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := s.FindDeleteJobs(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]influxdb.ID, 0, len(jobs))
+			for _, j := range jobs {
+				ids = append(ids, j.ID)
+			}
+			if diff := cmp.Diff(tt.ids, ids); diff != "" {
+				t.Fatalf("unexpected jobs -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_CancelDeleteJob(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t)
+
+	if err := s.CreateDeleteJob(ctx, &influxdb.DeleteJob{OrgID: orgID, BucketID: bucketID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CancelDeleteJob(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	job, err := s.FindDeleteJobByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != influxdb.DeleteJobCanceled || job.FinishedAt == nil {
+		t.Fatalf("expected canceled job with finish time, got %+v", job)
+	}
+
+	if err := s.CancelDeleteJob(ctx, 1); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict canceling a finished job, got %v", err)
+	}
+	if err := s.CancelDeleteJob(ctx, 2); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found canceling a missing job, got %v", err)
+	}
+	if _, err := s.FindDeleteJobByID(ctx, 2); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found finding a missing job, got %v", err)
+	}
+}
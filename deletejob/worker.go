@@ -0,0 +1,150 @@
+package deletejob
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/predicate"
+	"go.uber.org/zap"
+)
+
+// DefaultProgressInterval is the default interval at which the worker stores the
+// progress of the running job and checks whether it was canceled.
+const DefaultProgressInterval = time.Second
+
+// Worker executes the queued delete jobs of a Service one at a time.
+type Worker struct {
+	log           *zap.Logger
+	jobs          *Service
+	deleteService influxdb.DeleteService
+
+	ProgressInterval time.Duration
+}
+
+// NewWorker constructs a worker running the jobs of s with ds.
+func NewWorker(log *zap.Logger, s *Service, ds influxdb.DeleteService) *Worker {
+	return &Worker{
+		log:              log,
+		jobs:             s,
+		deleteService:    ds,
+		ProgressInterval: DefaultProgressInterval,
+	}
+}
+
+// Run executes queued jobs until ctx is done. Jobs left running by a previous
+// process are queued again first, and the job running when ctx is done is
+// queued again to be resumed by the next run.
+func (w *Worker) Run(ctx context.Context) error {
+	if err := w.jobs.requeue(ctx); err != nil {
+		return err
+	}
+
+	for {
+		job, err := w.jobs.start(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.log.Error("Failed to start delete job", zap.Error(err))
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-w.jobs.queued:
+			}
+			continue
+		}
+
+		w.run(ctx, job)
+	}
+}
+
+// run executes job, storing its progress as it goes and its outcome when done.
+func (w *Worker) run(ctx context.Context, job *influxdb.DeleteJob) {
+	log := w.log.With(zap.String("job_id", job.ID.String()), zap.String("bucket_id", job.BucketID.String()))
+	log.Info("Delete job started")
+
+	var progress influxdb.DeleteProgress
+	jobCtx, cancel := context.WithCancel(influxdb.NewContextWithDeleteProgress(ctx, &progress))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		pred, err := parsePredicate(job.Predicate)
+		if err != nil {
+			done <- err
+			return
+		}
+		done <- w.deleteService.DeleteBucketRangePredicate(jobCtx, job.OrgID, job.BucketID, job.Start.UnixNano(), job.Stop.UnixNano(), pred)
+	}()
+
+	ticker := time.NewTicker(w.ProgressInterval)
+	defer ticker.Stop()
+
+	var canceled bool
+	for {
+		select {
+		case err := <-done:
+			w.finish(log, job.ID, progress.Load(), err, canceled, ctx.Err() != nil)
+			return
+		case <-ticker.C:
+			j, err := w.jobs.update(ctx, job.ID, func(j *influxdb.DeleteJob) error {
+				j.Progress = progress.Load()
+				return nil
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warn("Failed to update delete job progress", zap.Error(err))
+				}
+				continue
+			}
+			if j.Status == influxdb.DeleteJobCanceling && !canceled {
+				canceled = true
+				cancel()
+			}
+		}
+	}
+}
+
+// finish stores the outcome of a job. The job is queued again if the worker
+// stopped while it was running.
+func (w *Worker) finish(log *zap.Logger, id influxdb.ID, progress influxdb.DeleteProgress, runErr error, canceled, stopped bool) {
+	// The context of the worker may be done, the outcome must be stored anyway.
+	_, err := w.jobs.update(context.Background(), id, func(job *influxdb.DeleteJob) error {
+		now := w.jobs.Now()
+		job.Progress = progress
+		job.FinishedAt = &now
+
+		switch {
+		case runErr == nil:
+			job.Status = influxdb.DeleteJobSuccess
+		case canceled:
+			job.Status = influxdb.DeleteJobCanceled
+		case stopped:
+			job.Status = influxdb.DeleteJobQueued
+			job.Progress = influxdb.DeleteProgress{}
+			job.StartedAt, job.FinishedAt = nil, nil
+		default:
+			job.Status = influxdb.DeleteJobFailed
+			job.Error = runErr.Error()
+		}
+		log.Info("Delete job finished",
+			zap.String("status", string(job.Status)),
+			zap.Int64("files_scanned", progress.FilesScanned),
+			zap.Int64("files_rewritten", progress.FilesRewritten),
+			zap.Int64("series_removed", progress.SeriesRemoved),
+			zap.NamedError("delete_error", runErr))
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to store delete job outcome", zap.Error(err))
+	}
+}
+
+func parsePredicate(s string) (influxdb.Predicate, error) {
+	node, err := predicate.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return predicate.New(node)
+}
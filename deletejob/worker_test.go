@@ -0,0 +1,162 @@
+package deletejob_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/deletejob"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// runWorker runs a worker deleting with fn until the returned function is called.
+func runWorker(t *testing.T, s *deletejob.Service, fn func(ctx context.Context, pred influxdb.Predicate) error) func() {
+	t.Helper()
+
+	ds := mock.NewDeleteService()
+	ds.DeleteBucketRangePredicateF = func(ctx context.Context, _, _ influxdb.ID, _, _ int64, pred influxdb.Predicate) error {
+		return fn(ctx, pred)
+	}
+
+	w := deletejob.NewWorker(zaptest.NewLogger(t), s, ds)
+	w.ProgressInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// waitForStatus waits for the job id to reach status and returns it.
+func waitForStatus(t *testing.T, s *deletejob.Service, id influxdb.ID, status influxdb.DeleteJobStatus) *influxdb.DeleteJob {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := s.FindDeleteJobByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, expected %s", id, job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWorker_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		s := newService(t)
+		var preds int32
+		stop := runWorker(t, s, func(ctx context.Context, pred influxdb.Predicate) error {
+			if pred != nil {
+				atomic.AddInt32(&preds, 1)
+			}
+			p := influxdb.DeleteProgressFromContext(ctx)
+			atomic.AddInt64(&p.FilesScanned, 2)
+			atomic.AddInt64(&p.FilesRewritten, 1)
+			atomic.AddInt64(&p.SeriesRemoved, 3)
+			return nil
+		})
+		defer stop()
+
+		if err := s.CreateDeleteJob(ctx, &influxdb.DeleteJob{OrgID: orgID, BucketID: bucketID, Predicate: `host="a" or host="b"`}); err != nil {
+			t.Fatal(err)
+		}
+		job := waitForStatus(t, s, 1, influxdb.DeleteJobSuccess)
+		exp := influxdb.DeleteProgress{FilesScanned: 2, FilesRewritten: 1, SeriesRemoved: 3}
+		if job.Progress != exp {
+			t.Fatalf("unexpected progress: got %+v, exp %+v", job.Progress, exp)
+		}
+		if job.StartedAt == nil || job.FinishedAt == nil {
+			t.Fatalf("expected start and finish times, got %+v", job)
+		}
+		if atomic.LoadInt32(&preds) != 1 {
+			t.Fatal("expected the predicate to be passed to the delete")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		s := newService(t)
+		stop := runWorker(t, s, func(ctx context.Context, pred influxdb.Predicate) error {
+			return errors.New("disk on fire")
+		})
+		defer stop()
+
+		for _, pred := range []string{"", `host=~/a(/`} {
+			if err := s.CreateDeleteJob(ctx, &influxdb.DeleteJob{OrgID: orgID, BucketID: bucketID, Predicate: pred}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if job := waitForStatus(t, s, 1, influxdb.DeleteJobFailed); job.Error != "disk on fire" {
+			t.Fatalf("unexpected job error %q", job.Error)
+		}
+		if job := waitForStatus(t, s, 2, influxdb.DeleteJobFailed); job.Error == "" {
+			t.Fatal("expected an error for an invalid predicate")
+		}
+	})
+
+	t.Run("cancel running job", func(t *testing.T) {
+		s := newService(t)
+		stop := runWorker(t, s, func(ctx context.Context, pred influxdb.Predicate) error {
+			atomic.AddInt64(&influxdb.DeleteProgressFromContext(ctx).FilesScanned, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		defer stop()
+
+		if err := s.CreateDeleteJob(ctx, &influxdb.DeleteJob{OrgID: orgID, BucketID: bucketID}); err != nil {
+			t.Fatal(err)
+		}
+		waitForStatus(t, s, 1, influxdb.DeleteJobRunning)
+		if err := s.CancelDeleteJob(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		if job := waitForStatus(t, s, 1, influxdb.DeleteJobCanceled); job.Progress.FilesScanned != 1 {
+			t.Fatalf("expected progress of the canceled job, got %+v", job.Progress)
+		}
+	})
+
+	t.Run("stop and resume", func(t *testing.T) {
+		s := newService(t)
+		var runs int32
+		stop := runWorker(t, s, func(ctx context.Context, pred influxdb.Predicate) error {
+			atomic.AddInt32(&runs, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		if err := s.CreateDeleteJob(ctx, &influxdb.DeleteJob{OrgID: orgID, BucketID: bucketID}); err != nil {
+			t.Fatal(err)
+		}
+		waitForStatus(t, s, 1, influxdb.DeleteJobRunning)
+		stop()
+		if job := waitForStatus(t, s, 1, influxdb.DeleteJobQueued); job.StartedAt != nil {
+			t.Fatalf("expected queued job without start time, got %+v", job)
+		}
+
+		stop = runWorker(t, s, func(ctx context.Context, pred influxdb.Predicate) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		})
+		defer stop()
+		waitForStatus(t, s, 1, influxdb.DeleteJobSuccess)
+		if got := atomic.LoadInt32(&runs); got != 2 {
+			t.Fatalf("expected 2 runs, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// DownsampleFieldType is the type of field values an aggregate of a
+// downsample policy is applied to.
+type DownsampleFieldType string
+
+// Field types supported by downsample aggregates.
+const (
+	DownsampleFieldTypeFloat    DownsampleFieldType = "float"
+	DownsampleFieldTypeInteger  DownsampleFieldType = "integer"
+	DownsampleFieldTypeUnsigned DownsampleFieldType = "unsigned"
+	DownsampleFieldTypeString   DownsampleFieldType = "string"
+	DownsampleFieldTypeBoolean  DownsampleFieldType = "boolean"
+)
+
+// downsampleFunctions maps the aggregate functions a downsample policy may use
+// to whether they accept non-numeric values.
+var downsampleFunctions = map[string]bool{
+	"count":  true,
+	"first":  true,
+	"last":   true,
+	"max":    false,
+	"mean":   false,
+	"median": false,
+	"min":    false,
+	"spread": false,
+	"stddev": false,
+	"sum":    false,
+}
+
+// DownsamplePolicy continuously aggregates the data written to the bucket it is
+// attached to into a destination bucket. The server materializes every policy
+// into a managed task; users never edit that task directly.
+type DownsamplePolicy struct {
+	DestinationBucketID ID                    `json:"destinationBucketID"`
+	Every               Duration              `json:"every"`
+	Lag                 Duration              `json:"lag"`
+	Aggregates          []DownsampleAggregate `json:"aggregates"`
+
+	// TaskID identifies the managed task executing the policy.
+	TaskID ID `json:"taskID,omitempty"`
+	// Status is read from the managed task and is never persisted.
+	Status *DownsampleStatus `json:"status,omitempty"`
+}
+
+// DownsampleAggregate is the aggregate function applied to fields of a given type.
+//
+// Flux cannot select rows by the type of their values, so the fields of an
+// aggregate are selected by name and must have its field type. A single
+// aggregate per policy may omit its fields, in which case it applies to every
+// field not listed by another aggregate of the policy whatever their type, so
+// its function must accept values of every type.
+type DownsampleAggregate struct {
+	FieldType DownsampleFieldType `json:"fieldType"`
+	Function  string              `json:"function"`
+	Fields    []string            `json:"fields,omitempty"`
+}
+
+// DownsampleStatus reports the state of the task backing a downsample policy.
+type DownsampleStatus struct {
+	TaskStatus      string    `json:"taskStatus"`
+	LatestCompleted time.Time `json:"latestCompleted"`
+	LastRunStatus   string    `json:"lastRunStatus,omitempty"`
+	LastRunError    string    `json:"lastRunError,omitempty"`
+}
+
+// Valid returns an error if the aggregate cannot be applied to its field type.
+func (a DownsampleAggregate) Valid() error {
+	anyType, ok := downsampleFunctions[a.Function]
+	if !ok {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unsupported downsample function %q", a.Function),
+		}
+	}
+
+	switch a.FieldType {
+	case DownsampleFieldTypeFloat, DownsampleFieldTypeInteger, DownsampleFieldTypeUnsigned:
+	case DownsampleFieldTypeString, DownsampleFieldTypeBoolean:
+		if !anyType {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("downsample function %q cannot be applied to %s fields", a.Function, a.FieldType),
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unsupported downsample field type %q", a.FieldType),
+		}
+	}
+	return nil
+}
+
+// Valid returns an error if the policy cannot be materialized into a task.
+func (p DownsamplePolicy) Valid() error {
+	if !p.DestinationBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample policy requires a destination bucket",
+		}
+	}
+	if p.Every.Duration < time.Second {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample window must be greater than or equal to one second",
+		}
+	}
+	if p.Lag.Duration < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample lag must not be negative",
+		}
+	}
+	if len(p.Aggregates) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample policy requires at least one aggregate",
+		}
+	}
+
+	var catchAll bool
+	fields := make(map[string]bool)
+	for _, a := range p.Aggregates {
+		if err := a.Valid(); err != nil {
+			return err
+		}
+		if len(a.Fields) == 0 {
+			if catchAll {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "only one downsample aggregate may omit its fields",
+				}
+			}
+			if !downsampleFunctions[a.Function] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("downsample function %q cannot be applied to fields of every type, the aggregate must list its fields", a.Function),
+				}
+			}
+			catchAll = true
+		}
+		for _, f := range a.Fields {
+			if fields[f] {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("field %q is downsampled by more than one aggregate", f),
+				}
+			}
+			fields[f] = true
+		}
+	}
+	return nil
+}
+
+// ValidDownsamplePolicies returns an error if any of the policies attached to
+// the source bucket is invalid. A bucket may not downsample into itself nor
+// into the same destination twice.
+func ValidDownsamplePolicies(sourceID ID, policies []DownsamplePolicy) error {
+	dests := make(map[ID]bool)
+	for _, p := range policies {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+		if p.DestinationBucketID == sourceID {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "a bucket cannot be downsampled into itself",
+			}
+		}
+		if dests[p.DestinationBucketID] {
+			return &Error{
+				Code: EConflict,
+				Msg:  fmt.Sprintf("bucket already downsamples into %s", p.DestinationBucketID),
+			}
+		}
+		dests[p.DestinationBucketID] = true
+	}
+	return nil
+}
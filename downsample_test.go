@@ -0,0 +1,170 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestDownsamplePolicyValid(t *testing.T) {
+	hour := influxdb.Duration{Duration: time.Hour}
+	tests := []struct {
+		name    string
+		policy  influxdb.DownsamplePolicy
+		wantErr bool
+	}{
+		{
+			name: "valid policy",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Lag:                 influxdb.Duration{Duration: 5 * time.Minute},
+				Aggregates: []influxdb.DownsampleAggregate{
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean", Fields: []string{"usage"}},
+					{FieldType: influxdb.DownsampleFieldTypeString, Function: "last"},
+					{FieldType: influxdb.DownsampleFieldTypeBoolean, Function: "count", Fields: []string{"up"}},
+				},
+			},
+		},
+		{
+			name: "requires a destination",
+			policy: influxdb.DownsamplePolicy{
+				Every:      hour,
+				Aggregates: []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "window of at least a second",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               influxdb.Duration{Duration: time.Millisecond},
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative lag",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Lag:                 influxdb.Duration{Duration: -time.Minute},
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "requires an aggregate",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown function",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "holtWinters"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "numeric function on string fields",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeString, Function: "mean", Fields: []string{"status"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "numeric function on every field",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown field type",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates:          []influxdb.DownsampleAggregate{{FieldType: "decimal", Function: "last"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "two aggregates without fields",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates: []influxdb.DownsampleAggregate{
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"},
+					{FieldType: influxdb.DownsampleFieldTypeInteger, Function: "count"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "field listed twice",
+			policy: influxdb.DownsamplePolicy{
+				DestinationBucketID: 2,
+				Every:               hour,
+				Aggregates: []influxdb.DownsampleAggregate{
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean", Fields: []string{"usage"}},
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "max", Fields: []string{"usage"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("DownsamplePolicy.Valid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidDownsamplePolicies(t *testing.T) {
+	policy := func(dest influxdb.ID) influxdb.DownsamplePolicy {
+		return influxdb.DownsamplePolicy{
+			DestinationBucketID: dest,
+			Every:               influxdb.Duration{Duration: time.Hour},
+			Aggregates:          []influxdb.DownsampleAggregate{{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"}},
+		}
+	}
+	tests := []struct {
+		name     string
+		policies []influxdb.DownsamplePolicy
+		wantErr  bool
+	}{
+		{
+			name:     "distinct destinations",
+			policies: []influxdb.DownsamplePolicy{policy(2), policy(3)},
+		},
+		{
+			name:     "into itself",
+			policies: []influxdb.DownsamplePolicy{policy(1)},
+			wantErr:  true,
+		},
+		{
+			name:     "same destination twice",
+			policies: []influxdb.DownsamplePolicy{policy(2), policy(2)},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := influxdb.ValidDownsamplePolicies(1, tt.policies); (err != nil) != tt.wantErr {
+				t.Errorf("ValidDownsamplePolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrEscalationPolicyNotFound is the error message for a missing escalation policy.
+const ErrEscalationPolicyNotFound = "escalation policy not found"
+
+// ops for escalation policies and acknowledgements.
+const (
+	OpFindEscalationPolicyByID = "FindEscalationPolicyByID"
+	OpFindEscalationPolicies   = "FindEscalationPolicies"
+	OpCreateEscalationPolicy   = "CreateEscalationPolicy"
+	OpUpdateEscalationPolicy   = "UpdateEscalationPolicy"
+	OpDeleteEscalationPolicy   = "DeleteEscalationPolicy"
+	OpAcknowledge              = "Acknowledge"
+)
+
+// EscalationPolicy is a chain of notification endpoints notified by the
+// notification rules referencing it, in addition to their own endpoint, once
+// the statuses they notified are still firing after the delay of a step and
+// have not been acknowledged.
+type EscalationPolicy struct {
+	ID          ID               `json:"id,omitempty"`
+	OrgID       ID               `json:"orgID"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Steps       []EscalationStep `json:"steps"`
+
+	CRUDLog
+}
+
+// EscalationStep notifies an endpoint once the statuses of a group have been
+// firing for Delay.
+type EscalationStep struct {
+	EndpointID ID       `json:"endpointID"`
+	Delay      Duration `json:"delay"`
+}
+
+// Valid returns an error if the escalation policy has no organization, no
+// name, or steps that are not ordered by increasing delays.
+func (p *EscalationPolicy) Valid() error {
+	if !p.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "escalation policy requires a valid orgID",
+		}
+	}
+	if p.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "escalation policy name can't be empty",
+		}
+	}
+	if len(p.Steps) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "escalation policy requires at least one step",
+		}
+	}
+
+	endpoints := make(map[ID]bool, len(p.Steps))
+	var prev time.Duration
+	for i, step := range p.Steps {
+		if !step.EndpointID.Valid() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("escalation step %d requires a valid endpointID", i+1),
+			}
+		}
+		if endpoints[step.EndpointID] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("escalation step %d notifies endpoint %s more than once", i+1, step.EndpointID),
+			}
+		}
+		endpoints[step.EndpointID] = true
+
+		if step.Delay.Duration <= prev {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("escalation step %d must have a delay greater than %s", i+1, prev),
+			}
+		}
+		prev = step.Delay.Duration
+	}
+	return nil
+}
+
+// EscalationPolicyUpdate is the set of fields to change on an escalation policy.
+type EscalationPolicyUpdate struct {
+	Name        *string          `json:"name,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	Steps       []EscalationStep `json:"steps,omitempty"`
+}
+
+// Apply applies the update to p.
+func (u EscalationPolicyUpdate) Apply(p *EscalationPolicy) {
+	if u.Name != nil {
+		p.Name = *u.Name
+	}
+	if u.Description != nil {
+		p.Description = *u.Description
+	}
+	if u.Steps != nil {
+		p.Steps = u.Steps
+	}
+}
+
+// EscalationPolicyFilter represents a set of filters that restrict the
+// returned escalation policies.
+type EscalationPolicyFilter struct {
+	OrgID *ID
+}
+
+// EscalationPolicyService represents a service for managing escalation policies.
+type EscalationPolicyService interface {
+	// FindEscalationPolicyByID returns a single escalation policy by ID.
+	FindEscalationPolicyByID(ctx context.Context, id ID) (*EscalationPolicy, error)
+
+	// FindEscalationPolicies returns the escalation policies matching filter.
+	FindEscalationPolicies(ctx context.Context, filter EscalationPolicyFilter) ([]*EscalationPolicy, error)
+
+	// CreateEscalationPolicy creates an escalation policy and sets p.ID with
+	// the new identifier.
+	CreateEscalationPolicy(ctx context.Context, p *EscalationPolicy) error
+
+	// UpdateEscalationPolicy updates an escalation policy and the tasks of the
+	// notification rules referencing it.
+	UpdateEscalationPolicy(ctx context.Context, id ID, upd EscalationPolicyUpdate) (*EscalationPolicy, error)
+
+	// DeleteEscalationPolicy removes an escalation policy that is not
+	// referenced by any notification rule.
+	DeleteEscalationPolicy(ctx context.Context, id ID) error
+}
+
+// Acknowledgement stops the escalation of the statuses of a group notified by
+// a notification rule until the group resolves. Tags identifies the group by
+// the values of the columns the rule groups statuses by.
+type Acknowledgement struct {
+	OrgID              ID                `json:"orgID"`
+	NotificationRuleID ID                `json:"notificationRuleID"`
+	Tags               map[string]string `json:"tags"`
+	// AcknowledgedBy is the user that acknowledged the statuses.
+	AcknowledgedBy ID        `json:"acknowledgedBy,omitempty"`
+	Comment        string    `json:"comment,omitempty"`
+	Time           time.Time `json:"time"`
+}
+
+// Valid returns an error if the acknowledgement does not identify a group of
+// a notification rule.
+func (a *Acknowledgement) Valid() error {
+	if !a.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "acknowledgement requires a valid orgID",
+		}
+	}
+	if !a.NotificationRuleID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "acknowledgement requires a valid notificationRuleID",
+		}
+	}
+	if len(a.Tags) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "acknowledgement requires the tags of the acknowledged group",
+		}
+	}
+	for k, v := range a.Tags {
+		if k == "" || v == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "acknowledgement tags can't have empty keys or values",
+			}
+		}
+	}
+	return nil
+}
+
+// AcknowledgementService records acknowledgements in the monitoring bucket
+// of their organization, where notification rules read them back.
+type AcknowledgementService interface {
+	// Acknowledge records an acknowledgement, at the current time if a.Time
+	// is not set.
+	Acknowledge(ctx context.Context, a *Acknowledgement) error
+}
@@ -0,0 +1,129 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestEscalationPolicyValid(t *testing.T) {
+	step := func(endpointID influxdb.ID, delay time.Duration) influxdb.EscalationStep {
+		return influxdb.EscalationStep{EndpointID: endpointID, Delay: influxdb.Duration{Duration: delay}}
+	}
+	tests := []struct {
+		name    string
+		policy  influxdb.EscalationPolicy
+		wantErr string
+	}{
+		{
+			name: "valid policy",
+			policy: influxdb.EscalationPolicy{
+				OrgID: 1,
+				Name:  "on call",
+				Steps: []influxdb.EscalationStep{step(2, 15*time.Minute), step(3, time.Hour)},
+			},
+		},
+		{
+			name:    "requires an org",
+			policy:  influxdb.EscalationPolicy{Name: "on call", Steps: []influxdb.EscalationStep{step(2, time.Minute)}},
+			wantErr: "escalation policy requires a valid orgID",
+		},
+		{
+			name:    "requires a name",
+			policy:  influxdb.EscalationPolicy{OrgID: 1, Steps: []influxdb.EscalationStep{step(2, time.Minute)}},
+			wantErr: "escalation policy name can't be empty",
+		},
+		{
+			name:    "requires a step",
+			policy:  influxdb.EscalationPolicy{OrgID: 1, Name: "on call"},
+			wantErr: "escalation policy requires at least one step",
+		},
+		{
+			name:    "requires an endpoint",
+			policy:  influxdb.EscalationPolicy{OrgID: 1, Name: "on call", Steps: []influxdb.EscalationStep{step(0, time.Minute)}},
+			wantErr: "escalation step 1 requires a valid endpointID",
+		},
+		{
+			name: "notifies an endpoint twice",
+			policy: influxdb.EscalationPolicy{
+				OrgID: 1,
+				Name:  "on call",
+				Steps: []influxdb.EscalationStep{step(2, time.Minute), step(2, time.Hour)},
+			},
+			wantErr: "escalation step 2 notifies endpoint 0000000000000002 more than once",
+		},
+		{
+			name: "delays do not increase",
+			policy: influxdb.EscalationPolicy{
+				OrgID: 1,
+				Name:  "on call",
+				Steps: []influxdb.EscalationStep{step(2, time.Hour), step(3, time.Minute)},
+			},
+			wantErr: "escalation step 2 must have a delay greater than 1h0m0s",
+		},
+		{
+			name:    "requires a delay",
+			policy:  influxdb.EscalationPolicy{OrgID: 1, Name: "on call", Steps: []influxdb.EscalationStep{step(2, 0)}},
+			wantErr: "escalation step 1 must have a delay greater than 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Valid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Fatalf("unexpected error: got %q want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAcknowledgementValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		ack     influxdb.Acknowledgement
+		wantErr string
+	}{
+		{
+			name: "valid acknowledgement",
+			ack:  influxdb.Acknowledgement{OrgID: 1, NotificationRuleID: 2, Tags: map[string]string{"_check_id": "0000000000000003"}},
+		},
+		{
+			name:    "requires a notification rule",
+			ack:     influxdb.Acknowledgement{OrgID: 1, Tags: map[string]string{"_check_id": "0000000000000003"}},
+			wantErr: "acknowledgement requires a valid notificationRuleID",
+		},
+		{
+			name:    "requires tags",
+			ack:     influxdb.Acknowledgement{OrgID: 1, NotificationRuleID: 2},
+			wantErr: "acknowledgement requires the tags of the acknowledged group",
+		},
+		{
+			name:    "empty tag value",
+			ack:     influxdb.Acknowledgement{OrgID: 1, NotificationRuleID: 2, Tags: map[string]string{"host": ""}},
+			wantErr: "acknowledgement tags can't have empty keys or values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ack.Valid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Fatalf("unexpected error: got %q want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/ratelimit"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -55,10 +56,20 @@ type APIBackend struct {
 
 	AlgoWProxy FeatureProxyHandler
 
+	// LegacyDBRPService resolves the database and retention policy of 1.x
+	// requests. Permissions are checked against the mapped bucket, so it
+	// may be an unauthorized service; DBRPService is used when it is nil.
+	LegacyDBRPService influxdb.DBRPMappingServiceV2
+
+	// RateLimiter enforces the write and query quotas of organizations and
+	// authorizations. Requests are not limited when it is nil.
+	RateLimiter *ratelimit.Limiter
+
 	PointsWriter                    storage.PointsWriter
-	DeleteService                   influxdb.DeleteService
+	DeleteJobService                influxdb.DeleteJobService
 	BackupService                   influxdb.BackupService
 	KVBackupService                 influxdb.KVBackupService
+	RestoreService                  influxdb.RestoreService
 	AuthorizationService            influxdb.AuthorizationService
 	DBRPService                     influxdb.DBRPMappingServiceV2
 	BucketService                   influxdb.BucketService
@@ -79,6 +90,9 @@ type APIBackend struct {
 	FluxService                     query.ProxyQueryService
 	FluxLanguageService             influxdb.FluxLanguageService
 	TaskService                     influxdb.TaskService
+	BackfillService                 influxdb.BackfillService
+	TaskVersionService              influxdb.TaskVersionService
+	TaskDryRunService               influxdb.TaskDryRunService
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -89,6 +103,9 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	SilenceService                  influxdb.SilenceService
+	EscalationPolicyService         influxdb.EscalationPolicyService
+	AcknowledgementService          influxdb.AcknowledgementService
 	Flagger                         feature.Flagger
 	FlagsHandler                    http.Handler
 }
@@ -105,6 +122,10 @@ func (b *APIBackend) PrometheusCollectors() []prometheus.Collector {
 		cs = append(cs, pc.PrometheusCollectors()...)
 	}
 
+	if b.RateLimiter != nil {
+		cs = append(cs, b.RateLimiter.PrometheusCollectors()...)
+	}
+
 	return cs
 }
 
@@ -142,6 +163,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixDashboards, NewDashboardHandler(b.Logger, dashboardBackend))
 
 	deleteBackend := NewDeleteBackend(b.Logger.With(zap.String("handler", "delete")), b)
+	deleteBackend.DeleteJobService = authorizer.NewDeleteJobService(b.DeleteJobService)
 	h.Mount(prefixDelete, NewDeleteHandler(b.Logger, deleteBackend))
 
 	documentBackend := NewDocumentBackend(b.Logger.With(zap.String("handler", "document")), b)
@@ -149,7 +171,11 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixDocuments, NewDocumentHandler(documentBackend))
 
 	fluxBackend := NewFluxBackend(b.Logger.With(zap.String("handler", "query")), b)
-	h.Mount(prefixQuery, NewFluxHandler(b.Logger, fluxBackend))
+	var fluxHandler http.Handler = NewFluxHandler(b.Logger, fluxBackend)
+	if b.RateLimiter != nil {
+		fluxHandler = rateLimitQueries(newRateLimitMiddleware(b), fluxHandler)
+	}
+	h.Mount(prefixQuery, fluxHandler)
 
 	notificationEndpointBackend := NewNotificationEndpointBackend(b.Logger.With(zap.String("handler", "notificationEndpoint")), b)
 	notificationEndpointBackend.NotificationEndpointService = authorizer.NewNotificationEndpointService(b.NotificationEndpointService,
@@ -161,6 +187,23 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 		b.UserResourceMappingService, b.OrganizationService)
 	h.Mount(prefixNotificationRules, NewNotificationRuleHandler(b.Logger, notificationRuleBackend))
 
+	if b.SilenceService != nil {
+		silenceBackend := NewSilenceBackend(b.Logger.With(zap.String("handler", "silence")), b)
+		silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+		h.Mount(prefixSilences, NewSilenceHandler(b.Logger, silenceBackend))
+	}
+
+	if b.EscalationPolicyService != nil && b.AcknowledgementService != nil {
+		escalationPolicyBackend := NewEscalationPolicyBackend(b.Logger.With(zap.String("handler", "escalation_policy")), b)
+		escalationPolicyBackend.EscalationPolicyService = authorizer.NewEscalationPolicyService(b.EscalationPolicyService)
+		escalationPolicyBackend.AcknowledgementService = authorizer.NewAcknowledgementService(b.AcknowledgementService)
+		escalationPolicyBackend.NotificationRuleStore = authorizer.NewNotificationRuleStore(b.NotificationRuleStore,
+			b.UserResourceMappingService, b.OrganizationService)
+		escalationPolicyHandler := NewEscalationPolicyHandler(b.Logger, escalationPolicyBackend)
+		h.Mount(prefixEscalationPolicies, escalationPolicyHandler)
+		h.Mount(prefixAcknowledgements, escalationPolicyHandler)
+	}
+
 	scraperBackend := NewScraperBackend(b.Logger.With(zap.String("handler", "scraper")), b)
 	scraperBackend.ScraperStorageService = authorizer.NewScraperTargetStoreService(b.ScraperTargetStoreService,
 		b.UserResourceMappingService,
@@ -177,6 +220,15 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	taskLogger := b.Logger.With(zap.String("handler", "bucket"))
 	taskBackend := NewTaskBackend(taskLogger, b)
 	taskBackend.TaskService = authorizer.NewTaskService(taskLogger, b.TaskService)
+	if b.BackfillService != nil {
+		taskBackend.BackfillService = authorizer.NewBackfillService(b.BackfillService)
+	}
+	if b.TaskVersionService != nil {
+		taskBackend.TaskVersionService = authorizer.NewTaskVersionService(b.TaskVersionService)
+	}
+	if b.TaskDryRunService != nil {
+		taskBackend.TaskDryRunService = authorizer.NewTaskDryRunService(b.TaskDryRunService)
+	}
 	taskHandler := NewTaskHandler(b.Logger, taskBackend)
 	h.Mount(prefixTasks, taskHandler)
 
@@ -195,17 +247,25 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	backupBackend.BackupService = authorizer.NewBackupService(backupBackend.BackupService)
 	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
 
+	restoreBackend := NewRestoreBackend(b)
+	restoreBackend.RestoreService = authorizer.NewRestoreService(restoreBackend.RestoreService)
+	h.Mount(prefixRestore, NewRestoreHandler(restoreBackend))
+
 	h.Mount(dbrp.PrefixDBRP, dbrp.NewHTTPHandler(b.Logger, b.DBRPService, b.OrganizationService))
 
 	writeBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "write")), b)
-	h.Mount(prefixWrite, NewWriteHandler(b.Logger, writeBackend,
+	var writeHandler http.Handler = NewWriteHandler(b.Logger, writeBackend,
 		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
 		WithParserOptions(
 			models.WithParserMaxBytes(b.WriteParserMaxBytes),
 			models.WithParserMaxLines(b.WriteParserMaxLines),
 			models.WithParserMaxValues(b.WriteParserMaxValues),
 		),
-	))
+	)
+	if b.RateLimiter != nil {
+		writeHandler = newRateLimitMiddleware(b).Writes()(writeHandler)
+	}
+	h.Mount(prefixWrite, writeHandler)
 
 	for _, o := range opts {
 		o(h)
@@ -216,10 +276,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
-	"authorizations": "/api/v2/authorizations",
-	"backup":         "/api/v2/backup",
-	"buckets":        "/api/v2/buckets",
-	"dashboards":     "/api/v2/dashboards",
+	"acknowledgements":   "/api/v2/acknowledgements",
+	"authorizations":     "/api/v2/authorizations",
+	"backup":             "/api/v2/backup",
+	"buckets":            "/api/v2/buckets",
+	"dashboards":         "/api/v2/dashboards",
+	"escalationPolicies": "/api/v2/escalationPolicies",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -230,6 +292,7 @@ var apiLinks = map[string]interface{}{
 	"notificationRules":     "/api/v2/notificationRules",
 	"notificationEndpoints": "/api/v2/notificationEndpoints",
 	"orgs":                  "/api/v2/orgs",
+	"quotas":                "/api/v2/quotas",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
@@ -239,6 +302,7 @@ var apiLinks = map[string]interface{}{
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
+	"silences": "/api/v2/silences",
 	"sources":  "/api/v2/sources",
 	"scrapers": "/api/v2/scrapers",
 	"swagger":  "/api/v2/swagger.json",
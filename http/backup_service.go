@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -18,6 +19,8 @@ import (
 	"github.com/influxdata/influxdb/v2/bolt"
 	"github.com/influxdata/influxdb/v2/internal/fs"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/snowflake"
+	"github.com/influxdata/influxdb/v2/storage"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
@@ -35,6 +38,8 @@ type BackupBackend struct {
 
 	BackupService   influxdb.BackupService
 	KVBackupService influxdb.KVBackupService
+	BucketService   influxdb.BucketService
+	IDGenerator     influxdb.IDGenerator
 }
 
 // NewBackupBackend returns a new instance of BackupBackend.
@@ -45,6 +50,8 @@ func NewBackupBackend(b *APIBackend) *BackupBackend {
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		BackupService:    b.BackupService,
 		KVBackupService:  b.KVBackupService,
+		BucketService:    b.BucketService,
+		IDGenerator:      snowflake.NewDefaultIDGenerator(),
 	}
 }
 
@@ -56,6 +63,8 @@ type BackupHandler struct {
 
 	BackupService   influxdb.BackupService
 	KVBackupService influxdb.KVBackupService
+	BucketService   influxdb.BucketService
+	IDGenerator     influxdb.IDGenerator
 }
 
 const (
@@ -79,6 +88,8 @@ func NewBackupHandler(b *BackupBackend) *BackupHandler {
 		Logger:           b.Logger,
 		BackupService:    b.BackupService,
 		KVBackupService:  b.KVBackupService,
+		BucketService:    b.BucketService,
+		IDGenerator:      b.IDGenerator,
 	}
 
 	h.HandlerFunc(http.MethodPost, prefixBackup, h.handleCreate)
@@ -87,61 +98,120 @@ func NewBackupHandler(b *BackupBackend) *BackupHandler {
 	return h
 }
 
-type backup struct {
-	ID    int      `json:"id,omitempty"`
-	Files []string `json:"files,omitempty"`
-}
-
 func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreate")
 	defer span.Finish()
 
 	ctx := r.Context()
 
-	id, files, err := h.BackupService.CreateBackup(ctx)
+	filter, err := decodeBackupFilter(r)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	internalBackupPath := h.BackupService.InternalBackupPath(id)
+	buckets, err := h.findBackupBuckets(ctx, &filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
 
-	boltPath := filepath.Join(internalBackupPath, bolt.DefaultFilename)
-	boltFile, err := os.OpenFile(boltPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+	manifest, err := h.BackupService.CreateBackup(ctx, filter)
 	if err != nil {
-		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	manifest.ID = h.IDGenerator.ID()
+	manifest.Buckets = buckets
 
-	if err = h.KVBackupService.Backup(ctx, boltFile); err != nil {
+	internalBackupPath := h.BackupService.InternalBackupPath(manifest.BackupID)
+
+	if !filter.Partial() {
+		if err := h.backupMetadata(ctx, internalBackupPath, manifest); err != nil {
+			err = multierr.Append(err, os.RemoveAll(internalBackupPath))
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	if err = json.NewEncoder(w).Encode(manifest); err != nil {
 		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+}
 
-	files = append(files, bolt.DefaultFilename)
+// decodeBackupFilter reads the optional filter of a backup request.
+func decodeBackupFilter(r *http.Request) (influxdb.BackupFilter, error) {
+	var filter influxdb.BackupFilter
+	if r.Body == nil {
+		return filter, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil && err != io.EOF {
+		return filter, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid backup filter",
+			Err:  err,
+		}
+	}
+	return filter, nil
+}
 
-	credsExist, err := h.backupCredentials(internalBackupPath)
+// findBackupBuckets returns the buckets included in the backup. The
+// organization of filter is set from its bucket when missing.
+func (h *BackupHandler) findBackupBuckets(ctx context.Context, filter *influxdb.BackupFilter) ([]*influxdb.Bucket, error) {
+	if filter.BucketID != nil {
+		b, err := h.BucketService.FindBucketByID(ctx, *filter.BucketID)
+		if err != nil {
+			return nil, err
+		}
+		if filter.OrgID != nil && *filter.OrgID != b.OrgID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "bucket does not belong to the organization of the backup",
+			}
+		}
+		filter.OrgID = &b.OrgID
+		return []*influxdb.Bucket{b}, nil
+	}
+	bs, _, err := h.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: filter.OrgID})
+	return bs, err
+}
 
+// backupMetadata adds the metadata database and the CLI configs to the backup.
+func (h *BackupHandler) backupMetadata(ctx context.Context, internalBackupPath string, manifest *influxdb.BackupManifest) error {
+	boltPath := filepath.Join(internalBackupPath, bolt.DefaultFilename)
+	boltFile, err := os.OpenFile(boltPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
 	if err != nil {
-		h.HandleHTTPError(ctx, err, w)
-		return
+		return err
 	}
 
-	if credsExist {
-		files = append(files, DefaultConfigsFile)
+	if err = h.KVBackupService.Backup(ctx, boltFile); err != nil {
+		return multierr.Append(err, boltFile.Close())
+	}
+	if err = boltFile.Close(); err != nil {
+		return err
 	}
 
-	b := backup{
-		ID:    id,
-		Files: files,
+	kv, err := storage.NewBackupManifestFile(boltPath)
+	if err != nil {
+		return err
 	}
-	if err = json.NewEncoder(w).Encode(&b); err != nil {
-		err = multierr.Append(err, os.RemoveAll(internalBackupPath))
-		h.HandleHTTPError(ctx, err, w)
-		return
+	manifest.KV = &kv
+
+	credsExist, err := h.backupCredentials(internalBackupPath)
+	if err != nil {
+		return err
+	}
+
+	if credsExist {
+		configs, err := storage.NewBackupManifestFile(filepath.Join(internalBackupPath, DefaultConfigsFile))
+		if err != nil {
+			return err
+		}
+		manifest.Configs = &configs
 	}
+	return nil
 }
 
 func (h *BackupHandler) backupCredentials(internalBackupPath string) (bool, error) {
@@ -191,19 +261,26 @@ type BackupService struct {
 	InsecureSkipVerify bool
 }
 
-func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error) {
+// CreateBackup creates a backup of the data matching filter on the server.
+func (s *BackupService) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.BackupManifest, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
 	u, err := NewURL(s.Addr, prefixBackup)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	body, err := json.Marshal(filter)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
 	SetToken(s.Token, req)
 	req = req.WithContext(ctx)
 
@@ -211,20 +288,19 @@ func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error)
 	hc.Timeout = httpClientTimeout
 	resp, err := hc.Do(req)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if err := CheckError(resp); err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
-	var b backup
-	if err = json.NewDecoder(resp.Body).Decode(&b); err != nil {
-		return 0, nil, err
+	var m influxdb.BackupManifest
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, err
 	}
-
-	return b.ID, b.Files, nil
+	return &m, nil
 }
 
 func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
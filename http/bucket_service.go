@@ -130,13 +130,14 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Type                string          `json:"type"`
-	Description         string          `json:"description,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                  influxdb.ID                 `json:"id,omitempty"`
+	OrgID               influxdb.ID                 `json:"orgID,omitempty"`
+	Type                string                      `json:"type"`
+	Description         string                      `json:"description,omitempty"`
+	Name                string                      `json:"name"`
+	RetentionPolicyName string                      `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule             `json:"retentionRules"`
+	DownsamplePolicies  []influxdb.DownsamplePolicy `json:"downsamplePolicies,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -184,6 +185,7 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		DownsamplePolicies:  b.DownsamplePolicies,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -210,6 +212,7 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		DownsamplePolicies:  pb.DownsamplePolicies,
 		CRUDLog:             pb.CRUDLog,
 	}
 }
@@ -219,6 +222,8 @@ type bucketUpdate struct {
 	Name           *string         `json:"name,omitempty"`
 	Description    *string         `json:"description,omitempty"`
 	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+
+	DownsamplePolicies *[]influxdb.DownsamplePolicy `json:"downsamplePolicies,omitempty"`
 }
 
 func (b *bucketUpdate) OK() error {
@@ -243,9 +248,10 @@ func (b *bucketUpdate) toInfluxDB() *influxdb.BucketUpdate {
 	}
 
 	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
+		Name:               b.Name,
+		Description:        b.Description,
+		RetentionPeriod:    &d,
+		DownsamplePolicies: b.DownsamplePolicies,
 	}
 }
 
@@ -255,9 +261,10 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:               pb.Name,
+		Description:        pb.Description,
+		RetentionRules:     []retentionRule{},
+		DownsamplePolicies: pb.DownsamplePolicies,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -334,11 +341,12 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 }
 
 type postBucketRequest struct {
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Name                string          `json:"name"`
-	Description         string          `json:"description"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	OrgID               influxdb.ID                 `json:"orgID,omitempty"`
+	Name                string                      `json:"name"`
+	Description         string                      `json:"description"`
+	RetentionPolicyName string                      `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule             `json:"retentionRules"`
+	DownsamplePolicies  []influxdb.DownsamplePolicy `json:"downsamplePolicies,omitempty"`
 }
 
 func (b *postBucketRequest) OK() error {
@@ -384,6 +392,7 @@ func (b postBucketRequest) toInfluxDB() *influxdb.Bucket {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
+		DownsamplePolicies:  b.DownsamplePolicies,
 	}
 }
 
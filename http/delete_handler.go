@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	http "net/http"
+	"net/url"
+	"path"
 	"time"
 
 	"github.com/influxdata/httprouter"
@@ -22,7 +24,7 @@ type DeleteBackend struct {
 	log *zap.Logger
 	influxdb.HTTPErrorHandler
 
-	DeleteService       influxdb.DeleteService
+	DeleteJobService    influxdb.DeleteJobService
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
 }
@@ -33,26 +35,30 @@ func NewDeleteBackend(log *zap.Logger, b *APIBackend) *DeleteBackend {
 		log: log,
 
 		HTTPErrorHandler:    b.HTTPErrorHandler,
-		DeleteService:       b.DeleteService,
+		DeleteJobService:    b.DeleteJobService,
 		BucketService:       b.BucketService,
 		OrganizationService: b.OrganizationService,
 	}
 }
 
-// DeleteHandler receives a delete request with a predicate and sends it to storage.
+// DeleteHandler receives a delete request with a predicate and queues it as a
+// delete job run in the background by storage.
 type DeleteHandler struct {
 	influxdb.HTTPErrorHandler
 	*httprouter.Router
 
 	log *zap.Logger
 
-	DeleteService       influxdb.DeleteService
+	DeleteJobService    influxdb.DeleteJobService
 	BucketService       influxdb.BucketService
 	OrganizationService influxdb.OrganizationService
 }
 
 const (
-	prefixDelete = "/api/v2/delete"
+	prefixDelete      = "/api/v2/delete"
+	deleteJobsPath    = "/api/v2/delete/jobs"
+	deleteJobsIDPath  = "/api/v2/delete/jobs/:id"
+	deleteJobStatusQP = "status"
 )
 
 // NewDeleteHandler creates a new handler at /api/v2/delete to recieve delete requests.
@@ -63,11 +69,14 @@ func NewDeleteHandler(log *zap.Logger, b *DeleteBackend) *DeleteHandler {
 		log:              log,
 
 		BucketService:       b.BucketService,
-		DeleteService:       b.DeleteService,
+		DeleteJobService:    b.DeleteJobService,
 		OrganizationService: b.OrganizationService,
 	}
 
 	h.HandlerFunc("POST", prefixDelete, h.handleDelete)
+	h.HandlerFunc("GET", deleteJobsPath, h.handleGetDeleteJobs)
+	h.HandlerFunc("GET", deleteJobsIDPath, h.handleGetDeleteJob)
+	h.HandlerFunc("DELETE", deleteJobsIDPath, h.handleCancelDeleteJob)
 	return h
 }
 
@@ -114,26 +123,147 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// send delete points request to storage
-	err = h.DeleteService.DeleteBucketRangePredicate(ctx,
-		dr.Org.ID,
-		dr.Bucket.ID,
-		dr.Start,
-		dr.Stop,
-		dr.Predicate,
-	)
-	if err != nil {
+	// queue the delete, it is sent to storage by the delete job worker
+	job := &influxdb.DeleteJob{
+		OrgID:     dr.Org.ID,
+		BucketID:  dr.Bucket.ID,
+		Start:     time.Unix(0, dr.Start).UTC(),
+		Stop:      time.Unix(0, dr.Stop).UTC(),
+		Predicate: dr.Expr,
+	}
+	if err := h.DeleteJobService.CreateDeleteJob(ctx, job); err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
-	h.log.Debug("Deleted",
+	h.log.Debug("Delete job created",
+		zap.String("jobID", job.ID.String()),
 		zap.String("orgID", fmt.Sprint(dr.Org.ID.String())),
 		zap.String("buketID", fmt.Sprint(dr.Bucket.ID.String())),
 	)
 
+	if err := encodeResponse(ctx, w, http.StatusAccepted, job); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type deleteJobsResponse struct {
+	Jobs []*influxdb.DeleteJob `json:"jobs"`
+}
+
+func (h *DeleteHandler) handleGetDeleteJobs(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DeleteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	filter, err := decodeDeleteJobFilter(ctx, r, h.OrganizationService, h.BucketService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	jobs, err := h.DeleteJobService.FindDeleteJobs(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, deleteJobsResponse{Jobs: jobs}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *DeleteHandler) handleGetDeleteJob(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DeleteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	job, err := h.DeleteJobService.FindDeleteJobByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, job); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *DeleteHandler) handleCancelDeleteJob(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "DeleteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.DeleteJobService.CancelDeleteJob(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Delete job canceled", zap.String("jobID", id.String()))
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// decodeDeleteJobFilter decodes the optional organization, bucket and status
+// query parameters of a request listing delete jobs.
+func decodeDeleteJobFilter(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService, bucketSvc influxdb.BucketService) (influxdb.DeleteJobFilter, error) {
+	var filter influxdb.DeleteJobFilter
+	qp := r.URL.Query()
+
+	if qp.Get(Org) != "" || qp.Get(OrgID) != "" {
+		org, err := queryOrganization(ctx, r, orgSvc)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &org.ID
+	}
+
+	if qp.Get(Bucket) != "" || qp.Get(BucketID) != "" {
+		if filter.OrgID == nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Please provide either orgID or org to filter by bucket",
+			}
+		}
+		b, err := queryBucket(ctx, *filter.OrgID, r, bucketSvc)
+		if err != nil {
+			return filter, err
+		}
+		filter.BucketID = &b.ID
+	}
+
+	if s := qp.Get(deleteJobStatusQP); s != "" {
+		status := influxdb.DeleteJobStatus(s)
+		switch status {
+		case influxdb.DeleteJobQueued, influxdb.DeleteJobRunning, influxdb.DeleteJobCanceling,
+			influxdb.DeleteJobSuccess, influxdb.DeleteJobFailed, influxdb.DeleteJobCanceled:
+		default:
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid delete job status %q", s),
+			}
+		}
+		filter.Status = &status
+	}
+	return filter, nil
+}
+
 func decodeDeleteRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService, bucketSvc influxdb.BucketService) (*deleteRequest, error) {
 	dr := new(deleteRequest)
 	err := json.NewDecoder(r.Body).Decode(dr)
@@ -160,6 +290,8 @@ type deleteRequest struct {
 	Start     int64
 	Stop      int64
 	Predicate influxdb.Predicate
+	// Expr is the predicate as sent by the client.
+	Expr string
 }
 
 type deleteRequestDecode struct {
@@ -208,6 +340,7 @@ func (dr *deleteRequest) UnmarshalJSON(b []byte) error {
 		}
 	}
 	dr.Stop = stop.UnixNano()
+	dr.Expr = drd.Predicate
 	node, err := predicate.Parse(drd.Predicate)
 	if err != nil {
 		return err
@@ -224,44 +357,122 @@ type DeleteService struct {
 }
 
 // DeleteBucketRangePredicate send delete request over http to delete points.
-func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, dr DeleteRequest) error {
+// The delete runs in the background as the returned delete job.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, dr DeleteRequest) (*influxdb.DeleteJob, error) {
 	u, err := NewURL(s.Addr, prefixDelete)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	buf := new(bytes.Buffer)
 	if err := json.NewEncoder(buf).Encode(dr); err != nil {
-		return err
+		return nil, err
 	}
 	req, err := http.NewRequest("POST", u.String(), buf)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	SetToken(s.Token, req)
 
-	params := req.URL.Query()
-	if dr.OrgID != "" {
-		params.Set("orgID", dr.OrgID)
-	} else if dr.Org != "" {
-		params.Set("org", dr.Org)
+	req.URL.RawQuery = deleteRequestParams(req.URL.Query(), dr).Encode()
+
+	var job influxdb.DeleteJob
+	if err := s.do(ctx, req, &job); err != nil {
+		return nil, err
 	}
+	return &job, nil
+}
 
-	if dr.BucketID != "" {
-		params.Set("bucketID", dr.BucketID)
-	} else if dr.Bucket != "" {
-		params.Set("bucket", dr.Bucket)
+// FindDeleteJobs returns the delete jobs of the organization and bucket of dr,
+// if any, with the given status, if any.
+func (s *DeleteService) FindDeleteJobs(ctx context.Context, dr DeleteRequest, status string) ([]*influxdb.DeleteJob, error) {
+	u, err := NewURL(s.Addr, deleteJobsPath)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	params := deleteRequestParams(req.URL.Query(), dr)
+	if status != "" {
+		params.Set(deleteJobStatusQP, status)
 	}
 	req.URL.RawQuery = params.Encode()
 
-	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	var resp deleteJobsResponse
+	if err := s.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+// FindDeleteJobByID returns a single delete job by ID.
+func (s *DeleteService) FindDeleteJobByID(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+	u, err := NewURL(s.Addr, path.Join(deleteJobsPath, id.String()))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	var job influxdb.DeleteJob
+	if err := s.do(ctx, req, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
 
-	resp, err := hc.Do(req)
+// CancelDeleteJob cancels a queued or running delete job.
+func (s *DeleteService) CancelDeleteJob(ctx context.Context, id influxdb.ID) error {
+	u, err := NewURL(s.Addr, path.Join(deleteJobsPath, id.String()))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	return s.do(ctx, req, nil)
+}
+
+func (s *DeleteService) do(ctx context.Context, req *http.Request, v interface{}) error {
+	hc := NewClient(req.URL.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req.WithContext(ctx))
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	return CheckError(resp)
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func deleteRequestParams(params url.Values, dr DeleteRequest) url.Values {
+	if dr.OrgID != "" {
+		params.Set("orgID", dr.OrgID)
+	} else if dr.Org != "" {
+		params.Set("org", dr.Org)
+	}
+
+	if dr.BucketID != "" {
+		params.Set("bucketID", dr.BucketID)
+	} else if dr.Bucket != "" {
+		params.Set("bucket", dr.Bucket)
+	}
+	return params
 }
@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	pcontext "github.com/influxdata/influxdb/v2/context"
@@ -21,15 +23,27 @@ func NewMockDeleteBackend(t *testing.T) *DeleteBackend {
 	return &DeleteBackend{
 		log: zaptest.NewLogger(t),
 
-		DeleteService:       mock.NewDeleteService(),
+		DeleteJobService:    mock.NewDeleteJobService(),
 		BucketService:       mock.NewBucketService(),
 		OrganizationService: mock.NewOrganizationService(),
 	}
 }
 
+// newDeleteJobService returns a mock DeleteJobService queuing jobs with ID 3.
+func newDeleteJobService() *mock.DeleteJobService {
+	s := mock.NewDeleteJobService()
+	s.CreateDeleteJobF = func(ctx context.Context, job *influxdb.DeleteJob) error {
+		job.ID = 3
+		job.Status = influxdb.DeleteJobQueued
+		job.CreatedAt = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		return nil
+	}
+	return s
+}
+
 func TestDelete(t *testing.T) {
 	type fields struct {
-		DeleteService       influxdb.DeleteService
+		DeleteJobService    influxdb.DeleteJobService
 		OrganizationService influxdb.OrganizationService
 		BucketService       influxdb.BucketService
 	}
@@ -207,7 +221,7 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			fields: fields{
-				DeleteService: mock.NewDeleteService(),
+				DeleteJobService: newDeleteJobService(),
 				BucketService: &mock.BucketService{
 					FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
 						return &influxdb.Bucket{
@@ -226,12 +240,21 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			wants: wants{
-				statusCode: http.StatusNoContent,
-				body:       ``,
+				statusCode: http.StatusAccepted,
+				body: `{
+					"id": "0000000000000003",
+					"orgID": "0000000000000001",
+					"bucketID": "0000000000000002",
+					"start": "2009-01-01T23:00:00Z",
+					"stop": "2019-11-10T01:00:00Z",
+					"status": "queued",
+					"progress": {"filesScanned": 0, "filesRewritten": 0, "seriesRemoved": 0},
+					"createdAt": "2020-06-01T00:00:00Z"
+				}`,
 			},
 		},
 		{
-			name: "unsupported delete",
+			name: "invalid regex delete",
 			args: args{
 				queryParams: map[string][]string{
 					"org":    []string{"org1"},
@@ -240,7 +263,7 @@ func TestDelete(t *testing.T) {
 				body: []byte(`{
 					"start":"2009-01-01T23:00:00Z",
 					"stop":"2019-11-10T01:00:00Z",
-					"predicate": "tag1=\"v1\" and (tag2=\"v2\" or tag3=\"v3\")"
+					"predicate": "tag1=\"v1\" and (tag2=~/v(2/ or tag3=\"v3\")"
 				}`),
 				authorizer: &influxdb.Authorization{
 					UserID: user1ID,
@@ -258,7 +281,7 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			fields: fields{
-				DeleteService: mock.NewDeleteService(),
+				DeleteJobService: newDeleteJobService(),
 				BucketService: &mock.BucketService{
 					FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
 						return &influxdb.Bucket{
@@ -280,7 +303,7 @@ func TestDelete(t *testing.T) {
 				statusCode: http.StatusBadRequest,
 				body: `{
 					"code": "invalid",
-					"message": "invalid request; error parsing request json: the logical operator OR is not supported yet at position 25"
+					"message": "invalid request; error parsing request json: bad regex /v(2/, at position 20: error parsing regexp: missing closing ): ` + "`v(2`" + `"
 				  }`,
 			},
 		},
@@ -294,7 +317,7 @@ func TestDelete(t *testing.T) {
 				body: []byte(`{
 					"start":"2009-01-01T23:00:00Z",
 					"stop":"2019-11-10T01:00:00Z",
-					"predicate": "tag1=\"v1\" and (tag2=\"v2\" and tag3=\"v3\")"
+					"predicate": "tag1=\"v1\" and (tag2=\"v2\" or tag3=~/v3/ or _field!=\"f1\")"
 				}`),
 				authorizer: &influxdb.Authorization{
 					UserID: user1ID,
@@ -312,7 +335,7 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			fields: fields{
-				DeleteService: mock.NewDeleteService(),
+				DeleteJobService: newDeleteJobService(),
 				BucketService: &mock.BucketService{
 					FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
 						return &influxdb.Bucket{
@@ -331,8 +354,18 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			wants: wants{
-				statusCode: http.StatusNoContent,
-				body:       ``,
+				statusCode: http.StatusAccepted,
+				body: `{
+					"id": "0000000000000003",
+					"orgID": "0000000000000001",
+					"bucketID": "0000000000000002",
+					"start": "2009-01-01T23:00:00Z",
+					"stop": "2019-11-10T01:00:00Z",
+					"predicate": "tag1=\"v1\" and (tag2=\"v2\" or tag3=~/v3/ or _field!=\"f1\")",
+					"status": "queued",
+					"progress": {"filesScanned": 0, "filesRewritten": 0, "seriesRemoved": 0},
+					"createdAt": "2020-06-01T00:00:00Z"
+				}`,
 			},
 		},
 	}
@@ -340,7 +373,7 @@ func TestDelete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			deleteBackend := NewMockDeleteBackend(t)
 			deleteBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
-			deleteBackend.DeleteService = tt.fields.DeleteService
+			deleteBackend.DeleteJobService = tt.fields.DeleteJobService
 			deleteBackend.OrganizationService = tt.fields.OrganizationService
 			deleteBackend.BucketService = tt.fields.BucketService
 			h := NewDeleteHandler(zaptest.NewLogger(t), deleteBackend)
@@ -380,3 +413,146 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteJobs(t *testing.T) {
+	job := &influxdb.DeleteJob{
+		ID:        3,
+		OrgID:     1,
+		BucketID:  2,
+		Start:     time.Date(2009, 1, 1, 23, 0, 0, 0, time.UTC),
+		Stop:      time.Date(2019, 11, 10, 1, 0, 0, 0, time.UTC),
+		Status:    influxdb.DeleteJobRunning,
+		Progress:  influxdb.DeleteProgress{FilesScanned: 4, FilesRewritten: 2, SeriesRemoved: 1},
+		CreatedAt: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	jobJSON := `{
+		"id": "0000000000000003",
+		"orgID": "0000000000000001",
+		"bucketID": "0000000000000002",
+		"start": "2009-01-01T23:00:00Z",
+		"stop": "2019-11-10T01:00:00Z",
+		"status": "running",
+		"progress": {"filesScanned": 4, "filesRewritten": 2, "seriesRemoved": 1},
+		"createdAt": "2020-06-01T00:00:00Z"
+	}`
+
+	var (
+		gotFilter   influxdb.DeleteJobFilter
+		canceledIDs []influxdb.ID
+	)
+	jobSvc := &mock.DeleteJobService{
+		FindDeleteJobsF: func(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+			gotFilter = filter
+			return []*influxdb.DeleteJob{job}, nil
+		},
+		FindDeleteJobByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+			if id != job.ID {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "delete job not found"}
+			}
+			return job, nil
+		},
+		CancelDeleteJobF: func(ctx context.Context, id influxdb.ID) error {
+			canceledIDs = append(canceledIDs, id)
+			return nil
+		},
+	}
+
+	deleteBackend := NewMockDeleteBackend(t)
+	deleteBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	deleteBackend.DeleteJobService = jobSvc
+	deleteBackend.OrganizationService = &mock.OrganizationService{
+		FindOrganizationF: func(ctx context.Context, f influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+			return &influxdb.Organization{ID: 1, Name: "org1"}, nil
+		},
+	}
+	deleteBackend.BucketService = &mock.BucketService{
+		FindBucketFn: func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+			return &influxdb.Bucket{ID: 2, OrgID: 1, Name: "bucket1"}, nil
+		},
+	}
+	h := NewDeleteHandler(zaptest.NewLogger(t), deleteBackend)
+
+	running := influxdb.DeleteJobRunning
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		body       string
+		filter     *influxdb.DeleteJobFilter
+	}{
+		{
+			name:       "list jobs of bucket",
+			method:     "GET",
+			path:       "/api/v2/delete/jobs?org=org1&bucket=bucket1&status=running",
+			statusCode: http.StatusOK,
+			body:       `{"jobs": [` + jobJSON + `]}`,
+			filter:     &influxdb.DeleteJobFilter{OrgID: &orgID, BucketID: &bucketID, Status: &running},
+		},
+		{
+			name:       "list jobs of bucket without org",
+			method:     "GET",
+			path:       "/api/v2/delete/jobs?bucket=bucket1",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code": "invalid", "message": "Please provide either orgID or org to filter by bucket"}`,
+		},
+		{
+			name:       "list jobs with invalid status",
+			method:     "GET",
+			path:       "/api/v2/delete/jobs?status=done",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code": "invalid", "message": "invalid delete job status \"done\""}`,
+		},
+		{
+			name:       "get job",
+			method:     "GET",
+			path:       "/api/v2/delete/jobs/0000000000000003",
+			statusCode: http.StatusOK,
+			body:       jobJSON,
+		},
+		{
+			name:       "get missing job",
+			method:     "GET",
+			path:       "/api/v2/delete/jobs/0000000000000004",
+			statusCode: http.StatusNotFound,
+			body:       `{"code": "not found", "message": "delete job not found"}`,
+		},
+		{
+			name:       "cancel job",
+			method:     "DELETE",
+			path:       "/api/v2/delete/jobs/0000000000000003",
+			statusCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFilter = influxdb.DeleteJobFilter{}
+
+			r := httptest.NewRequest(tt.method, "http://any.tld"+tt.path, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got status %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.body); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("unexpected body ***%s***", diff)
+				}
+			}
+			if tt.filter != nil && !reflect.DeepEqual(gotFilter, *tt.filter) {
+				t.Errorf("got filter %+v, want %+v", gotFilter, *tt.filter)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(canceledIDs, []influxdb.ID{3}) {
+		t.Errorf("got canceled jobs %v, want [3]", canceledIDs)
+	}
+}
@@ -0,0 +1,386 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+// EscalationPolicyBackend is all services and associated parameters required to construct
+// the EscalationPolicyHandler.
+type EscalationPolicyBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	EscalationPolicyService influxdb.EscalationPolicyService
+	AcknowledgementService  influxdb.AcknowledgementService
+	NotificationRuleStore   influxdb.NotificationRuleStore
+	OrganizationService     influxdb.OrganizationService
+}
+
+// NewEscalationPolicyBackend returns a new instance of EscalationPolicyBackend.
+func NewEscalationPolicyBackend(log *zap.Logger, b *APIBackend) *EscalationPolicyBackend {
+	return &EscalationPolicyBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		EscalationPolicyService: b.EscalationPolicyService,
+		AcknowledgementService:  b.AcknowledgementService,
+		NotificationRuleStore:   b.NotificationRuleStore,
+		OrganizationService:     b.OrganizationService,
+	}
+}
+
+// EscalationPolicyHandler is the handler for the escalation policies of
+// notification rules and the acknowledgements stopping their escalation.
+type EscalationPolicyHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	EscalationPolicyService influxdb.EscalationPolicyService
+	AcknowledgementService  influxdb.AcknowledgementService
+	NotificationRuleStore   influxdb.NotificationRuleStore
+	OrganizationService     influxdb.OrganizationService
+}
+
+const (
+	prefixEscalationPolicies = "/api/v2/escalationPolicies"
+	escalationPoliciesIDPath = "/api/v2/escalationPolicies/:id"
+	prefixAcknowledgements   = "/api/v2/acknowledgements"
+)
+
+// NewEscalationPolicyHandler returns a new instance of EscalationPolicyHandler.
+func NewEscalationPolicyHandler(log *zap.Logger, b *EscalationPolicyBackend) *EscalationPolicyHandler {
+	h := &EscalationPolicyHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		EscalationPolicyService: b.EscalationPolicyService,
+		AcknowledgementService:  b.AcknowledgementService,
+		NotificationRuleStore:   b.NotificationRuleStore,
+		OrganizationService:     b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", prefixEscalationPolicies, h.handlePostEscalationPolicy)
+	h.HandlerFunc("GET", prefixEscalationPolicies, h.handleGetEscalationPolicies)
+	h.HandlerFunc("GET", escalationPoliciesIDPath, h.handleGetEscalationPolicy)
+	h.HandlerFunc("PATCH", escalationPoliciesIDPath, h.handlePatchEscalationPolicy)
+	h.HandlerFunc("DELETE", escalationPoliciesIDPath, h.handleDeleteEscalationPolicy)
+	h.HandlerFunc("POST", prefixAcknowledgements, h.handlePostAcknowledgement)
+	return h
+}
+
+type escalationPoliciesResponse struct {
+	EscalationPolicies []*influxdb.EscalationPolicy `json:"escalationPolicies"`
+}
+
+// handlePostEscalationPolicy is the HTTP handler for the POST /api/v2/escalationPolicies route.
+func (h *EscalationPolicyHandler) handlePostEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	var p influxdb.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.EscalationPolicyService.CreateEscalationPolicy(ctx, &p); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Escalation policy created", zap.String("escalationPolicyID", p.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, &p); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetEscalationPolicies is the HTTP handler for the GET /api/v2/escalationPolicies route.
+func (h *EscalationPolicyHandler) handleGetEscalationPolicies(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	filter, err := decodeEscalationPolicyFilter(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ps, err := h.EscalationPolicyService.FindEscalationPolicies(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	resp := escalationPoliciesResponse{EscalationPolicies: []*influxdb.EscalationPolicy{}}
+	resp.EscalationPolicies = append(resp.EscalationPolicies, ps...)
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetEscalationPolicy is the HTTP handler for the GET /api/v2/escalationPolicies/:id route.
+func (h *EscalationPolicyHandler) handleGetEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	p, err := h.EscalationPolicyService.FindEscalationPolicyByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, p); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePatchEscalationPolicy is the HTTP handler for the PATCH /api/v2/escalationPolicies/:id route.
+func (h *EscalationPolicyHandler) handlePatchEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.EscalationPolicyUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	p, err := h.EscalationPolicyService.UpdateEscalationPolicy(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Escalation policy updated", zap.String("escalationPolicyID", p.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, p); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteEscalationPolicy is the HTTP handler for the DELETE /api/v2/escalationPolicies/:id route.
+func (h *EscalationPolicyHandler) handleDeleteEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.EscalationPolicyService.DeleteEscalationPolicy(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Escalation policy deleted", zap.String("escalationPolicyID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePostAcknowledgement is the HTTP handler for the POST /api/v2/acknowledgements route.
+// The acknowledgement is recorded in the organization of its notification
+// rule on behalf of the user of the request.
+func (h *EscalationPolicyHandler) handlePostAcknowledgement(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "EscalationPolicyHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	var a influxdb.Acknowledgement
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, a.NotificationRuleID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	a.OrgID = nr.GetOrgID()
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	a.AcknowledgedBy = auth.GetUserID()
+
+	if err := h.AcknowledgementService.Acknowledge(ctx, &a); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Notification rule acknowledged", zap.String("notificationRuleID", a.NotificationRuleID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, &a); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeEscalationPolicyFilter decodes the optional organization query
+// parameters of a request listing escalation policies.
+func decodeEscalationPolicyFilter(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (influxdb.EscalationPolicyFilter, error) {
+	var filter influxdb.EscalationPolicyFilter
+	qp := r.URL.Query()
+
+	if qp.Get(Org) != "" || qp.Get(OrgID) != "" {
+		o, err := queryOrganization(ctx, r, orgSvc)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	}
+	return filter, nil
+}
+
+// EscalationPolicyService connects to Influx via HTTP using tokens to manage escalation policies.
+type EscalationPolicyService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.EscalationPolicyService = (*EscalationPolicyService)(nil)
+
+// FindEscalationPolicyByID returns a single escalation policy by ID.
+func (s *EscalationPolicyService) FindEscalationPolicyByID(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var p influxdb.EscalationPolicy
+	err := s.Client.
+		Get(prefixEscalationPolicies, id.String()).
+		DecodeJSON(&p).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindEscalationPolicies returns the escalation policies matching filter.
+func (s *EscalationPolicyService) FindEscalationPolicies(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{OrgID, filter.OrgID.String()})
+	}
+
+	var resp escalationPoliciesResponse
+	err := s.Client.
+		Get(prefixEscalationPolicies).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.EscalationPolicies, nil
+}
+
+// CreateEscalationPolicy creates an escalation policy and sets p.ID with the new identifier.
+func (s *EscalationPolicyService) CreateEscalationPolicy(ctx context.Context, p *influxdb.EscalationPolicy) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		PostJSON(p, prefixEscalationPolicies).
+		DecodeJSON(p).
+		Do(ctx)
+}
+
+// UpdateEscalationPolicy updates an escalation policy and returns the updated escalation policy.
+func (s *EscalationPolicyService) UpdateEscalationPolicy(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var p influxdb.EscalationPolicy
+	err := s.Client.
+		PatchJSON(upd, prefixEscalationPolicies, id.String()).
+		DecodeJSON(&p).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeleteEscalationPolicy removes an escalation policy by ID.
+func (s *EscalationPolicyService) DeleteEscalationPolicy(ctx context.Context, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(prefixEscalationPolicies, id.String()).
+		Do(ctx)
+}
+
+// AcknowledgementService connects to Influx via HTTP using tokens to acknowledge
+// the statuses of notification rules.
+type AcknowledgementService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.AcknowledgementService = (*AcknowledgementService)(nil)
+
+// Acknowledge records an acknowledgement, its organization and user are set
+// by the server.
+func (s *AcknowledgementService) Acknowledge(ctx context.Context, a *influxdb.Acknowledgement) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		PostJSON(a, prefixAcknowledgements).
+		DecodeJSON(a).
+		Do(ctx)
+}
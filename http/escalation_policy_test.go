@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestEscalationPolicyHandler(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := func(id influxdb.ID) *influxdb.EscalationPolicy {
+		return &influxdb.EscalationPolicy{
+			ID:    id,
+			OrgID: 10,
+			Name:  "on call",
+			Steps: []influxdb.EscalationStep{
+				{EndpointID: 2, Delay: influxdb.Duration{Duration: 15 * time.Minute}},
+			},
+			CRUDLog: influxdb.CRUDLog{CreatedAt: createdAt, UpdatedAt: createdAt},
+		}
+	}
+	policyBody := `
+{
+  "id": "0000000000000001",
+  "orgID": "000000000000000a",
+  "name": "on call",
+  "steps": [{"endpointID": "0000000000000002", "delay": "15m0s"}],
+  "createdAt": "2020-06-01T00:00:00Z",
+  "updatedAt": "2020-06-01T00:00:00Z"
+}
+`
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:       "create escalation policy",
+			method:     "POST",
+			path:       "/api/v2/escalationPolicies",
+			body:       `{"orgID": "000000000000000a", "name": "on call", "steps": [{"endpointID": "0000000000000002", "delay": "15m"}]}`,
+			statusCode: http.StatusCreated,
+			respBody:   policyBody,
+		},
+		{
+			name:       "list escalation policies of an org",
+			method:     "GET",
+			path:       "/api/v2/escalationPolicies?orgID=000000000000000a",
+			statusCode: http.StatusOK,
+			respBody:   `{"escalationPolicies": [` + policyBody + `]}`,
+		},
+		{
+			name:       "get escalation policy",
+			method:     "GET",
+			path:       "/api/v2/escalationPolicies/0000000000000001",
+			statusCode: http.StatusOK,
+			respBody:   policyBody,
+		},
+		{
+			name:       "update escalation policy with decreasing delays",
+			method:     "PATCH",
+			path:       "/api/v2/escalationPolicies/0000000000000001",
+			body:       `{"steps": [{"endpointID": "0000000000000002", "delay": "1h"}, {"endpointID": "0000000000000003", "delay": "15m"}]}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "delete escalation policy",
+			method:     "DELETE",
+			path:       "/api/v2/escalationPolicies/0000000000000001",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "acknowledge",
+			method:     "POST",
+			path:       "/api/v2/acknowledgements",
+			body:       `{"notificationRuleID": "0000000000000004", "tags": {"_check_id": "0000000000000005"}, "comment": "on it"}`,
+			statusCode: http.StatusCreated,
+			respBody: `
+{
+  "orgID": "000000000000000a",
+  "notificationRuleID": "0000000000000004",
+  "tags": {"_check_id": "0000000000000005"},
+  "acknowledgedBy": "0000000000000003",
+  "comment": "on it",
+  "time": "2020-06-01T00:00:00Z"
+}
+`,
+		},
+		{
+			name:       "acknowledge a missing notification rule",
+			method:     "POST",
+			path:       "/api/v2/acknowledgements",
+			body:       `{"notificationRuleID": "0000000000000009", "tags": {"_check_id": "0000000000000005"}}`,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := mock.NewEscalationPolicyService()
+			ps.CreateEscalationPolicyF = func(ctx context.Context, p *influxdb.EscalationPolicy) error {
+				if err := p.Valid(); err != nil {
+					return err
+				}
+				*p = *policy(1)
+				return nil
+			}
+			ps.FindEscalationPoliciesF = func(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+				if filter.OrgID == nil || *filter.OrgID != 10 {
+					return nil, fmt.Errorf("unexpected filter %+v", filter)
+				}
+				return []*influxdb.EscalationPolicy{policy(1)}, nil
+			}
+			ps.FindEscalationPolicyByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+				return policy(id), nil
+			}
+			ps.UpdateEscalationPolicyF = func(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+				p := policy(id)
+				upd.Apply(p)
+				if err := p.Valid(); err != nil {
+					return nil, err
+				}
+				return p, nil
+			}
+
+			as := mock.NewAcknowledgementService()
+			as.AcknowledgeF = func(ctx context.Context, a *influxdb.Acknowledgement) error {
+				if a.OrgID != 10 || a.AcknowledgedBy != 3 {
+					return fmt.Errorf("unexpected acknowledgement %+v", a)
+				}
+				a.Time = createdAt
+				return nil
+			}
+
+			rs := mock.NewNotificationRuleStore()
+			rs.FindNotificationRuleByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+				if id != 4 {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "notification rule not found"}
+				}
+				return &rule.Slack{Base: rule.Base{ID: id, OrgID: 10}}, nil
+			}
+
+			b := &EscalationPolicyBackend{
+				HTTPErrorHandler:        kithttp.ErrorHandler(0),
+				log:                     zaptest.NewLogger(t),
+				EscalationPolicyService: ps,
+				AcknowledgementService:  as,
+				NotificationRuleStore:   rs,
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+						return &influxdb.Organization{ID: *filter.ID}, nil
+					},
+				},
+			}
+			h := NewEscalationPolicyHandler(zaptest.NewLogger(t), b)
+
+			r := httptest.NewRequest(tt.method, "http://localhost:9999"+tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pctx.SetAuthorizer(r.Context(), &influxdb.Authorization{UserID: 3}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	platcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+)
+
+// ErrLegacyCredentialsMissing is returned when a 1.x request carries no credentials.
+var ErrLegacyCredentialsMissing = errors.New("unable to parse authentication credentials")
+
+// LegacyAuthenticationHandler is a middleware for authenticating requests made
+// with influxdb 1.x clients. The token of an authorization may be provided
+// with the "Token" authorization scheme, as the password of basic
+// authentication or as the "p" query parameter. The username is accepted for
+// compatibility with 1.x clients but is not used to resolve the authorization.
+type LegacyAuthenticationHandler struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	AuthorizationService influxdb.AuthorizationService
+	UserService          influxdb.UserService
+
+	// This is only really used for it's lookup method the specific http
+	// handler used to register routes does not matter.
+	noAuthRouter *httprouter.Router
+
+	Handler http.Handler
+}
+
+// NewLegacyAuthenticationHandler creates an authentication handler for 1.x requests.
+func NewLegacyAuthenticationHandler(log *zap.Logger, h influxdb.HTTPErrorHandler) *LegacyAuthenticationHandler {
+	return &LegacyAuthenticationHandler{
+		log:              log,
+		HTTPErrorHandler: h,
+		Handler:          http.DefaultServeMux,
+		noAuthRouter:     httprouter.New(),
+	}
+}
+
+// RegisterNoAuthRoute excludes routes from needing authentication.
+func (h *LegacyAuthenticationHandler) RegisterNoAuthRoute(method, path string) {
+	// the handler specified here does not matter.
+	h.noAuthRouter.HandlerFunc(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+// ServeHTTP extracts the 1.x credentials from the http request and places the
+// resulting authorization on the request context.
+func (h *LegacyAuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if handler, _, _ := h.noAuthRouter.Lookup(r.Method, r.URL.Path); handler != nil {
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	auth, err := h.extractAuthorization(ctx, r)
+	if err != nil {
+		h.log.Info("Unauthorized", zap.Error(err))
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization failed",
+		}, w)
+		return
+	}
+
+	if err := h.isUserActive(ctx, auth); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ctx = platcontext.SetAuthorizer(ctx, auth)
+
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span.SetTag("user_id", auth.GetUserID().String())
+	}
+
+	h.Handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (h *LegacyAuthenticationHandler) extractAuthorization(ctx context.Context, r *http.Request) (*influxdb.Authorization, error) {
+	token, err := GetLegacyToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := h.AuthorizationService.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.IsActive() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is inactive",
+		}
+	}
+	return auth, nil
+}
+
+func (h *LegacyAuthenticationHandler) isUserActive(ctx context.Context, auth influxdb.Authorizer) error {
+	if !auth.GetUserID().Valid() {
+		return nil
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, auth.GetUserID())
+	if err != nil {
+		return err
+	}
+
+	if u.Status != "inactive" {
+		return nil
+	}
+
+	return &influxdb.Error{Code: influxdb.EForbidden, Msg: "User is inactive"}
+}
+
+// GetLegacyToken parses the token from an influxdb 1.x request. The token is
+// read from the "Token" authorization scheme, the password of basic
+// authentication or the "p" query parameter, in that order.
+func GetLegacyToken(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if strings.HasPrefix(header, tokenScheme) {
+			return header[len(tokenScheme):], nil
+		}
+		if _, p, ok := r.BasicAuth(); ok && p != "" {
+			return p, nil
+		}
+	}
+
+	if p := r.URL.Query().Get("p"); p != "" {
+		return p, nil
+	}
+	return "", ErrLegacyCredentialsMissing
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/ratelimit"
+	"github.com/influxdata/influxdb/v2/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixLegacyWrite = "/write"
+	prefixLegacyQuery = "/query"
+	prefixLegacyPing  = "/ping"
+
+	legacyVersionHeader = "X-Influxdb-Version"
+	legacyBuildHeader   = "X-Influxdb-Build"
+	legacyErrorHeader   = "X-Influxdb-Error"
+)
+
+// LegacyBackend is all services and associated parameters required to construct
+// the LegacyHandler.
+type LegacyBackend struct {
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	MaxBatchSizeBytes  int64
+	WriteParserOptions []models.ParserOption
+
+	AuthorizationService influxdb.AuthorizationService
+	UserService          influxdb.UserService
+	DBRPMappingService   influxdb.DBRPMappingServiceV2
+	PointsWriter         storage.PointsWriter
+	InfluxQLService      query.ProxyQueryService
+	RateLimiter          *ratelimit.Limiter
+}
+
+// NewLegacyBackend returns a new instance of LegacyBackend.
+func NewLegacyBackend(log *zap.Logger, b *APIBackend) *LegacyBackend {
+	dbrpService := b.LegacyDBRPService
+	if dbrpService == nil {
+		dbrpService = b.DBRPService
+	}
+	return &LegacyBackend{
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		MaxBatchSizeBytes: b.MaxBatchSizeBytes,
+		WriteParserOptions: []models.ParserOption{
+			models.WithParserMaxBytes(b.WriteParserMaxBytes),
+			models.WithParserMaxLines(b.WriteParserMaxLines),
+			models.WithParserMaxValues(b.WriteParserMaxValues),
+		},
+
+		AuthorizationService: b.AuthorizationService,
+		UserService:          b.UserService,
+		DBRPMappingService:   dbrpService,
+		PointsWriter:         b.PointsWriter,
+		InfluxQLService:      b.InfluxQLService,
+		RateLimiter:          b.RateLimiter,
+	}
+}
+
+// LegacyHandler serves the influxdb 1.x compatible /write, /query and /ping
+// endpoints. Databases and retention policies are resolved to buckets
+// through the DBRP mapping service.
+type LegacyHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	authentication *LegacyAuthenticationHandler
+}
+
+// NewLegacyHandler returns a handler for the influxdb 1.x compatible endpoints.
+func NewLegacyHandler(log *zap.Logger, b *LegacyBackend) *LegacyHandler {
+	errorHandler := legacyErrorHandler{}
+	h := &LegacyHandler{
+		Router:           NewRouter(errorHandler),
+		HTTPErrorHandler: errorHandler,
+		log:              log,
+	}
+
+	var writeHandler http.Handler = NewLegacyWriteHandler(log.With(zap.String("handler", "legacy_write")), b)
+	var queryHandler http.Handler = NewLegacyQueryHandler(log.With(zap.String("handler", "legacy_query")), b)
+	if b.RateLimiter != nil {
+		rl := &ratelimit.Middleware{
+			Limiter:      b.RateLimiter,
+			ErrorHandler: errorHandler,
+		}
+		writeHandler = rl.Writes()(writeHandler)
+		queryHandler = rl.Queries()(queryHandler)
+	}
+
+	h.Handler(http.MethodPost, prefixLegacyWrite, writeHandler)
+	h.Handler(http.MethodGet, prefixLegacyQuery, queryHandler)
+	h.Handler(http.MethodPost, prefixLegacyQuery, queryHandler)
+	h.HandlerFunc(http.MethodGet, prefixLegacyPing, h.handlePing)
+	h.HandlerFunc(http.MethodHead, prefixLegacyPing, h.handlePing)
+
+	h.authentication = NewLegacyAuthenticationHandler(log, errorHandler)
+	h.authentication.AuthorizationService = b.AuthorizationService
+	h.authentication.UserService = b.UserService
+	h.authentication.Handler = h.Router
+	h.authentication.RegisterNoAuthRoute(http.MethodGet, prefixLegacyPing)
+	h.authentication.RegisterNoAuthRoute(http.MethodHead, prefixLegacyPing)
+	return h
+}
+
+// ServeHTTP authenticates the request and delegates it to the appropriate endpoint.
+func (h *LegacyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info := influxdb.GetBuildInfo()
+	w.Header().Set(legacyVersionHeader, info.Version)
+	w.Header().Set(legacyBuildHeader, "OSS")
+	h.authentication.ServeHTTP(w, r)
+}
+
+// IsLegacyPath reports whether the path is served by the LegacyHandler.
+func IsLegacyPath(path string) bool {
+	switch path {
+	case prefixLegacyWrite, prefixLegacyQuery, prefixLegacyPing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *LegacyHandler) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// legacyErrorHandler encodes errors in the influxdb 1.x response format.
+type legacyErrorHandler struct{}
+
+// HandleHTTPError writes err as a 1.x error body with a status code derived
+// from the influxdb or flux error code.
+func (legacyErrorHandler) HandleHTTPError(ctx context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		return
+	}
+
+	code := legacyStatusCode(ctx, err)
+	w.Header().Set(kithttp.PlatformErrorCodeHeader, influxdb.ErrorCode(err))
+	w.Header().Set(legacyErrorHeader, err.Error())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+
+	b, _ := json.Marshal(struct {
+		Err string `json:"error"`
+	}{Err: err.Error()})
+	_, _ = w.Write(b)
+}
+
+func legacyStatusCode(ctx context.Context, err error) int {
+	if _, ok := err.(*influxdb.Error); ok {
+		return kithttp.ErrorCodeToStatusCode(ctx, influxdb.ErrorCode(err))
+	}
+
+	switch flux.ErrorCode(err) {
+	case codes.Invalid, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return kithttp.ErrorCodeToStatusCode(ctx, influxdb.EInternal)
+	}
+}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/dbrp/mocks"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/influxql"
+	querymock "github.com/influxdata/influxdb/v2/query/mock"
+	influxtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	legacyTestOrgID    = "043e0780ee2b1000"
+	legacyTestBucketID = "04504b356e23b000"
+	legacyTestToken    = "mytoken"
+)
+
+func newLegacyTestHandler(t *testing.T, auth *influxdb.Authorization, dbrps influxdb.DBRPMappingServiceV2, pw *mock.PointsWriter, qs query.ProxyQueryService) *LegacyHandler {
+	t.Helper()
+
+	auths := mock.NewAuthorizationService()
+	auths.FindAuthorizationByTokenFn = func(ctx context.Context, token string) (*influxdb.Authorization, error) {
+		if token != legacyTestToken {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "authorization not found"}
+		}
+		return auth, nil
+	}
+	users := mock.NewUserService()
+	users.FindUserByIDFn = func(context.Context, influxdb.ID) (*influxdb.User, error) {
+		return &influxdb.User{Status: influxdb.Active}, nil
+	}
+
+	b := &LegacyBackend{
+		log:                  zaptest.NewLogger(t),
+		WriteEventRecorder:   &metric.NopEventRecorder{},
+		QueryEventRecorder:   &metric.NopEventRecorder{},
+		AuthorizationService: auths,
+		UserService:          users,
+		DBRPMappingService:   dbrps,
+		PointsWriter:         pw,
+		InfluxQLService:      qs,
+	}
+	return NewLegacyHandler(zaptest.NewLogger(t), b)
+}
+
+func TestLegacyHandler_handleWrite(t *testing.T) {
+	orgID := influxtesting.MustIDBase16(legacyTestOrgID)
+	bucketID := influxtesting.MustIDBase16(legacyTestBucketID)
+	mapping := &influxdb.DBRPMappingV2{
+		ID:              1,
+		Database:        "telegraf",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  orgID,
+		BucketID:        bucketID,
+	}
+
+	tests := []struct {
+		name     string
+		auth     *influxdb.Authorization
+		mappings []*influxdb.DBRPMappingV2
+		url      string
+		setAuth  func(r *http.Request)
+		body     string
+		code     int
+		respBody string
+		points   int
+	}{
+		{
+			name:     "token authorization",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			mappings: []*influxdb.DBRPMappingV2{mapping},
+			url:      "/write?db=telegraf",
+			setAuth:  func(r *http.Request) { SetToken(legacyTestToken, r) },
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusNoContent,
+			points:   1,
+		},
+		{
+			name:     "basic authorization",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			mappings: []*influxdb.DBRPMappingV2{mapping},
+			url:      "/write?db=telegraf&rp=autogen&precision=s",
+			setAuth:  func(r *http.Request) { r.SetBasicAuth("user", legacyTestToken) },
+			body:     "m1,t1=v1 f1=1 1590000000",
+			code:     http.StatusNoContent,
+			points:   1,
+		},
+		{
+			name:     "query parameter authorization",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			mappings: []*influxdb.DBRPMappingV2{mapping},
+			url:      "/write?db=telegraf&u=user&p=" + legacyTestToken,
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusNoContent,
+			points:   1,
+		},
+		{
+			name:     "invalid token is unauthorized",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			url:      "/write?db=telegraf&u=user&p=invalid",
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusUnauthorized,
+			respBody: `{"error":"authorization failed"}`,
+		},
+		{
+			name:     "missing credentials are unauthorized",
+			url:      "/write?db=telegraf",
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusUnauthorized,
+			respBody: `{"error":"authorization failed"}`,
+		},
+		{
+			name:     "missing database",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			url:      "/write",
+			setAuth:  func(r *http.Request) { SetToken(legacyTestToken, r) },
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusBadRequest,
+			respBody: `{"error":"database is required"}`,
+		},
+		{
+			name:     "unmapped database",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			url:      "/write?db=missing",
+			setAuth:  func(r *http.Request) { SetToken(legacyTestToken, r) },
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusNotFound,
+			respBody: `{"error":"database not found: \"missing\""}`,
+		},
+		{
+			name:     "invalid precision",
+			auth:     bucketWritePermission(legacyTestOrgID, legacyTestBucketID),
+			url:      "/write?db=telegraf&precision=d",
+			setAuth:  func(r *http.Request) { SetToken(legacyTestToken, r) },
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusBadRequest,
+			respBody: `{"error":"invalid precision; valid precision units are n, ns, u, us, ms, and s"}`,
+		},
+		{
+			name:     "forbidden to write with insufficient permission",
+			auth:     bucketWritePermission(legacyTestOrgID, "000000000000000a"),
+			mappings: []*influxdb.DBRPMappingV2{mapping},
+			url:      "/write?db=telegraf",
+			setAuth:  func(r *http.Request) { SetToken(legacyTestToken, r) },
+			body:     "m1,t1=v1 f1=1",
+			code:     http.StatusForbidden,
+			respBody: `{"error":"insufficient permissions for write"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dbrps := mocks.NewMockDBRPMappingServiceV2(ctrl)
+			dbrps.EXPECT().
+				FindMany(gomock.Any(), gomock.Any()).
+				Return(tt.mappings, len(tt.mappings), nil).
+				AnyTimes()
+
+			pw := &mock.PointsWriter{}
+			h := newLegacyTestHandler(t, tt.auth, dbrps, pw, nil)
+
+			r := httptest.NewRequest(http.MethodPost, "http://localhost:9999"+tt.url, strings.NewReader(tt.body))
+			if tt.setAuth != nil {
+				tt.setAuth(r)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.respBody; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+			if got, want := len(pw.Points), tt.points; got != want {
+				t.Errorf("unexpected number of points written: got %d want %d", got, want)
+			}
+			if got := w.Header().Get(legacyVersionHeader); got == "" {
+				t.Errorf("expected %s header to be set", legacyVersionHeader)
+			}
+		})
+	}
+}
+
+func TestLegacyHandler_handleQuery(t *testing.T) {
+	orgID := influxtesting.MustIDBase16(legacyTestOrgID)
+	auth := &influxdb.Authorization{
+		OrgID:  orgID,
+		Status: influxdb.Active,
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		accept      string
+		code        int
+		contentType string
+		respBody    string
+		compiler    *influxql.Compiler
+		dialect     *influxql.Dialect
+	}{
+		{
+			name:        "json query",
+			url:         "/query?db=telegraf&q=SELECT+*+FROM+cpu",
+			code:        http.StatusOK,
+			contentType: "application/json",
+			respBody:    "results",
+			compiler:    &influxql.Compiler{DB: "telegraf", Query: "SELECT * FROM cpu"},
+			dialect:     &influxql.Dialect{Encoding: influxql.JSON},
+		},
+		{
+			name:        "csv query with epoch",
+			url:         "/query?db=telegraf&rp=autogen&epoch=ms&q=SELECT+*+FROM+cpu",
+			accept:      "application/csv",
+			code:        http.StatusOK,
+			contentType: "text/csv",
+			respBody:    "results",
+			compiler:    &influxql.Compiler{DB: "telegraf", RP: "autogen", Query: "SELECT * FROM cpu"},
+			dialect:     &influxql.Dialect{Encoding: influxql.CSV, TimeFormat: influxql.Millisecond},
+		},
+		{
+			name:     "missing query",
+			url:      "/query?db=telegraf",
+			code:     http.StatusBadRequest,
+			respBody: `{"error":"missing required parameter \"q\""}`,
+		},
+		{
+			name:     "invalid query",
+			url:      "/query?db=telegraf&q=SELEC",
+			code:     http.StatusBadRequest,
+			respBody: `{"error":"error parsing query: found SELEC, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1"}`,
+		},
+		{
+			name:     "invalid epoch",
+			url:      "/query?db=telegraf&epoch=d&q=SELECT+*+FROM+cpu",
+			code:     http.StatusBadRequest,
+			respBody: `{"error":"invalid epoch; valid epoch units are h, m, s, ms, u, and ns"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *query.ProxyRequest
+			qs := &querymock.ProxyQueryService{
+				QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+					got = req
+					_, err := io.WriteString(w, "results")
+					return flux.Statistics{}, err
+				},
+			}
+			h := newLegacyTestHandler(t, auth, nil, &mock.PointsWriter{}, qs)
+
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:9999"+tt.url, nil)
+			SetToken(legacyTestToken, r)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.respBody; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+			if tt.compiler == nil {
+				return
+			}
+			if got, want := w.Header().Get("Content-Type"), tt.contentType; got != want {
+				t.Errorf("unexpected content type: got %s want %s", got, want)
+			}
+
+			if got == nil {
+				t.Fatal("expected query to be executed")
+			}
+			if got.Request.OrganizationID != orgID {
+				t.Errorf("unexpected organization: got %s want %s", got.Request.OrganizationID, orgID)
+			}
+			c, ok := got.Request.Compiler.(*influxql.Compiler)
+			if !ok {
+				t.Fatalf("unexpected compiler type: %T", got.Request.Compiler)
+			}
+			if c.DB != tt.compiler.DB || c.RP != tt.compiler.RP || c.Query != tt.compiler.Query {
+				t.Errorf("unexpected compiler: got db=%q rp=%q query=%q", c.DB, c.RP, c.Query)
+			}
+			if d, ok := got.Dialect.(*influxql.Dialect); !ok || *d != *tt.dialect {
+				t.Errorf("unexpected dialect: got %+v want %+v", got.Dialect, tt.dialect)
+			}
+		})
+	}
+}
+
+func TestGetLegacyToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(r *http.Request)
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "token scheme",
+			setup: func(r *http.Request) { SetToken("a", r) },
+			want:  "a",
+		},
+		{
+			name:  "basic authentication",
+			setup: func(r *http.Request) { r.SetBasicAuth("user", "b") },
+			want:  "b",
+		},
+		{
+			name:  "query parameter",
+			setup: func(r *http.Request) { r.URL.RawQuery = "u=user&p=c" },
+			want:  "c",
+		},
+		{
+			name:    "no credentials",
+			setup:   func(r *http.Request) {},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://localhost:9999/query", &bytes.Buffer{})
+			tt.setup(r)
+			got, err := GetLegacyToken(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected token: got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
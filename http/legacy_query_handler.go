@@ -0,0 +1,190 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/dbrp"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/logger"
+	"github.com/influxdata/influxdb/v2/query"
+	transpiler "github.com/influxdata/influxdb/v2/query/influxql"
+	"github.com/influxdata/influxql"
+	"go.uber.org/zap"
+)
+
+const opLegacyQueryHandler = "http/legacyQueryHandler"
+
+// LegacyQueryHandler executes InfluxQL queries sent to the influxdb 1.x
+// /query endpoint. The queries are transpiled to flux using the DBRP
+// mappings of the organization of the authorization.
+type LegacyQueryHandler struct {
+	influxdb.HTTPErrorHandler
+	DBRPMappingService influxdb.DBRPMappingServiceV2
+	ProxyQueryService  query.ProxyQueryService
+	EventRecorder      metric.EventRecorder
+
+	router *httprouter.Router
+	log    *zap.Logger
+}
+
+// NewLegacyQueryHandler returns a new handler at /query for InfluxQL queries.
+func NewLegacyQueryHandler(log *zap.Logger, b *LegacyBackend) *LegacyQueryHandler {
+	errorHandler := legacyErrorHandler{}
+	h := &LegacyQueryHandler{
+		HTTPErrorHandler:   errorHandler,
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.InfluxQLService,
+		EventRecorder:      b.QueryEventRecorder,
+
+		router: NewRouter(errorHandler),
+		log:    log,
+	}
+
+	h.router.HandlerFunc(http.MethodGet, prefixLegacyQuery, h.handleQuery)
+	h.router.HandlerFunc(http.MethodPost, prefixLegacyQuery, h.handleQuery)
+	return h
+}
+
+func (h *LegacyQueryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+func (h *LegacyQueryHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyQueryHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	log := h.log.With(logger.TraceFields(ctx)...)
+	if id, _, found := tracing.InfoFromContext(ctx); found {
+		w.Header().Set(traceIDHeader, id)
+	}
+
+	var orgID influxdb.ID
+	sw := kithttp.NewStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.ResponseBytes(),
+			Status:        sw.Code(),
+		})
+	}()
+
+	auth, err := legacyAuthorization(ctx, opLegacyQueryHandler)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	orgID = auth.OrgID
+
+	req, err := decodeLegacyQueryRequest(r, auth, h.DBRPMappingService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req.Dialect.(HTTPDialect).SetHeaders(w)
+
+	ctx = query.ContextWithPriority(ctx, query.PriorityInteractive)
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		log.Info("Error writing response to client",
+			zap.String("handler", "legacy_query"),
+			zap.Error(err),
+		)
+	}
+}
+
+// decodeLegacyQueryRequest creates a query request with the influxql compiler
+// from the parameters of an influxdb 1.x query.
+func decodeLegacyQueryRequest(r *http.Request, auth *influxdb.Authorization, svc influxdb.DBRPMappingServiceV2) (*query.ProxyRequest, error) {
+	q := strings.TrimSpace(r.FormValue("q"))
+	if q == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opLegacyQueryHandler,
+			Msg:  `missing required parameter "q"`,
+		}
+	}
+
+	// Parse the query ahead of compilation so syntax errors are
+	// reported as invalid requests like influxdb 1.x.
+	if _, err := influxql.ParseQuery(q); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opLegacyQueryHandler,
+			Msg:  "error parsing query: " + err.Error(),
+		}
+	}
+
+	dialect, err := decodeLegacyDialect(r)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := transpiler.NewCompiler(dbrp.NewLegacyService(svc, auth.OrgID))
+	compiler.DB = r.FormValue("db")
+	compiler.RP = r.FormValue("rp")
+	compiler.Query = q
+
+	return &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  auth,
+			OrganizationID: auth.OrgID,
+			Compiler:       compiler,
+			Source:         r.Header.Get("User-Agent"),
+		},
+		Dialect: dialect,
+	}, nil
+}
+
+// decodeLegacyDialect reads the response format from the Accept header and
+// the epoch and pretty parameters.
+func decodeLegacyDialect(r *http.Request) (*transpiler.Dialect, error) {
+	dialect := &transpiler.Dialect{Encoding: transpiler.JSON}
+	switch r.Header.Get("Accept") {
+	case "application/csv", "text/csv":
+		dialect.Encoding = transpiler.CSV
+	default:
+		if r.FormValue("pretty") == "true" {
+			dialect.Encoding = transpiler.JSONPretty
+		}
+	}
+
+	switch epoch := r.FormValue("epoch"); epoch {
+	case "":
+		dialect.TimeFormat = transpiler.RFC3339Nano
+	case "h":
+		dialect.TimeFormat = transpiler.Hour
+	case "m":
+		dialect.TimeFormat = transpiler.Minute
+	case "s":
+		dialect.TimeFormat = transpiler.Second
+	case "ms":
+		dialect.TimeFormat = transpiler.Millisecond
+	case "u", "us":
+		dialect.TimeFormat = transpiler.Microsecond
+	case "n", "ns":
+		dialect.TimeFormat = transpiler.Nanosecond
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   opLegacyQueryHandler,
+			Msg:  "invalid epoch; valid epoch units are h, m, s, ms, u, and ns",
+		}
+	}
+	return dialect, nil
+}
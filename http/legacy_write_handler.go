@@ -0,0 +1,235 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/http/metric"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	opLegacyWriteHandler      = "http/legacyWriteHandler"
+	msgInvalidLegacyPrecision = "invalid precision; valid precision units are n, ns, u, us, ms, and s"
+)
+
+// LegacyWriteHandler receives line protocol written to an influxdb 1.x
+// database and retention policy and writes it to the mapped bucket.
+type LegacyWriteHandler struct {
+	influxdb.HTTPErrorHandler
+	DBRPMappingService influxdb.DBRPMappingServiceV2
+	PointsWriter       storage.PointsWriter
+	EventRecorder      metric.EventRecorder
+
+	router            *httprouter.Router
+	log               *zap.Logger
+	maxBatchSizeBytes int64
+	parserOptions     []models.ParserOption
+}
+
+// NewLegacyWriteHandler creates a new handler at /write to receive line protocol.
+func NewLegacyWriteHandler(log *zap.Logger, b *LegacyBackend) *LegacyWriteHandler {
+	errorHandler := legacyErrorHandler{}
+	h := &LegacyWriteHandler{
+		HTTPErrorHandler:   errorHandler,
+		DBRPMappingService: b.DBRPMappingService,
+		PointsWriter:       b.PointsWriter,
+		EventRecorder:      b.WriteEventRecorder,
+
+		router:            NewRouter(errorHandler),
+		log:               log,
+		maxBatchSizeBytes: b.MaxBatchSizeBytes,
+		parserOptions:     b.WriteParserOptions,
+	}
+
+	h.router.HandlerFunc(http.MethodPost, prefixLegacyWrite, h.handleWrite)
+	return h
+}
+
+func (h *LegacyWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+func (h *LegacyWriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "LegacyWriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	auth, err := legacyAuthorization(ctx, opLegacyWriteHandler)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeLegacyWriteRequest(r, h.maxBatchSizeBytes)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	span.LogKV("org_id", auth.OrgID)
+
+	sw := kithttp.NewStatusResponseWriter(w)
+	recorder := NewWriteUsageRecorder(sw, h.EventRecorder)
+	var requestBytes int
+	defer func() {
+		// Close around the requestBytes variable to placate the linter.
+		recorder.Record(ctx, requestBytes, auth.OrgID, r.URL.Path)
+	}()
+
+	mapping, err := findLegacyMapping(ctx, h.DBRPMappingService, auth.OrgID, req.Database, req.RetentionPolicy)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
+	span.LogKV("bucket_id", mapping.BucketID)
+
+	if err := checkBucketWritePermissions(auth, auth.OrgID, mapping.BucketID); err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
+
+	opts := append([]models.ParserOption{}, h.parserOptions...)
+	opts = append(opts, models.WithParserPrecision(req.Precision))
+	parsed, err := NewPointsParser(opts...).ParsePoints(ctx, auth.OrgID, mapping.BucketID, req.Body)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, sw)
+		return
+	}
+	requestBytes = parsed.RawSize
+
+	if err := h.PointsWriter.WritePoints(ctx, parsed.Points); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   opLegacyWriteHandler,
+			Msg:  msgUnexpectedWriteError,
+			Err:  err,
+		}, sw)
+		return
+	}
+
+	sw.WriteHeader(http.StatusNoContent)
+}
+
+// legacyAuthorization returns the authorization placed on the context by the
+// LegacyAuthenticationHandler.
+func legacyAuthorization(ctx context.Context, op string) (*influxdb.Authorization, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	auth, ok := a.(*influxdb.Authorization)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   op,
+			Msg:  influxdb.ErrAuthorizerNotSupported.Error(),
+		}
+	}
+	return auth, nil
+}
+
+// findLegacyMapping returns the mapping of the database and retention policy
+// in the organization. The default mapping of the database is used when no
+// retention policy is provided.
+func findLegacyMapping(ctx context.Context, svc influxdb.DBRPMappingServiceV2, orgID influxdb.ID, db, rp string) (*influxdb.DBRPMappingV2, error) {
+	filter := influxdb.DBRPMappingFilterV2{
+		OrgID:    &orgID,
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		def := true
+		filter.Default = &def
+	}
+
+	mappings, n, err := svc.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		msg := fmt.Sprintf("database not found: %q", db)
+		if rp != "" {
+			msg = fmt.Sprintf("retention policy not found: %q", rp)
+		}
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  msg,
+		}
+	}
+	return mappings[0], nil
+}
+
+// legacyWriteRequest is a request object holding information about a batch
+// of points to be written to a database and retention policy.
+type legacyWriteRequest struct {
+	Database        string
+	RetentionPolicy string
+	Precision       string
+	Body            io.ReadCloser
+}
+
+// decodeLegacyWriteRequest extracts information from an http.Request object to
+// produce a legacyWriteRequest.
+func decodeLegacyWriteRequest(r *http.Request, maxBatchSizeBytes int64) (*legacyWriteRequest, error) {
+	qp := r.URL.Query()
+	precision, ok := legacyPrecision(qp.Get("precision"))
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/newLegacyWriteRequest",
+			Msg:  msgInvalidLegacyPrecision,
+		}
+	}
+
+	db := qp.Get("db")
+	if db == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/newLegacyWriteRequest",
+			Msg:  "database is required",
+		}
+	}
+
+	encoding := r.Header.Get("Content-Encoding")
+	body, err := PointBatchReadCloser(r.Body, encoding, maxBatchSizeBytes)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   "http/newLegacyWriteRequest",
+			Msg:  msgInvalidGzipHeader,
+			Err:  err,
+		}
+	}
+
+	return &legacyWriteRequest{
+		Database:        db,
+		RetentionPolicy: qp.Get("rp"),
+		Precision:       precision,
+		Body:            body,
+	}, nil
+}
+
+// legacyPrecision converts an influxdb 1.x precision to the precision used by
+// the points parser.
+func legacyPrecision(precision string) (string, bool) {
+	switch precision {
+	case "", "n", "ns":
+		return "ns", true
+	case "u", "us":
+		return "us", true
+	case "ms", "s":
+		return precision, true
+	default:
+		return "", false
+	}
+}
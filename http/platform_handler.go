@@ -6,13 +6,15 @@ import (
 
 	"github.com/influxdata/influxdb/v2/kit/feature"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
 )
 
 // PlatformHandler is a collection of all the service handlers.
 type PlatformHandler struct {
-	AssetHandler *AssetHandler
-	DocsHandler  http.HandlerFunc
-	APIHandler   http.Handler
+	AssetHandler  *AssetHandler
+	DocsHandler   http.HandlerFunc
+	APIHandler    http.Handler
+	LegacyHandler http.Handler
 }
 
 // NewPlatformHandler returns a platform handler that serves the API and associated assets.
@@ -37,10 +39,15 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	wrappedHandler := kithttp.SetCORS(h)
 	wrappedHandler = kithttp.SkipOptions(wrappedHandler)
 
+	legacyBackend := NewLegacyBackend(b.Logger.With(zap.String("handler", "legacy")), b)
+	legacyHandler := kithttp.SetCORS(NewLegacyHandler(b.Logger, legacyBackend))
+	legacyHandler = kithttp.SkipOptions(legacyHandler)
+
 	return &PlatformHandler{
-		AssetHandler: assetHandler,
-		DocsHandler:  Redoc("/api/v2/swagger.json"),
-		APIHandler:   wrappedHandler,
+		AssetHandler:  assetHandler,
+		DocsHandler:   Redoc("/api/v2/swagger.json"),
+		APIHandler:    wrappedHandler,
+		LegacyHandler: legacyHandler,
 	}
 }
 
@@ -51,6 +58,12 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Serve the influxdb 1.x compatible endpoints.
+	if IsLegacyPath(r.URL.Path) {
+		h.LegacyHandler.ServeHTTP(w, r)
+		return
+	}
+
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
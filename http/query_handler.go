@@ -176,6 +176,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 	// Transform the context into one with the request's authorization.
 	ctx = pcontext.SetAuthorizer(ctx, req.Request.Authorization)
+	ctx = query.ContextWithPriority(ctx, query.PriorityInteractive)
 	if h.Flagger != nil {
 		ctx, _ = feature.Annotate(ctx, h.Flagger)
 	}
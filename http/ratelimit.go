@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/influxdata/influxdb/v2/ratelimit"
+)
+
+func newRateLimitMiddleware(b *APIBackend) *ratelimit.Middleware {
+	return &ratelimit.Middleware{
+		Limiter:             b.RateLimiter,
+		OrganizationService: b.OrganizationService,
+		ErrorHandler:        b.HTTPErrorHandler,
+	}
+}
+
+// rateLimitQueries applies the query quotas of m to the queries served by
+// next, but not to the analysis and suggestion routes it also serves.
+func rateLimitQueries(m *ratelimit.Middleware, next http.Handler) http.Handler {
+	limited := m.Queries()(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case prefixQuery, prefixLegacyQuery:
+			limited.ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
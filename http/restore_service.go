@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// RestoreBackend is all services and associated parameters required to construct the RestoreHandler.
+type RestoreBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	RestoreService influxdb.RestoreService
+	BucketService  influxdb.BucketService
+}
+
+// NewRestoreBackend returns a new instance of RestoreBackend.
+func NewRestoreBackend(b *APIBackend) *RestoreBackend {
+	return &RestoreBackend{
+		Logger: b.Logger.With(zap.String("handler", "restore")),
+
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		RestoreService:   b.RestoreService,
+		BucketService:    b.BucketService,
+	}
+}
+
+// RestoreHandler is http handler for restore service.
+type RestoreHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RestoreService influxdb.RestoreService
+	BucketService  influxdb.BucketService
+}
+
+const (
+	prefixRestore          = "/api/v2/restore"
+	restoreBucketParamName = "bucket_id"
+	restoreBucketPath      = prefixRestore + "/bucket/:" + restoreBucketParamName
+)
+
+func composeRestoreBucketPath(bucketID influxdb.ID) string {
+	return path.Join(prefixRestore, "bucket", bucketID.String())
+}
+
+// NewRestoreHandler creates a new handler at /api/v2/restore to receive restore requests.
+func NewRestoreHandler(b *RestoreBackend) *RestoreHandler {
+	h := &RestoreHandler{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Router:           NewRouter(b.HTTPErrorHandler),
+		Logger:           b.Logger,
+		RestoreService:   b.RestoreService,
+		BucketService:    b.BucketService,
+	}
+
+	h.HandlerFunc(http.MethodPost, restoreBucketPath, h.handleRestoreBucket)
+
+	return h
+}
+
+// handleRestoreBucket loads the TSM file in the request body into a bucket.
+// The query parameters identify the backed up bucket the file holds data of.
+func (h *RestoreHandler) handleRestoreBucket(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "RestoreHandler.handleRestoreBucket")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	params := httprouter.ParamsFromContext(ctx)
+	var bucketID influxdb.ID
+	if err := bucketID.DecodeFromString(params.ByName(restoreBucketParamName)); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	src, err := decodeRestoreSource(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RestoreService.RestoreBucket(ctx, b.OrgID, b.ID, src, r.Body); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("Bucket data restored", zap.Stringer("bucketID", b.ID), zap.Stringer("sourceBucketID", src.BucketID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeRestoreSource(r *http.Request) (influxdb.BackupManifestBucket, error) {
+	var src influxdb.BackupManifestBucket
+	qp := r.URL.Query()
+	if err := src.OrgID.DecodeFromString(qp.Get("sourceOrgID")); err != nil {
+		return src, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid sourceOrgID",
+			Err:  err,
+		}
+	}
+	if err := src.BucketID.DecodeFromString(qp.Get("sourceBucketID")); err != nil {
+		return src, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid sourceBucketID",
+			Err:  err,
+		}
+	}
+	return src, nil
+}
+
+// RestoreService is the client implementation of influxdb.RestoreService.
+type RestoreService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// RestoreBucket uploads a TSM file holding data of the backed up bucket src
+// into the bucket bucketID. The organization of the bucket is resolved by the server.
+func (s *RestoreService) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, src influxdb.BackupManifestBucket, r io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, composeRestoreBucketPath(bucketID))
+	if err != nil {
+		return err
+	}
+	qp := u.Query()
+	qp.Set("sourceOrgID", src.OrgID.String())
+	qp.Set("sourceBucketID", src.BucketID.String())
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	SetToken(s.Token, req)
+	req = req.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	hc.Timeout = httpClientTimeout
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return fmt.Errorf("failed to restore data of bucket %s: %v", src.BucketID, err)
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+// SilenceBackend is all services and associated parameters required to construct
+// the SilenceHandler.
+type SilenceBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceBackend returns a new instance of SilenceBackend.
+func NewSilenceBackend(log *zap.Logger, b *APIBackend) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// SilenceHandler is the handler for the silences of notifications.
+type SilenceHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+
+	// now returns the time the states of silences are reported at.
+	now func() time.Time
+}
+
+const (
+	prefixSilences   = "/api/v2/silences"
+	silencesIDPath   = "/api/v2/silences/:id"
+	silenceExpiredQP = "expired"
+)
+
+// NewSilenceHandler returns a new instance of SilenceHandler.
+func NewSilenceHandler(log *zap.Logger, b *SilenceBackend) *SilenceHandler {
+	h := &SilenceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+
+		now: time.Now,
+	}
+
+	h.HandlerFunc("POST", prefixSilences, h.handlePostSilence)
+	h.HandlerFunc("GET", prefixSilences, h.handleGetSilences)
+	h.HandlerFunc("GET", silencesIDPath, h.handleGetSilence)
+	h.HandlerFunc("PATCH", silencesIDPath, h.handlePatchSilence)
+	h.HandlerFunc("DELETE", silencesIDPath, h.handleDeleteSilence)
+	return h
+}
+
+// silenceResponse is a silence along with its state at the time of the request.
+type silenceResponse struct {
+	*influxdb.Silence
+	State influxdb.SilenceState `json:"state"`
+}
+
+type silencesResponse struct {
+	Silences []silenceResponse `json:"silences"`
+}
+
+func (h *SilenceHandler) newSilenceResponse(s *influxdb.Silence) silenceResponse {
+	return silenceResponse{
+		Silence: s,
+		State:   s.State(h.now()),
+	}
+}
+
+// handlePostSilence is the HTTP handler for the POST /api/v2/silences route.
+func (h *SilenceHandler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	var s influxdb.Silence
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	s.CreatedBy = auth.GetUserID()
+
+	if err := h.SilenceService.CreateSilence(ctx, &s); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence created", zap.String("silenceID", s.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, h.newSilenceResponse(&s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetSilences is the HTTP handler for the GET /api/v2/silences route.
+func (h *SilenceHandler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	filter, err := decodeSilenceFilter(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, err := h.SilenceService.FindSilences(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	resp := silencesResponse{Silences: []silenceResponse{}}
+	for _, s := range ss {
+		resp.Silences = append(resp.Silences, h.newSilenceResponse(s))
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetSilence is the HTTP handler for the GET /api/v2/silences/:id route.
+func (h *SilenceHandler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, h.newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePatchSilence is the HTTP handler for the PATCH /api/v2/silences/:id route.
+func (h *SilenceHandler) handlePatchSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.SilenceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	s, err := h.SilenceService.UpdateSilence(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence updated", zap.String("silenceID", s.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, h.newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteSilence is the HTTP handler for the DELETE /api/v2/silences/:id route.
+func (h *SilenceHandler) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "SilenceHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.DeleteSilence(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence deleted", zap.String("silenceID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeSilenceFilter decodes the optional organization and expired query
+// parameters of a request listing silences.
+func decodeSilenceFilter(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (influxdb.SilenceFilter, error) {
+	var filter influxdb.SilenceFilter
+	qp := r.URL.Query()
+
+	if qp.Get(Org) != "" || qp.Get(OrgID) != "" {
+		o, err := queryOrganization(ctx, r, orgSvc)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if s := qp.Get(silenceExpiredQP); s != "" {
+		expired, err := strconv.ParseBool(s)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid expired value %q", s),
+			}
+		}
+		filter.Expired = &expired
+	}
+	return filter, nil
+}
+
+// SilenceService connects to Influx via HTTP using tokens to manage silences.
+type SilenceService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// FindSilenceByID returns a single silence by ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp silenceResponse
+	err := s.Client.
+		Get(prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Silence, nil
+}
+
+// FindSilences returns the silences matching filter.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if filter.OrgID != nil {
+		params = append(params, [2]string{OrgID, filter.OrgID.String()})
+	}
+	if filter.Expired != nil {
+		params = append(params, [2]string{silenceExpiredQP, strconv.FormatBool(*filter.Expired)})
+	}
+
+	var resp silencesResponse
+	err := s.Client.
+		Get(prefixSilences).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]*influxdb.Silence, 0, len(resp.Silences))
+	for _, sr := range resp.Silences {
+		ss = append(ss, sr.Silence)
+	}
+	return ss, nil
+}
+
+// CreateSilence creates a silence and sets sl.ID with the new identifier.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp silenceResponse
+	err := s.Client.
+		PostJSON(sl, prefixSilences).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*sl = *resp.Silence
+	return nil
+}
+
+// UpdateSilence updates a silence and returns the updated silence.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp silenceResponse
+	err := s.Client.
+		PatchJSON(upd, prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Silence, nil
+}
+
+// DeleteSilence removes a silence by ID.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(prefixSilences, id.String()).
+		Do(ctx)
+}
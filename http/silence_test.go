@@ -0,0 +1,163 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	pctx "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSilenceHandler(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	silence := func(id influxdb.ID) *influxdb.Silence {
+		return &influxdb.Silence{
+			ID:        id,
+			OrgID:     10,
+			Matchers:  []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal}},
+			StartsAt:  createdAt.Add(time.Hour),
+			EndsAt:    createdAt.Add(2 * time.Hour),
+			CreatedBy: 3,
+			Comment:   "maintenance",
+			CRUDLog:   influxdb.CRUDLog{CreatedAt: createdAt, UpdatedAt: createdAt},
+		}
+	}
+	silenceBody := `
+{
+  "id": "0000000000000001",
+  "orgID": "000000000000000a",
+  "matchers": [{"key": "host", "value": "db01", "operator": "equal"}],
+  "startsAt": "2020-06-01T01:00:00Z",
+  "endsAt": "2020-06-01T02:00:00Z",
+  "createdBy": "0000000000000003",
+  "comment": "maintenance",
+  "state": "pending",
+  "createdAt": "2020-06-01T00:00:00Z",
+  "updatedAt": "2020-06-01T00:00:00Z"
+}
+`
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:   "create silence",
+			method: "POST",
+			path:   "/api/v2/silences",
+			body: `{"orgID": "000000000000000a", "matchers": [{"key": "host", "value": "db01", "operator": "equal"}],
+"startsAt": "2020-06-01T01:00:00Z", "endsAt": "2020-06-01T02:00:00Z", "comment": "maintenance"}`,
+			statusCode: http.StatusCreated,
+			respBody:   silenceBody,
+		},
+		{
+			name:       "list unexpired silences of an org",
+			method:     "GET",
+			path:       "/api/v2/silences?orgID=000000000000000a&expired=false",
+			statusCode: http.StatusOK,
+			respBody:   `{"silences": [` + silenceBody + `]}`,
+		},
+		{
+			name:       "list silences with invalid expired",
+			method:     "GET",
+			path:       "/api/v2/silences?expired=maybe",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "get silence",
+			method:     "GET",
+			path:       "/api/v2/silences/0000000000000001",
+			statusCode: http.StatusOK,
+			respBody:   silenceBody,
+		},
+		{
+			name:       "end silence early",
+			method:     "PATCH",
+			path:       "/api/v2/silences/0000000000000001",
+			body:       `{"endsAt": "2020-06-01T00:00:00Z"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "delete silence",
+			method:     "DELETE",
+			path:       "/api/v2/silences/0000000000000001",
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := mock.NewSilenceService()
+			ss.CreateSilenceF = func(ctx context.Context, s *influxdb.Silence) error {
+				if s.OrgID != 10 || s.CreatedBy != 3 || len(s.Matchers) != 1 {
+					return fmt.Errorf("unexpected silence %+v", s)
+				}
+				*s = *silence(1)
+				return nil
+			}
+			ss.FindSilencesF = func(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+				if filter.OrgID == nil || *filter.OrgID != 10 || filter.Expired == nil || *filter.Expired {
+					return nil, fmt.Errorf("unexpected filter %+v", filter)
+				}
+				return []*influxdb.Silence{silence(1)}, nil
+			}
+			ss.FindSilenceByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+				return silence(id), nil
+			}
+			ss.UpdateSilenceF = func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+				s := silence(id)
+				upd.Apply(s)
+				if err := s.Valid(); err != nil {
+					return nil, err
+				}
+				return s, nil
+			}
+
+			b := &SilenceBackend{
+				HTTPErrorHandler: kithttp.ErrorHandler(0),
+				log:              zaptest.NewLogger(t),
+				SilenceService:   ss,
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+						return &influxdb.Organization{ID: *filter.ID}, nil
+					},
+				},
+			}
+			h := NewSilenceHandler(zaptest.NewLogger(t), b)
+			h.now = func() time.Time { return createdAt }
+
+			r := httptest.NewRequest(tt.method, "http://localhost:9999"+tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pctx.SetAuthorizer(r.Context(), &influxdb.Authorization{UserID: 3}))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
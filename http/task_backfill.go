@@ -0,0 +1,268 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// backfillRequest is the body of a request backfilling a task.
+type backfillRequest struct {
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Concurrency int       `json:"concurrency,omitempty"`
+}
+
+type backfillsResponse struct {
+	Backfills []*influxdb.Backfill `json:"backfills"`
+}
+
+// handlePostBackfill is the HTTP handler for the POST /api/v2/tasks/:id/backfills route.
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req backfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.Start.IsZero() || req.Stop.IsZero() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "backfill start and stop are required",
+		}, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b := &influxdb.Backfill{
+		TaskID:      task.ID,
+		OrgID:       task.OrganizationID,
+		Start:       req.Start,
+		Stop:        req.Stop,
+		Concurrency: req.Concurrency,
+	}
+	if err := h.BackfillService.CreateBackfill(ctx, b); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Backfill created",
+		zap.String("backfillID", b.ID.String()),
+		zap.String("taskID", b.TaskID.String()),
+		zap.Int64("runs", b.Progress.Total),
+	)
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, b); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetBackfills is the HTTP handler for the GET /api/v2/tasks/:id/backfills route.
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.BackfillFilter{TaskID: &taskID}
+	if s := r.URL.Query().Get(backfillStatusQP); s != "" {
+		status := influxdb.BackfillStatus(s)
+		switch status {
+		case influxdb.BackfillQueued, influxdb.BackfillRunning, influxdb.BackfillCanceling,
+			influxdb.BackfillSuccess, influxdb.BackfillFailed, influxdb.BackfillCanceled:
+		default:
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid backfill status %q", s),
+			}, w)
+			return
+		}
+		filter.Status = &status
+	}
+
+	backfills, err := h.BackfillService.FindBackfills(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, backfillsResponse{Backfills: backfills}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetBackfill is the HTTP handler for the GET /api/v2/tasks/:id/backfills/:bid route.
+func (h *TaskHandler) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	b, err := h.findBackfill(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, b); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleCancelBackfill is the HTTP handler for the DELETE /api/v2/tasks/:id/backfills/:bid route.
+func (h *TaskHandler) handleCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	b, err := h.findBackfill(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BackfillService.CancelBackfill(ctx, b.ID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Backfill canceled", zap.String("backfillID", b.ID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findBackfill returns the backfill of the route, which must belong to the
+// task of the route.
+func (h *TaskHandler) findBackfill(ctx context.Context) (*influxdb.Backfill, error) {
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	id, err := decodeIDFromCtx(ctx, "bid")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := h.BackfillService.FindBackfillByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.TaskID != taskID {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "backfill not found",
+		}
+	}
+	return b, nil
+}
+
+// Backfill runs the task taskID for every time it is scheduled for in
+// [start, stop], running at most concurrency runs at a time. The backfill
+// runs in the background; it is returned as queued.
+func (t TaskService) Backfill(ctx context.Context, taskID influxdb.ID, start, stop time.Time, concurrency int) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	req := backfillRequest{
+		Start:       start,
+		Stop:        stop,
+		Concurrency: concurrency,
+	}
+
+	var b influxdb.Backfill
+	err := t.Client.
+		PostJSON(req, taskIDBackfillsPath(taskID)).
+		DecodeJSON(&b).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// FindBackfills returns the backfills of the task taskID with the given
+// status, if any, oldest first.
+func (t TaskService) FindBackfills(ctx context.Context, taskID influxdb.ID, status string) ([]*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if status != "" {
+		params = append(params, [2]string{backfillStatusQP, status})
+	}
+
+	var resp backfillsResponse
+	err := t.Client.
+		Get(taskIDBackfillsPath(taskID)).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Backfills, nil
+}
+
+// FindBackfillByID returns a single backfill of the task taskID by ID.
+func (t TaskService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var b influxdb.Backfill
+	err := t.Client.
+		Get(taskIDBackfillsPath(taskID), id.String()).
+		DecodeJSON(&b).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// CancelBackfill cancels a queued or running backfill of the task taskID.
+func (t TaskService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return t.Client.
+		Delete(taskIDBackfillsPath(taskID), id.String()).
+		Do(ctx)
+}
+
+func taskIDBackfillsPath(id influxdb.ID) string {
+	return path.Join(prefixTasks, id.String(), "backfills")
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_Backfills(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	backfill := func(id, taskID influxdb.ID) *influxdb.Backfill {
+		return &influxdb.Backfill{
+			ID:          id,
+			TaskID:      taskID,
+			OrgID:       10,
+			Start:       createdAt.Add(-2 * time.Hour),
+			Stop:        createdAt,
+			Concurrency: 2,
+			Status:      influxdb.BackfillQueued,
+			Progress:    influxdb.BackfillProgress{Total: 3},
+			CreatedAt:   createdAt,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:       "create backfill",
+			method:     "POST",
+			path:       "/api/v2/tasks/0000000000000064/backfills",
+			body:       `{"start": "2020-05-31T22:00:00Z", "stop": "2020-06-01T00:00:00Z", "concurrency": 2}`,
+			statusCode: http.StatusCreated,
+			respBody: `
+{
+  "id": "0000000000000001",
+  "taskID": "0000000000000064",
+  "orgID": "000000000000000a",
+  "start": "2020-05-31T22:00:00Z",
+  "stop": "2020-06-01T00:00:00Z",
+  "concurrency": 2,
+  "status": "queued",
+  "progress": {"total": 3, "succeeded": 0, "failed": 0},
+  "createdAt": "2020-06-01T00:00:00Z"
+}
+`,
+		},
+		{
+			name:       "create backfill without range",
+			method:     "POST",
+			path:       "/api/v2/tasks/0000000000000064/backfills",
+			body:       `{"concurrency": 2}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "list backfills",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/backfills?status=queued",
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "backfills": [
+    {
+      "id": "0000000000000001",
+      "taskID": "0000000000000064",
+      "orgID": "000000000000000a",
+      "start": "2020-05-31T22:00:00Z",
+      "stop": "2020-06-01T00:00:00Z",
+      "concurrency": 2,
+      "status": "queued",
+      "progress": {"total": 3, "succeeded": 0, "failed": 0},
+      "createdAt": "2020-06-01T00:00:00Z"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:       "list backfills with invalid status",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/backfills?status=done",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "backfill of another task",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000065/backfills/0000000000000001",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "cancel backfill",
+			method:     "DELETE",
+			path:       "/api/v2/tasks/0000000000000064/backfills/0000000000000001",
+			statusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewBackfillService()
+			bs.CreateBackfillF = func(ctx context.Context, b *influxdb.Backfill) error {
+				if b.TaskID != 100 || b.OrgID != 10 || b.Concurrency != 2 {
+					return fmt.Errorf("unexpected backfill %+v", b)
+				}
+				*b = *backfill(1, b.TaskID)
+				return nil
+			}
+			bs.FindBackfillsF = func(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+				if filter.TaskID == nil || *filter.TaskID != 100 || filter.Status == nil || *filter.Status != influxdb.BackfillQueued {
+					return nil, fmt.Errorf("unexpected filter %+v", filter)
+				}
+				return []*influxdb.Backfill{backfill(1, 100)}, nil
+			}
+			bs.FindBackfillByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error) {
+				return backfill(id, 100), nil
+			}
+
+			taskBE := NewMockTaskBackend(t)
+			taskBE.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			taskBE.TaskService = &mock.TaskService{
+				FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+					return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+				},
+			}
+			taskBE.BackfillService = bs
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBE)
+
+			r := httptest.NewRequest(tt.method, "http://localhost:9999"+tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+// TaskDependencies is the dependency graph of a task: the tasks it depends on
+// and the tasks depending on it, directly or not, nearest first.
+type TaskDependencies struct {
+	TaskID     influxdb.ID      `json:"taskID"`
+	Upstream   []TaskDependency `json:"upstream"`
+	Downstream []TaskDependency `json:"downstream"`
+}
+
+// TaskDependency is a task of a dependency graph, with the edges to its
+// own upstream tasks.
+type TaskDependency struct {
+	ID              influxdb.ID   `json:"id"`
+	Name            string        `json:"name"`
+	Status          string        `json:"status"`
+	LatestCompleted string        `json:"latestCompleted,omitempty"`
+	LastRunStatus   string        `json:"lastRunStatus,omitempty"`
+	DependsOn       []influxdb.ID `json:"dependsOn,omitempty"`
+}
+
+func newTaskDependency(t *influxdb.Task) TaskDependency {
+	latestCompleted := ""
+	if !t.LatestCompleted.IsZero() {
+		latestCompleted = t.LatestCompleted.Format(time.RFC3339)
+	}
+	return TaskDependency{
+		ID:              t.ID,
+		Name:            t.Name,
+		Status:          t.Status,
+		LatestCompleted: latestCompleted,
+		LastRunStatus:   t.LastRunStatus,
+		DependsOn:       t.DependsOn,
+	}
+}
+
+// handleGetDependencies is the HTTP handler for the GET /api/v2/tasks/:id/dependencies route.
+func (h *TaskHandler) handleGetDependencies(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tasks, err := h.findOrgTasks(ctx, task.OrganizationID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, dependencyGraph(task, tasks)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// findOrgTasks returns all the tasks of the organization orgID the request may read.
+func (h *TaskHandler) findOrgTasks(ctx context.Context, orgID influxdb.ID) ([]*influxdb.Task, error) {
+	filter := influxdb.TaskFilter{
+		OrganizationID: &orgID,
+		Limit:          influxdb.TaskMaxPageSize,
+	}
+
+	var all []*influxdb.Task
+	for {
+		tasks, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, tasks...)
+		if len(tasks) < filter.Limit {
+			return all, nil
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+}
+
+// dependencyGraph returns the dependency graph of task among tasks. Upstream
+// tasks missing from tasks, deleted or not readable, are left out.
+func dependencyGraph(task *influxdb.Task, tasks []*influxdb.Task) TaskDependencies {
+	byID := make(map[influxdb.ID]*influxdb.Task, len(tasks))
+	downstream := make(map[influxdb.ID][]influxdb.ID)
+	for _, t := range tasks {
+		byID[t.ID] = t
+		for _, id := range t.DependsOn {
+			downstream[id] = append(downstream[id], t.ID)
+		}
+	}
+
+	// walk the graph breadth first so nearer tasks come first.
+	walk := func(next func(*influxdb.Task) []influxdb.ID) []TaskDependency {
+		deps := []TaskDependency{}
+		seen := map[influxdb.ID]bool{task.ID: true}
+		queue := next(task)
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			t, ok := byID[id]
+			if seen[id] || !ok {
+				continue
+			}
+			seen[id] = true
+			deps = append(deps, newTaskDependency(t))
+			queue = append(queue, next(t)...)
+		}
+		return deps
+	}
+
+	return TaskDependencies{
+		TaskID: task.ID,
+		Upstream: walk(func(t *influxdb.Task) []influxdb.ID {
+			return t.DependsOn
+		}),
+		Downstream: walk(func(t *influxdb.Task) []influxdb.ID {
+			return downstream[t.ID]
+		}),
+	}
+}
+
+// FindTaskDependencies returns the dependency graph of the task taskID.
+func (t TaskService) FindTaskDependencies(ctx context.Context, taskID influxdb.ID) (*TaskDependencies, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var deps TaskDependencies
+	err := t.Client.
+		Get(path.Join(prefixTasks, taskID.String(), "dependencies")).
+		DecodeJSON(&deps).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
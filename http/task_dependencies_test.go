@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_Dependencies(t *testing.T) {
+	// a <- b <- c, and d depending on a and c.
+	tasks := []*influxdb.Task{
+		{ID: 1, OrganizationID: 10, Name: "a", Status: "active", LastRunStatus: "success"},
+		{ID: 2, OrganizationID: 10, Name: "b", Status: "active", DependsOn: []influxdb.ID{1}},
+		{ID: 3, OrganizationID: 10, Name: "c", Status: "inactive", DependsOn: []influxdb.ID{2}},
+		{ID: 4, OrganizationID: 10, Name: "d", Status: "active", DependsOn: []influxdb.ID{1, 3}},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:       "upstream and downstream tasks",
+			path:       "/api/v2/tasks/0000000000000003/dependencies",
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "taskID": "0000000000000003",
+  "upstream": [
+    {"id": "0000000000000002", "name": "b", "status": "active", "dependsOn": ["0000000000000001"]},
+    {"id": "0000000000000001", "name": "a", "status": "active", "lastRunStatus": "success"}
+  ],
+  "downstream": [
+    {"id": "0000000000000004", "name": "d", "status": "active", "dependsOn": ["0000000000000001", "0000000000000003"]}
+  ]
+}
+`,
+		},
+		{
+			name:       "downstream tasks nearest first",
+			path:       "/api/v2/tasks/0000000000000001/dependencies",
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "taskID": "0000000000000001",
+  "upstream": [],
+  "downstream": [
+    {"id": "0000000000000002", "name": "b", "status": "active", "dependsOn": ["0000000000000001"]},
+    {"id": "0000000000000004", "name": "d", "status": "active", "dependsOn": ["0000000000000001", "0000000000000003"]},
+    {"id": "0000000000000003", "name": "c", "status": "inactive", "dependsOn": ["0000000000000002"]}
+  ]
+}
+`,
+		},
+		{
+			name:       "missing task",
+			path:       "/api/v2/tasks/0000000000000005/dependencies",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskBE := NewMockTaskBackend(t)
+			taskBE.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			taskBE.TaskService = &mock.TaskService{
+				FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+					for _, task := range tasks {
+						if task.ID == id {
+							return task, nil
+						}
+					}
+					return nil, influxdb.ErrTaskNotFound
+				},
+				FindTasksFn: func(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+					if filter.OrganizationID == nil || *filter.OrganizationID != 10 {
+						t.Errorf("unexpected filter %+v", filter)
+					}
+					return tasks, len(tasks), nil
+				},
+			}
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBE)
+
+			r := httptest.NewRequest("GET", "http://localhost:9999"+tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+type taskTestRequest struct {
+	// ScheduledFor defaults to the current time.
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+}
+
+// handleTestTask is the HTTP handler for the POST /api/v2/tasks/:id/test route.
+func (h *TaskHandler) handleTestTask(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req taskTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	d := &influxdb.TaskDryRun{
+		TaskID:       task.ID,
+		OrgID:        task.OrganizationID,
+		ScheduledFor: time.Now().UTC(),
+	}
+	if req.ScheduledFor != nil {
+		d.ScheduledFor = *req.ScheduledFor
+	}
+	if err := h.TaskDryRunService.DryRunTask(ctx, d); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Task tested", zap.String("taskID", d.TaskID.String()), zap.Int("tables", len(d.Tables)))
+
+	if d.Tables == nil {
+		// guarantee we never return null tables
+		d.Tables = []*influxdb.DryRunTable{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, d); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// DryRunTask executes the task taskID for scheduledFor without writing data,
+// returning the tables it would have written.
+func (t TaskService) DryRunTask(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time) (*influxdb.TaskDryRun, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var req taskTestRequest
+	if !scheduledFor.IsZero() {
+		req.ScheduledFor = &scheduledFor
+	}
+
+	var d influxdb.TaskDryRun
+	err := t.Client.
+		PostJSON(req, path.Join(prefixTasks, taskID.String(), "test")).
+		DecodeJSON(&d).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
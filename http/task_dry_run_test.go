@@ -0,0 +1,135 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_Test(t *testing.T) {
+	scheduledFor := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:       "test task",
+			path:       "/api/v2/tasks/0000000000000064/test",
+			body:       `{"scheduledFor": "2020-06-01T00:00:00Z"}`,
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "taskID": "0000000000000064",
+  "orgID": "000000000000000a",
+  "taskVersion": 2,
+  "scheduledFor": "2020-06-01T00:00:00Z",
+  "tables": [
+    {
+      "bucketID": "0000000000000001",
+      "measurement": "cpu",
+      "tags": {"host": "a"},
+      "rows": [
+        {"time": "2020-05-31T23:59:00Z", "fields": {"usage": 0.5, "count": 2}}
+      ]
+    }
+  ],
+  "stats": {
+    "rowsRead": 10,
+    "pointsWritten": {"0000000000000001": 2},
+    "queryDuration": "1s",
+    "maxAllocated": 1024
+  }
+}
+`,
+		},
+		{
+			name:       "test task with invalid body",
+			path:       "/api/v2/tasks/0000000000000064/test",
+			body:       `{"scheduledFor": "yesterday"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "test missing task",
+			path:       "/api/v2/tasks/0000000000000065/test",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := mock.NewTaskDryRunService()
+			ds.DryRunTaskF = func(ctx context.Context, d *influxdb.TaskDryRun) error {
+				if d.TaskID != 100 || d.OrgID != 10 || !d.ScheduledFor.Equal(scheduledFor) {
+					return fmt.Errorf("unexpected dry run %+v", d)
+				}
+				d.TaskVersion = 2
+				d.Tables = []*influxdb.DryRunTable{
+					{
+						BucketID:    1,
+						Measurement: "cpu",
+						Tags:        map[string]string{"host": "a"},
+						Rows: []influxdb.DryRunRow{
+							{
+								Time:   scheduledFor.Add(-time.Minute),
+								Fields: map[string]interface{}{"usage": 0.5, "count": int64(2)},
+							},
+						},
+					},
+				}
+				d.Stats = &influxdb.RunStats{
+					RowsRead:      10,
+					PointsWritten: map[influxdb.ID]int64{1: 2},
+					QueryDuration: influxdb.Duration{Duration: time.Second},
+					MaxAllocated:  1024,
+				}
+				return nil
+			}
+
+			taskBE := NewMockTaskBackend(t)
+			taskBE.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			taskBE.TaskService = &mock.TaskService{
+				FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+					if id != 100 {
+						return nil, influxdb.ErrTaskNotFound
+					}
+					return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+				},
+			}
+			taskBE.TaskDryRunService = ds
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBE)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999"+tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
@@ -28,6 +28,9 @@ type TaskBackend struct {
 
 	AlgoWProxy                 FeatureProxyHandler
 	TaskService                influxdb.TaskService
+	BackfillService            influxdb.BackfillService
+	TaskVersionService         influxdb.TaskVersionService
+	TaskDryRunService          influxdb.TaskDryRunService
 	AuthorizationService       influxdb.AuthorizationService
 	OrganizationService        influxdb.OrganizationService
 	UserResourceMappingService influxdb.UserResourceMappingService
@@ -43,6 +46,9 @@ func NewTaskBackend(log *zap.Logger, b *APIBackend) *TaskBackend {
 		log:                        log,
 		AlgoWProxy:                 b.AlgoWProxy,
 		TaskService:                b.TaskService,
+		BackfillService:            b.BackfillService,
+		TaskVersionService:         b.TaskVersionService,
+		TaskDryRunService:          b.TaskDryRunService,
 		AuthorizationService:       b.AuthorizationService,
 		OrganizationService:        b.OrganizationService,
 		UserResourceMappingService: b.UserResourceMappingService,
@@ -59,6 +65,9 @@ type TaskHandler struct {
 	log *zap.Logger
 
 	TaskService                influxdb.TaskService
+	BackfillService            influxdb.BackfillService
+	TaskVersionService         influxdb.TaskVersionService
+	TaskDryRunService          influxdb.TaskDryRunService
 	AuthorizationService       influxdb.AuthorizationService
 	OrganizationService        influxdb.OrganizationService
 	UserResourceMappingService influxdb.UserResourceMappingService
@@ -68,19 +77,27 @@ type TaskHandler struct {
 }
 
 const (
-	prefixTasks            = "/api/v2/tasks"
-	tasksIDPath            = "/api/v2/tasks/:id"
-	tasksIDLogsPath        = "/api/v2/tasks/:id/logs"
-	tasksIDMembersPath     = "/api/v2/tasks/:id/members"
-	tasksIDMembersIDPath   = "/api/v2/tasks/:id/members/:userID"
-	tasksIDOwnersPath      = "/api/v2/tasks/:id/owners"
-	tasksIDOwnersIDPath    = "/api/v2/tasks/:id/owners/:userID"
-	tasksIDRunsPath        = "/api/v2/tasks/:id/runs"
-	tasksIDRunsIDPath      = "/api/v2/tasks/:id/runs/:rid"
-	tasksIDRunsIDLogsPath  = "/api/v2/tasks/:id/runs/:rid/logs"
-	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
-	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
-	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
+	prefixTasks             = "/api/v2/tasks"
+	tasksIDPath             = "/api/v2/tasks/:id"
+	tasksIDLogsPath         = "/api/v2/tasks/:id/logs"
+	tasksIDMembersPath      = "/api/v2/tasks/:id/members"
+	tasksIDMembersIDPath    = "/api/v2/tasks/:id/members/:userID"
+	tasksIDOwnersPath       = "/api/v2/tasks/:id/owners"
+	tasksIDOwnersIDPath     = "/api/v2/tasks/:id/owners/:userID"
+	tasksIDRunsPath         = "/api/v2/tasks/:id/runs"
+	tasksIDRunsIDPath       = "/api/v2/tasks/:id/runs/:rid"
+	tasksIDRunsIDLogsPath   = "/api/v2/tasks/:id/runs/:rid/logs"
+	tasksIDRunsIDRetryPath  = "/api/v2/tasks/:id/runs/:rid/retry"
+	tasksIDLabelsPath       = "/api/v2/tasks/:id/labels"
+	tasksIDLabelsIDPath     = "/api/v2/tasks/:id/labels/:lid"
+	tasksIDBackfillsPath    = "/api/v2/tasks/:id/backfills"
+	tasksIDBackfillsIDPath  = "/api/v2/tasks/:id/backfills/:bid"
+	tasksIDDependenciesPath = "/api/v2/tasks/:id/dependencies"
+	tasksIDVersionsPath     = "/api/v2/tasks/:id/versions"
+	tasksIDVersionsIDPath   = "/api/v2/tasks/:id/versions/:version"
+	tasksIDRollbackPath     = "/api/v2/tasks/:id/versions/:version/rollback"
+	tasksIDTestPath         = "/api/v2/tasks/:id/test"
+	backfillStatusQP        = "status"
 )
 
 // NewTaskHandler returns a new instance of TaskHandler.
@@ -91,6 +108,9 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 		log:              log,
 
 		TaskService:                b.TaskService,
+		BackfillService:            b.BackfillService,
+		TaskVersionService:         b.TaskVersionService,
+		TaskDryRunService:          b.TaskDryRunService,
 		AuthorizationService:       b.AuthorizationService,
 		OrganizationService:        b.OrganizationService,
 		UserResourceMappingService: b.UserResourceMappingService,
@@ -139,6 +159,25 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("GET", tasksIDDependenciesPath, h.handleGetDependencies)
+
+	if b.BackfillService != nil {
+		h.HandlerFunc("POST", tasksIDBackfillsPath, h.handlePostBackfill)
+		h.HandlerFunc("GET", tasksIDBackfillsPath, h.handleGetBackfills)
+		h.HandlerFunc("GET", tasksIDBackfillsIDPath, h.handleGetBackfill)
+		h.HandlerFunc("DELETE", tasksIDBackfillsIDPath, h.handleCancelBackfill)
+	}
+
+	if b.TaskVersionService != nil {
+		h.HandlerFunc("GET", tasksIDVersionsPath, h.handleGetTaskVersions)
+		h.HandlerFunc("GET", tasksIDVersionsIDPath, h.handleGetTaskVersion)
+		h.HandlerFunc("POST", tasksIDRollbackPath, h.handleRollbackTask)
+	}
+
+	if b.TaskDryRunService != nil {
+		h.HandlerFunc("POST", tasksIDTestPath, h.handleTestTask)
+	}
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -165,7 +204,14 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Timezone        string                 `json:"timezone,omitempty"`
 	Offset          string                 `json:"offset,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`
+	CatchUp         string                 `json:"catchUp,omitempty"`
+	MaxMissedRuns   int64                  `json:"maxMissedRuns,omitempty"`
+	DependsOn       []influxdb.ID          `json:"dependsOn,omitempty"`
+	Params          []influxdb.TaskParam   `json:"params,omitempty"`
+	Version         int                    `json:"version,omitempty"`
 	LatestCompleted string                 `json:"latestCompleted,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
@@ -210,7 +256,14 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		Flux:            t.Flux,
 		Every:           t.Every,
 		Cron:            t.Cron,
+		Timezone:        t.Timezone,
 		Offset:          offset,
+		Retry:           t.Retry,
+		CatchUp:         t.CatchUp,
+		MaxMissedRuns:   t.MaxMissedRuns,
+		DependsOn:       t.DependsOn,
+		Params:          t.Params,
+		Version:         t.Version,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
@@ -336,14 +389,20 @@ type runResponse struct {
 // it uses a pointer to a time.Time instead of a time.Time so that we can pass a nil
 // value for empty time values
 type httpRun struct {
-	ID           influxdb.ID    `json:"id,omitempty"`
-	TaskID       influxdb.ID    `json:"taskID"`
-	Status       string         `json:"status"`
-	ScheduledFor *time.Time     `json:"scheduledFor"`
-	StartedAt    *time.Time     `json:"startedAt,omitempty"`
-	FinishedAt   *time.Time     `json:"finishedAt,omitempty"`
-	RequestedAt  *time.Time     `json:"requestedAt,omitempty"`
-	Log          []influxdb.Log `json:"log,omitempty"`
+	ID           influxdb.ID        `json:"id,omitempty"`
+	TaskID       influxdb.ID        `json:"taskID"`
+	Status       string             `json:"status"`
+	ScheduledFor *time.Time         `json:"scheduledFor"`
+	StartedAt    *time.Time         `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time         `json:"finishedAt,omitempty"`
+	RequestedAt  *time.Time         `json:"requestedAt,omitempty"`
+	RetryOf      *influxdb.ID       `json:"retryOf,omitempty"`
+	Retry        int                `json:"retry,omitempty"`
+	Stats        *influxdb.RunStats `json:"stats,omitempty"`
+	TaskVersion  int                `json:"taskVersion,omitempty"`
+	Log          []influxdb.Log     `json:"log,omitempty"`
+
+	Params map[string]interface{} `json:"params,omitempty"`
 }
 
 func newRunResponse(r influxdb.Run) runResponse {
@@ -353,6 +412,10 @@ func newRunResponse(r influxdb.Run) runResponse {
 		Status:       r.Status,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
+		Retry:        r.Retry,
+		Stats:        r.Stats,
+		TaskVersion:  r.TaskVersion,
+		Params:       r.Params,
 	}
 
 	if !r.StartedAt.IsZero() {
@@ -364,6 +427,9 @@ func newRunResponse(r influxdb.Run) runResponse {
 	if !r.RequestedAt.IsZero() {
 		run.RequestedAt = &r.RequestedAt
 	}
+	if r.RetryOf.Valid() {
+		run.RetryOf = &r.RetryOf
+	}
 
 	return runResponse{
 		Links: map[string]string{
@@ -378,10 +444,18 @@ func newRunResponse(r influxdb.Run) runResponse {
 
 func convertRun(r httpRun) *influxdb.Run {
 	run := &influxdb.Run{
-		ID:     r.ID,
-		TaskID: r.TaskID,
-		Status: r.Status,
-		Log:    r.Log,
+		ID:          r.ID,
+		TaskID:      r.TaskID,
+		Status:      r.Status,
+		Retry:       r.Retry,
+		Stats:       r.Stats,
+		TaskVersion: r.TaskVersion,
+		Log:         r.Log,
+		Params:      r.Params,
+	}
+
+	if r.RetryOf != nil {
+		run.RetryOf = *r.RetryOf
 	}
 
 	if r.StartedAt != nil {
@@ -1045,7 +1119,7 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.TaskService.ForceRun(ctx, req.TaskID, req.Timestamp)
+	run, err := h.TaskService.ForceRun(ctx, req.TaskID, req.Timestamp, req.Params)
 	if err != nil {
 		err := &influxdb.Error{
 			Err: err,
@@ -1066,6 +1140,7 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 type forceRunRequest struct {
 	TaskID    influxdb.ID
 	Timestamp int64
+	Params    map[string]interface{}
 }
 
 func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunRequest, error) {
@@ -1084,10 +1159,11 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	}
 
 	var req struct {
-		ScheduledFor string `json:"scheduledFor"`
+		ScheduledFor string                 `json:"scheduledFor"`
+		Params       map[string]interface{} `json:"params"`
 	}
 
-	if r.ContentLength != 0 && r.ContentLength < 1000 { // prevent attempts to use up memory since r.Body should include at most one item (RunManually)
+	if r.ContentLength != 0 && r.ContentLength < 64<<10 { // prevent attempts to use up memory since r.Body should include at most one item (RunManually)
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			return forceRunRequest{}, err
 		}
@@ -1107,6 +1183,7 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	return forceRunRequest{
 		TaskID:    ti,
 		Timestamp: t.Unix(),
+		Params:    req.Params,
 	}, nil
 }
 
@@ -1643,14 +1720,18 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*
 }
 
 // ForceRun starts a run manually right now.
-func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (t TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
 	type body struct {
-		scheduledFor string
+		ScheduledFor string                 `json:"scheduledFor"`
+		Params       map[string]interface{} `json:"params,omitempty"`
+	}
+	b := body{
+		ScheduledFor: time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339),
+		Params:       params,
 	}
-	b := body{scheduledFor: time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339)}
 
 	rs := &runResponse{}
 	err := t.Client.
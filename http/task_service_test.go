@@ -983,7 +983,7 @@ func TestTaskHandler_NotFoundStatus(t *testing.T) {
 		{
 			name: "force run",
 			svc: &mock.TaskService{
-				ForceRunFn: func(_ context.Context, tid influxdb.ID, _ int64) (*influxdb.Run, error) {
+				ForceRunFn: func(_ context.Context, tid influxdb.ID, _ int64, _ map[string]interface{}) (*influxdb.Run, error) {
 					if tid != taskID {
 						return nil, influxdb.ErrTaskNotFound
 					}
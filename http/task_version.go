@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"go.uber.org/zap"
+)
+
+type taskVersionsResponse struct {
+	Versions []*influxdb.TaskVersion `json:"versions"`
+}
+
+// handleGetTaskVersions is the HTTP handler for the GET /api/v2/tasks/:id/versions route.
+func (h *TaskHandler) handleGetTaskVersions(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	versions, err := h.TaskVersionService.FindTaskVersions(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, taskVersionsResponse{Versions: versions}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetTaskVersion is the HTTP handler for the GET /api/v2/tasks/:id/versions/:version route.
+func (h *TaskHandler) handleGetTaskVersion(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	v, err := h.findTaskVersion(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, v); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleRollbackTask is the HTTP handler for the POST /api/v2/tasks/:id/versions/:version/rollback route.
+func (h *TaskHandler) handleRollbackTask(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "TaskHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	v, err := h.findTaskVersion(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// the task is updated to the script of the version, as a new version.
+	task, err := h.TaskService.UpdateTask(ctx, v.TaskID, influxdb.TaskUpdate{Flux: &v.Flux})
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err: err,
+			Msg: "failed to roll back task",
+		}, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID, ResourceType: influxdb.TasksResourceType})
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}, w)
+		return
+	}
+	h.log.Debug("Task rolled back",
+		zap.String("taskID", task.ID.String()),
+		zap.Int("toVersion", v.Version),
+		zap.Int("version", task.Version),
+	)
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// findTaskVersion returns the version of the task of the route.
+func (h *TaskHandler) findTaskVersion(ctx context.Context) (*influxdb.TaskVersion, error) {
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	s := httprouter.ParamsFromContext(ctx).ByName("version")
+	version, err := strconv.Atoi(s)
+	if err != nil || version < 1 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid task version %q", s),
+		}
+	}
+
+	return h.TaskVersionService.FindTaskVersion(ctx, taskID, version)
+}
+
+// FindTaskVersions returns the versions of the task taskID, oldest first.
+func (t TaskService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var resp taskVersionsResponse
+	err := t.Client.
+		Get(taskIDVersionsPath(taskID)).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Versions, nil
+}
+
+// FindTaskVersion returns a single version of the task taskID.
+func (t TaskService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var v influxdb.TaskVersion
+	err := t.Client.
+		Get(taskIDVersionsPath(taskID), strconv.Itoa(version)).
+		DecodeJSON(&v).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// RollbackTask updates the task taskID to the script of its version version.
+// The task gets a new version.
+func (t TaskService) RollbackTask(ctx context.Context, taskID influxdb.ID, version int) (*Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var tr taskResponse
+	err := t.Client.
+		Post(nil, taskIDVersionsPath(taskID), strconv.Itoa(version), "rollback").
+		DecodeJSON(&tr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tr.Task, nil
+}
+
+func taskIDVersionsPath(id influxdb.ID) string {
+	return path.Join(prefixTasks, id.String(), "versions")
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskHandler_Versions(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	version := func(taskID influxdb.ID, v int) *influxdb.TaskVersion {
+		return &influxdb.TaskVersion{
+			TaskID:    taskID,
+			OrgID:     10,
+			Version:   v,
+			Flux:      fmt.Sprintf(`option task = {name: "t%d", every: 1m}`, v),
+			Name:      fmt.Sprintf("t%d", v),
+			Every:     "1m",
+			CreatedBy: 2,
+			CreatedAt: createdAt.Add(time.Duration(v) * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		respBody   string
+		// flux is the script the task is expected to be updated to.
+		flux string
+	}{
+		{
+			name:       "list versions",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/versions",
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "versions": [
+    {
+      "taskID": "0000000000000064",
+      "orgID": "000000000000000a",
+      "version": 1,
+      "flux": "option task = {name: \"t1\", every: 1m}",
+      "name": "t1",
+      "every": "1m",
+      "offset": "0s",
+      "createdBy": "0000000000000002",
+      "createdAt": "2020-06-01T01:00:00Z"
+    },
+    {
+      "taskID": "0000000000000064",
+      "orgID": "000000000000000a",
+      "version": 2,
+      "flux": "option task = {name: \"t2\", every: 1m}",
+      "name": "t2",
+      "every": "1m",
+      "offset": "0s",
+      "createdBy": "0000000000000002",
+      "createdAt": "2020-06-01T02:00:00Z"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:       "get version",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/versions/1",
+			statusCode: http.StatusOK,
+			respBody: `
+{
+  "taskID": "0000000000000064",
+  "orgID": "000000000000000a",
+  "version": 1,
+  "flux": "option task = {name: \"t1\", every: 1m}",
+  "name": "t1",
+  "every": "1m",
+  "offset": "0s",
+  "createdBy": "0000000000000002",
+  "createdAt": "2020-06-01T01:00:00Z"
+}
+`,
+		},
+		{
+			name:       "get missing version",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/versions/3",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "get invalid version",
+			method:     "GET",
+			path:       "/api/v2/tasks/0000000000000064/versions/first",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "roll back",
+			method:     "POST",
+			path:       "/api/v2/tasks/0000000000000064/versions/1/rollback",
+			statusCode: http.StatusOK,
+			flux:       `option task = {name: "t1", every: 1m}`,
+		},
+		{
+			name:       "roll back to missing version",
+			method:     "POST",
+			path:       "/api/v2/tasks/0000000000000064/versions/3/rollback",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := mock.NewTaskVersionService()
+			vs.FindTaskVersionsF = func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+				return []*influxdb.TaskVersion{version(taskID, 1), version(taskID, 2)}, nil
+			}
+			vs.FindTaskVersionF = func(ctx context.Context, taskID influxdb.ID, v int) (*influxdb.TaskVersion, error) {
+				if v > 2 {
+					return nil, influxdb.ErrTaskVersionNotFound
+				}
+				return version(taskID, v), nil
+			}
+
+			var updated string
+			taskBE := NewMockTaskBackend(t)
+			taskBE.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			taskBE.TaskService = &mock.TaskService{
+				UpdateTaskFn: func(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
+					if upd.Flux == nil {
+						return nil, fmt.Errorf("unexpected update %+v", upd)
+					}
+					updated = *upd.Flux
+					return &influxdb.Task{ID: id, OrganizationID: 10, Flux: *upd.Flux, Version: 3}, nil
+				},
+			}
+			taskBE.TaskVersionService = vs
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBE)
+
+			r := httptest.NewRequest(tt.method, "http://localhost:9999"+tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.statusCode {
+				t.Errorf("got %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.respBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.respBody); err != nil {
+					t.Errorf("%q. error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. unexpected response ***%s***", tt.name, diff)
+				}
+			}
+			if updated != tt.flux {
+				t.Errorf("task updated to %q, want %q", updated, tt.flux)
+			}
+		})
+	}
+}
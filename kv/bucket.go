@@ -677,6 +677,10 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.DownsamplePolicies != nil {
+		b.DownsamplePolicies = *upd.DownsamplePolicies
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
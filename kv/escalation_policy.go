@@ -0,0 +1,345 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+var (
+	escalationPolicyBucket = []byte("escalationpoliciesv1")
+)
+
+var _ influxdb.EscalationPolicyService = (*Service)(nil)
+
+// escalatable is implemented by notification rules that generate flux
+// notifying the steps of an escalation policy.
+type escalatable interface {
+	GetEscalationPolicyID() influxdb.ID
+	SetEscalations([]rule.Escalation)
+}
+
+// FindEscalationPolicyByID retrieves an escalation policy by id.
+func (s *Service) FindEscalationPolicyByID(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+	var p *influxdb.EscalationPolicy
+	err := s.kv.View(ctx, func(tx Tx) error {
+		policy, err := s.findEscalationPolicyByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		p = policy
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindEscalationPolicyByID,
+			Err: err,
+		}
+	}
+	return p, nil
+}
+
+func (s *Service) findEscalationPolicyByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(escalationPolicyBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrEscalationPolicyNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var p influxdb.EscalationPolicy
+	if err := json.Unmarshal(v, &p); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &p, nil
+}
+
+// FindEscalationPolicies retrieves all escalation policies matching filter.
+func (s *Service) FindEscalationPolicies(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+	ps := []*influxdb.EscalationPolicy{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachEscalationPolicy(ctx, tx, func(p *influxdb.EscalationPolicy) bool {
+			if filter.OrgID == nil || p.OrgID == *filter.OrgID {
+				ps = append(ps, p)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindEscalationPolicies,
+			Err: err,
+		}
+	}
+	return ps, nil
+}
+
+// CreateEscalationPolicy creates an escalation policy and sets p.ID.
+func (s *Service) CreateEscalationPolicy(ctx context.Context, p *influxdb.EscalationPolicy) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := s.validEscalationPolicy(ctx, tx, p); err != nil {
+			return err
+		}
+
+		p.ID = s.IDGenerator.ID()
+		now := s.Now()
+		p.SetCreatedAt(now)
+		p.SetUpdatedAt(now)
+		return s.putEscalationPolicy(ctx, tx, p)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateEscalationPolicy,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateEscalationPolicy updates an escalation policy and the tasks of the
+// notification rules referencing it.
+func (s *Service) UpdateEscalationPolicy(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+	var p *influxdb.EscalationPolicy
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		policy, err := s.findEscalationPolicyByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(policy)
+		if err := s.validEscalationPolicy(ctx, tx, policy); err != nil {
+			return err
+		}
+		policy.SetUpdatedAt(s.Now())
+
+		if err := s.putEscalationPolicy(ctx, tx, policy); err != nil {
+			return err
+		}
+		p = policy
+
+		rules, err := s.findEscalatedNotificationRules(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		for _, nr := range rules {
+			if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateEscalationPolicy,
+			Err: err,
+		}
+	}
+	return p, nil
+}
+
+// DeleteEscalationPolicy removes an escalation policy that no notification
+// rule references.
+func (s *Service) DeleteEscalationPolicy(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findEscalationPolicyByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		rules, err := s.findEscalatedNotificationRules(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if len(rules) > 0 {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("escalation policy is used by notification rule %q", rules[0].GetName()),
+			}
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(escalationPolicyBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodedID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteEscalationPolicy,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// validEscalationPolicy returns an error if p is invalid or one of its steps
+// notifies an endpoint that is not in the organization of p.
+func (s *Service) validEscalationPolicy(ctx context.Context, tx Tx, p *influxdb.EscalationPolicy) error {
+	if err := p.Valid(); err != nil {
+		return err
+	}
+	for i, step := range p.Steps {
+		ep, err := s.findNotificationEndpointByID(ctx, tx, step.EndpointID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		if err != nil || ep.GetOrgID() != p.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("escalation step %d notifies endpoint %s which is not in the organization of the policy", i+1, step.EndpointID),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) putEscalationPolicy(ctx context.Context, tx Tx, p *influxdb.EscalationPolicy) error {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := p.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(escalationPolicyBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// forEachEscalationPolicy will iterate through all escalation policies while fn returns true.
+func (s *Service) forEachEscalationPolicy(ctx context.Context, tx Tx, fn func(*influxdb.EscalationPolicy) bool) error {
+	b, err := tx.Bucket(escalationPolicyBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		p := &influxdb.EscalationPolicy{}
+		if err := json.Unmarshal(v, p); err != nil {
+			return err
+		}
+		if !fn(p) {
+			break
+		}
+	}
+
+	return cur.Err()
+}
+
+// checkEscalatedNotificationEndpoint returns a conflict if an escalation
+// policy notifies the endpoint id.
+func (s *Service) checkEscalatedNotificationEndpoint(ctx context.Context, tx Tx, id influxdb.ID) error {
+	var used *influxdb.EscalationPolicy
+	err := s.forEachEscalationPolicy(ctx, tx, func(p *influxdb.EscalationPolicy) bool {
+		for _, step := range p.Steps {
+			if step.EndpointID == id {
+				used = p
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if used != nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("notification endpoint is used by escalation policy %q", used.Name),
+		}
+	}
+	return nil
+}
+
+// findEscalatedNotificationRules returns the notification rules referencing
+// the escalation policy id.
+func (s *Service) findEscalatedNotificationRules(ctx context.Context, tx Tx, id influxdb.ID) ([]influxdb.NotificationRule, error) {
+	var rules []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		if r, ok := nr.(escalatable); ok && r.GetEscalationPolicyID() == id {
+			rules = append(rules, nr)
+		}
+		return true
+	})
+	return rules, err
+}
+
+// setNotificationRuleEscalations sets the steps of the escalation policy of
+// nr along with their endpoints, so that the flux generated for nr notifies
+// them.
+func (s *Service) setNotificationRuleEscalations(ctx context.Context, tx Tx, nr influxdb.NotificationRule) error {
+	r, ok := nr.(escalatable)
+	if !ok {
+		return nil
+	}
+	if !r.GetEscalationPolicyID().Valid() {
+		r.SetEscalations(nil)
+		return nil
+	}
+
+	p, err := s.findEscalationPolicyByID(ctx, tx, r.GetEscalationPolicyID())
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	if err != nil || p.OrgID != nr.GetOrgID() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("escalation policy %s is not in the organization of the notification rule", r.GetEscalationPolicyID()),
+		}
+	}
+
+	es := make([]rule.Escalation, 0, len(p.Steps))
+	for _, step := range p.Steps {
+		ep, err := s.findNotificationEndpointByID(ctx, tx, step.EndpointID)
+		if err != nil {
+			return err
+		}
+		es = append(es, rule.Escalation{
+			Delay:    step.Delay.Duration,
+			Endpoint: ep,
+		})
+	}
+	r.SetEscalations(es)
+	return nil
+}
@@ -0,0 +1,145 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestEscalationPolicyService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), s, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+	})
+	svc.IDGenerator = mock.NewMockIDGenerator()
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	ctx := context.Background()
+	org := &influxdb.Organization{ID: 0x2000, Name: "org"}
+	if err := svc.PutOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	userID := influxdb.ID(0x1000)
+
+	newEndpoint := func(name string) *endpoint.Slack {
+		t.Helper()
+		ep := &endpoint.Slack{
+			Base: endpoint.Base{Name: name, OrgID: &org.ID, Status: influxdb.Active},
+			URL:  "http://localhost:7777/" + name,
+		}
+		if err := svc.CreateNotificationEndpoint(ctx, ep, userID); err != nil {
+			t.Fatal(err)
+		}
+		return ep
+	}
+	ep, onCall := newEndpoint("slack"), newEndpoint("on-call")
+
+	p := &influxdb.EscalationPolicy{
+		OrgID: org.ID,
+		Name:  "on call",
+		Steps: []influxdb.EscalationStep{
+			{EndpointID: *onCall.ID, Delay: influxdb.Duration{Duration: 15 * time.Minute}},
+		},
+	}
+	if err := svc.CreateEscalationPolicy(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	invalid := &influxdb.EscalationPolicy{
+		OrgID: org.ID,
+		Name:  "missing endpoint",
+		Steps: []influxdb.EscalationStep{{EndpointID: 0x9999, Delay: influxdb.Duration{Duration: time.Minute}}},
+	}
+	if err := svc.CreateEscalationPolicy(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid escalation policy, got %v", err)
+	}
+
+	got, err := svc.FindEscalationPolicyByID(ctx, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(p, got); diff != "" {
+		t.Fatalf("unexpected escalation policy -want/+got:\n%s", diff)
+	}
+	otherOrg := org.ID + 1
+	ps, err := svc.FindEscalationPolicies(ctx, influxdb.EscalationPolicyFilter{OrgID: &otherOrg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 0 {
+		t.Fatalf("expected no escalation policies for another org, got %v", ps)
+	}
+
+	every, err := notification.FromTimeDuration(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr := &rule.Slack{
+		Base: rule.Base{
+			Name:               "rule",
+			OrgID:              org.ID,
+			EndpointID:         *ep.ID,
+			EscalationPolicyID: p.ID,
+			Every:              &every,
+			StatusRules:        []notification.StatusRule{{CurrentLevel: notification.Critical}},
+		},
+		MessageTemplate: "msg",
+	}
+	if err := svc.CreateNotificationRule(ctx, influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}, userID); err != nil {
+		t.Fatal(err)
+	}
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	if flux := taskFlux(); !strings.Contains(flux, "int(v: 15m)") || !strings.Contains(flux, `"`+onCall.ID.String()+`"`) {
+		t.Fatalf("expected the rule task to escalate to the on call endpoint, got:\n%s", flux)
+	}
+
+	steps := []influxdb.EscalationStep{
+		{EndpointID: *onCall.ID, Delay: influxdb.Duration{Duration: 30 * time.Minute}},
+	}
+	if _, err := svc.UpdateEscalationPolicy(ctx, p.ID, influxdb.EscalationPolicyUpdate{Steps: steps}); err != nil {
+		t.Fatal(err)
+	}
+	if flux := taskFlux(); !strings.Contains(flux, "int(v: 30m)") {
+		t.Fatalf("expected the rule task to escalate after the updated delay, got:\n%s", flux)
+	}
+
+	if _, _, err := svc.DeleteNotificationEndpoint(ctx, *onCall.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict deleting an escalation endpoint, got %v", err)
+	}
+	if err := svc.DeleteEscalationPolicy(ctx, p.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict deleting a referenced escalation policy, got %v", err)
+	}
+
+	if err := svc.DeleteNotificationRule(ctx, nr.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteEscalationPolicy(ctx, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindEscalationPolicyByID(ctx, p.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected escalation policy not found, got %v", err)
+	}
+}
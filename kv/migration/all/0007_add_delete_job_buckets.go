@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var deleteJobBucket = []byte("deletejobsv1")
+
+// Migration0007_AddDeleteJobBuckets creates the buckets necessary for the delete job service to operate.
+var Migration0007_AddDeleteJobBuckets = migration.CreateBuckets(
+	"create delete job buckets",
+	deleteJobBucket,
+)
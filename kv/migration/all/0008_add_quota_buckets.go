@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var quotaBucket = []byte("quotasv1")
+
+// Migration0008_AddQuotaBuckets creates the buckets necessary for the quota service to operate.
+var Migration0008_AddQuotaBuckets = migration.CreateBuckets(
+	"create quota buckets",
+	quotaBucket,
+)
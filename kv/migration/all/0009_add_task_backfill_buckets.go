@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskBackfillBucket = []byte("taskbackfillsv1")
+
+// Migration0009_AddTaskBackfillBuckets creates the buckets necessary for the task backfill service to operate.
+var Migration0009_AddTaskBackfillBuckets = migration.CreateBuckets(
+	"create task backfill buckets",
+	taskBackfillBucket,
+)
@@ -0,0 +1,15 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var (
+	taskLeaseBucket      = []byte("taskleasesv1")
+	taskLeaseOwnerBucket = []byte("taskleaseownersv1")
+)
+
+// Migration0010_AddTaskLeaseBuckets creates the buckets necessary for task leasing to operate.
+var Migration0010_AddTaskLeaseBuckets = migration.CreateBuckets(
+	"create task lease buckets",
+	taskLeaseBucket,
+	taskLeaseOwnerBucket,
+)
@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskVersionBucket = []byte("taskversionsv1")
+
+// Migration0011_AddTaskVersionBuckets creates the buckets necessary for task versioning to operate.
+var Migration0011_AddTaskVersionBuckets = migration.CreateBuckets(
+	"create task version buckets",
+	taskVersionBucket,
+)
@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var silenceBucket = []byte("silencesv1")
+
+// Migration0012_AddSilenceBuckets creates the buckets necessary for notification silences to operate.
+var Migration0012_AddSilenceBuckets = migration.CreateBuckets(
+	"create silence buckets",
+	silenceBucket,
+)
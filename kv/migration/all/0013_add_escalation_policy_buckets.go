@@ -0,0 +1,11 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var escalationPolicyBucket = []byte("escalationpoliciesv1")
+
+// Migration0013_AddEscalationPolicyBuckets creates the buckets necessary for notification escalation policies to operate.
+var Migration0013_AddEscalationPolicyBuckets = migration.CreateBuckets(
+	"create escalation policy buckets",
+	escalationPolicyBucket,
+)
@@ -19,5 +19,19 @@ var Migrations = [...]migration.Spec{
 	Migration0005_AddPkgerBuckets,
 	// delete bucket sessionsv1
 	Migration0006_DeleteBucketSessionsv1,
+	// add delete job buckets
+	Migration0007_AddDeleteJobBuckets,
+	// add quota buckets
+	Migration0008_AddQuotaBuckets,
+	// add task backfill buckets
+	Migration0009_AddTaskBackfillBuckets,
+	// add task lease buckets
+	Migration0010_AddTaskLeaseBuckets,
+	// add task version buckets
+	Migration0011_AddTaskVersionBuckets,
+	// add silence buckets
+	Migration0012_AddSilenceBuckets,
+	// add escalation policy buckets
+	Migration0013_AddEscalationPolicyBuckets,
 	// {{ do_not_edit . }}
 }
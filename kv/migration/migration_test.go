@@ -5,12 +5,14 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/influxdata/influxdb/v2/bolt"
 	"github.com/influxdata/influxdb/v2/inmem"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/kv/migration"
+	"github.com/influxdata/influxdb/v2/sqlite"
 	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
@@ -40,6 +42,35 @@ func Test_Bolt_Migrator(t *testing.T) {
 	influxdbtesting.Migrator(t, store, newMigrator)
 }
 
+func Test_SQLite_Migrator(t *testing.T) {
+	store, closeSQLite, err := NewTestSQLiteStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeSQLite()
+
+	influxdbtesting.Migrator(t, store, newMigrator)
+}
+
+func NewTestSQLiteStore(t *testing.T) (kv.SchemaStore, func(), error) {
+	dir, err := ioutil.TempDir("", "influxdata-sqlite-")
+	if err != nil {
+		return nil, nil, errors.New("unable to create temporary sqlite directory")
+	}
+
+	s := sqlite.NewKVStore(zaptest.NewLogger(t), filepath.Join(dir, sqlite.DefaultFilename))
+	if err := s.Open(context.Background()); err != nil {
+		return nil, nil, err
+	}
+
+	close := func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+
+	return s, close, nil
+}
+
 func NewTestBoltStore(t *testing.T) (kv.SchemaStore, func(), error) {
 	f, err := ioutil.TempFile("", "influxdata-bolt-")
 	if err != nil {
@@ -323,6 +323,10 @@ func (s *Service) deleteNotificationEndpoint(ctx context.Context, tx Tx, id infl
 		return nil, 0, err
 	}
 
+	if err := s.checkEscalatedNotificationEndpoint(ctx, tx, id); err != nil {
+		return nil, 0, err
+	}
+
 	if err := s.endpointStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)}); err != nil {
 		return nil, 0, err
 	}
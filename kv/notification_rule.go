@@ -107,6 +107,13 @@ func (s *Service) createNotificationTask(ctx context.Context, tx Tx, r influxdb.
 		return nil, err
 	}
 
+	if err := s.setNotificationRuleSilences(ctx, tx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+	if err := s.setNotificationRuleEscalations(ctx, tx, r.NotificationRule); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -136,6 +143,13 @@ func (s *Service) updateNotificationTask(ctx context.Context, tx Tx, r influxdb.
 		return nil, err
 	}
 
+	if err := s.setNotificationRuleSilences(ctx, tx, r); err != nil {
+		return nil, err
+	}
+	if err := s.setNotificationRuleEscalations(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,252 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	quotaBucket = []byte("quotasv1")
+)
+
+var _ influxdb.QuotaService = (*Service)(nil)
+
+// FindQuotaByID retrieves a quota by id.
+func (s *Service) FindQuotaByID(ctx context.Context, id influxdb.ID) (*influxdb.Quota, error) {
+	var q *influxdb.Quota
+	err := s.kv.View(ctx, func(tx Tx) error {
+		quota, err := s.findQuotaByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		q = quota
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQuotaByID,
+			Err: err,
+		}
+	}
+	return q, nil
+}
+
+func (s *Service) findQuotaByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Quota, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(quotaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrQuotaNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var q influxdb.Quota
+	if err := json.Unmarshal(v, &q); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &q, nil
+}
+
+// FindQuotas retrieves all quotas matching filter. Quotas are few, so this
+// scans all of them.
+func (s *Service) FindQuotas(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+	qs := []*influxdb.Quota{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachQuota(ctx, tx, func(q *influxdb.Quota) bool {
+			if filterQuota(q, filter) {
+				qs = append(qs, q)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQuotas,
+			Err: err,
+		}
+	}
+	return qs, nil
+}
+
+func filterQuota(q *influxdb.Quota, filter influxdb.QuotaFilter) bool {
+	if filter.OrgID != nil && q.OrgID != *filter.OrgID {
+		return false
+	}
+	if filter.AuthorizationID != nil && (q.AuthorizationID == nil || *q.AuthorizationID != *filter.AuthorizationID) {
+		return false
+	}
+	return true
+}
+
+// CreateQuota creates a quota and sets q.ID. It is a conflict to create a
+// second quota for the same organization or authorization.
+func (s *Service) CreateQuota(ctx context.Context, q *influxdb.Quota) error {
+	if err := q.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQuota,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var exists bool
+		err := s.forEachQuota(ctx, tx, func(other *influxdb.Quota) bool {
+			exists = other.OrgID == q.OrgID && sameAuthorization(other.AuthorizationID, q.AuthorizationID)
+			return !exists
+		})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "a quota already exists for this organization or authorization",
+			}
+		}
+
+		q.ID = s.IDGenerator.ID()
+		now := s.Now()
+		q.SetCreatedAt(now)
+		q.SetUpdatedAt(now)
+		return s.putQuota(ctx, tx, q)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQuota,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func sameAuthorization(a, b *influxdb.ID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// UpdateQuota updates the limits of a quota.
+func (s *Service) UpdateQuota(ctx context.Context, id influxdb.ID, upd influxdb.QuotaUpdate) (*influxdb.Quota, error) {
+	var q *influxdb.Quota
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		quota, err := s.findQuotaByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(quota)
+		if err := quota.Valid(); err != nil {
+			return err
+		}
+		quota.SetUpdatedAt(s.Now())
+
+		if err := s.putQuota(ctx, tx, quota); err != nil {
+			return err
+		}
+		q = quota
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateQuota,
+			Err: err,
+		}
+	}
+	return q, nil
+}
+
+// DeleteQuota removes a quota.
+func (s *Service) DeleteQuota(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findQuotaByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(quotaBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodedID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteQuota,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putQuota(ctx context.Context, tx Tx, q *influxdb.Quota) error {
+	v, err := json.Marshal(q)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := q.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(quotaBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// forEachQuota will iterate through all quotas while fn returns true.
+func (s *Service) forEachQuota(ctx context.Context, tx Tx, fn func(*influxdb.Quota) bool) error {
+	b, err := tx.Bucket(quotaBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		q := &influxdb.Quota{}
+		if err := json.Unmarshal(v, q); err != nil {
+			return err
+		}
+		if !fn(q) {
+			break
+		}
+	}
+
+	return cur.Err()
+}
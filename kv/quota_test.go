@@ -0,0 +1,90 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestQuotaService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	svc.IDGenerator = &mock.MockIDGenerator{Count: 1}
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	ctx := context.Background()
+	orgID, authID := influxdb.ID(0x1000), influxdb.ID(0x2000)
+
+	orgQuota := &influxdb.Quota{OrgID: orgID, PointsPerSecond: 1000}
+	if err := svc.CreateQuota(ctx, orgQuota); err != nil {
+		t.Fatal(err)
+	}
+	authQuota := &influxdb.Quota{OrgID: orgID, AuthorizationID: &authID, QueriesPerMinute: 10}
+	if err := svc.CreateQuota(ctx, authQuota); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.CreateQuota(ctx, &influxdb.Quota{OrgID: orgID}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict creating a second org quota, got %v", err)
+	}
+	if err := svc.CreateQuota(ctx, &influxdb.Quota{OrgID: orgID, BytesPerSecond: -1}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid quota, got %v", err)
+	}
+
+	got, err := svc.FindQuotaByID(ctx, orgQuota.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &influxdb.Quota{
+		ID:              1,
+		OrgID:           orgID,
+		PointsPerSecond: 1000,
+		CRUDLog:         influxdb.CRUDLog{CreatedAt: now, UpdatedAt: now},
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("unexpected quota -want/+got:\n%s", diff)
+	}
+
+	qs, err := svc.FindQuotas(ctx, influxdb.QuotaFilter{AuthorizationID: &authID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 || qs[0].ID != authQuota.ID {
+		t.Fatalf("expected the authorization quota, got %+v", qs)
+	}
+
+	concurrent := int64(4)
+	updated, err := svc.UpdateQuota(ctx, orgQuota.ID, influxdb.QuotaUpdate{ConcurrentQueries: &concurrent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ConcurrentQueries != 4 || updated.PointsPerSecond != 1000 {
+		t.Fatalf("unexpected updated quota %+v", updated)
+	}
+
+	if err := svc.DeleteQuota(ctx, orgQuota.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindQuotaByID(ctx, orgQuota.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	qs, err = svc.FindQuotas(ctx, influxdb.QuotaFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 {
+		t.Fatalf("expected one remaining quota, got %d", len(qs))
+	}
+}
@@ -0,0 +1,286 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var (
+	silenceBucket = []byte("silencesv1")
+)
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+// silenceable is implemented by notification rules that generate flux
+// respecting silences.
+type silenceable interface {
+	SetSilences([]*influxdb.Silence)
+}
+
+// FindSilenceByID retrieves a silence by id.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		silence, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		sl = silence
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSilenceByID,
+			Err: err,
+		}
+	}
+	return sl, nil
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrSilenceNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sl influxdb.Silence
+	if err := json.Unmarshal(v, &sl); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return &sl, nil
+}
+
+// FindSilences retrieves all silences matching filter.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	var ss []*influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		ss, err = s.findSilences(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSilences,
+			Err: err,
+		}
+	}
+	return ss, nil
+}
+
+func (s *Service) findSilences(ctx context.Context, tx Tx, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	now := s.Now()
+	ss := []*influxdb.Silence{}
+	err := s.forEachSilence(ctx, tx, func(sl *influxdb.Silence) bool {
+		if filter.OrgID != nil && sl.OrgID != *filter.OrgID {
+			return true
+		}
+		if filter.Expired != nil && *filter.Expired != (sl.State(now) == influxdb.SilenceExpired) {
+			return true
+		}
+		ss = append(ss, sl)
+		return true
+	})
+	return ss, err
+}
+
+// CreateSilence creates a silence, sets sl.ID and updates the tasks of the
+// notification rules of its organization to respect it.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	if err := sl.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateSilence,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		sl.ID = s.IDGenerator.ID()
+		now := s.Now()
+		sl.SetCreatedAt(now)
+		sl.SetUpdatedAt(now)
+		if err := s.putSilence(ctx, tx, sl); err != nil {
+			return err
+		}
+		return s.updateSilencedNotificationTasks(ctx, tx, sl.OrgID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateSilence,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateSilence updates a silence and the tasks of the notification rules of
+// its organization.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		silence, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(silence)
+		if err := silence.Valid(); err != nil {
+			return err
+		}
+		silence.SetUpdatedAt(s.Now())
+
+		if err := s.putSilence(ctx, tx, silence); err != nil {
+			return err
+		}
+		sl = silence
+		return s.updateSilencedNotificationTasks(ctx, tx, silence.OrgID)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateSilence,
+			Err: err,
+		}
+	}
+	return sl, nil
+}
+
+// DeleteSilence removes a silence and updates the tasks of the notification
+// rules of its organization.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		sl, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(silenceBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(encodedID); err != nil {
+			return err
+		}
+		return s.updateSilencedNotificationTasks(ctx, tx, sl.OrgID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteSilence,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := sl.ID.Encode()
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// forEachSilence will iterate through all silences while fn returns true.
+func (s *Service) forEachSilence(ctx context.Context, tx Tx, fn func(*influxdb.Silence) bool) error {
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		sl := &influxdb.Silence{}
+		if err := json.Unmarshal(v, sl); err != nil {
+			return err
+		}
+		if !fn(sl) {
+			break
+		}
+	}
+
+	return cur.Err()
+}
+
+// setNotificationRuleSilences sets the silences of the organization of nr that
+// have not expired, so that the flux generated for nr respects them.
+func (s *Service) setNotificationRuleSilences(ctx context.Context, tx Tx, nr influxdb.NotificationRule) error {
+	r, ok := nr.(silenceable)
+	if !ok {
+		return nil
+	}
+
+	orgID, expired := nr.GetOrgID(), false
+	ss, err := s.findSilences(ctx, tx, influxdb.SilenceFilter{OrgID: &orgID, Expired: &expired})
+	if err != nil {
+		return err
+	}
+	r.SetSilences(ss)
+	return nil
+}
+
+// updateSilencedNotificationTasks regenerates the tasks of the notification
+// rules of an organization after its silences changed.
+func (s *Service) updateSilencedNotificationTasks(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	var rules []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		if nr.GetOrgID() == orgID {
+			rules = append(rules, nr)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range rules {
+		if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSilenceService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), s, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+	})
+	svc.IDGenerator = mock.NewMockIDGenerator()
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	ctx := context.Background()
+	org := &influxdb.Organization{ID: 0x2000, Name: "org"}
+	if err := svc.PutOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	userID := influxdb.ID(0x1000)
+
+	ep := &endpoint.Slack{
+		Base: endpoint.Base{Name: "slack", OrgID: &org.ID, Status: influxdb.Active},
+		URL:  "http://localhost:7777",
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, ep, userID); err != nil {
+		t.Fatal(err)
+	}
+	every, err := notification.FromTimeDuration(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nr := &rule.Slack{
+		Base: rule.Base{
+			Name:        "rule",
+			OrgID:       org.ID,
+			EndpointID:  *ep.ID,
+			Every:       &every,
+			StatusRules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+		},
+		MessageTemplate: "msg",
+	}
+	if err := svc.CreateNotificationRule(ctx, influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}, userID); err != nil {
+		t.Fatal(err)
+	}
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	if strings.Contains(taskFlux(), "silence_id") {
+		t.Fatal("expected a rule task without silences")
+	}
+
+	sl := &influxdb.Silence{
+		OrgID:     org.ID,
+		Matchers:  []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal}},
+		StartsAt:  now.Add(time.Hour),
+		EndsAt:    now.Add(2 * time.Hour),
+		CreatedBy: userID,
+		Comment:   "maintenance",
+	}
+	if err := svc.CreateSilence(ctx, sl); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSilence(ctx, &influxdb.Silence{OrgID: org.ID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid silence, got %v", err)
+	}
+
+	got, err := svc.FindSilenceByID(ctx, sl.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(sl, got); diff != "" {
+		t.Fatalf("unexpected silence -want/+got:\n%s", diff)
+	}
+	if flux := taskFlux(); !strings.Contains(flux, `"`+sl.ID.String()+`"`) {
+		t.Fatalf("expected the rule task to respect the silence, got:\n%s", flux)
+	}
+
+	past := now.Add(-time.Hour)
+	expired := &influxdb.Silence{
+		OrgID:    org.ID,
+		Matchers: []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "db02"}, Operator: influxdb.Equal}},
+		StartsAt: past.Add(-time.Hour),
+		EndsAt:   past,
+	}
+	if err := svc.CreateSilence(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(taskFlux(), `"`+expired.ID.String()+`"`) {
+		t.Fatal("expected the rule task to ignore the expired silence")
+	}
+
+	isExpired := true
+	ss, err := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID, Expired: &isExpired})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != 1 || ss[0].ID != expired.ID {
+		t.Fatalf("expected the expired silence, got %v", ss)
+	}
+	otherOrg := org.ID + 1
+	ss, err = svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &otherOrg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != 0 {
+		t.Fatalf("expected no silences for another org, got %v", ss)
+	}
+
+	endsAt := now.Add(3 * time.Hour)
+	updated, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{EndsAt: &endsAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.EndsAt.Equal(endsAt) {
+		t.Fatalf("expected the silence to end at %s, got %s", endsAt, updated.EndsAt)
+	}
+	if _, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{EndsAt: &past}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid silence update, got %v", err)
+	}
+
+	if err := svc.DeleteSilence(ctx, sl.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindSilenceByID(ctx, sl.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected silence not found, got %v", err)
+	}
+	if strings.Contains(taskFlux(), "silence_id") {
+		t.Fatal("expected the rule task to no longer respect the deleted silence")
+	}
+}
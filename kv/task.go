@@ -24,6 +24,8 @@ import (
 //   <taskID>/latestCompleted: run data for the latest completed run of a task
 // taskIndexBucket
 //   <orgID>/<taskID>: index for tasks by org
+// taskVersionBucket
+//   <taskID>/<version>: versions of the script of a task
 
 // We may want to add a <taskName>/<taskID> index to allow us to look up tasks by task name.
 
@@ -47,9 +49,16 @@ type kvTask struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Timezone        string                 `json:"timezone,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	Offset          influxdb.Duration      `json:"offset,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`
+	CatchUp         string                 `json:"catchUp,omitempty"`
+	MaxMissedRuns   int64                  `json:"maxMissedRuns,omitempty"`
+	DependsOn       []influxdb.ID          `json:"dependsOn,omitempty"`
+	Params          []influxdb.TaskParam   `json:"params,omitempty"`
+	Version         int                    `json:"version,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
@@ -58,6 +67,12 @@ type kvTask struct {
 }
 
 func kvToInfluxTask(k *kvTask) *influxdb.Task {
+	// tasks created before versioning are at their first version.
+	version := k.Version
+	if version == 0 {
+		version = 1
+	}
+
 	return &influxdb.Task{
 		ID:              k.ID,
 		Type:            k.Type,
@@ -70,9 +85,16 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		Flux:            k.Flux,
 		Every:           k.Every,
 		Cron:            k.Cron,
+		Timezone:        k.Timezone,
 		LastRunStatus:   k.LastRunStatus,
 		LastRunError:    k.LastRunError,
 		Offset:          k.Offset.Duration,
+		Retry:           k.Retry,
+		CatchUp:         k.CatchUp,
+		MaxMissedRuns:   k.MaxMissedRuns,
+		DependsOn:       k.DependsOn,
+		Params:          k.Params,
+		Version:         version,
 		LatestCompleted: k.LatestCompleted,
 		LatestScheduled: k.LatestScheduled,
 		CreatedAt:       k.CreatedAt,
@@ -685,6 +707,8 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		Flux:            tc.Flux,
 		Every:           opts.Every.String(),
 		Cron:            opts.Cron,
+		Timezone:        opts.Timezone,
+		Version:         1,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
@@ -699,6 +723,25 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	}
 
+	if opts.Retry != nil {
+		task.Retry = *opts.Retry
+	}
+
+	task.CatchUp = opts.CatchUp
+	if opts.MaxMissedRuns != nil {
+		task.MaxMissedRuns = *opts.MaxMissedRuns
+	}
+
+	task.DependsOn = uniqueTaskIDs(tc.DependsOn)
+	if err := s.checkTaskDependencies(ctx, tx, task); err != nil {
+		return nil, err
+	}
+
+	task.Params = tc.Params
+	if err := influxdb.ValidateTaskParams(s.FluxLanguageService, task.Flux, task.Params); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -736,6 +779,10 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	if err := s.createTaskVersion(ctx, tx, task, createdAt); err != nil {
+		return nil, err
+	}
+
 	if !feature.UrmFreeTasks().Enabled(ctx) {
 		if err := s.createTaskURM(ctx, tx, task); err != nil {
 			s.log.Info("Error creating user resource mapping for task", zap.Stringer("taskID", task.ID), zap.Error(err))
@@ -813,6 +860,8 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 
 	// update the flux script
 	if !upd.Options.IsZero() || upd.Flux != nil {
+		from := *task
+
 		if err = upd.UpdateFlux(s.FluxLanguageService, task.Flux); err != nil {
 			return nil, err
 		}
@@ -825,6 +874,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.Name = opts.Name
 		task.Every = opts.Every.String()
 		task.Cron = opts.Cron
+		task.Timezone = opts.Timezone
 
 		var off time.Duration
 		if opts.Offset != nil {
@@ -834,7 +884,28 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 			}
 		}
 		task.Offset = off
+
+		task.Retry = 0
+		if opts.Retry != nil {
+			task.Retry = *opts.Retry
+		}
+
+		task.CatchUp = opts.CatchUp
+		task.MaxMissedRuns = 0
+		if opts.MaxMissedRuns != nil {
+			task.MaxMissedRuns = *opts.MaxMissedRuns
+		}
 		task.UpdatedAt = updatedAt
+
+		if task.Flux != from.Flux {
+			if err := s.ensureTaskVersion(ctx, tx, &from); err != nil {
+				return nil, err
+			}
+			task.Version = from.Version + 1
+			if err := s.createTaskVersion(ctx, tx, task, updatedAt); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if upd.Description != nil {
@@ -859,6 +930,25 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.DependsOn != nil {
+		task.DependsOn = uniqueTaskIDs(*upd.DependsOn)
+		if err := s.checkTaskDependencies(ctx, tx, task); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = updatedAt
+	}
+
+	if upd.Params != nil {
+		task.Params = *upd.Params
+		task.UpdatedAt = updatedAt
+	}
+	if upd.Params != nil || upd.Flux != nil {
+		// the script may read parameters the task no longer declares.
+		if err := influxdb.ValidateTaskParams(s.FluxLanguageService, task.Flux, task.Params); err != nil {
+			return nil, err
+		}
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -921,6 +1011,74 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 	return task, nil
 }
 
+// checkTaskDependencies returns an error if an upstream task of t is not a task
+// of its organization, or if t is one of the upstream tasks of its upstream tasks.
+func (s *Service) checkTaskDependencies(ctx context.Context, tx Tx, t *influxdb.Task) error {
+	for _, id := range t.DependsOn {
+		if id == t.ID {
+			return influxdb.ErrInvalidTaskDependency(id, "a task cannot depend on itself")
+		}
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return influxdb.ErrInvalidTaskDependency(id, "task not found")
+		}
+		if err != nil {
+			return err
+		}
+		if upstream.OrganizationID != t.OrganizationID {
+			return influxdb.ErrInvalidTaskDependency(id, "task belongs to another organization")
+		}
+	}
+
+	// walk the upstream tasks depth first, the path leading back to t is the cycle.
+	visited := make(map[influxdb.ID]bool)
+	var walk func(path []influxdb.ID) error
+	walk = func(path []influxdb.ID) error {
+		id := path[len(path)-1]
+		if id == t.ID {
+			return influxdb.ErrTaskDependencyCycle(append([]influxdb.ID{t.ID}, path...))
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			// deleted upstream tasks no longer hold their downstream tasks back.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, next := range upstream.DependsOn {
+			if err := walk(append(path[:len(path):len(path)], next)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range t.DependsOn {
+		if err := walk([]influxdb.ID{id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueTaskIDs returns ids without duplicates, in order, or nil if ids is empty.
+func uniqueTaskIDs(ids []influxdb.ID) []influxdb.ID {
+	var unique []influxdb.ID
+	seen := make(map[influxdb.ID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
@@ -1005,6 +1163,10 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	if err := s.deleteTaskVersions(ctx, tx, task.ID); err != nil {
+		return err
+	}
+
 	if err := s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID: task.ID,
 	}); err != nil {
@@ -1094,6 +1256,11 @@ func (s *Service) findRuns(ctx context.Context, tx Tx, filter influxdb.RunFilter
 		return nil, 0, influxdb.ErrOutOfBoundsLimit
 	}
 
+	after, before, err := filter.ScheduledRange()
+	if err != nil {
+		return nil, 0, err
+	}
+
 	var runs []*influxdb.Run
 	// manual runs
 	manualRuns, err := s.manualRuns(ctx, tx, filter.Task)
@@ -1101,6 +1268,9 @@ func (s *Service) findRuns(ctx context.Context, tx Tx, filter influxdb.RunFilter
 		return nil, 0, err
 	}
 	for _, run := range manualRuns {
+		if !influxdb.ScheduledWithin(run.ScheduledFor, after, before) {
+			continue
+		}
 		runs = append(runs, run)
 		if len(runs) >= filter.Limit {
 			return runs, len(runs), nil
@@ -1113,6 +1283,9 @@ func (s *Service) findRuns(ctx context.Context, tx Tx, filter influxdb.RunFilter
 		return nil, 0, err
 	}
 	for _, run := range currentlyRunning {
+		if !influxdb.ScheduledWithin(run.ScheduledFor, after, before) {
+			continue
+		}
 		runs = append(runs, run)
 		if len(runs) >= filter.Limit {
 			return runs, len(runs), nil
@@ -1237,6 +1410,8 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 	r.StartedAt = time.Time{}
 	r.FinishedAt = time.Time{}
 	r.RequestedAt = time.Time{}
+	r.RetryOf = runID
+	r.Retry++
 
 	// add a clean copy of the run to the manual runs
 	bucket, err := tx.Bucket(taskRunBucket)
@@ -1282,10 +1457,10 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 
 // ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 // The value of scheduledFor may or may not align with the task's schedule.
-func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.forceRun(ctx, tx, taskID, scheduledFor)
+		run, err := s.forceRun(ctx, tx, taskID, scheduledFor, params)
 		if err != nil {
 			return err
 		}
@@ -1295,7 +1470,17 @@ func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor
 	return r, err
 }
 
-func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
+	if len(params) > 0 {
+		task, err := s.findTaskByID(ctx, tx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if params, err = influxdb.ParseTaskParamValues(task.Params, params); err != nil {
+			return nil, err
+		}
+	}
+
 	// create a run
 	t := time.Unix(scheduledFor, 0).UTC()
 	r := &influxdb.Run{
@@ -1305,23 +1490,56 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 		RequestedAt:  time.Now().UTC(),
 		ScheduledFor: t,
 		Log:          []influxdb.Log{},
+		Params:       params,
 	}
 
-	// add a clean copy of the run to the manual runs
+	if err := s.queueManualRun(ctx, tx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateRetryRun queues a manual run retrying the failed run, to be executed once runAt has passed.
+func (s *Service) CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	r := &influxdb.Run{
+		ID:           s.IDGenerator.ID(),
+		TaskID:       run.TaskID,
+		Status:       influxdb.RunScheduled.String(),
+		RequestedAt:  time.Now().UTC(),
+		ScheduledFor: run.ScheduledFor,
+		RunAt:        runAt.UTC(),
+		RetryOf:      run.ID,
+		Retry:        run.Retry + 1,
+		Log:          []influxdb.Log{},
+		Params:       run.Params,
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.queueManualRun(ctx, tx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// queueManualRun adds r to the manual runs of its task unless a run
+// with the same scheduled for time is already queued.
+func (s *Service) queueManualRun(ctx context.Context, tx Tx, r *influxdb.Run) error {
 	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
-		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	runs, err := s.manualRuns(ctx, tx, taskID)
+	runs, err := s.manualRuns(ctx, tx, r.TaskID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// check to see if this run is already queued
 	for _, run := range runs {
 		if run.ScheduledFor == r.ScheduledFor {
-			return nil, influxdb.ErrTaskRunAlreadyQueued
+			return influxdb.ErrTaskRunAlreadyQueued
 		}
 	}
 	runs = append(runs, r)
@@ -1329,19 +1547,19 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 	// save manual runs
 	runsBytes, err := json.Marshal(runs)
 	if err != nil {
-		return nil, influxdb.ErrInternalTaskServiceError(err)
+		return influxdb.ErrInternalTaskServiceError(err)
 	}
 
-	key, err := taskManualRunKey(taskID)
+	key, err := taskManualRunKey(r.TaskID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := bucket.Put(key, runsBytes); err != nil {
-		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	return r, nil
+	return nil
 }
 
 // CreateRun creates a run with a scheduledFor time as now.
@@ -1358,6 +1576,11 @@ func (s *Service) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFo
 	return r, err
 }
 func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
 	id := s.IDGenerator.ID()
 	t := time.Unix(scheduledFor.Unix(), 0).UTC()
 
@@ -1367,6 +1590,7 @@ func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, sche
 		ScheduledFor: t,
 		RunAt:        runAt,
 		Status:       influxdb.RunScheduled.String(),
+		TaskVersion:  task.Version,
 		Log:          []influxdb.Log{},
 	}
 
@@ -1525,6 +1749,13 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID influ
 		return nil, influxdb.ErrRunNotFound
 	}
 
+	// the run executes the version of the task it starts with.
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	run.TaskVersion = task.Version
+
 	// save manual runs
 	mRunsBytes, err := json.Marshal(mRuns)
 	if err != nil {
@@ -1590,7 +1821,7 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 		LatestCompleted: &scheduled,
 		LastRunStatus:   &r.Status,
 		LastRunError: func() *string {
-			if r.Status == "failed" {
+			if r.Status == "failed" || r.Status == influxdb.RunRetried.String() {
 				// prefer the second to last log message as the error message
 				// per https://github.com/influxdata/influxdb/issues/15153#issuecomment-547706005
 				if len(r.Log) > 1 {
@@ -1646,7 +1877,7 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 	switch state {
 	case influxdb.RunStarted:
 		run.StartedAt = when
-	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled:
+	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled, influxdb.RunRetried:
 		run.FinishedAt = when
 	}
 
@@ -1674,8 +1905,13 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 
 // AddRunLog adds a log line to the run.
 func (s *Service) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
+	return s.AddRunLogEntry(ctx, taskID, runID, influxdb.Log{Time: when.Format(time.RFC3339Nano), Message: log})
+}
+
+// AddRunLogEntry adds a structured log entry to the run.
+func (s *Service) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, entry influxdb.Log) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		err := s.addRunLog(ctx, tx, taskID, runID, when, log)
+		err := s.addRunLog(ctx, tx, taskID, runID, entry)
 		if err != nil {
 			return err
 		}
@@ -1684,16 +1920,32 @@ func (s *Service) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when
 	return err
 }
 
-func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID influxdb.ID, when time.Time, log string) error {
+func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID influxdb.ID, entry influxdb.Log) error {
 	// find run
 	run, err := s.findRunByID(ctx, tx, taskID, runID)
 	if err != nil {
 		return err
 	}
 	// update log
-	l := influxdb.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log}
-	run.Log = append(run.Log, l)
-	// save run
+	entry.RunID = runID
+	run.Log = append(run.Log, entry)
+	return s.putRun(ctx, tx, taskID, run)
+}
+
+// SetRunStats sets the statistics of the query of the run.
+func (s *Service) SetRunStats(ctx context.Context, taskID, runID influxdb.ID, stats *influxdb.RunStats) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		run, err := s.findRunByID(ctx, tx, taskID, runID)
+		if err != nil {
+			return err
+		}
+		run.Stats = stats
+		return s.putRun(ctx, tx, taskID, run)
+	})
+}
+
+// putRun saves the run of the task taskID.
+func (s *Service) putRun(ctx context.Context, tx Tx, taskID influxdb.ID, run *influxdb.Run) error {
 	b, err := tx.Bucket(taskRunBucket)
 	if err != nil {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -248,6 +249,158 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_TaskDependencies(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	create := func(name string, dependsOn ...influxdb.ID) (*influxdb.Task, error) {
+		return ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: %q, every: 1h} from(bucket:"test") |> range(start:-1h)`, name),
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+			DependsOn:      dependsOn,
+		})
+	}
+
+	a, err := create("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := create("b", a.ID, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]influxdb.ID{a.ID}, b.DependsOn); diff != "" {
+		t.Fatalf("unexpected upstream tasks -want/+got:\n%s", diff)
+	}
+	c, err := create("c", b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := create("d", influxdb.ID(0xdead)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid dependency on a missing task, got %v", err)
+	}
+
+	self := []influxdb.ID{a.ID}
+	if _, err := ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &self}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid dependency of a task on itself, got %v", err)
+	}
+
+	cycle := []influxdb.ID{c.ID}
+	_, err = ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &cycle})
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected dependency cycle, got %v", err)
+	}
+	exp := fmt.Sprintf("task dependency cycle: %s -> %s -> %s -> %s", a.ID, c.ID, b.ID, a.ID)
+	if msg := influxdb.ErrorMessage(err); msg != exp {
+		t.Fatalf("unexpected error message %q, expected %q", msg, exp)
+	}
+
+	none := []influxdb.ID{}
+	updated, err := ts.Service.UpdateTask(ctx, b.ID, influxdb.TaskUpdate{DependsOn: &none})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.DependsOn) != 0 {
+		t.Fatalf("expected no upstream tasks, got %v", updated.DependsOn)
+	}
+	if _, err := ts.Service.UpdateTask(ctx, a.ID, influxdb.TaskUpdate{DependsOn: &cycle}); err != nil {
+		t.Fatalf("expected no cycle once b no longer depends on a, got %v", err)
+	}
+}
+
+func TestService_TaskVersions(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	c := clock.NewMock()
+	c.Set(time.Unix(1000, 0))
+
+	ts := newService(t, ctx, c)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	v1 := `option task = {name: "a task", every: 1h} from(bucket:"test") |> range(start:-1h)`
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           v1,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+	if task.Version != 1 {
+		t.Fatalf("expected a created task at version 1, got %d", task.Version)
+	}
+
+	// only changes to the script create a version.
+	inactive := string(influxdb.TaskInactive)
+	c.Add(time.Second)
+	if task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Status: &inactive}); err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+	if task.Version != 1 {
+		t.Fatalf("expected a status update to keep version 1, got %d", task.Version)
+	}
+
+	v2 := `option task = {name: "a task", every: 2h} from(bucket:"test") |> range(start:-2h)`
+	c.Add(time.Second)
+	if task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Flux: &v2}); err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+	if task.Version != 2 {
+		t.Fatalf("expected a script update to create version 2, got %d", task.Version)
+	}
+
+	versions, err := ts.Service.FindTaskVersions(ctx, task.ID)
+	if err != nil {
+		t.Fatal("FindTaskVersions", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions))
+	}
+	for i, exp := range []struct {
+		flux      string
+		every     string
+		createdAt time.Time
+	}{
+		{v1, "1h", time.Unix(1000, 0)},
+		{v2, "2h", time.Unix(1002, 0)},
+	} {
+		v := versions[i]
+		if v.Version != i+1 || v.Flux != exp.flux || v.Every != exp.every || v.CreatedBy != ts.User.ID || !v.CreatedAt.Equal(exp.createdAt) {
+			t.Fatalf("unexpected version %d: %+v", i+1, v)
+		}
+	}
+
+	// a run records the version it executes.
+	run, err := ts.Service.CreateRun(ctx, task.ID, c.Now(), c.Now())
+	if err != nil {
+		t.Fatal("CreateRun", err)
+	}
+	if run.TaskVersion != 2 {
+		t.Fatalf("expected a run of version 2, got %d", run.TaskVersion)
+	}
+
+	if _, err := ts.Service.FindTaskVersion(ctx, task.ID, 3); err != influxdb.ErrTaskVersionNotFound {
+		t.Fatalf("expected version not found, got %v", err)
+	}
+
+	if err := ts.Service.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal("DeleteTask", err)
+	}
+	if _, err := ts.Service.FindTaskVersions(ctx, task.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected the versions of a deleted task not found, got %v", err)
+	}
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, close, err := NewTestBoltStore(t)
 	if err != nil {
@@ -319,6 +472,62 @@ func TestTaskRunCancellation(t *testing.T) {
 	}
 }
 
+func TestTaskRunStatsAndLogEntries(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h) |> to(bucket:"other")`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := ts.Service.CreateRun(ctx, task.ID, time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := influxdb.Log{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   influxdb.LogLevelWarn,
+		Message: "Task limit reached",
+		Fields:  map[string]string{"reason": "concurrency"},
+	}
+	if err := ts.Service.AddRunLogEntry(ctx, task.ID, run.ID, entry); err != nil {
+		t.Fatal(err)
+	}
+	stats := &influxdb.RunStats{
+		RowsRead:      10,
+		PointsWritten: map[influxdb.ID]int64{ts.Org.ID: 10},
+		QueryDuration: influxdb.Duration{Duration: time.Second},
+		MaxAllocated:  2048,
+	}
+	if err := ts.Service.SetRunStats(ctx, task.ID, run.ID, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ts.Service.FindRunByID(ctx, task.ID, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry.RunID = run.ID
+	if diff := cmp.Diff([]influxdb.Log{entry}, got.Log); diff != "" {
+		t.Fatalf("unexpected run log -want/+got:\n%s", diff)
+	}
+	if diff := cmp.Diff(stats, got.Stats); diff != "" {
+		t.Fatalf("unexpected run stats -want/+got:\n%s", diff)
+	}
+}
+
 type taskOptions struct {
 	name        string
 	every       string
@@ -356,7 +565,6 @@ func TestExtractTaskOptions(t *testing.T) {
 				name:        "whatever",
 				cron:        "* * * * *",
 				concurrency: 1,
-				retry:       1,
 			},
 		},
 		{
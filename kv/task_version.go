@@ -0,0 +1,215 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+)
+
+var taskVersionBucket = []byte("taskversionsv1")
+
+var _ influxdb.TaskVersionService = (*Service)(nil)
+
+// FindTaskVersions returns the versions of the task taskID, oldest first.
+func (s *Service) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	var versions []*influxdb.TaskVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		task, err := s.findTaskByID(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		vs, err := s.findTaskVersions(ctx, tx, task.ID)
+		if err != nil {
+			return err
+		}
+		// tasks created before versioning have their first version stored
+		// when their script first changes.
+		if len(vs) == 0 {
+			vs = append(vs, initialTaskVersion(task))
+		}
+		versions = vs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// FindTaskVersion returns a single version of the task taskID.
+func (s *Service) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	var v *influxdb.TaskVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		task, err := s.findTaskByID(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		tv, err := s.findTaskVersion(ctx, tx, task.ID, version)
+		if err == influxdb.ErrTaskVersionNotFound && version == task.Version {
+			tv, err = initialTaskVersion(task), nil
+		}
+		if err != nil {
+			return err
+		}
+		v = tv
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (s *Service) findTaskVersions(ctx context.Context, tx Tx, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskVersionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := b.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	var versions []*influxdb.TaskVersion
+	err = WalkCursor(ctx, c, func(_, v []byte) error {
+		tv := &influxdb.TaskVersion{}
+		if err := json.Unmarshal(v, tv); err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		versions = append(versions, tv)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (s *Service) findTaskVersion(ctx context.Context, tx Tx, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskVersionKey(taskID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, influxdb.ErrTaskVersionNotFound
+	}
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	tv := &influxdb.TaskVersion{}
+	if err := json.Unmarshal(v, tv); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return tv, nil
+}
+
+// createTaskVersion stores the current script of task as its version
+// task.Version, created by the user of ctx at createdAt.
+func (s *Service) createTaskVersion(ctx context.Context, tx Tx, task *influxdb.Task, createdAt time.Time) error {
+	uid, _ := icontext.GetUserID(ctx)
+	return putTaskVersion(tx, influxdb.NewTaskVersion(task, task.Version, uid, createdAt))
+}
+
+// ensureTaskVersion stores the current script of a task created before
+// versioning as its first version.
+func (s *Service) ensureTaskVersion(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	_, err := s.findTaskVersion(ctx, tx, task.ID, task.Version)
+	if err != influxdb.ErrTaskVersionNotFound {
+		return err
+	}
+	return putTaskVersion(tx, initialTaskVersion(task))
+}
+
+func (s *Service) deleteTaskVersions(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	versions, err := s.findTaskVersions(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	for _, v := range versions {
+		key, err := taskVersionKey(taskID, v.Version)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+	return nil
+}
+
+// initialTaskVersion returns the version of the script of a task created
+// before versioning, attributed to its owner.
+func initialTaskVersion(task *influxdb.Task) *influxdb.TaskVersion {
+	createdAt := task.UpdatedAt
+	if createdAt.IsZero() {
+		createdAt = task.CreatedAt
+	}
+	return influxdb.NewTaskVersion(task, task.Version, task.OwnerID, createdAt)
+}
+
+func putTaskVersion(tx Tx, v *influxdb.TaskVersion) error {
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskVersionKey(v.TaskID, v.Version)
+	if err != nil {
+		return err
+	}
+
+	vBytes, err := json.Marshal(v)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := b.Put(key, vBytes); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+func taskVersionPrefix(taskID influxdb.ID) ([]byte, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, influxdb.ErrInvalidTaskID
+	}
+	return []byte(string(encodedID) + "/"), nil
+}
+
+func taskVersionKey(taskID influxdb.ID, version int) ([]byte, error) {
+	prefix, err := taskVersionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+	// versions are zero padded to be listed in order.
+	return []byte(fmt.Sprintf("%s%010d", prefix, version)), nil
+}
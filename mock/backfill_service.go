@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.BackfillService = &BackfillService{}
+
+// BackfillService is a mock task backfill service.
+type BackfillService struct {
+	CreateBackfillF   func(ctx context.Context, b *influxdb.Backfill) error
+	FindBackfillByIDF func(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error)
+	FindBackfillsF    func(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error)
+	CancelBackfillF   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewBackfillService returns a mock BackfillService where its methods will return
+// zero values.
+func NewBackfillService() *BackfillService {
+	return &BackfillService{
+		CreateBackfillF: func(ctx context.Context, b *influxdb.Backfill) error {
+			return nil
+		},
+		FindBackfillByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		FindBackfillsF: func(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		CancelBackfillF: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// CreateBackfill calls CreateBackfillF.
+func (s *BackfillService) CreateBackfill(ctx context.Context, b *influxdb.Backfill) error {
+	return s.CreateBackfillF(ctx, b)
+}
+
+// FindBackfillByID calls FindBackfillByIDF.
+func (s *BackfillService) FindBackfillByID(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error) {
+	return s.FindBackfillByIDF(ctx, id)
+}
+
+// FindBackfills calls FindBackfillsF.
+func (s *BackfillService) FindBackfills(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+	return s.FindBackfillsF(ctx, filter)
+}
+
+// CancelBackfill calls CancelBackfillF.
+func (s *BackfillService) CancelBackfill(ctx context.Context, id influxdb.ID) error {
+	return s.CancelBackfillF(ctx, id)
+}
@@ -27,3 +27,52 @@ func NewDeleteService() DeleteService {
 func (s DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
 	return s.DeleteBucketRangePredicateF(ctx, orgID, bucketID, min, max, pred)
 }
+
+var _ influxdb.DeleteJobService = &DeleteJobService{}
+
+// DeleteJobService is a mock delete job service.
+type DeleteJobService struct {
+	CreateDeleteJobF   func(ctx context.Context, job *influxdb.DeleteJob) error
+	FindDeleteJobByIDF func(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error)
+	FindDeleteJobsF    func(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error)
+	CancelDeleteJobF   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewDeleteJobService returns a mock DeleteJobService where its methods will return
+// zero values.
+func NewDeleteJobService() *DeleteJobService {
+	return &DeleteJobService{
+		CreateDeleteJobF: func(ctx context.Context, job *influxdb.DeleteJob) error {
+			return nil
+		},
+		FindDeleteJobByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+			return nil, nil
+		},
+		FindDeleteJobsF: func(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+			return nil, nil
+		},
+		CancelDeleteJobF: func(ctx context.Context, id influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// CreateDeleteJob calls CreateDeleteJobF.
+func (s *DeleteJobService) CreateDeleteJob(ctx context.Context, job *influxdb.DeleteJob) error {
+	return s.CreateDeleteJobF(ctx, job)
+}
+
+// FindDeleteJobByID calls FindDeleteJobByIDF.
+func (s *DeleteJobService) FindDeleteJobByID(ctx context.Context, id influxdb.ID) (*influxdb.DeleteJob, error) {
+	return s.FindDeleteJobByIDF(ctx, id)
+}
+
+// FindDeleteJobs calls FindDeleteJobsF.
+func (s *DeleteJobService) FindDeleteJobs(ctx context.Context, filter influxdb.DeleteJobFilter) ([]*influxdb.DeleteJob, error) {
+	return s.FindDeleteJobsF(ctx, filter)
+}
+
+// CancelDeleteJob calls CancelDeleteJobF.
+func (s *DeleteJobService) CancelDeleteJob(ctx context.Context, id influxdb.ID) error {
+	return s.CancelDeleteJobF(ctx, id)
+}
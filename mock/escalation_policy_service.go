@@ -0,0 +1,81 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.EscalationPolicyService = &EscalationPolicyService{}
+
+// EscalationPolicyService is a mock escalation policy service.
+type EscalationPolicyService struct {
+	FindEscalationPolicyByIDF func(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error)
+	FindEscalationPoliciesF   func(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error)
+	CreateEscalationPolicyF   func(ctx context.Context, p *influxdb.EscalationPolicy) error
+	UpdateEscalationPolicyF   func(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error)
+	DeleteEscalationPolicyF   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewEscalationPolicyService returns a mock EscalationPolicyService where its
+// methods will return zero values.
+func NewEscalationPolicyService() *EscalationPolicyService {
+	return &EscalationPolicyService{
+		FindEscalationPolicyByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+			return nil, nil
+		},
+		FindEscalationPoliciesF: func(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+			return nil, nil
+		},
+		CreateEscalationPolicyF: func(ctx context.Context, p *influxdb.EscalationPolicy) error { return nil },
+		UpdateEscalationPolicyF: func(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+			return nil, nil
+		},
+		DeleteEscalationPolicyF: func(ctx context.Context, id influxdb.ID) error { return nil },
+	}
+}
+
+// FindEscalationPolicyByID calls FindEscalationPolicyByIDF.
+func (s *EscalationPolicyService) FindEscalationPolicyByID(ctx context.Context, id influxdb.ID) (*influxdb.EscalationPolicy, error) {
+	return s.FindEscalationPolicyByIDF(ctx, id)
+}
+
+// FindEscalationPolicies calls FindEscalationPoliciesF.
+func (s *EscalationPolicyService) FindEscalationPolicies(ctx context.Context, filter influxdb.EscalationPolicyFilter) ([]*influxdb.EscalationPolicy, error) {
+	return s.FindEscalationPoliciesF(ctx, filter)
+}
+
+// CreateEscalationPolicy calls CreateEscalationPolicyF.
+func (s *EscalationPolicyService) CreateEscalationPolicy(ctx context.Context, p *influxdb.EscalationPolicy) error {
+	return s.CreateEscalationPolicyF(ctx, p)
+}
+
+// UpdateEscalationPolicy calls UpdateEscalationPolicyF.
+func (s *EscalationPolicyService) UpdateEscalationPolicy(ctx context.Context, id influxdb.ID, upd influxdb.EscalationPolicyUpdate) (*influxdb.EscalationPolicy, error) {
+	return s.UpdateEscalationPolicyF(ctx, id, upd)
+}
+
+// DeleteEscalationPolicy calls DeleteEscalationPolicyF.
+func (s *EscalationPolicyService) DeleteEscalationPolicy(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteEscalationPolicyF(ctx, id)
+}
+
+var _ influxdb.AcknowledgementService = &AcknowledgementService{}
+
+// AcknowledgementService is a mock acknowledgement service.
+type AcknowledgementService struct {
+	AcknowledgeF func(ctx context.Context, a *influxdb.Acknowledgement) error
+}
+
+// NewAcknowledgementService returns a mock AcknowledgementService where its
+// methods will return zero values.
+func NewAcknowledgementService() *AcknowledgementService {
+	return &AcknowledgementService{
+		AcknowledgeF: func(ctx context.Context, a *influxdb.Acknowledgement) error { return nil },
+	}
+}
+
+// Acknowledge calls AcknowledgeF.
+func (s *AcknowledgementService) Acknowledge(ctx context.Context, a *influxdb.Acknowledgement) error {
+	return s.AcknowledgeF(ctx, a)
+}
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.QuotaService = &QuotaService{}
+
+// QuotaService is a mock quota service.
+type QuotaService struct {
+	FindQuotaByIDF func(ctx context.Context, id influxdb.ID) (*influxdb.Quota, error)
+	FindQuotasF    func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error)
+	CreateQuotaF   func(ctx context.Context, q *influxdb.Quota) error
+	UpdateQuotaF   func(ctx context.Context, id influxdb.ID, upd influxdb.QuotaUpdate) (*influxdb.Quota, error)
+	DeleteQuotaF   func(ctx context.Context, id influxdb.ID) error
+}
+
+// NewQuotaService returns a mock QuotaService where its methods will return
+// zero values.
+func NewQuotaService() *QuotaService {
+	return &QuotaService{
+		FindQuotaByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Quota, error) { return nil, nil },
+		FindQuotasF: func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+			return nil, nil
+		},
+		CreateQuotaF: func(ctx context.Context, q *influxdb.Quota) error { return nil },
+		UpdateQuotaF: func(ctx context.Context, id influxdb.ID, upd influxdb.QuotaUpdate) (*influxdb.Quota, error) {
+			return nil, nil
+		},
+		DeleteQuotaF: func(ctx context.Context, id influxdb.ID) error { return nil },
+	}
+}
+
+// FindQuotaByID calls FindQuotaByIDF.
+func (s *QuotaService) FindQuotaByID(ctx context.Context, id influxdb.ID) (*influxdb.Quota, error) {
+	return s.FindQuotaByIDF(ctx, id)
+}
+
+// FindQuotas calls FindQuotasF.
+func (s *QuotaService) FindQuotas(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+	return s.FindQuotasF(ctx, filter)
+}
+
+// CreateQuota calls CreateQuotaF.
+func (s *QuotaService) CreateQuota(ctx context.Context, q *influxdb.Quota) error {
+	return s.CreateQuotaF(ctx, q)
+}
+
+// UpdateQuota calls UpdateQuotaF.
+func (s *QuotaService) UpdateQuota(ctx context.Context, id influxdb.ID, upd influxdb.QuotaUpdate) (*influxdb.Quota, error) {
+	return s.UpdateQuotaF(ctx, id, upd)
+}
+
+// DeleteQuota calls DeleteQuotaF.
+func (s *QuotaService) DeleteQuota(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteQuotaF(ctx, id)
+}
@@ -32,7 +32,7 @@ type TaskService struct {
 	CancelRunCalls    SafeCount
 	RetryRunFn        func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error)
 	RetryRunCalls     SafeCount
-	ForceRunFn        func(context.Context, influxdb.ID, int64) (*influxdb.Run, error)
+	ForceRunFn        func(context.Context, influxdb.ID, int64, map[string]interface{}) (*influxdb.Run, error)
 	ForceRunCalls     SafeCount
 }
 
@@ -68,7 +68,7 @@ func NewTaskService() *TaskService {
 		RetryRunFn: func(ctx context.Context, id influxdb.ID, id2 influxdb.ID) (*influxdb.Run, error) {
 			return nil, nil
 		},
-		ForceRunFn: func(ctx context.Context, id influxdb.ID, i int64) (*influxdb.Run, error) {
+		ForceRunFn: func(ctx context.Context, id influxdb.ID, i int64, params map[string]interface{}) (*influxdb.Run, error) {
 			return nil, nil
 		},
 	}
@@ -124,9 +124,9 @@ func (s *TaskService) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (
 	return s.RetryRunFn(ctx, taskID, runID)
 }
 
-func (s *TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 	defer s.ForceRunCalls.IncrFn()()
-	return s.ForceRunFn(ctx, taskID, scheduledFor)
+	return s.ForceRunFn(ctx, taskID, scheduledFor, params)
 }
 
 type TaskControlService struct {
@@ -137,6 +137,9 @@ type TaskControlService struct {
 	FinishRunFn        func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 	UpdateRunStateFn   func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state influxdb.RunStatus) error
 	AddRunLogFn        func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+	AddRunLogEntryFn   func(ctx context.Context, taskID, runID influxdb.ID, entry influxdb.Log) error
+	SetRunStatsFn      func(ctx context.Context, taskID, runID influxdb.ID, stats *influxdb.RunStats) error
+	CreateRetryRunFn   func(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error)
 }
 
 func (tcs *TaskControlService) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
@@ -160,3 +163,12 @@ func (tcs *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID
 func (tcs *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
 	return tcs.AddRunLogFn(ctx, taskID, runID, when, log)
 }
+func (tcs *TaskControlService) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, entry influxdb.Log) error {
+	return tcs.AddRunLogEntryFn(ctx, taskID, runID, entry)
+}
+func (tcs *TaskControlService) SetRunStats(ctx context.Context, taskID, runID influxdb.ID, stats *influxdb.RunStats) error {
+	return tcs.SetRunStatsFn(ctx, taskID, runID, stats)
+}
+func (tcs *TaskControlService) CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	return tcs.CreateRetryRunFn(ctx, run, runAt)
+}
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.TaskVersionService = &TaskVersionService{}
+
+// TaskVersionService is a mock task version service.
+type TaskVersionService struct {
+	FindTaskVersionsF func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error)
+	FindTaskVersionF  func(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error)
+}
+
+// NewTaskVersionService returns a mock TaskVersionService where its methods will return
+// zero values.
+func NewTaskVersionService() *TaskVersionService {
+	return &TaskVersionService{
+		FindTaskVersionsF: func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+			return nil, nil
+		},
+		FindTaskVersionF: func(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindTaskVersions calls FindTaskVersionsF.
+func (s *TaskVersionService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	return s.FindTaskVersionsF(ctx, taskID)
+}
+
+// FindTaskVersion calls FindTaskVersionF.
+func (s *TaskVersionService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	return s.FindTaskVersionF(ctx, taskID, version)
+}
@@ -207,6 +207,13 @@ var typeToCheck = map[string](func() influxdb.Check){
 	"custom":    func() influxdb.Check { return &Custom{} },
 }
 
+// IsType returns true if typ is the type of a check. The task of a check
+// has the type of its check.
+func IsType(typ string) bool {
+	_, ok := typeToCheck[typ]
+	return ok
+}
+
 // UnmarshalJSON will convert
 func UnmarshalJSON(b []byte) (influxdb.Check, error) {
 	var raw struct {
@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
+)
+
+var _ influxdb.NotificationEndpoint = &SMTP{}
+
+const (
+	smtpUsernameSuffix = "-username"
+	smtpPasswordSuffix = "-password"
+)
+
+// SMTP is the notification endpoint config of email sent through an SMTP server.
+type SMTP struct {
+	Base
+	// Host is the host name of the SMTP server.
+	Host string `json:"host"`
+	// Port is the port of the SMTP server, 25 or 465 with TLS when unset.
+	Port int `json:"port,omitempty"`
+	// TLS connects with implicit TLS, otherwise STARTTLS is used when the server offers it.
+	TLS bool `json:"tls,omitempty"`
+	// From is the sender address of the email.
+	From     string               `json:"from"`
+	Username influxdb.SecretField `json:"username,omitempty"`
+	Password influxdb.SecretField `json:"password,omitempty"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *SMTP) BackfillSecretKeys() {
+	if s.Username.Key == "" && s.Username.Value != nil {
+		s.Username.Key = s.idStr() + smtpUsernameSuffix
+	}
+	if s.Password.Key == "" && s.Password.Value != nil {
+		s.Password.Key = s.idStr() + smtpPasswordSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s SMTP) SecretFields() []influxdb.SecretField {
+	arr := make([]influxdb.SecretField, 0)
+	if s.Username.Key != "" {
+		arr = append(arr, s.Username)
+	}
+	if s.Password.Key != "" {
+		arr = append(arr, s.Password)
+	}
+	return arr
+}
+
+// URL returns the smtp:// or smtps:// url messages are posted to.
+func (s SMTP) URL() string {
+	u := url.URL{Scheme: smtp.Scheme, Host: s.Host}
+	if s.TLS {
+		u.Scheme = smtp.TLSScheme
+	}
+	if s.Port != 0 {
+		u.Host = net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	}
+	return u.String()
+}
+
+// Valid returns error if some configuration is invalid
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint host is empty",
+		}
+	}
+	if u, err := url.Parse(s.URL()); err != nil || u.Hostname() != s.Host {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint host %q is invalid", s.Host),
+		}
+	}
+	if s.Port < 0 || s.Port > 65535 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint port %d is invalid", s.Port),
+		}
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint from address is invalid: %s", err.Error()),
+		}
+	}
+	if (s.Username.Key == "") != (s.Password.Key == "") {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint username and password must be provided together",
+		}
+	}
+	return nil
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s SMTP) Type() string {
+	return SMTPType
+}
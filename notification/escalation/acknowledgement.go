@@ -0,0 +1,100 @@
+// Package escalation records the acknowledgements read back by the
+// notification rules escalating their statuses.
+package escalation
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+const (
+	// Measurement is the measurement of the acknowledgements in the
+	// monitoring bucket.
+	Measurement = "acknowledgements"
+
+	notificationRuleIDTag = "_notification_rule_id"
+	acknowledgedByField   = "_acknowledged_by"
+	commentField          = "_comment"
+)
+
+var _ influxdb.AcknowledgementService = (*AcknowledgementService)(nil)
+
+// AcknowledgementService writes acknowledgements as points to the monitoring
+// bucket of their organization.
+type AcknowledgementService struct {
+	bs influxdb.BucketService
+	pw storage.PointsWriter
+
+	now func() time.Time
+}
+
+// NewAcknowledgementService constructs an acknowledgement service writing to
+// the monitoring buckets found with bs through pw.
+func NewAcknowledgementService(bs influxdb.BucketService, pw storage.PointsWriter) *AcknowledgementService {
+	return &AcknowledgementService{
+		bs:  bs,
+		pw:  pw,
+		now: time.Now,
+	}
+}
+
+// Acknowledge writes the acknowledgement tagged with its notification rule
+// and group to the monitoring bucket of its organization.
+func (s *AcknowledgementService) Acknowledge(ctx context.Context, a *influxdb.Acknowledgement) error {
+	if err := a.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAcknowledge,
+			Err: err,
+		}
+	}
+	if a.Time.IsZero() {
+		a.Time = s.now().UTC()
+	}
+
+	if err := s.write(ctx, a); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAcknowledge,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *AcknowledgementService) write(ctx context.Context, a *influxdb.Acknowledgement) error {
+	bucket, err := s.bs.FindBucketByName(ctx, a.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string]string, len(a.Tags)+1)
+	for k, v := range a.Tags {
+		tags[k] = v
+	}
+	tags[notificationRuleIDTag] = a.NotificationRuleID.String()
+
+	fields := map[string]interface{}{
+		acknowledgedByField: a.AcknowledgedBy.String(),
+	}
+	if a.Comment != "" {
+		fields[commentField] = a.Comment
+	}
+
+	point, err := models.NewPoint(Measurement, models.NewTags(tags), fields, a.Time)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	points, err := tsdb.ExplodePoints(a.OrgID, bucket.ID, models.Points{point})
+	if err != nil {
+		return err
+	}
+	return s.pw.WritePoints(ctx, points)
+}
@@ -0,0 +1,139 @@
+package escalation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/notification/escalation"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+func TestAcknowledgementService_Acknowledge(t *testing.T) {
+	orgID, bucketID := influxdb.ID(0x1000), influxdb.ID(0x2000)
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		ack        influxdb.Acknowledgement
+		findBucket func(ctx context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error)
+		wantCode   string
+		wantFields map[string]string
+	}{
+		{
+			name: "writes the acknowledgement and its comment",
+			ack: influxdb.Acknowledgement{
+				OrgID:              orgID,
+				NotificationRuleID: 0x3000,
+				Tags:               map[string]string{"_check_id": "0000000000004000"},
+				AcknowledgedBy:     0x5000,
+				Comment:            "on it",
+				Time:               now,
+			},
+			wantFields: map[string]string{
+				"_acknowledged_by": "0000000000005000",
+				"_comment":         "on it",
+			},
+		},
+		{
+			name: "writes the acknowledgement without comment",
+			ack: influxdb.Acknowledgement{
+				OrgID:              orgID,
+				NotificationRuleID: 0x3000,
+				Tags:               map[string]string{"_check_id": "0000000000004000"},
+				AcknowledgedBy:     0x5000,
+				Time:               now,
+			},
+			wantFields: map[string]string{
+				"_acknowledged_by": "0000000000005000",
+			},
+		},
+		{
+			name: "invalid acknowledgement",
+			ack: influxdb.Acknowledgement{
+				OrgID:              orgID,
+				NotificationRuleID: 0x3000,
+			},
+			wantCode: influxdb.EInvalid,
+		},
+		{
+			name: "missing monitoring bucket",
+			ack: influxdb.Acknowledgement{
+				OrgID:              orgID,
+				NotificationRuleID: 0x3000,
+				Tags:               map[string]string{"_check_id": "0000000000004000"},
+				Time:               now,
+			},
+			findBucket: func(ctx context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+				return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+			},
+			wantCode: influxdb.ENotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewBucketService()
+			bs.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+				if id != orgID || name != influxdb.MonitoringSystemBucketName {
+					t.Fatalf("unexpected bucket lookup %s in org %s", name, id)
+				}
+				return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+			}
+			if tt.findBucket != nil {
+				bs.FindBucketByNameFn = tt.findBucket
+			}
+			pw := &mock.PointsWriter{}
+
+			err := escalation.NewAcknowledgementService(bs, pw).Acknowledge(context.Background(), &tt.ack)
+			if got := influxdb.ErrorCode(err); got != tt.wantCode {
+				t.Fatalf("unexpected error code: got %q want %q (%v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != "" {
+				if len(pw.Points) != 0 {
+					t.Fatalf("expected no points written, got %v", pw.Points)
+				}
+				return
+			}
+
+			if len(pw.Points) != len(tt.wantFields) {
+				t.Fatalf("expected %d points, got %d", len(tt.wantFields), len(pw.Points))
+			}
+			for _, p := range pw.Points {
+				if org, bucket := tsdb.DecodeNameSlice(p.Name()); org != orgID || bucket != bucketID {
+					t.Errorf("point written to org %s bucket %s", org, bucket)
+				}
+				if !p.Time().Equal(now) {
+					t.Errorf("unexpected point time %s", p.Time())
+				}
+				tags := p.Tags()
+				if got := tags.GetString(models.MeasurementTagKey); got != escalation.Measurement {
+					t.Errorf("unexpected measurement %q", got)
+				}
+				if got := tags.GetString("_notification_rule_id"); got != "0000000000003000" {
+					t.Errorf("unexpected notification rule id %q", got)
+				}
+				if got := tags.GetString("_check_id"); got != "0000000000004000" {
+					t.Errorf("unexpected check id %q", got)
+				}
+
+				field := tags.GetString(models.FieldKeyTagKey)
+				want, ok := tt.wantFields[field]
+				if !ok {
+					t.Errorf("unexpected field %q", field)
+					continue
+				}
+				iter := p.FieldIterator()
+				if !iter.Next() {
+					t.Fatalf("point has no field")
+				}
+				if got := iter.StringValue(); got != want {
+					t.Errorf("unexpected value of %s: got %q want %q", field, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package flux
 
-import "github.com/influxdata/flux/ast"
+import (
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
 
 // File creates a new *ast.File.
 func File(name string, imports []*ast.ImportDeclaration, body []ast.Statement) *ast.File {
@@ -38,6 +43,42 @@ func Equal(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	}
 }
 
+// NotEqual returns a not equal to *ast.BinaryExpression.
+func NotEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// GreaterThanEqual returns a greater than or equal to *ast.BinaryExpression.
+func GreaterThanEqual(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.GreaterThanEqualOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// RegexpMatch returns a regular expression match *ast.BinaryExpression.
+func RegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.RegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
+// NotRegexpMatch returns a regular expression not match *ast.BinaryExpression.
+func NotRegexpMatch(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // Subtract returns a subtraction *ast.BinaryExpression.
 func Subtract(lhs, rhs ast.Expression) *ast.BinaryExpression {
 	return &ast.BinaryExpression{
@@ -64,6 +105,15 @@ func Member(p, c string) *ast.MemberExpression {
 	}
 }
 
+// Divide returns a division *ast.BinaryExpression.
+func Divide(lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     lhs,
+		Right:    rhs,
+	}
+}
+
 // And returns an and *ast.LogicalExpression.
 func And(lhs, rhs ast.Expression) *ast.LogicalExpression {
 	return &ast.LogicalExpression{
@@ -153,6 +203,20 @@ func String(s string) *ast.StringLiteral {
 	}
 }
 
+// Regexp returns an *ast.RegexpLiteral of re.
+func Regexp(re *regexp.Regexp) *ast.RegexpLiteral {
+	return &ast.RegexpLiteral{
+		Value: re,
+	}
+}
+
+// DateTime returns an *ast.DateTimeLiteral of t.
+func DateTime(t time.Time) *ast.DateTimeLiteral {
+	return &ast.DateTimeLiteral{
+		Value: t,
+	}
+}
+
 // Bool returns an *ast.BooleanLiteral of b.
 func Bool(b bool) *ast.BooleanLiteral {
 	return &ast.BooleanLiteral{
@@ -266,6 +330,13 @@ func FunctionParams(args ...string) []*ast.Property {
 	return params
 }
 
+// PipeParams returns the parameters of a function that takes its input from a pipe. (e.g. (tables=<-) => ...)
+func PipeParams(arg string) []*ast.Property {
+	return []*ast.Property{
+		{Key: &ast.Identifier{Name: arg}, Value: &ast.PipeLiteral{}},
+	}
+}
+
 // Imports returns a []*ast.ImportDeclaration for each package in pkgs.
 func Imports(pkgs ...string) []*ast.ImportDeclaration {
 	var is []*ast.ImportDeclaration
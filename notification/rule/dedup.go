@@ -0,0 +1,217 @@
+package rule
+
+import (
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// deduplicates reports whether the statuses are grouped, repeated after an
+// interval or resolved before being notified.
+func (b *Base) deduplicates() bool {
+	return len(b.GroupBy) > 0 || b.RepeatInterval != nil || b.NotifyOnResolve
+}
+
+func (b *Base) groupByColumns() []string {
+	if len(b.GroupBy) == 0 {
+		return []string{"_check_id"}
+	}
+	return b.GroupBy
+}
+
+// generateDeduplication defines all_statuses from the firing statuses. Only the
+// latest status of every group is notified, and not again until the repeat
+// interval elapsed if it is still at the same level. The notifications sent
+// are read back from the notifications logged by previous runs, so the state
+// survives restarts. If the rule notifies on resolve, the groups whose latest
+// status is ok and whose last notification was not are notified too.
+func (b *Base) generateDeduplication(timeFilter *ast.FunctionExpression) []ast.Statement {
+	groupBy := b.groupByColumns()
+	keyColumns := append(append([]string{}, groupBy...), "_time", "_level")
+	levelColumns := append(append([]string{}, groupBy...), "_level")
+
+	stmts := []ast.Statement{}
+	if b.RepeatInterval != nil || b.NotifyOnResolve {
+		stmts = append(stmts, flux.DefineVariable("last_sent", flux.Pipe(
+			flux.Call(
+				flux.Member("monitor", "logs"),
+				flux.Object(
+					flux.Property("start", flux.Negative(b.sentLookback())),
+					flux.Property("fn", flux.Function(
+						flux.FunctionParams("r"),
+						flux.And(
+							flux.Equal(flux.Member("r", "_notification_rule_id"), flux.String(b.ID.String())),
+							flux.Equal(flux.Member("r", "_sent"), flux.String("true")),
+						),
+					)),
+				),
+			),
+			groupCall(groupBy),
+			sortCall(),
+			lastCall(),
+		)))
+	}
+
+	stmts = append(stmts, flux.DefineVariable("latest_keys", flux.Pipe(
+		flux.Identifier("firing_statuses"),
+		groupCall(groupBy),
+		sortCall(),
+		lastCall(),
+		keepCall(keyColumns),
+	)))
+
+	notifyKeys := "latest_keys"
+	if b.RepeatInterval != nil {
+		notifyKeys = "notify_keys"
+		stmts = append(stmts,
+			flux.DefineVariable("sent_keys", flux.Pipe(
+				flux.Identifier("last_sent"),
+				filterCall(flux.GreaterThan(
+					flux.Member("r", "_time"),
+					flux.Call(
+						flux.Member("experimental", "subDuration"),
+						flux.Object(
+							flux.Property("from", flux.Call(flux.Identifier("now"), flux.Object())),
+							flux.Property("d", (*ast.DurationLiteral)(b.RepeatInterval)),
+						),
+					),
+				)),
+			)),
+			flux.DefineVariable(notifyKeys, excludeKeys(
+				flux.Identifier("latest_keys"),
+				flux.Identifier("sent_keys"),
+				levelColumns,
+				"_sent_recently",
+			)),
+		)
+	}
+
+	// the statuses are joined back with their keys so that they keep their
+	// group key, which the notifications are logged with.
+	var deduped ast.Expression = joinCall("statuses", flux.Identifier("firing_statuses"), "keys", flux.Identifier(notifyKeys), keyColumns)
+	if !b.NotifyOnResolve {
+		return append(stmts, flux.DefineVariable("all_statuses", deduped))
+	}
+
+	var resolved ast.Expression = joinCall("statuses", flux.Identifier("statuses"), "keys", flux.Identifier("resolved_keys"), keyColumns)
+	if len(b.Silences) > 0 {
+		resolved = flux.Pipe(
+			resolved,
+			filterCall(flux.Equal(
+				flux.Call(flux.Identifier("silence_id"), flux.Object(flux.Property("r", flux.Identifier("r")))),
+				flux.String(""),
+			)),
+		)
+	}
+
+	return append(stmts,
+		flux.DefineVariable("deduped_statuses", deduped),
+		flux.DefineVariable("latest_ok", flux.Pipe(
+			flux.Identifier("statuses"),
+			flux.Call(flux.Identifier("filter"), flux.Object(flux.Property("fn", timeFilter))),
+			groupCall(groupBy),
+			sortCall(),
+			lastCall(),
+			filterCall(flux.Equal(flux.Member("r", "_level"), flux.String("ok"))),
+			keepCall(keyColumns),
+		)),
+		flux.DefineVariable("firing_sent", flux.Pipe(
+			flux.Identifier("last_sent"),
+			filterCall(flux.NotEqual(flux.Member("r", "_level"), flux.String("ok"))),
+			keepCall(groupBy),
+		)),
+		flux.DefineVariable("resolved_keys", joinCall("status", flux.Identifier("latest_ok"), "sent", flux.Identifier("firing_sent"), groupBy)),
+		flux.DefineVariable("resolved_statuses", resolved),
+		flux.DefineVariable("all_statuses", flux.Call(
+			flux.Identifier("union"),
+			flux.Object(flux.Property("tables", flux.Array(
+				flux.Identifier("deduped_statuses"),
+				flux.Identifier("resolved_statuses"),
+			))),
+		)),
+	)
+}
+
+// sentLookback is how far back the last notifications sent are looked up. A
+// group still firing was notified at most a repeat interval ago, or during the
+// previous run without one.
+func (b *Base) sentLookback() *ast.DurationLiteral {
+	every := (*ast.DurationLiteral)(b.Every)
+	d := &ast.DurationLiteral{}
+	d.Values = append(d.Values, every.Values...)
+	if b.RepeatInterval != nil {
+		d.Values = append(d.Values, b.RepeatInterval.Values...)
+	} else {
+		d.Values = append(d.Values, every.Values...)
+	}
+	return d
+}
+
+// excludeKeys returns the rows of tables whose values of columns are not
+// in the keys of excluded. Both are marked with a column, so that the rows
+// of a group of columns with only the mark of tables are kept.
+func excludeKeys(tables, excluded ast.Expression, columns []string, mark string) *ast.PipeExpression {
+	return flux.Pipe(
+		flux.Call(
+			flux.Identifier("union"),
+			flux.Object(flux.Property("tables", flux.Array(
+				flux.Pipe(tables, mapWithCall(flux.Property(mark, flux.Integer(0)))),
+				flux.Pipe(excluded, keepCall(columns), mapWithCall(flux.Property(mark, flux.Integer(1)))),
+			))),
+		),
+		groupCall(columns),
+		flux.Call(flux.Identifier("max"), flux.Object(flux.Property("column", flux.String(mark)))),
+		filterCall(flux.Equal(flux.Member("r", mark), flux.Integer(0))),
+		flux.Call(flux.Identifier("drop"), flux.Object(flux.Property("columns", columnsArray([]string{mark})))),
+	)
+}
+
+func columnsArray(columns []string) *ast.ArrayExpression {
+	cols := make([]ast.Expression, 0, len(columns))
+	for _, c := range columns {
+		cols = append(cols, flux.String(c))
+	}
+	return flux.Array(cols...)
+}
+
+func groupCall(columns []string) *ast.CallExpression {
+	return flux.Call(flux.Identifier("group"), flux.Object(flux.Property("columns", columnsArray(columns))))
+}
+
+func keepCall(columns []string) *ast.CallExpression {
+	return flux.Call(flux.Identifier("keep"), flux.Object(flux.Property("columns", columnsArray(columns))))
+}
+
+func sortCall() *ast.CallExpression {
+	return flux.Call(flux.Identifier("sort"), flux.Object(flux.Property("columns", columnsArray([]string{"_time"}))))
+}
+
+func lastCall() *ast.CallExpression {
+	return flux.Call(flux.Identifier("last"), flux.Object(flux.Property("column", flux.String("_time"))))
+}
+
+func filterCall(e ast.Expression) *ast.CallExpression {
+	return flux.Call(
+		flux.Identifier("filter"),
+		flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), e))),
+	)
+}
+
+func mapWithCall(props ...*ast.Property) *ast.CallExpression {
+	return flux.Call(
+		flux.Identifier("map"),
+		flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r", props...)))),
+	)
+}
+
+func joinCall(leftName string, left ast.Expression, rightName string, right ast.Expression, on []string) *ast.CallExpression {
+	return flux.Call(
+		flux.Identifier("join"),
+		flux.Object(
+			flux.Property("tables", flux.Object(
+				flux.Property(leftName, left),
+				flux.Property(rightName, right),
+			)),
+			flux.Property("on", columnsArray(on)),
+		),
+	)
+}
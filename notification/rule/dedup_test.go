@@ -0,0 +1,160 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestDeduplication_GenerateFlux(t *testing.T) {
+	tests := []struct {
+		name string
+		base rule.Base
+		want string
+	}{
+		{
+			name: "group by",
+			base: rule.Base{GroupBy: []string{"host"}},
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+firing_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+latest_keys = firing_statuses
+	|> group(columns: ["host"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> keep(columns: ["host", "_time", "_level"])
+all_statuses = join(tables: {statuses: firing_statuses, keys: latest_keys}, on: ["host", "_time", "_level"])
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`,
+		},
+		{
+			name: "repeat interval and notify on resolve",
+			base: rule.Base{
+				RepeatInterval:  mustDuration("4h"),
+				NotifyOnResolve: true,
+			},
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+firing_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+last_sent = monitor["logs"](start: -1h4h, fn: (r) =>
+	(r["_notification_rule_id"] == "0000000000000001" and r["_sent"] == "true"))
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+latest_keys = firing_statuses
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> keep(columns: ["_check_id", "_time", "_level"])
+sent_keys = last_sent
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 4h)))
+notify_keys = union(tables: [latest_keys
+	|> map(fn: (r) =>
+		({r with _sent_recently: 0})), sent_keys
+	|> keep(columns: ["_check_id", "_level"])
+	|> map(fn: (r) =>
+		({r with _sent_recently: 1}))])
+	|> group(columns: ["_check_id", "_level"])
+	|> max(column: "_sent_recently")
+	|> filter(fn: (r) =>
+		(r["_sent_recently"] == 0))
+	|> drop(columns: ["_sent_recently"])
+deduped_statuses = join(tables: {statuses: firing_statuses, keys: notify_keys}, on: ["_check_id", "_time", "_level"])
+latest_ok = statuses
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> filter(fn: (r) =>
+		(r["_level"] == "ok"))
+	|> keep(columns: ["_check_id", "_time", "_level"])
+firing_sent = last_sent
+	|> filter(fn: (r) =>
+		(r["_level"] != "ok"))
+	|> keep(columns: ["_check_id"])
+resolved_keys = join(tables: {status: latest_ok, sent: firing_sent}, on: ["_check_id"])
+resolved_statuses = join(tables: {statuses: statuses, keys: resolved_keys}, on: ["_check_id", "_time", "_level"])
+all_statuses = union(tables: [deduped_statuses, resolved_statuses])
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &rule.Slack{Base: tt.base, MessageTemplate: "blah"}
+			s.ID = 1
+			s.Name = "foo"
+			s.Every = mustDuration("1h")
+			s.EndpointID = 2
+			s.StatusRules = []notification.StatusRule{{CurrentLevel: notification.Critical}}
+
+			id := influxdb.ID(2)
+			e := &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   &id,
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			}
+
+			f, err := s.GenerateFlux(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if f != tt.want {
+				t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", tt.want, f)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// Escalation is a step of the escalation policy of a rule along with the
+// endpoint it notifies.
+type Escalation struct {
+	Delay    time.Duration
+	Endpoint influxdb.NotificationEndpoint
+}
+
+// GetEscalationPolicyID returns the id of the escalation policy of the rule.
+func (b *Base) GetEscalationPolicyID() influxdb.ID {
+	return b.EscalationPolicyID
+}
+
+// SetEscalations sets the steps of the escalation policy of the rule, they
+// are not part of the rule and set by the store before generating the flux.
+func (b *Base) SetEscalations(es []Escalation) {
+	b.Escalations = es
+}
+
+// firingStatuses is the name of the statuses matched by the level checks of
+// the rule that are not silenced.
+func (b *Base) firingStatuses() string {
+	if b.deduplicates() {
+		return "firing_statuses"
+	}
+	return "all_statuses"
+}
+
+// generateFluxASTEscalationTo returns the statements notifying e, the endpoint
+// of an escalation step of another type than the rule, with message as the
+// text of the notifications. An smtp endpoint only escalates smtp rules, as
+// the recipients are set by the rule.
+func (b *Base) generateFluxASTEscalationTo(e influxdb.NotificationEndpoint, message string) ([]ast.Statement, error) {
+	switch e := e.(type) {
+	case *endpoint.Slack:
+		r := &Slack{Base: *b, MessageTemplate: message}
+		return r.generateFluxASTEscalation(e)
+	case *endpoint.PagerDuty:
+		r := &PagerDuty{Base: *b, MessageTemplate: message}
+		return r.generateFluxASTEscalation(e)
+	case *endpoint.HTTP:
+		r := &HTTP{Base: *b}
+		return r.generateFluxASTEscalation(e, nil)
+	case *endpoint.Opsgenie:
+		r := &Opsgenie{Base: *b, MessageTemplate: message}
+		return r.generateFluxASTEscalation(e)
+	case *endpoint.Teams:
+		r := &Teams{Base: *b, MessageTemplate: message}
+		return r.generateFluxASTEscalation(e)
+	}
+	return nil, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("escalation endpoint %q is a %s endpoint, which only escalates rules of its type", e.GetName(), e.Type()),
+	}
+}
+
+// escalationImports returns the imports of packages along with the packages
+// used to notify the endpoints of the escalation steps.
+func (b *Base) escalationImports(packages ...string) []*ast.ImportDeclaration {
+	seen := make(map[string]bool, len(packages))
+	for _, pkg := range packages {
+		seen[pkg] = true
+	}
+	for _, esc := range b.Escalations {
+		for _, pkg := range escalationPackages(esc.Endpoint) {
+			if !seen[pkg] {
+				seen[pkg] = true
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	return flux.Imports(packages...)
+}
+
+// escalationPackages returns the packages used to notify e.
+func escalationPackages(e influxdb.NotificationEndpoint) []string {
+	const secrets = "influxdata/influxdb/secrets"
+	switch e := e.(type) {
+	case *endpoint.Slack:
+		if e.Token.Key != "" {
+			return []string{"slack", secrets}
+		}
+		return []string{"slack"}
+	case *endpoint.PagerDuty:
+		return []string{"pagerduty", secrets}
+	case *endpoint.HTTP:
+		if usesSecrets(e) {
+			return []string{"http", "json", secrets}
+		}
+		return []string{"http", "json"}
+	case *endpoint.SMTP:
+		if e.Username.Key != "" {
+			return []string{"http", secrets}
+		}
+		return []string{"http"}
+	case *endpoint.Opsgenie:
+		return []string{"http", "json", secrets}
+	case *endpoint.Teams:
+		return []string{"contrib/sranka/teams"}
+	}
+	return nil
+}
+
+// firingLevels returns whether the level of the status r is the current level
+// of a status rule, the firing duration of a group is how long its statuses
+// have had such a level.
+func (b *Base) firingLevels() ast.Expression {
+	var levels ast.Expression = flux.Bool(false)
+	seen := make(map[notification.CheckLevel]bool, len(b.StatusRules))
+	for i, r := range b.StatusRules {
+		if r.CurrentLevel == notification.Any {
+			return flux.Bool(true)
+		}
+		if seen[r.CurrentLevel] {
+			continue
+		}
+		seen[r.CurrentLevel] = true
+
+		level := flux.Equal(flux.Member("r", "_level"), flux.String(strings.ToLower(r.CurrentLevel.String())))
+		if i == 0 {
+			levels = level
+		} else {
+			levels = flux.Or(levels, level)
+		}
+	}
+	return levels
+}
+
+// generateEscalations returns the statements notifying the endpoints of the
+// escalation steps of the groups that have been firing for the delay of a
+// step, that were not acknowledged since they started firing and that were
+// not notified to the endpoint of the step yet. The acknowledgements and the
+// notifications sent are read from the monitoring bucket, groups firing for
+// longer than the escalation lookback are escalated again.
+//
+// notify returns the statements defining an escalation endpoint and notifying
+// it of all_statuses with notification as the data, they are generated in a
+// function block in which both are shadowed.
+func (b *Base) generateEscalations(notify func(influxdb.NotificationEndpoint) ([]ast.Statement, error)) ([]ast.Statement, error) {
+	if len(b.Escalations) == 0 {
+		return nil, nil
+	}
+
+	groupBy := b.groupByColumns()
+	keyColumns := append(append([]string{}, groupBy...), "_time", "_level")
+	lookback := b.escalationLookback()
+	firing := b.firingStatuses()
+
+	stmts := []ast.Statement{
+		flux.DefineVariable("escalation_durations", flux.Pipe(
+			flux.Call(
+				flux.Member("monitor", "from"),
+				flux.Object(flux.Property("start", flux.Negative(lookback))),
+			),
+			groupCall(groupBy),
+			sortCall(),
+			flux.Call(
+				flux.Identifier("stateDuration"),
+				flux.Object(
+					flux.Property("fn", flux.Function(
+						flux.FunctionParams("r"),
+						b.firingLevels(),
+					)),
+					flux.Property("column", flux.String("_firing_duration")),
+					flux.Property("unit", flux.Duration(1, "ns")),
+				),
+			),
+			lastCall(),
+			keepCall(append(append([]string{}, keyColumns...), "_firing_duration")),
+		)),
+		flux.DefineVariable("acknowledgements", flux.Pipe(
+			flux.Call(
+				flux.Identifier("from"),
+				flux.Object(flux.Property("bucket", flux.String(influxdb.MonitoringSystemBucketName))),
+			),
+			flux.Call(
+				flux.Identifier("range"),
+				flux.Object(flux.Property("start", flux.Negative(lookback))),
+			),
+			filterCall(flux.And(
+				flux.And(
+					flux.Equal(flux.Member("r", "_measurement"), flux.String("acknowledgements")),
+					flux.Equal(flux.Member("r", "_notification_rule_id"), flux.String(b.ID.String())),
+				),
+				flux.Equal(flux.Member("r", "_field"), flux.String("_acknowledged_by")),
+			)),
+			groupCall(groupBy),
+			sortCall(),
+			lastCall(),
+			keepCall(append(append([]string{}, groupBy...), "_time")),
+			renameTimeCall("_acknowledged_at"),
+		)),
+		flux.DefineVariable("acknowledged_keys", flux.Pipe(
+			joinCall("durations", flux.Identifier("escalation_durations"), "acknowledgements", flux.Identifier("acknowledgements"), groupBy),
+			filterCall(sinceFiring("_acknowledged_at")),
+		)),
+		flux.DefineVariable("escalation_keys", excludeKeys(
+			joinCall(
+				"firing", flux.Pipe(
+					flux.Identifier(firing),
+					groupCall(groupBy),
+					sortCall(),
+					lastCall(),
+					keepCall(keyColumns),
+				),
+				"durations", flux.Identifier("escalation_durations"),
+				keyColumns,
+			),
+			flux.Identifier("acknowledged_keys"),
+			groupBy,
+			"_acknowledged",
+		)),
+	}
+
+	for i, esc := range b.Escalations {
+		n := strconv.Itoa(i + 1)
+		delay, err := notification.FromTimeDuration(esc.Delay)
+		if err != nil {
+			return nil, err
+		}
+
+		endpointStmts, err := notify(esc.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		body := []ast.Statement{
+			flux.DefineVariable("notification", flux.Identifier("escalation_notification_"+n)),
+			flux.DefineVariable("all_statuses", flux.Identifier("escalated_statuses_"+n)),
+		}
+		body = append(body, endpointStmts[:len(endpointStmts)-1]...)
+		body = append(body, &ast.ReturnStatement{
+			Argument: endpointStmts[len(endpointStmts)-1].(*ast.ExpressionStatement).Expression,
+		})
+
+		stmts = append(stmts,
+			flux.DefineVariable("escalation_sent_"+n, flux.Pipe(
+				flux.Call(
+					flux.Member("monitor", "logs"),
+					flux.Object(
+						flux.Property("start", flux.Negative(lookback)),
+						flux.Property("fn", flux.Function(
+							flux.FunctionParams("r"),
+							flux.And(
+								flux.And(
+									flux.Equal(flux.Member("r", "_notification_rule_id"), flux.String(b.ID.String())),
+									flux.Equal(flux.Member("r", "_notification_endpoint_id"), flux.String(esc.Endpoint.GetID().String())),
+								),
+								flux.Equal(flux.Member("r", "_sent"), flux.String("true")),
+							),
+						)),
+					),
+				),
+				groupCall(groupBy),
+				sortCall(),
+				lastCall(),
+				keepCall(append(append([]string{}, groupBy...), "_time")),
+				renameTimeCall("_sent_at"),
+			)),
+			flux.DefineVariable("escalated_keys_"+n, flux.Pipe(
+				excludeKeys(
+					flux.Pipe(
+						flux.Identifier("escalation_keys"),
+						filterCall(flux.GreaterThanEqual(
+							flux.Member("r", "_firing_duration"),
+							intCall(trimDuration(delay)),
+						)),
+					),
+					flux.Pipe(
+						joinCall("keys", flux.Identifier("escalation_keys"), "sent", flux.Identifier("escalation_sent_"+n), groupBy),
+						filterCall(sinceFiring("_sent_at")),
+					),
+					groupBy,
+					"_escalated",
+				),
+				keepCall(keyColumns),
+			)),
+			flux.DefineVariable("escalated_statuses_"+n,
+				joinCall("statuses", flux.Identifier(firing), "keys", flux.Identifier("escalated_keys_"+n), keyColumns),
+			),
+			flux.DefineVariable("escalation_notification_"+n, flux.ObjectWith("notification",
+				flux.Property("_notification_endpoint_id", flux.String(esc.Endpoint.GetID().String())),
+				flux.Property("_notification_endpoint_name", flux.String(esc.Endpoint.GetName())),
+			)),
+			flux.DefineVariable("escalate_"+n, flux.FuncBlock(nil, body...)),
+			flux.ExpressionStatement(flux.Pipe(
+				flux.Call(flux.Identifier("escalate_"+n), flux.Object()),
+				flux.Call(flux.Identifier("yield"), flux.Object(flux.Property("name", flux.String("escalation_"+n)))),
+			)),
+		)
+	}
+	return stmts, nil
+}
+
+// escalationLookback is how far back the statuses, acknowledgements and
+// notifications sent are looked up to escalate, so that a group firing since
+// the delay of the last step is found.
+func (b *Base) escalationLookback() *ast.DurationLiteral {
+	d := increaseDur((*ast.DurationLiteral)(b.Every))
+	last, _ := notification.FromTimeDuration(b.Escalations[len(b.Escalations)-1].Delay)
+	d.Values = append(d.Values, trimDuration(last).Values...)
+	return d
+}
+
+// trimDuration drops the zero values of a duration converted from a
+// time.Duration, e.g. 15m0s is 15m.
+func trimDuration(d notification.Duration) *ast.DurationLiteral {
+	dur := &ast.DurationLiteral{}
+	for _, v := range d.Values {
+		if v.Magnitude != 0 {
+			dur.Values = append(dur.Values, v)
+		}
+	}
+	if len(dur.Values) == 0 {
+		return (*ast.DurationLiteral)(&d)
+	}
+	return dur
+}
+
+// sinceFiring returns whether the time of column is after the group started
+// firing, which is the time of its latest status minus its firing duration.
+func sinceFiring(column string) ast.Expression {
+	return flux.GreaterThanEqual(
+		intCall(flux.Member("r", column)),
+		flux.Subtract(intCall(flux.Member("r", "_time")), flux.Member("r", "_firing_duration")),
+	)
+}
+
+func intCall(e ast.Expression) *ast.CallExpression {
+	return flux.Call(flux.Identifier("int"), flux.Object(flux.Property("v", e)))
+}
+
+func renameTimeCall(to string) *ast.CallExpression {
+	return flux.Call(
+		flux.Identifier("rename"),
+		flux.Object(flux.Property("columns", flux.Object(flux.Property("_time", flux.String(to))))),
+	)
+}
@@ -0,0 +1,308 @@
+package rule_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestEscalations_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1m}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2m)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1m)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))
+
+escalation_durations = monitor["from"](start: -2m15m)
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> stateDuration(fn: (r) =>
+		(r["_level"] == "crit"), column: "_firing_duration", unit: 1ns)
+	|> last(column: "_time")
+	|> keep(columns: ["_check_id", "_time", "_level", "_firing_duration"])
+acknowledgements = from(bucket: "_monitoring")
+	|> range(start: -2m15m)
+	|> filter(fn: (r) =>
+		(r["_measurement"] == "acknowledgements" and r["_notification_rule_id"] == "0000000000000001" and r["_field"] == "_acknowledged_by"))
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> keep(columns: ["_check_id", "_time"])
+	|> rename(columns: {_time: "_acknowledged_at"})
+acknowledged_keys = join(tables: {durations: escalation_durations, acknowledgements: acknowledgements}, on: ["_check_id"])
+	|> filter(fn: (r) =>
+		(int(v: r["_acknowledged_at"]) >= int(v: r["_time"]) - r["_firing_duration"]))
+escalation_keys = union(tables: [join(tables: {firing: all_statuses
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> keep(columns: ["_check_id", "_time", "_level"]), durations: escalation_durations}, on: ["_check_id", "_time", "_level"])
+	|> map(fn: (r) =>
+		({r with _acknowledged: 0})), acknowledged_keys
+	|> keep(columns: ["_check_id"])
+	|> map(fn: (r) =>
+		({r with _acknowledged: 1}))])
+	|> group(columns: ["_check_id"])
+	|> max(column: "_acknowledged")
+	|> filter(fn: (r) =>
+		(r["_acknowledged"] == 0))
+	|> drop(columns: ["_acknowledged"])
+escalation_sent_1 = monitor["logs"](start: -2m15m, fn: (r) =>
+	(r["_notification_rule_id"] == "0000000000000001" and r["_notification_endpoint_id"] == "0000000000000003" and r["_sent"] == "true"))
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last(column: "_time")
+	|> keep(columns: ["_check_id", "_time"])
+	|> rename(columns: {_time: "_sent_at"})
+escalated_keys_1 = union(tables: [escalation_keys
+	|> filter(fn: (r) =>
+		(r["_firing_duration"] >= int(v: 15m)))
+	|> map(fn: (r) =>
+		({r with _escalated: 0})), join(tables: {keys: escalation_keys, sent: escalation_sent_1}, on: ["_check_id"])
+	|> filter(fn: (r) =>
+		(int(v: r["_sent_at"]) >= int(v: r["_time"]) - r["_firing_duration"]))
+	|> keep(columns: ["_check_id"])
+	|> map(fn: (r) =>
+		({r with _escalated: 1}))])
+	|> group(columns: ["_check_id"])
+	|> max(column: "_escalated")
+	|> filter(fn: (r) =>
+		(r["_escalated"] == 0))
+	|> drop(columns: ["_escalated"])
+	|> keep(columns: ["_check_id", "_time", "_level"])
+escalated_statuses_1 = join(tables: {statuses: all_statuses, keys: escalated_keys_1}, on: ["_check_id", "_time", "_level"])
+escalation_notification_1 = {notification with _notification_endpoint_id: "0000000000000003", _notification_endpoint_name: "on call"}
+escalate_1 = () => {
+	notification = escalation_notification_1
+	all_statuses = escalated_statuses_1
+	slack_secret = secrets["get"](key: "on-call-token")
+	slack_endpoint = slack["endpoint"](token: slack_secret, url: "https://hooks.slack.com/services/on/call")
+
+	return all_statuses
+		|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+			({channel: "", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))
+}
+
+escalate_1()
+	|> yield(name: "escalation_1")`
+
+	id, escalationID := influxdb.ID(2), influxdb.ID(3)
+	s := &rule.Slack{
+		Base: rule.Base{
+			ID:                 1,
+			Name:               "foo",
+			Every:              mustDuration("1m"),
+			EndpointID:         id,
+			EscalationPolicyID: 4,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		MessageTemplate: "blah",
+	}
+	s.SetEscalations([]rule.Escalation{
+		{
+			Delay: 15 * time.Minute,
+			Endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   &escalationID,
+					Name: "on call",
+				},
+				URL:   "https://hooks.slack.com/services/on/call",
+				Token: influxdb.SecretField{Key: "on-call-token"},
+			},
+		},
+	})
+
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestEscalations_EndpointType(t *testing.T) {
+	id, escalationID := influxdb.ID(2), influxdb.ID(3)
+	tests := []struct {
+		name     string
+		endpoint influxdb.NotificationEndpoint
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "pagerduty endpoint of a slack rule",
+			endpoint: &endpoint.PagerDuty{
+				Base:       endpoint.Base{ID: &escalationID, Name: "on call"},
+				ClientURL:  "http://localhost:7777/host/${r.host}",
+				RoutingKey: influxdb.SecretField{Key: "on-call-key"},
+			},
+			want: []string{
+				`import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+import "pagerduty"
+`,
+				`	pagerduty_secret = secrets["get"](key: "on-call-key")
+	pagerduty_endpoint = pagerduty["endpoint"]()
+`,
+				`		|> monitor["notify"](data: notification, endpoint: pagerduty_endpoint(mapFn: (r) =>`,
+			},
+		},
+		{
+			name: "teams endpoint of a slack rule",
+			endpoint: &endpoint.Teams{
+				Base: endpoint.Base{ID: &escalationID, Name: "on call"},
+				URL:  "https://outlook.office.com/webhook/on/call",
+			},
+			want: []string{
+				`import "experimental"
+import "contrib/sranka/teams"
+`,
+				`	teams_endpoint = teams["endpoint"](url: "https://outlook.office.com/webhook/on/call")
+`,
+				`		|> monitor["notify"](data: notification, endpoint: teams_endpoint(mapFn: (r) =>
+			({title: "", text: "blah"})))
+`,
+			},
+		},
+		{
+			name: "smtp endpoint of a slack rule",
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{ID: &escalationID, Name: "on call"},
+				Host: "smtp.example.com",
+				From: "alerts@example.com",
+			},
+			wantErr: `escalation endpoint "on call" is a smtp endpoint, which only escalates rules of its type`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &rule.Slack{
+				Base: rule.Base{
+					ID:          1,
+					Name:        "foo",
+					Every:       mustDuration("1m"),
+					EndpointID:  id,
+					StatusRules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+				},
+				MessageTemplate: "blah",
+			}
+			s.SetEscalations([]rule.Escalation{{Delay: 15 * time.Minute, Endpoint: tt.endpoint}})
+
+			f, err := s.GenerateFlux(&endpoint.Slack{Base: endpoint.Base{ID: &id, Name: "foo"}, URL: "http://localhost:7777"})
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Fatalf("unexpected error: got %q want %q", got, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(f, want) {
+					t.Errorf("script does not contain:\n%v\n\ngot:\n%v", want, f)
+				}
+			}
+		})
+	}
+}
+
+func TestEscalations_FiringLevels(t *testing.T) {
+	id, escalationID := influxdb.ID(2), influxdb.ID(3)
+	warn := notification.Warn
+	tests := []struct {
+		name  string
+		rules []notification.StatusRule
+		want  string
+	}{
+		{
+			name:  "current level",
+			rules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+			want:  `(r["_level"] == "crit")`,
+		},
+		{
+			name: "several levels",
+			rules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+				{PreviousLevel: &warn, CurrentLevel: notification.Critical},
+				{CurrentLevel: notification.Info},
+			},
+			want: `(r["_level"] == "crit" or r["_level"] == "info")`,
+		},
+		{
+			name:  "any level",
+			rules: []notification.StatusRule{{CurrentLevel: notification.Critical}, {CurrentLevel: notification.Any}},
+			want:  `(true)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &rule.Slack{
+				Base: rule.Base{
+					ID:          1,
+					Name:        "foo",
+					Every:       mustDuration("1m"),
+					EndpointID:  id,
+					StatusRules: tt.rules,
+				},
+				MessageTemplate: "blah",
+			}
+			s.SetEscalations([]rule.Escalation{
+				{
+					Delay: 15 * time.Minute,
+					Endpoint: &endpoint.Slack{
+						Base: endpoint.Base{ID: &escalationID, Name: "on call"},
+						URL:  "https://hooks.slack.com/services/on/call",
+					},
+				},
+			})
+
+			f, err := s.GenerateFlux(&endpoint.Slack{Base: endpoint.Base{ID: &id, Name: "foo"}, URL: "http://localhost:7777"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "|> stateDuration(fn: (r) =>\n\t\t" + tt.want + ", column"
+			if !strings.Contains(f, want) {
+				t.Errorf("script does not contain:\n%v\n\ngot:\n%v", want, f)
+			}
+		})
+	}
+}
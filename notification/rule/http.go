@@ -3,16 +3,25 @@ package rule
 import (
 	"encoding/json"
 	"fmt"
+	"net/textproto"
+	"sort"
+	"strings"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/notification/endpoint"
 	"github.com/influxdata/influxdb/v2/notification/flux"
+	"golang.org/x/net/http/httpguts"
 )
 
 // HTTP is the notification rule config of http.
 type HTTP struct {
 	Base
+	// BodyTemplate is the JSON body of each notification, see parseBodyTemplate
+	// for the placeholders. The status record is sent when empty.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+	// Headers are extra headers sent with each notification.
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // GenerateFlux generates a flux script for the http notification rule.
@@ -30,15 +39,33 @@ func (s *HTTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the http notification rule.
 func (s *HTTP) GenerateFluxAST(e *endpoint.HTTP) (*ast.Package, error) {
+	var parts []templatePart
+	if s.BodyTemplate != "" {
+		var err error
+		if parts, err = parseBodyTemplate(s.BodyTemplate); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http body template is invalid: %s", err.Error()),
+			}
+		}
+	}
+
+	escalations, err := s.generateEscalations(func(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+		return s.generateFluxASTEscalation(e, parts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	f := flux.File(
 		s.Name,
-		s.imports(e),
-		s.generateFluxASTBody(e),
+		s.imports(e, parts),
+		append(s.generateFluxASTBody(e, parts), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
 
-func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
+func (s *HTTP) imports(e *endpoint.HTTP, parts []templatePart) []*ast.ImportDeclaration {
 	packages := []string{
 		"influxdata/influxdb/monitor",
 		"http",
@@ -46,26 +73,54 @@ func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
 		"experimental",
 	}
 
-	if e.AuthMethod == "bearer" || e.AuthMethod == "basic" {
+	if usesSecrets(e) {
 		packages = append(packages, "influxdata/influxdb/secrets")
 	}
+	if templateUsesStrings(parts) {
+		packages = append(packages, "strings")
+	}
 
-	return flux.Imports(packages...)
+	return s.escalationImports(packages...)
 }
 
-func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
+func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP, parts []templatePart) []ast.Statement {
 	var statements []ast.Statement
 	statements = append(statements, s.generateTaskOption())
 	statements = append(statements, s.generateHeaders(e))
 	statements = append(statements, s.generateFluxASTEndpoint(e))
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, generateTemplateHelpers(parts)...)
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateLevelChecks()...)
-	statements = append(statements, s.generateFluxASTNotifyPipe())
+	statements = append(statements, s.generateFluxASTNotifyPipe(parts))
 
 	return statements
 }
 
+func usesSecrets(e *endpoint.HTTP) bool {
+	return e.AuthMethod == "bearer" || e.AuthMethod == "basic"
+}
+
+// defaultEscalationMessage is the text of the notifications sent to the
+// escalation endpoints of another type than the http rule, which has no text.
+const defaultEscalationMessage = "${r._message}"
+
+// generateFluxASTEscalation generates the statements notifying the http
+// endpoint of an escalation step, an endpoint of another type is notified
+// with the status message of the rule.
+func (s *HTTP) generateFluxASTEscalation(e influxdb.NotificationEndpoint, parts []templatePart) ([]ast.Statement, error) {
+	httpEndpoint, ok := e.(*endpoint.HTTP)
+	if !ok {
+		return s.generateFluxASTEscalationTo(e, defaultEscalationMessage)
+	}
+
+	var statements []ast.Statement
+	statements = append(statements, s.generateHeaders(httpEndpoint))
+	statements = append(statements, s.generateFluxASTEndpoint(httpEndpoint))
+	statements = append(statements, s.generateFluxASTNotifyPipe(parts))
+	return statements, nil
+}
+
 func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
 	props := []*ast.Property{
 		flux.Dictionary(
@@ -112,20 +167,43 @@ func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
 		auth := flux.Dictionary("Authorization", basic)
 		props = append(props, auth)
 	}
+
+	keys := make([]string, 0, len(s.Headers))
+	for k := range s.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		props = append(props, flux.Dictionary(k, flux.String(s.Headers[k])))
+	}
 	return flux.DefineVariable("headers", flux.Object(props...))
 }
 
+// reservedHTTPHeaders are set from the endpoint and the body of a rule.
+var reservedHTTPHeaders = map[string]bool{
+	"Authorization": true,
+	"Content-Type":  true,
+}
+
 func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
 	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL))))
 
 	return flux.DefineVariable("endpoint", call)
 }
 
-func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
+func (s *HTTP) generateFluxASTNotifyPipe(parts []templatePart) ast.Statement {
 	endpointBody := flux.Call(
 		flux.Member("json", "encode"),
 		flux.Object(flux.Property("v", flux.Identifier("body"))),
 	)
+	body := s.generateBody()
+	if len(parts) > 0 {
+		endpointBody = flux.Call(
+			flux.Identifier("bytes"),
+			flux.Object(flux.Property("v", flux.Identifier("body"))),
+		)
+		body = flux.DefineVariable("body", s.generateTemplateBody(parts))
+	}
 	headers := flux.Property("headers", flux.Identifier("headers"))
 
 	endpointProps := []*ast.Property{
@@ -133,7 +211,7 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 		flux.Property("data", endpointBody),
 	}
 	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
-		s.generateBody(),
+		body,
 		&ast.ReturnStatement{
 			Argument: flux.Object(endpointProps...),
 		},
@@ -180,6 +258,34 @@ func (s HTTP) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
+	if s.BodyTemplate != "" {
+		if _, err := parseBodyTemplate(s.BodyTemplate); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http body template is invalid: %s", err.Error()),
+			}
+		}
+	}
+	for k, v := range s.Headers {
+		if !httpguts.ValidHeaderFieldName(k) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http header name %q is invalid", k),
+			}
+		}
+		if reservedHTTPHeaders[textproto.CanonicalMIMEHeaderKey(k)] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http header %q can not be set by the rule", k),
+			}
+		}
+		if !httpguts.ValidHeaderFieldValue(v) || strings.Contains(v, "${") {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http header %q has an invalid value", k),
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,214 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// placeholders of a body template.
+const (
+	templateRunbookLink  = "runbookLink"
+	templateStatusPrefix = "r."
+	templateNotifyPrefix = "notification."
+)
+
+var templateNotificationFields = map[string]bool{
+	"_notification_rule_id":       true,
+	"_notification_rule_name":     true,
+	"_notification_endpoint_id":   true,
+	"_notification_endpoint_name": true,
+}
+
+// templatePart is either literal text or a placeholder of a body template.
+type templatePart struct {
+	text string
+	ref  string
+	// inString is set when the placeholder is inside a JSON string.
+	inString bool
+}
+
+// parseBodyTemplate splits the body template of an http rule into its parts.
+// The template is JSON text with {{ ref }} placeholders, where ref is one of
+//
+//	r.<column>              a column of the status, e.g. r._check_name,
+//	                        r._level, r._message, r._time or a tag r.host
+//	notification.<field>    a field of the notification record, e.g.
+//	                        notification._notification_rule_name
+//	runbookLink             the runbook link of the rule
+//
+// A placeholder inside a JSON string is replaced by the escaped text of the
+// value, anywhere else it is replaced by the value encoded as JSON:
+//
+//	{"text": "{{ r._check_name }} is {{ r._level }}", "status": {{ r._level }}}
+func parseBodyTemplate(tmpl string) ([]templatePart, error) {
+	var (
+		parts    []templatePart
+		text     strings.Builder
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(tmpl); {
+		if strings.HasPrefix(tmpl[i:], "{{") {
+			end := strings.Index(tmpl[i+2:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("placeholder at offset %d is not closed", i)
+			}
+			ref := strings.TrimSpace(tmpl[i+2 : i+2+end])
+			if err := validTemplateRef(ref); err != nil {
+				return nil, err
+			}
+			if text.Len() > 0 {
+				parts = append(parts, templatePart{text: text.String()})
+				text.Reset()
+			}
+			parts = append(parts, templatePart{ref: ref, inString: inString})
+			i += end + 4
+			continue
+		}
+
+		c := tmpl[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		}
+		text.WriteByte(c)
+		i++
+	}
+	if text.Len() > 0 {
+		parts = append(parts, templatePart{text: text.String()})
+	}
+
+	// render with a sample value to make sure every notification is valid JSON.
+	var sample strings.Builder
+	for _, p := range parts {
+		switch {
+		case p.ref == "":
+			sample.WriteString(p.text)
+		case p.inString:
+			sample.WriteString("x")
+		default:
+			sample.WriteString(`"x"`)
+		}
+	}
+	if !json.Valid([]byte(sample.String())) {
+		return nil, fmt.Errorf("template does not render valid JSON")
+	}
+	return parts, nil
+}
+
+func validTemplateRef(ref string) error {
+	switch {
+	case ref == templateRunbookLink:
+		return nil
+	case strings.HasPrefix(ref, templateStatusPrefix) && len(ref) > len(templateStatusPrefix):
+		return nil
+	case strings.HasPrefix(ref, templateNotifyPrefix) &&
+		templateNotificationFields[strings.TrimPrefix(ref, templateNotifyPrefix)]:
+		return nil
+	}
+	return fmt.Errorf("unknown placeholder %q", ref)
+}
+
+// templateRefExpression returns the flux expression of a placeholder.
+func (s *HTTP) templateRefExpression(ref string) ast.Expression {
+	switch {
+	case ref == templateRunbookLink:
+		return flux.String(s.RunbookLink)
+	case strings.HasPrefix(ref, templateNotifyPrefix):
+		return flux.Member("notification", strings.TrimPrefix(ref, templateNotifyPrefix))
+	default:
+		return flux.Member("r", strings.TrimPrefix(ref, templateStatusPrefix))
+	}
+}
+
+// generateTemplateHelpers defines the functions the compiled body template
+// encodes values with, only the ones the template uses are defined.
+func generateTemplateHelpers(parts []templatePart) []ast.Statement {
+	var value, str bool
+	for _, p := range parts {
+		if p.ref == "" {
+			continue
+		}
+		if p.inString {
+			str = true
+		} else {
+			value = true
+		}
+	}
+
+	var statements []ast.Statement
+	if value || str {
+		// encode_value = (v) => string(v: json.encode(v: v))
+		encode := flux.Call(flux.Member("json", "encode"), flux.Object(flux.Property("v", flux.Identifier("v"))))
+		statements = append(statements, flux.DefineVariable("encode_value",
+			flux.Function(flux.FunctionParams("v"),
+				flux.Call(flux.Identifier("string"), flux.Object(flux.Property("v", encode))))))
+	}
+	if str {
+		// the encoded string without its quotes.
+		encoded := flux.Call(flux.Identifier("encode_value"), flux.Object(flux.Property("v", flux.Identifier("v"))))
+		trimmed := flux.Call(flux.Member("strings", "trimSuffix"), flux.Object(
+			flux.Property("v", flux.Call(flux.Member("strings", "trimPrefix"), flux.Object(
+				flux.Property("v", encoded),
+				flux.Property("prefix", flux.String(`"`)),
+			))),
+			flux.Property("suffix", flux.String(`"`)),
+		))
+		statements = append(statements, flux.DefineVariable("encode_string",
+			flux.Function(flux.FunctionParams("v"), trimmed)))
+	}
+	return statements
+}
+
+func templateUsesStrings(parts []templatePart) bool {
+	for _, p := range parts {
+		if p.ref != "" && p.inString {
+			return true
+		}
+	}
+	return false
+}
+
+// generateTemplateBody compiles the body template into a string expression.
+func (s *HTTP) generateTemplateBody(parts []templatePart) ast.Expression {
+	var body ast.Expression
+	add := func(e ast.Expression) {
+		if body == nil {
+			body = e
+			return
+		}
+		body = flux.Add(body, e)
+	}
+
+	for _, p := range parts {
+		if p.ref == "" {
+			// flux interpolates ${ in string literals, split the literal there.
+			chunks := strings.Split(p.text, "${")
+			for i, chunk := range chunks {
+				if i > 0 {
+					chunk = "{" + chunk
+				}
+				if i < len(chunks)-1 {
+					chunk += "$"
+				}
+				add(flux.String(chunk))
+			}
+			continue
+		}
+
+		fn := "encode_value"
+		if p.inString {
+			fn = "encode_string"
+		}
+		add(flux.Call(flux.Identifier(fn), flux.Object(flux.Property("v", s.templateRefExpression(p.ref)))))
+	}
+	return body
+}
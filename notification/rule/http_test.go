@@ -297,3 +297,161 @@ all_statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestHTTP_GenerateFlux_bodyTemplate(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+import "strings"
+
+option task = {name: "foo", every: 1h, offset: 1s}
+
+headers = {"Content-Type": "application/json", "X-Api-Version": "2", "X-Source": "influxdb"}
+endpoint = http["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+encode_value = (v) =>
+	(string(v: json["encode"](v: v)))
+encode_string = (v) =>
+	(strings["trimSuffix"](v: strings["trimPrefix"](v: encode_value(v: v), prefix: "\""), suffix: "\""))
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = "{\"text\": \"" + encode_string(v: r["_check_name"]) + " is " + encode_string(v: r["_level"]) + " on " + encode_string(v: r["host"]) + "\", \"message\": " + encode_value(v: r["_message"]) + ", \"time\": " + encode_value(v: r["_time"]) + ", \"rule\": " + encode_value(v: notification["_notification_rule_name"]) + ", \"runbook\": " + encode_value(v: "https://example.com/runbook") + ", \"price\": \"$" + "{5}\"}"
+
+		return {headers: headers, data: bytes(v: body)}
+	}))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:          1,
+			Name:        "foo",
+			Every:       mustDuration("1h"),
+			Offset:      mustDuration("1s"),
+			EndpointID:  2,
+			RunbookLink: "https://example.com/runbook",
+			TagRules:    []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		BodyTemplate: `{"text": "{{ r._check_name }} is {{r._level}} on {{ r.host }}", "message": {{ r._message }}, "time": {{ r._time }}, "rule": {{ notification._notification_rule_name }}, "runbook": {{ runbookLink }}, "price": "${5}"}`,
+		Headers: map[string]string{
+			"X-Source":      "influxdb",
+			"X-Api-Version": "2",
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
+
+func TestHTTP_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		Name:       "foo",
+		OwnerID:    2,
+		OrgID:      3,
+		EndpointID: 4,
+	}
+	tests := []struct {
+		name string
+		rule *rule.HTTP
+		want string
+	}{
+		{
+			name: "valid",
+			rule: &rule.HTTP{
+				Base:         base,
+				BodyTemplate: `{"summary": "{{ r._check_name }}: {{ r._message }}", "level": {{ r._level }}}`,
+				Headers:      map[string]string{"X-Source": "influxdb"},
+			},
+		},
+		{
+			name: "unclosed placeholder",
+			rule: &rule.HTTP{Base: base, BodyTemplate: `{"level": {{ r._level }`},
+			want: "http body template is invalid: placeholder at offset 10 is not closed",
+		},
+		{
+			name: "unknown placeholder",
+			rule: &rule.HTTP{Base: base, BodyTemplate: `{"level": {{ level }}}`},
+			want: `http body template is invalid: unknown placeholder "level"`,
+		},
+		{
+			name: "unknown notification field",
+			rule: &rule.HTTP{Base: base, BodyTemplate: `{"rule": {{ notification.name }}}`},
+			want: `http body template is invalid: unknown placeholder "notification.name"`,
+		},
+		{
+			name: "invalid json",
+			rule: &rule.HTTP{Base: base, BodyTemplate: `{"level": "{{ r._level }}"`},
+			want: "http body template is invalid: template does not render valid JSON",
+		},
+		{
+			name: "placeholder quoted twice",
+			rule: &rule.HTTP{Base: base, BodyTemplate: `{"level": ""{{ r._level }}""}`},
+			want: "http body template is invalid: template does not render valid JSON",
+		},
+		{
+			name: "invalid header name",
+			rule: &rule.HTTP{Base: base, Headers: map[string]string{"X Source": "influxdb"}},
+			want: `http header name "X Source" is invalid`,
+		},
+		{
+			name: "invalid header value",
+			rule: &rule.HTTP{Base: base, Headers: map[string]string{"X-Source": "influx\ndb"}},
+			want: `http header "X-Source" has an invalid value`,
+		},
+		{
+			name: "reserved header",
+			rule: &rule.HTTP{Base: base, Headers: map[string]string{"authorization": "Bearer x"}},
+			want: `http header "authorization" can not be set by the rule`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Valid()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || influxdb.ErrorMessage(err) != tt.want {
+				t.Fatalf("unexpected error: got %v want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// Opsgenie is the notification rule config of opsgenie.
+type Opsgenie struct {
+	Base
+	MessageTemplate string `json:"messageTemplate"`
+	// Tags are attached to every alert created by the rule.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// GenerateFlux generates a flux script for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(opsgenieEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFluxAST(e *endpoint.Opsgenie) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(s.generateFluxASTEscalation)
+	if err != nil {
+		return nil, err
+	}
+
+	f := flux.File(
+		s.Name,
+		s.escalationImports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets", "experimental"),
+		append(s.generateFluxASTBody(e), escalations...),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Opsgenie) generateFluxASTBody(e *endpoint.Opsgenie) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateHeaders())
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+// generateFluxASTEscalation generates the statements notifying the opsgenie
+// endpoint of an escalation step, an endpoint of another type is notified
+// with the message of the rule.
+func (s *Opsgenie) generateFluxASTEscalation(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return s.generateFluxASTEscalationTo(e, s.MessageTemplate)
+	}
+
+	var statements []ast.Statement
+	statements = append(statements, s.generateFluxASTSecrets(opsgenieEndpoint))
+	statements = append(statements, s.generateHeaders())
+	statements = append(statements, s.generateFluxASTEndpoint(opsgenieEndpoint))
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+	return statements, nil
+}
+
+func (s *Opsgenie) generateFluxASTSecrets(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.APIKey.Key))))
+
+	return flux.DefineVariable("opsgenie_secret", call)
+}
+
+func (s *Opsgenie) generateHeaders() ast.Statement {
+	props := []*ast.Property{
+		flux.Dictionary("Content-Type", flux.String("application/json")),
+		flux.Dictionary("Authorization", flux.Add(flux.String("GenieKey "), flux.Identifier("opsgenie_secret"))),
+	}
+	return flux.DefineVariable("headers", flux.Object(props...))
+}
+
+// generateFluxASTEndpoint defines the endpoint in the script rather than using
+// http.endpoint, opsgenie accepts alerts with 202 which http.endpoint does not
+// count as sent.
+func (s *Opsgenie) generateFluxASTEndpoint(e *endpoint.Opsgenie) ast.Statement {
+	post := flux.Call(
+		flux.Member("http", "post"),
+		flux.Object(
+			flux.Property("url", flux.String(e.AlertURL())),
+			flux.Property("headers", flux.Member("obj", "headers")),
+			flux.Property("data", flux.Member("obj", "data")),
+		),
+	)
+	// 2 == http.post(...) / 100 holds for any 2xx status.
+	sent := flux.Call(
+		flux.Identifier("string"),
+		flux.Object(flux.Property("v", flux.Equal(flux.Integer(2), flux.Divide(post, flux.Integer(100))))),
+	)
+	mapFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("obj", flux.Call(flux.Identifier("mapFn"), flux.Object(flux.Property("r", flux.Identifier("r"))))),
+		&ast.ReturnStatement{
+			Argument: flux.ObjectWith("r", flux.Property("_sent", sent)),
+		},
+	)
+	send := flux.Function(
+		flux.PipeParams("tables"),
+		flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", mapFn))),
+		),
+	)
+
+	return flux.DefineVariable("opsgenie_endpoint", flux.Function(flux.FunctionParams("mapFn"), send))
+}
+
+func (s *Opsgenie) generateFluxASTNotifyPipe() ast.Statement {
+	endpointBody := flux.Call(
+		flux.Member("json", "encode"),
+		flux.Object(flux.Property("v", flux.Identifier("body"))),
+	)
+
+	endpointProps := []*ast.Property{
+		flux.Property("headers", flux.Identifier("headers")),
+		flux.Property("data", endpointBody),
+	}
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		s.generateBody(),
+		&ast.ReturnStatement{
+			Argument: flux.Object(endpointProps...),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("opsgenie_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+// generateBody builds the alert, see https://docs.opsgenie.com/docs/alert-api#create-alert.
+// The alias ties the alerts of a check and rule together so that opsgenie
+// de-duplicates repeated notifications.
+func (s *Opsgenie) generateBody() ast.Statement {
+	alias := flux.Add(
+		flux.Add(flux.Member("notification", "_notification_rule_id"), flux.String("-")),
+		flux.Member("r", "_check_id"),
+	)
+
+	props := []*ast.Property{
+		flux.Property("message", flux.String(s.MessageTemplate)),
+		flux.Property("alias", alias),
+		flux.Property("description", flux.Member("r", "_message")),
+		flux.Property("priority", s.generatePriority()),
+		flux.Property("source", flux.String("influxdb")),
+		flux.Property("entity", flux.Member("r", "_source_measurement")),
+	}
+	if len(s.Tags) > 0 {
+		tags := make([]ast.Expression, 0, len(s.Tags))
+		for _, t := range s.Tags {
+			tags = append(tags, flux.String(t))
+		}
+		props = append(props, flux.Property("tags", flux.Array(tags...)))
+	}
+
+	return flux.DefineVariable("body", flux.Object(props...))
+}
+
+func (s *Opsgenie) generatePriority() ast.Expression {
+	level := flux.Member("r", "_level")
+	return flux.If(
+		flux.Equal(level, flux.String("crit")),
+		flux.String("P1"),
+		flux.If(
+			flux.Equal(level, flux.String("warn")),
+			flux.String("P3"),
+			flux.String("P5"),
+		),
+	)
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie message template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Opsgenie) Type() string {
+	return "opsgenie"
+}
@@ -0,0 +1,95 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestOpsgenie_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets["get"](key: "opsgenie-api-key")
+headers = {"Content-Type": "application/json", "Authorization": "GenieKey " + opsgenie_secret}
+opsgenie_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) => {
+				obj = mapFn(r: r)
+
+				return {r with _sent: string(v: 2 == http["post"](url: "https://api.eu.opsgenie.com/v2/alerts", headers: obj["headers"], data: obj["data"]) / 100)}
+			})))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: opsgenie_endpoint(mapFn: (r) => {
+		body = {
+			message: "${r._check_name} is ${r._level}",
+			alias: notification["_notification_rule_id"] + "-" + r["_check_id"],
+			description: r["_message"],
+			priority: if r["_level"] == "crit" then "P1" else if r["_level"] == "warn" then "P3" else "P5",
+			source: "influxdb",
+			entity: r["_source_measurement"],
+			tags: ["influxdb", "cpu"],
+		}
+
+		return {headers: headers, data: json["encode"](v: body)}
+	}))`
+
+	s := &rule.Opsgenie{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		MessageTemplate: "${r._check_name} is ${r._level}",
+		Tags:            []string{"influxdb", "cpu"},
+	}
+
+	e := &endpoint.Opsgenie{
+		Base: endpoint.Base{
+			ID:   idPtr(2),
+			Name: "foo",
+		},
+		URL:    "https://api.eu.opsgenie.com/v2/alerts",
+		APIKey: influxdb.SecretField{Key: "opsgenie-api-key"},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
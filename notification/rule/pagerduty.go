@@ -64,10 +64,15 @@ func (s *PagerDuty) GenerateFlux(e influxdb.NotificationEndpoint) (string, error
 
 // GenerateFluxAST generates a flux AST for the pagerduty notification rule.
 func (s *PagerDuty) GenerateFluxAST(e *endpoint.PagerDuty) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(s.generateFluxASTEscalation)
+	if err != nil {
+		return nil, err
+	}
+
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "pagerduty", "influxdata/influxdb/secrets", "experimental"),
-		s.generateFluxASTBody(e),
+		s.escalationImports("influxdata/influxdb/monitor", "pagerduty", "influxdata/influxdb/secrets", "experimental"),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
@@ -85,6 +90,22 @@ func (s *PagerDuty) generateFluxASTBody(e *endpoint.PagerDuty) []ast.Statement {
 	return statements
 }
 
+// generateFluxASTEscalation generates the statements notifying the pagerduty
+// endpoint of an escalation step, an endpoint of another type is notified
+// with the message of the rule.
+func (s *PagerDuty) generateFluxASTEscalation(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	pagerdutyEndpoint, ok := e.(*endpoint.PagerDuty)
+	if !ok {
+		return s.generateFluxASTEscalationTo(e, s.MessageTemplate)
+	}
+
+	var statements []ast.Statement
+	statements = append(statements, s.generateFluxASTSecrets(pagerdutyEndpoint))
+	statements = append(statements, s.generateFluxASTEndpoint(pagerdutyEndpoint))
+	statements = append(statements, s.generateFluxASTNotifyPipe(pagerdutyEndpoint.ClientURL))
+	return statements, nil
+}
+
 func (s *PagerDuty) generateFluxASTSecrets(e *endpoint.PagerDuty) ast.Statement {
 	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.RoutingKey.Key))))
 
@@ -16,6 +16,16 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"slack":     func() influxdb.NotificationRule { return &Slack{} },
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
+	"smtp":      func() influxdb.NotificationRule { return &SMTP{} },
+	"opsgenie":  func() influxdb.NotificationRule { return &Opsgenie{} },
+	"teams":     func() influxdb.NotificationRule { return &Teams{} },
+}
+
+// IsType returns true if typ is the type of a notification rule. The task of a
+// notification rule has the type of its rule.
+func IsType(typ string) bool {
+	_, ok := typeToRule[typ]
+	return ok
 }
 
 // UnmarshalJSON will convert
@@ -57,6 +67,25 @@ type Base struct {
 	RunbookLink string                    `json:"runbookLink"`
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	// GroupBy are the columns statuses are grouped by, only the latest status
+	// of each group is notified. It defaults to the check id when statuses are
+	// deduplicated.
+	GroupBy []string `json:"groupBy,omitempty"`
+	// RepeatInterval is the interval before a group still at the level it was
+	// notified at is notified again.
+	RepeatInterval *notification.Duration `json:"repeatInterval,omitempty"`
+	// NotifyOnResolve notifies the groups that were notified at a level other
+	// than ok once their latest status is ok.
+	NotifyOnResolve bool `json:"notifyOnResolve,omitempty"`
+	// EscalationPolicyID is the escalation policy notifying other endpoints
+	// of the statuses of the rule that keep firing.
+	EscalationPolicyID influxdb.ID `json:"escalationPolicyID,omitempty"`
+	// Escalations are the steps of the escalation policy of the rule, they
+	// are set by the store before generating the flux.
+	Escalations []Escalation `json:"-"`
+	// Silences mute the notifications of the generated flux, they are not
+	// part of the rule and set by the store before generating it.
+	Silences []*influxdb.Silence `json:"-"`
 	*influxdb.Limit
 	influxdb.CRUDLog
 }
@@ -103,6 +132,20 @@ func (b Base) valid() error {
 			return err
 		}
 	}
+	for _, col := range b.GroupBy {
+		if col == "" || col == "_time" || col == "_level" {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("Notification Rule can't be grouped by %q", col),
+			}
+		}
+	}
+	if b.RepeatInterval != nil && b.RepeatInterval.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "if repeatInterval is set, it must be larger than 0",
+		}
+	}
 	if b.Limit != nil {
 		if b.Limit.Every <= 0 || b.Limit.Rate <= 0 {
 			return &influxdb.Error{
@@ -181,9 +224,20 @@ func (b *Base) generateLevelChecks() []ast.Statement {
 		)
 	}
 
-	stmts = append(stmts, flux.DefineVariable("all_statuses", pipe))
-
-	return stmts
+	name := "all_statuses"
+	if b.deduplicates() {
+		name = "firing_statuses"
+	}
+	if len(b.Silences) == 0 {
+		stmts = append(stmts, flux.DefineVariable(name, pipe))
+	} else {
+		stmts = append(stmts, flux.DefineVariable("checked_statuses", pipe))
+		stmts = append(stmts, b.generateSilences(name)...)
+	}
+	if !b.deduplicates() {
+		return stmts
+	}
+	return append(stmts, b.generateDeduplication(timeFilter)...)
 }
 
 func (b *Base) generateLevelCheck(r notification.StatusRule) (ast.Statement, *ast.Identifier) {
@@ -305,6 +359,11 @@ func (b *Base) generateFluxASTStatuses() ast.Statement {
 	return flux.DefineVariable("statuses", base)
 }
 
+// SetSilences sets the silences the generated flux respects.
+func (b *Base) SetSilences(silences []*influxdb.Silence) {
+	b.Silences = silences
+}
+
 // GetID implements influxdb.Getter interface.
 func (b Base) GetID() influxdb.ID {
 	return b.ID
@@ -195,6 +195,42 @@ func TestValidRule(t *testing.T) {
 				Msg:  `if limit is set, limit and limitEvery must be larger than 0`,
 			},
 		},
+		{
+			name: "group by time",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Name:       "name1",
+					GroupBy:    []string{"host", "_time"},
+				},
+				MessageTemplate: "blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `Notification Rule can't be grouped by "_time"`,
+			},
+		},
+		{
+			name: "bad repeat interval",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:             influxTesting.MustIDBase16(id1),
+					OwnerID:        influxTesting.MustIDBase16(id2),
+					OrgID:          influxTesting.MustIDBase16(id3),
+					EndpointID:     1,
+					Name:           "name1",
+					RepeatInterval: mustDuration("0s"),
+				},
+				MessageTemplate: "blah",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `if repeatInterval is set, it must be larger than 0`,
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -321,6 +357,66 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "msg1",
 			},
 		},
+		{
+			name: "simple opsgenie",
+			src: &rule.Opsgenie{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					OwnerID: influxTesting.MustIDBase16(id2),
+					Name:    "name1",
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				MessageTemplate: "msg1",
+				Tags:            []string{"tag1", "tag2"},
+			},
+		},
+		{
+			name: "simple smtp",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					OwnerID: influxTesting.MustIDBase16(id2),
+					Name:    "name1",
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				To:              "ops@example.com",
+				SubjectTemplate: "subject1",
+				BodyTemplate:    "body1",
+			},
+		},
+		{
+			name: "simple teams",
+			src: &rule.Teams{
+				Base: rule.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					OwnerID: influxTesting.MustIDBase16(id2),
+					Name:    "name1",
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				TitleTemplate:   "title1",
+				MessageTemplate: "msg1",
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -0,0 +1,81 @@
+package rule
+
+import (
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// generateSilences splits the checked statuses into the statuses to notify,
+// defined as name, and the silenced ones. The silenced statuses are logged as
+// suppressed notifications with the id of the silence instead of being sent.
+func (b *Base) generateSilences(name string) []ast.Statement {
+	silenceID := b.generateSilenceID()
+	call := func(fn string) *ast.CallExpression {
+		return flux.Call(flux.Identifier(fn), flux.Object(flux.Property("r", flux.Identifier("r"))))
+	}
+	filter := func(e ast.Expression) *ast.CallExpression {
+		return flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), e))),
+		)
+	}
+	mapWith := func(props ...*ast.Property) *ast.CallExpression {
+		return flux.Call(
+			flux.Identifier("map"),
+			flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r", props...)))),
+		)
+	}
+
+	suppress := flux.Function(
+		flux.PipeParams("tables"),
+		flux.Pipe(
+			flux.Identifier("tables"),
+			mapWith(
+				flux.Property("_sent", flux.String("false")),
+				flux.Property("_suppressed", flux.String("true")),
+			),
+		),
+	)
+	suppressed := flux.Pipe(
+		flux.Identifier("checked_statuses"),
+		filter(flux.NotEqual(call("silence_id"), flux.String(""))),
+		mapWith(flux.Property("_silence_id", call("silence_id"))),
+		flux.Call(
+			flux.Member("monitor", "notify"),
+			flux.Object(
+				flux.Property("data", flux.Identifier("notification")),
+				flux.Property("endpoint", suppress),
+			),
+		),
+		flux.Call(flux.Identifier("yield"), flux.Object(flux.Property("name", flux.String("suppressed")))),
+	)
+
+	return []ast.Statement{
+		flux.DefineVariable("silence_id", silenceID),
+		flux.DefineVariable(name, flux.Pipe(
+			flux.Identifier("checked_statuses"),
+			filter(flux.Equal(call("silence_id"), flux.String(""))),
+		)),
+		flux.ExpressionStatement(suppressed),
+	}
+}
+
+// generateSilenceID returns a function of a status that returns the id of the
+// first silence muting it, or an empty string.
+func (b *Base) generateSilenceID() ast.Expression {
+	var id ast.Expression = flux.String("")
+	for i := len(b.Silences) - 1; i >= 0; i-- {
+		s := b.Silences[i]
+
+		var match ast.Expression = flux.And(
+			flux.GreaterThanEqual(flux.Member("r", "_time"), flux.DateTime(s.StartsAt)),
+			flux.LessThan(flux.Member("r", "_time"), flux.DateTime(s.EndsAt)),
+		)
+		for _, m := range s.Matchers {
+			match = flux.And(match, notification.TagRule(m).GenerateFluxAST())
+		}
+		id = flux.If(match, flux.String(s.ID.String()), id)
+	}
+	return flux.Function(flux.FunctionParams("r"), id)
+}
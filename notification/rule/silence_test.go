@@ -0,0 +1,109 @@
+package rule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestSilences_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack["endpoint"](url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+checked_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+silence_id = (r) =>
+	(if r["_time"] >= 2020-06-01T00:00:00Z and r["_time"] < 2020-06-01T01:00:00Z and r["host"] == "db01" then "0000000000000003" else if r["_time"] >= 2020-06-01T00:00:00Z and r["_time"] < 2020-06-01T02:00:00Z and r["region"] =~ /us-.*/ and r["env"] != "prod" then "0000000000000004" else "")
+all_statuses = checked_statuses
+	|> filter(fn: (r) =>
+		(silence_id(r: r) == ""))
+
+checked_statuses
+	|> filter(fn: (r) =>
+		(silence_id(r: r) != ""))
+	|> map(fn: (r) =>
+		({r with _silence_id: silence_id(r: r)}))
+	|> monitor["notify"](data: notification, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "false", _suppressed: "true"}))))
+	|> yield(name: "suppressed")
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "", text: "blah", color: if r["_level"] == "crit" then "danger" else if r["_level"] == "warn" then "warning" else "good"})))`
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := &rule.Slack{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		MessageTemplate: "blah",
+	}
+	s.SetSilences([]*influxdb.Silence{
+		{
+			ID:       3,
+			StartsAt: start,
+			EndsAt:   start.Add(time.Hour),
+			Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal},
+			},
+		},
+		{
+			ID:       4,
+			StartsAt: start,
+			EndsAt:   start.Add(2 * time.Hour),
+			Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "region", Value: "us-.*"}, Operator: influxdb.RegexEqual},
+				{Tag: influxdb.Tag{Key: "env", Value: "prod"}, Operator: influxdb.NotEqual},
+			},
+		},
+	})
+
+	id := influxdb.ID(2)
+	e := &endpoint.Slack{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
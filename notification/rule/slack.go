@@ -32,10 +32,15 @@ func (s *Slack) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the slack notification rule.
 func (s *Slack) GenerateFluxAST(e *endpoint.Slack) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(s.generateFluxASTEscalation)
+	if err != nil {
+		return nil, err
+	}
+
 	f := flux.File(
 		s.Name,
-		flux.Imports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets", "experimental"),
-		s.generateFluxASTBody(e),
+		s.escalationImports("influxdata/influxdb/monitor", "slack", "influxdata/influxdb/secrets", "experimental"),
+		append(s.generateFluxASTBody(e), escalations...),
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
@@ -55,6 +60,24 @@ func (s *Slack) generateFluxASTBody(e *endpoint.Slack) []ast.Statement {
 	return statements
 }
 
+// generateFluxASTEscalation generates the statements notifying the slack
+// endpoint of an escalation step, an endpoint of another type is notified
+// with the message of the rule.
+func (s *Slack) generateFluxASTEscalation(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	slackEndpoint, ok := e.(*endpoint.Slack)
+	if !ok {
+		return s.generateFluxASTEscalationTo(e, s.MessageTemplate)
+	}
+
+	var statements []ast.Statement
+	if slackEndpoint.Token.Key != "" {
+		statements = append(statements, s.generateFluxASTSecrets(slackEndpoint))
+	}
+	statements = append(statements, s.generateFluxASTEndpoint(slackEndpoint))
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+	return statements, nil
+}
+
 func (s *Slack) generateFluxASTSecrets(e *endpoint.Slack) ast.Statement {
 	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Token.Key))))
 
@@ -0,0 +1,193 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// SMTP is the notification rule config of email.
+type SMTP struct {
+	Base
+	// To is the comma separated list of recipients.
+	To              string `json:"to"`
+	SubjectTemplate string `json:"subjectTemplate"`
+	// BodyTemplate is the plain text body, the status message when empty.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+}
+
+// GenerateFlux generates a flux script for the smtp notification rule.
+func (s *SMTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	smtpEndpoint, ok := e.(*endpoint.SMTP)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an SMTP endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(smtpEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the smtp notification rule.
+// The flux http package posts each message to the smtp:// url of the
+// endpoint, which the query http client delivers as email.
+func (s *SMTP) GenerateFluxAST(e *endpoint.SMTP) (*ast.Package, error) {
+	escalations, err := s.generateEscalations(s.generateFluxASTEscalation)
+	if err != nil {
+		return nil, err
+	}
+
+	f := flux.File(
+		s.Name,
+		s.imports(e),
+		append(s.generateFluxASTBody(e), escalations...),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *SMTP) imports(e *endpoint.SMTP) []*ast.ImportDeclaration {
+	packages := []string{
+		"influxdata/influxdb/monitor",
+		"http",
+		"experimental",
+	}
+
+	if e.Username.Key != "" {
+		packages = append(packages, "influxdata/influxdb/secrets")
+	}
+
+	return s.escalationImports(packages...)
+}
+
+func (s *SMTP) generateFluxASTBody(e *endpoint.SMTP) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	if e.Username.Key != "" {
+		statements = append(statements, s.generateFluxASTSecrets(e)...)
+	}
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateLevelChecks()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe(e))
+
+	return statements
+}
+
+// generateFluxASTEscalation generates the statements notifying the smtp
+// endpoint of an escalation step, an endpoint of another type is notified
+// with the subject of the rule.
+func (s *SMTP) generateFluxASTEscalation(e influxdb.NotificationEndpoint) ([]ast.Statement, error) {
+	smtpEndpoint, ok := e.(*endpoint.SMTP)
+	if !ok {
+		return s.generateFluxASTEscalationTo(e, s.SubjectTemplate)
+	}
+
+	var statements []ast.Statement
+	if smtpEndpoint.Username.Key != "" {
+		statements = append(statements, s.generateFluxASTSecrets(smtpEndpoint)...)
+	}
+	statements = append(statements, s.generateFluxASTEndpoint(smtpEndpoint))
+	statements = append(statements, s.generateFluxASTNotifyPipe(smtpEndpoint))
+	return statements, nil
+}
+
+func (s *SMTP) generateFluxASTSecrets(e *endpoint.SMTP) []ast.Statement {
+	username := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Username.Key))))
+	password := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Password.Key))))
+
+	return []ast.Statement{
+		flux.DefineVariable("smtp_username", username),
+		flux.DefineVariable("smtp_password", password),
+	}
+}
+
+func (s *SMTP) generateFluxASTEndpoint(e *endpoint.SMTP) ast.Statement {
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", flux.String(e.URL()))))
+
+	return flux.DefineVariable("smtp_endpoint", call)
+}
+
+func (s *SMTP) generateFluxASTNotifyPipe(e *endpoint.SMTP) ast.Statement {
+	headers := []*ast.Property{
+		flux.Dictionary("Content-Type", flux.String("text/plain; charset=utf-8")),
+		flux.Dictionary("From", flux.String(e.From)),
+		flux.Dictionary("To", flux.String(s.To)),
+		flux.Dictionary("Subject", flux.String(s.SubjectTemplate)),
+	}
+	if e.Username.Key != "" {
+		basic := flux.Call(
+			flux.Member("http", "basicAuth"),
+			flux.Object(
+				flux.Property("u", flux.Identifier("smtp_username")),
+				flux.Property("p", flux.Identifier("smtp_password")),
+			),
+		)
+		headers = append(headers, flux.Dictionary("Authorization", basic))
+	}
+
+	var body ast.Expression = flux.Member("r", "_message")
+	if s.BodyTemplate != "" {
+		body = flux.String(s.BodyTemplate)
+	}
+
+	endpointProps := []*ast.Property{
+		flux.Property("headers", flux.Object(headers...)),
+		flux.Property("data", flux.Call(flux.Identifier("bytes"), flux.Object(flux.Property("v", body)))),
+	}
+	endpointFn := flux.Function(flux.FunctionParams("r"), flux.Object(endpointProps...))
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("smtp_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.SubjectTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp subject template is empty",
+		}
+	}
+	if _, err := mail.ParseAddressList(s.To); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp recipients are invalid: %s", err.Error()),
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s SMTP) Type() string {
+	return "smtp"
+}
@@ -0,0 +1,201 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+)
+
+func TestSMTP_GenerateFlux(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     *rule.SMTP
+		endpoint *endpoint.SMTP
+		want     string
+	}{
+		{
+			name: "with credentials",
+			rule: &rule.SMTP{
+				Base: rule.Base{
+					ID:         1,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					EndpointID: 2,
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+				},
+				To:              "ops@example.com, dev@example.com",
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+			},
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				From:     "alerts@example.com",
+				Username: influxdb.SecretField{Key: "smtp-username"},
+				Password: influxdb.SecretField{Key: "smtp-password"},
+			},
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "experimental"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+smtp_username = secrets["get"](key: "smtp-username")
+smtp_password = secrets["get"](key: "smtp-password")
+smtp_endpoint = http["endpoint"](url: "smtp://smtp.example.com:587")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({headers: {
+			"Content-Type": "text/plain; charset=utf-8",
+			"From": "alerts@example.com",
+			"To": "ops@example.com, dev@example.com",
+			"Subject": "${r._check_name} is ${r._level}",
+			"Authorization": http["basicAuth"](u: smtp_username, p: smtp_password),
+		}, data: bytes(v: r["_message"])})))`,
+		},
+		{
+			name: "tls with body template",
+			rule: &rule.SMTP{
+				Base: rule.Base{
+					ID:         1,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					EndpointID: 2,
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+				},
+				To:              "ops@example.com",
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message} at ${string(v: r._source_timestamp)}",
+			},
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				Host: "smtp.example.com",
+				TLS:  true,
+				From: "alerts@example.com",
+			},
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+smtp_endpoint = http["endpoint"](url: "smtps://smtp.example.com")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor["from"](start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r["_level"] == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r["_time"] > experimental["subDuration"](from: now(), d: 1h)))
+
+all_statuses
+	|> monitor["notify"](data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({headers: {
+			"Content-Type": "text/plain; charset=utf-8",
+			"From": "alerts@example.com",
+			"To": "ops@example.com",
+			"Subject": "${r._check_name} is ${r._level}",
+		}, data: bytes(v: "${r._message} at ${string(v: r._source_timestamp)}")})))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if f != tt.want {
+				t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", tt.want, f)
+			}
+		})
+	}
+}
+
+func TestSMTP_Valid(t *testing.T) {
+	base := rule.Base{
+		ID:         1,
+		Name:       "foo",
+		OwnerID:    2,
+		OrgID:      3,
+		EndpointID: 4,
+	}
+	tests := []struct {
+		name string
+		rule *rule.SMTP
+		want string
+	}{
+		{
+			name: "valid",
+			rule: &rule.SMTP{Base: base, To: "ops@example.com", SubjectTemplate: "subject"},
+		},
+		{
+			name: "missing subject",
+			rule: &rule.SMTP{Base: base, To: "ops@example.com"},
+			want: "smtp subject template is empty",
+		},
+		{
+			name: "missing recipients",
+			rule: &rule.SMTP{Base: base, SubjectTemplate: "subject"},
+			want: "smtp recipients are invalid: mail: no address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Valid()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || influxdb.ErrorMessage(err) != tt.want {
+				t.Fatalf("unexpected error: got %v want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Package smtp delivers email notifications.
+//
+// Flux has no SMTP support, so notification rules for SMTP endpoints
+// post each message to an smtp:// or smtps:// URL through the Flux http
+// package. The RoundTripper in this package is registered for those
+// schemes on the HTTP client of the queries of notification rule tasks
+// and turns the requests into mail.
+// The sender and recipients are read from the From and To headers, the
+// subject from the Subject header and the credentials, if any, from
+// basic auth. The request body is the message body.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+)
+
+// URL schemes handled by the RoundTripper.
+const (
+	// Scheme connects in plain text and upgrades with STARTTLS
+	// when the server supports it.
+	Scheme = "smtp"
+	// TLSScheme connects with implicit TLS.
+	TLSScheme = "smtps"
+)
+
+// Default ports used when the URL does not name one.
+const (
+	DefaultPort    = 25
+	DefaultTLSPort = 465
+)
+
+const defaultContentType = "text/plain; charset=utf-8"
+
+// RoundTripper sends the body of an http request as an email.
+// Malformed requests are answered with 400 and failed deliveries with 502,
+// so that notification rules record the message as not sent rather than
+// failing the task.
+type RoundTripper struct {
+	// TLSConfig is used for smtps connections and STARTTLS.
+	// When nil, the server name of the URL is verified with the system roots.
+	TLSConfig *tls.Config
+	// Timeout bounds the whole delivery. Zero means no timeout beyond the
+	// request context.
+	Timeout time.Duration
+	// Validator, if set, validates the address of the server when it is
+	// dialed, so that a host name resolving to a forbidden IP, e.g. a
+	// private one, is rejected even if it resolved otherwise when the url
+	// was validated.
+	Validator fluxurl.Validator
+
+	now func() time.Time
+}
+
+// RegisterProtocols registers a RoundTripper for the smtp and smtps schemes on t,
+// which dials only the servers that validator accepts.
+func RegisterProtocols(t *http.Transport, validator fluxurl.Validator) {
+	rt := &RoundTripper{Timeout: 30 * time.Second, Validator: validator}
+	t.RegisterProtocol(Scheme, rt)
+	t.RegisterProtocol(TLSScheme, rt)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	if req.Method != http.MethodPost {
+		return response(req, http.StatusMethodNotAllowed, "email can only be sent with POST"), nil
+	}
+
+	msg, err := t.newMessage(req)
+	if err != nil {
+		return response(req, http.StatusBadRequest, err.Error()), nil
+	}
+
+	ctx := req.Context()
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	if err := t.send(ctx, req, msg); err != nil {
+		return response(req, http.StatusBadGateway, err.Error()), nil
+	}
+	return response(req, http.StatusOK, ""), nil
+}
+
+type message struct {
+	from string
+	to   []string
+	data []byte
+}
+
+func (t *RoundTripper) newMessage(req *http.Request) (*message, error) {
+	from, err := mail.ParseAddress(req.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid From header: %v", err)
+	}
+	to, err := mail.ParseAddressList(req.Header.Get("To"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid To header: %v", err)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+	}
+	header("From", from.String())
+	addrs := make([]string, 0, len(to))
+	recipients := make([]string, 0, len(to))
+	for _, a := range to {
+		addrs = append(addrs, a.String())
+		recipients = append(recipients, a.Address)
+	}
+	header("To", strings.Join(addrs, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", req.Header.Get("Subject")))
+	header("Date", now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", contentType)
+	buf.WriteString("\r\n")
+	buf.Write(normalizeNewlines(body))
+
+	return &message{
+		from: from.Address,
+		to:   recipients,
+		data: buf.Bytes(),
+	}, nil
+}
+
+func (t *RoundTripper) send(ctx context.Context, req *http.Request, msg *message) error {
+	host, addr := hostAddr(req)
+
+	tlsConfig := &tls.Config{ServerName: host}
+	if t.TLSConfig != nil {
+		tlsConfig = t.TLSConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = host
+		}
+	}
+
+	d := net.Dialer{Control: t.validate}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if req.URL.Scheme == TLSScheme {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if req.URL.Scheme == Scheme {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	if username, password, ok := req.BasicAuth(); ok {
+		if err := c.Auth(smtp.PlainAuth("", username, password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(msg.from); err != nil {
+		return err
+	}
+	for _, rcpt := range msg.to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// validate checks the address about to be dialed with the validator.
+func (t *RoundTripper) validate(network, address string, _ syscall.RawConn) error {
+	if t.Validator == nil {
+		return nil
+	}
+	return t.Validator.Validate(&url.URL{Host: address})
+}
+
+// hostAddr returns the host name and the host:port address to dial for the request.
+func hostAddr(req *http.Request) (string, string) {
+	host, port := req.URL.Hostname(), req.URL.Port()
+	if port == "" {
+		port = fmt.Sprint(DefaultPort)
+		if req.URL.Scheme == TLSScheme {
+			port = fmt.Sprint(DefaultTLSPort)
+		}
+	}
+	return host, net.JoinHostPort(host, port)
+}
+
+func normalizeNewlines(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n"))
+}
+
+func response(req *http.Request, code int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,267 @@
+package smtp
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+)
+
+// server is a minimal SMTP stand-in that records what it receives.
+type server struct {
+	ln net.Listener
+
+	// rejectRcpt makes the server refuse every recipient.
+	rejectRcpt bool
+
+	mu   sync.Mutex
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *server) close() {
+	s.ln.Close()
+}
+
+func (s *server) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			parts := strings.Fields(line)
+			b, _ := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+			s.mu.Lock()
+			s.auth = string(b)
+			s.mu.Unlock()
+			reply("235 ok")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			s.mu.Unlock()
+			reply("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				reply("550 no such user")
+				continue
+			}
+			s.mu.Lock()
+			s.to = append(s.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			s.mu.Unlock()
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func newRequest(t *testing.T, url string, header map[string]string, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+func TestRoundTripper(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("delivers message", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		rt := &RoundTripper{now: func() time.Time { return now }}
+
+		req := newRequest(t, "smtp://"+s.addr(), map[string]string{
+			"From":    "InfluxDB <alerts@example.com>",
+			"To":      "ops@example.com, Dev <dev@example.com>",
+			"Subject": "cpu is crit",
+		}, "cpu usage is 99%\non host a")
+		req.SetBasicAuth("user", "pass")
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if want := "\x00user\x00pass"; s.auth != want {
+			t.Errorf("unexpected auth: got %q want %q", s.auth, want)
+		}
+		if want := "alerts@example.com"; s.from != want {
+			t.Errorf("unexpected sender: got %q want %q", s.from, want)
+		}
+		if got, want := strings.Join(s.to, ","), "ops@example.com,dev@example.com"; got != want {
+			t.Errorf("unexpected recipients: got %q want %q", got, want)
+		}
+		want := "From: \"InfluxDB\" <alerts@example.com>\r\n" +
+			"To: <ops@example.com>, \"Dev\" <dev@example.com>\r\n" +
+			"Subject: cpu is crit\r\n" +
+			"Date: Mon, 01 Jun 2020 12:00:00 +0000\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"\r\n" +
+			"cpu usage is 99%\r\n" +
+			"on host a\r\n"
+		if s.data != want {
+			t.Errorf("unexpected message:\ngot:\n%s\nwant:\n%s", s.data, want)
+		}
+	})
+
+	t.Run("invalid recipients", func(t *testing.T) {
+		rt := &RoundTripper{}
+		req := newRequest(t, "smtp://127.0.0.1:1", map[string]string{
+			"From": "alerts@example.com",
+			"To":   "not an address",
+		}, "body")
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+	})
+
+	t.Run("rejected by server", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		s.rejectRcpt = true
+		rt := &RoundTripper{}
+
+		req := newRequest(t, "smtp://"+s.addr(), map[string]string{
+			"From": "alerts@example.com",
+			"To":   "nobody@example.com",
+		}, "body")
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+	})
+
+	t.Run("private address rejected when dialed", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		rt := &RoundTripper{Validator: fluxurl.PrivateIPValidator{}}
+
+		req := newRequest(t, "smtp://"+s.addr(), map[string]string{
+			"From": "alerts@example.com",
+			"To":   "ops@example.com",
+		}, "body")
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.from != "" {
+			t.Errorf("unexpected message delivered from %q", s.from)
+		}
+	})
+
+	t.Run("registered on transport", func(t *testing.T) {
+		s := newServer(t)
+		defer s.close()
+		tr := &http.Transport{}
+		RegisterProtocols(tr, fluxurl.PassValidator{})
+		client := &http.Client{Transport: tr}
+
+		req := newRequest(t, "smtp://"+s.addr(), map[string]string{
+			"From": "alerts@example.com",
+			"To":   "ops@example.com",
+		}, "body")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+	})
+}
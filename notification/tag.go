@@ -1,6 +1,8 @@
 package notification
 
 import (
+	"regexp"
+
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/notification/flux"
@@ -19,10 +21,19 @@ func (tr TagRule) GenerateFluxAST() ast.Expression {
 	k := flux.Member("r", tr.Key)
 	v := flux.String(tr.Value)
 
+	// values that are not valid regular expressions are compared as strings.
 	switch tr.Operator {
-	case influxdb.Equal:
-		return flux.Equal(k, v)
-		// TODO(desa): have this work for all operator types
+	case influxdb.NotEqual:
+		return flux.NotEqual(k, v)
+	case influxdb.RegexEqual:
+		if re, err := regexp.Compile(tr.Value); err == nil {
+			return flux.RegexpMatch(k, flux.Regexp(re))
+		}
+	case influxdb.NotRegexEqual:
+		if re, err := regexp.Compile(tr.Value); err == nil {
+			return flux.NotRegexpMatch(k, flux.Regexp(re))
+		}
+		return flux.NotEqual(k, v)
 	}
 
 	return flux.Equal(k, v)
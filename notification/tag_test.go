@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestTagRuleGenerateFluxAST(t *testing.T) {
+	cases := []struct {
+		name string
+		rule TagRule
+		want string
+	}{
+		{
+			name: "equal",
+			rule: TagRule{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal},
+			want: `r["host"] == "db01"`,
+		},
+		{
+			name: "not equal",
+			rule: TagRule{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.NotEqual},
+			want: `r["host"] != "db01"`,
+		},
+		{
+			name: "regex",
+			rule: TagRule{Tag: influxdb.Tag{Key: "host", Value: "db0[0-9]"}, Operator: influxdb.RegexEqual},
+			want: `r["host"] =~ /db0[0-9]/`,
+		},
+		{
+			name: "not regex",
+			rule: TagRule{Tag: influxdb.Tag{Key: "host", Value: "db0[0-9]"}, Operator: influxdb.NotRegexEqual},
+			want: `r["host"] !~ /db0[0-9]/`,
+		},
+		{
+			name: "invalid regex",
+			rule: TagRule{Tag: influxdb.Tag{Key: "host", Value: "db(0"}, Operator: influxdb.NotRegexEqual},
+			want: `r["host"] != "db(0"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ast.Format(c.rule.GenerateFluxAST()); got != c.want {
+				t.Errorf("unexpected flux: got %s want %s", got, c.want)
+			}
+		})
+	}
+}
@@ -54,13 +54,16 @@ var kindPriorities = map[Kind]int{
 	KindCheckThreshold:                5,
 	KindNotificationEndpoint:          6,
 	KindNotificationEndpointHTTP:      7,
-	KindNotificationEndpointPagerDuty: 8,
-	KindNotificationEndpointSlack:     9,
-	KindNotificationRule:              10,
-	KindTask:                          11,
-	KindVariable:                      12,
-	KindDashboard:                     13,
-	KindTelegraf:                      14,
+	KindNotificationEndpointOpsgenie:  8,
+	KindNotificationEndpointPagerDuty: 9,
+	KindNotificationEndpointSMTP:      10,
+	KindNotificationEndpointSlack:     11,
+	KindNotificationEndpointTeams:     12,
+	KindNotificationRule:              13,
+	KindTask:                          14,
+	KindVariable:                      15,
+	KindDashboard:                     16,
+	KindTelegraf:                      17,
 }
 
 type exportKey struct {
@@ -251,8 +254,11 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		mapResource(l.OrgID, uniqByNameResID, KindLabel, LabelToObject(r.Name, *l))
 	case r.Kind.is(KindNotificationEndpoint),
 		r.Kind.is(KindNotificationEndpointHTTP),
+		r.Kind.is(KindNotificationEndpointOpsgenie),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSMTP),
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointTeams):
 		e, err := ex.endpointSVC.FindNotificationEndpointByID(ctx, r.ID)
 		if err != nil {
 			return err
@@ -894,6 +900,31 @@ func NotificationEndpointToObject(name string, e influxdb.NotificationEndpoint)
 		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.Opsgenie:
+		o.Kind = KindNotificationEndpointOpsgenie
+		assignNonZeroStrings(o.Spec, map[string]string{
+			fieldNotificationEndpointURL: actual.URL,
+		})
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointAPIKey: actual.APIKey,
+		})
+	case *endpoint.SMTP:
+		o.Kind = KindNotificationEndpointSMTP
+		o.Spec[fieldNotificationEndpointHost] = actual.Host
+		o.Spec[fieldNotificationEndpointFrom] = actual.From
+		assignNonZeroInts(o.Spec, map[string]int{
+			fieldNotificationEndpointPort: actual.Port,
+		})
+		assignNonZeroBools(o.Spec, map[string]bool{
+			fieldNotificationEndpointTLS: actual.TLS,
+		})
+		assignNonZeroSecrets(o.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
+	case *endpoint.Teams:
+		o.Kind = KindNotificationEndpointTeams
+		o.Spec[fieldNotificationEndpointURL] = actual.URL
 	}
 
 	return o
@@ -913,8 +944,15 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 
 	assignBase := func(base rule.Base) {
 		assignNonZeroFluxDurs(o.Spec, map[string]*notification.Duration{
-			fieldEvery:  base.Every,
-			fieldOffset: base.Offset,
+			fieldEvery:                          base.Every,
+			fieldOffset:                         base.Offset,
+			fieldNotificationRuleRepeatInterval: base.RepeatInterval,
+		})
+		if len(base.GroupBy) > 0 {
+			o.Spec[fieldNotificationRuleGroupBy] = base.GroupBy
+		}
+		assignNonZeroBools(o.Spec, map[string]bool{
+			fieldNotificationRuleNotifyOnResolve: base.NotifyOnResolve,
 		})
 
 		var tagRes []Resource
@@ -947,6 +985,10 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 	switch t := iRule.(type) {
 	case *rule.HTTP:
 		assignBase(t.Base)
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleBodyTemplate: t.BodyTemplate})
+		if len(t.Headers) > 0 {
+			o.Spec[fieldNotificationRuleHeaders] = t.Headers
+		}
 	case *rule.PagerDuty:
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
@@ -954,6 +996,21 @@ func NotificationRuleToObject(name, endpointPkgName string, iRule influxdb.Notif
 		assignBase(t.Base)
 		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.Opsgenie:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		if len(t.Tags) > 0 {
+			o.Spec[fieldNotificationRuleTags] = t.Tags
+		}
+	case *rule.SMTP:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleTo] = t.To
+		o.Spec[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleBodyTemplate: t.BodyTemplate})
+	case *rule.Teams:
+		assignBase(t.Base)
+		o.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(o.Spec, map[string]string{fieldNotificationRuleTitleTemplate: t.TitleTemplate})
 	}
 
 	return o
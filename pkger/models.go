@@ -22,8 +22,11 @@ const (
 	KindLabel                         Kind = "Label"
 	KindNotificationEndpoint          Kind = "NotificationEndpoint"
 	KindNotificationEndpointHTTP      Kind = "NotificationEndpointHTTP"
+	KindNotificationEndpointOpsgenie  Kind = "NotificationEndpointOpsgenie"
 	KindNotificationEndpointPagerDuty Kind = "NotificationEndpointPagerDuty"
+	KindNotificationEndpointSMTP      Kind = "NotificationEndpointSMTP"
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
+	KindNotificationEndpointTeams     Kind = "NotificationEndpointTeams"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
 	KindTask                          Kind = "Task"
@@ -49,8 +52,11 @@ var kinds = map[Kind]bool{
 	KindLabel:                         true,
 	KindNotificationEndpoint:          true,
 	KindNotificationEndpointHTTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
 	KindNotificationEndpointPagerDuty: true,
+	KindNotificationEndpointSMTP:      true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointTeams:     true,
 	KindNotificationRule:              true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
@@ -95,8 +101,11 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 		return influxdb.LabelsResourceType
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams:
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
@@ -495,8 +495,11 @@ func (p *Template) Contains(k Kind, pkgName string) bool {
 		return ok
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams:
 		_, ok := p.mNotificationEndpoints[pkgName]
 		return ok
 	case KindNotificationRule:
@@ -989,6 +992,18 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointOpsgenie,
+			notificationKind: notificationKindOpsgenie,
+		},
+		{
+			kind:             KindNotificationEndpointSMTP,
+			notificationKind: notificationKindSMTP,
+		},
+		{
+			kind:             KindNotificationEndpointTeams,
+			notificationKind: notificationKindTeams,
+		},
 	}
 
 	var pErr parseErr
@@ -1002,12 +1017,17 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			endpoint := &notificationEndpoint{
 				kind:        nk.notificationKind,
 				identity:    ident,
+				apiKey:      o.Spec.references(fieldNotificationEndpointAPIKey),
 				description: o.Spec.stringShort(fieldDescription),
+				from:        o.Spec.stringShort(fieldNotificationEndpointFrom),
+				host:        o.Spec.stringShort(fieldNotificationEndpointHost),
 				method:      strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationEndpointHTTPMethod))),
 				httpType:    normStr(o.Spec.stringShort(fieldType)),
 				password:    o.Spec.references(fieldNotificationEndpointPassword),
+				port:        o.Spec.intShort(fieldNotificationEndpointPort),
 				routingKey:  o.Spec.references(fieldNotificationEndpointRoutingKey),
 				status:      normStr(o.Spec.stringShort(fieldStatus)),
+				tls:         o.Spec.boolShort(fieldNotificationEndpointTLS),
 				token:       o.Spec.references(fieldNotificationEndpointToken),
 				url:         o.Spec.stringShort(fieldNotificationEndpointURL),
 				username:    o.Spec.references(fieldNotificationEndpointUsername),
@@ -1022,6 +1042,7 @@ func (p *Template) graphNotificationEndpoints() *parseErr {
 			p.setRefs(
 				endpoint.name,
 				endpoint.displayName,
+				endpoint.apiKey,
 				endpoint.password,
 				endpoint.routingKey,
 				endpoint.token,
@@ -1051,14 +1072,23 @@ func (p *Template) graphNotificationRules() *parseErr {
 		}
 
 		rule := &notificationRule{
-			identity:     ident,
-			endpointName: p.getRefWithKnownEnvs(o.Spec, fieldNotificationRuleEndpointName),
-			description:  o.Spec.stringShort(fieldDescription),
-			channel:      o.Spec.stringShort(fieldNotificationRuleChannel),
-			every:        o.Spec.durationShort(fieldEvery),
-			msgTemplate:  o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
-			offset:       o.Spec.durationShort(fieldOffset),
-			status:       normStr(o.Spec.stringShort(fieldStatus)),
+			identity:        ident,
+			endpointName:    p.getRefWithKnownEnvs(o.Spec, fieldNotificationRuleEndpointName),
+			bodyTemplate:    o.Spec.stringShort(fieldNotificationRuleBodyTemplate),
+			description:     o.Spec.stringShort(fieldDescription),
+			channel:         o.Spec.stringShort(fieldNotificationRuleChannel),
+			every:           o.Spec.durationShort(fieldEvery),
+			groupBy:         o.Spec.slcStr(fieldNotificationRuleGroupBy),
+			headers:         o.Spec.mapStrStr(fieldNotificationRuleHeaders),
+			msgTemplate:     o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
+			notifyOnResolve: o.Spec.boolShort(fieldNotificationRuleNotifyOnResolve),
+			offset:          o.Spec.durationShort(fieldOffset),
+			repeatInterval:  o.Spec.durationShort(fieldNotificationRuleRepeatInterval),
+			status:          normStr(o.Spec.stringShort(fieldStatus)),
+			subjectTemplate: o.Spec.stringShort(fieldNotificationRuleSubjectTemplate),
+			tags:            o.Spec.slcStr(fieldNotificationRuleTags),
+			titleTemplate:   o.Spec.stringShort(fieldNotificationRuleTitleTemplate),
+			to:              o.Spec.stringShort(fieldNotificationRuleTo),
 		}
 
 		for _, sRule := range o.Spec.slcResource(fieldNotificationRuleStatusRules) {
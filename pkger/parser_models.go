@@ -2,6 +2,7 @@ package pkger
 
 import (
 	"fmt"
+	"net/mail"
 	"net/url"
 	"regexp"
 	"sort"
@@ -1233,14 +1234,20 @@ const (
 	notificationKindHTTP notificationEndpointKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindOpsgenie
+	notificationKindSMTP
+	notificationKindTeams
 )
 
 func (n notificationEndpointKind) String() string {
-	if n > 0 && n < 4 {
+	if n > 0 && n < 7 {
 		return [...]string{
 			endpoint.HTTPType,
 			endpoint.PagerDutyType,
 			endpoint.SlackType,
+			endpoint.OpsgenieType,
+			endpoint.SMTPType,
+			endpoint.TeamsType,
 		}[n-1]
 	}
 	return ""
@@ -1253,9 +1260,14 @@ const (
 )
 
 const (
+	fieldNotificationEndpointAPIKey     = "apiKey"
+	fieldNotificationEndpointFrom       = "from"
+	fieldNotificationEndpointHost       = "host"
 	fieldNotificationEndpointHTTPMethod = "method"
 	fieldNotificationEndpointPassword   = "password"
+	fieldNotificationEndpointPort       = "port"
 	fieldNotificationEndpointRoutingKey = "routingKey"
+	fieldNotificationEndpointTLS        = "tls"
 	fieldNotificationEndpointToken      = "token"
 	fieldNotificationEndpointURL        = "url"
 	fieldNotificationEndpointUsername   = "username"
@@ -1265,11 +1277,16 @@ type notificationEndpoint struct {
 	identity
 
 	kind        notificationEndpointKind
+	apiKey      *references
 	description string
+	from        string
+	host        string
 	method      string
 	password    *references
+	port        int
 	routingKey  *references
 	status      string
+	tls         bool
 	token       *references
 	httpType    string
 	url         string
@@ -1338,6 +1355,30 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindOpsgenie:
+		sum.Kind = KindNotificationEndpointOpsgenie
+		sum.NotificationEndpoint = &endpoint.Opsgenie{
+			Base:   base,
+			URL:    n.url,
+			APIKey: n.apiKey.SecretField(),
+		}
+	case notificationKindSMTP:
+		sum.Kind = KindNotificationEndpointSMTP
+		sum.NotificationEndpoint = &endpoint.SMTP{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.port,
+			TLS:      n.tls,
+			From:     n.from,
+			Username: n.username.SecretField(),
+			Password: n.password.SecretField(),
+		}
+	case notificationKindTeams:
+		sum.Kind = KindNotificationEndpointTeams
+		sum.NotificationEndpoint = &endpoint.Teams{
+			Base: base,
+			URL:  n.url,
+		}
 	}
 	return sum
 }
@@ -1366,7 +1407,9 @@ func (n *notificationEndpoint) valid() []validationErr {
 		failures = append(failures, err)
 	}
 
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
+	// smtp endpoints have a host rather than a url and opsgenie defaults its url.
+	urlRequired := n.kind != notificationKindSMTP && n.kind != notificationKindOpsgenie
+	if _, err := url.Parse(n.url); err != nil || (urlRequired && n.url == "") {
 		failures = append(failures, validationErr{
 			Field: fieldNotificationEndpointURL,
 			Msg:   "must be valid url",
@@ -1382,6 +1425,38 @@ func (n *notificationEndpoint) valid() []validationErr {
 	}
 
 	switch n.kind {
+	case notificationKindOpsgenie:
+		if !n.apiKey.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointAPIKey,
+				Msg:   "must be provided",
+			})
+		}
+	case notificationKindSMTP:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must be provided",
+			})
+		}
+		if n.port < 0 || n.port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   "must be a valid port",
+			})
+		}
+		if _, err := mail.ParseAddress(n.from); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must be a valid email address",
+			})
+		}
+		if n.username.hasValue() != n.password.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPassword,
+				Msg:   "username and password must be provided together",
+			})
+		}
 	case notificationKindPagerDuty:
 		if !n.routingKey.hasValue() {
 			failures = append(failures, validationErr{
@@ -1443,26 +1518,44 @@ func (n *notificationEndpoint) valid() []validationErr {
 }
 
 const (
+	fieldNotificationRuleBodyTemplate    = "bodyTemplate"
 	fieldNotificationRuleChannel         = "channel"
 	fieldNotificationRuleCurrentLevel    = "currentLevel"
 	fieldNotificationRuleEndpointName    = "endpointName"
+	fieldNotificationRuleGroupBy         = "groupBy"
+	fieldNotificationRuleHeaders         = "headers"
 	fieldNotificationRuleMessageTemplate = "messageTemplate"
+	fieldNotificationRuleNotifyOnResolve = "notifyOnResolve"
 	fieldNotificationRulePreviousLevel   = "previousLevel"
+	fieldNotificationRuleRepeatInterval  = "repeatInterval"
 	fieldNotificationRuleStatusRules     = "statusRules"
+	fieldNotificationRuleSubjectTemplate = "subjectTemplate"
 	fieldNotificationRuleTagRules        = "tagRules"
+	fieldNotificationRuleTags            = "tags"
+	fieldNotificationRuleTitleTemplate   = "titleTemplate"
+	fieldNotificationRuleTo              = "to"
 )
 
 type notificationRule struct {
 	identity
 
-	channel     string
-	description string
-	every       time.Duration
-	msgTemplate string
-	offset      time.Duration
-	status      string
-	statusRules []struct{ curLvl, prevLvl string }
-	tagRules    []struct{ k, v, op string }
+	bodyTemplate    string
+	channel         string
+	description     string
+	every           time.Duration
+	groupBy         []string
+	headers         map[string]string
+	msgTemplate     string
+	notifyOnResolve bool
+	offset          time.Duration
+	repeatInterval  time.Duration
+	status          string
+	statusRules     []struct{ curLvl, prevLvl string }
+	subjectTemplate string
+	tagRules        []struct{ k, v, op string }
+	tags            []string
+	titleTemplate   string
+	to              string
 
 	associatedEndpoint *notificationEndpoint
 	endpointName       *references
@@ -1526,10 +1619,15 @@ func (r *notificationRule) summarize() SummaryNotificationRule {
 
 func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 	base := rule.Base{
-		Name:        r.Name(),
-		Description: r.description,
-		Every:       toNotificationDuration(r.every),
-		Offset:      toNotificationDuration(r.offset),
+		Name:            r.Name(),
+		Description:     r.description,
+		Every:           toNotificationDuration(r.every),
+		Offset:          toNotificationDuration(r.offset),
+		GroupBy:         r.groupBy,
+		NotifyOnResolve: r.notifyOnResolve,
+	}
+	if r.repeatInterval > 0 {
+		base.RepeatInterval = toNotificationDuration(r.repeatInterval)
 	}
 	for _, sr := range r.statusRules {
 		var prevLvl *notification.CheckLevel
@@ -1554,7 +1652,11 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 
 	switch r.associatedEndpoint.kind {
 	case notificationKindHTTP:
-		return &rule.HTTP{Base: base}
+		return &rule.HTTP{
+			Base:         base,
+			BodyTemplate: r.bodyTemplate,
+			Headers:      r.headers,
+		}
 	case notificationKindPagerDuty:
 		return &rule.PagerDuty{
 			Base:            base,
@@ -1566,6 +1668,25 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case notificationKindOpsgenie:
+		return &rule.Opsgenie{
+			Base:            base,
+			MessageTemplate: r.msgTemplate,
+			Tags:            r.tags,
+		}
+	case notificationKindSMTP:
+		return &rule.SMTP{
+			Base:            base,
+			To:              r.to,
+			SubjectTemplate: r.subjectTemplate,
+			BodyTemplate:    r.bodyTemplate,
+		}
+	case notificationKindTeams:
+		return &rule.Teams{
+			Base:            base,
+			TitleTemplate:   r.titleTemplate,
+			MessageTemplate: r.msgTemplate,
+		}
 	}
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/v2/notification"
 	icheck "github.com/influxdata/influxdb/v2/notification/check"
 	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/notification/rule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -2580,6 +2581,96 @@ spec:
 			})
 		})
 
+		t.Run("with email, opsgenie and teams endpoints should be successful", func(t *testing.T) {
+			templateStr := `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointOpsgenie
+metadata:
+  name: opsgenie-notification-endpoint
+spec:
+  description: opsgenie desc
+  apiKey:
+    secretRef:
+      key: opsgenie-key
+---
+apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSMTP
+metadata:
+  name: smtp-notification-endpoint
+spec:
+  host: smtp.example.com
+  port: 465
+  tls: true
+  from: alerts@example.com
+  username: smtp user
+  password: smtp pass
+---
+apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointTeams
+metadata:
+  name: teams-notification-endpoint
+spec:
+  url: https://outlook.office.com/webhook/abc
+`
+			template := newParsedTemplate(t, FromString(templateStr), EncodingYAML)
+
+			expected := []SummaryNotificationEndpoint{
+				{
+					SummaryIdentifier: SummaryIdentifier{
+						Kind:     KindNotificationEndpointOpsgenie,
+						MetaName: "opsgenie-notification-endpoint",
+					},
+					NotificationEndpoint: &endpoint.Opsgenie{
+						Base: endpoint.Base{
+							Name:        "opsgenie-notification-endpoint",
+							Description: "opsgenie desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						APIKey: influxdb.SecretField{Key: "opsgenie-key"},
+					},
+				},
+				{
+					SummaryIdentifier: SummaryIdentifier{
+						Kind:     KindNotificationEndpointSMTP,
+						MetaName: "smtp-notification-endpoint",
+					},
+					NotificationEndpoint: &endpoint.SMTP{
+						Base: endpoint.Base{
+							Name:   "smtp-notification-endpoint",
+							Status: influxdb.TaskStatusActive,
+						},
+						Host:     "smtp.example.com",
+						Port:     465,
+						TLS:      true,
+						From:     "alerts@example.com",
+						Username: influxdb.SecretField{Value: strPtr("smtp user")},
+						Password: influxdb.SecretField{Value: strPtr("smtp pass")},
+					},
+				},
+				{
+					SummaryIdentifier: SummaryIdentifier{
+						Kind:     KindNotificationEndpointTeams,
+						MetaName: "teams-notification-endpoint",
+					},
+					NotificationEndpoint: &endpoint.Teams{
+						Base: endpoint.Base{
+							Name:   "teams-notification-endpoint",
+							Status: influxdb.TaskStatusActive,
+						},
+						URL: "https://outlook.office.com/webhook/abc",
+					},
+				},
+			}
+
+			endpoints := template.Summary().NotificationEndpoints
+			require.Len(t, endpoints, len(expected))
+			for i := range expected {
+				exp, actual := expected[i], endpoints[i]
+				assert.Equalf(t, exp.Kind, actual.Kind, "index=%d", i)
+				assert.Equalf(t, exp.MetaName, actual.MetaName, "index=%d", i)
+				assert.Equalf(t, exp.NotificationEndpoint, actual.NotificationEndpoint, "index=%d", i)
+			}
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []struct {
 				kind   Kind
@@ -2610,6 +2701,65 @@ kind: NotificationEndpointPagerDuty
 metadata:
   name: pager-duty-notification-endpoint
 spec:
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointOpsgenie,
+					resErr: testTemplateResourceError{
+						name:           "missing opsgenie api key",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointAPIKey},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointOpsgenie
+metadata:
+  name: opsgenie-notification-endpoint
+spec:
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testTemplateResourceError{
+						name:           "missing smtp host",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointHost},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSMTP
+metadata:
+  name: smtp-notification-endpoint
+spec:
+  from: alerts@example.com
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testTemplateResourceError{
+						name:           "invalid smtp from address",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointFrom},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSMTP
+metadata:
+  name: smtp-notification-endpoint
+spec:
+  host: smtp.example.com
+  from: not an address
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointTeams,
+					resErr: testTemplateResourceError{
+						name:           "missing teams url",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldNotificationEndpointURL},
+						templateStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointTeams
+metadata:
+  name: teams-notification-endpoint
+spec:
 `,
 					},
 				},
@@ -2910,6 +3060,76 @@ spec:
 			})
 		})
 
+		t.Run("http rule with body template and headers", func(t *testing.T) {
+			templateStr := `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointHTTP
+metadata:
+  name: endpoint-0
+spec:
+  type: none
+  method: POST
+  url: https://www.example.com/endpoint/noneauth
+---
+apiVersion: influxdata.com/v2alpha1
+kind: NotificationRule
+metadata:
+  name: rule-0
+spec:
+  endpointName: endpoint-0
+  every: 10m
+  bodyTemplate: '{"text": "{{ r._check_name }} is {{ r._level }}"}'
+  headers:
+    X-Source: influxdb
+  statusRules:
+    - currentLevel: CRIT
+`
+			template := newParsedTemplate(t, FromString(templateStr), EncodingYAML)
+
+			rules := template.notificationRules()
+			require.Len(t, rules, 1)
+
+			actual, ok := rules[0].toInfluxRule().(*rule.HTTP)
+			require.True(t, ok)
+			assert.Equal(t, `{"text": "{{ r._check_name }} is {{ r._level }}"}`, actual.BodyTemplate)
+			assert.Equal(t, map[string]string{"X-Source": "influxdb"}, actual.Headers)
+		})
+
+		t.Run("rule with deduplication", func(t *testing.T) {
+			templateStr := `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSlack
+metadata:
+  name: endpoint-0
+spec:
+  url: https://hooks.slack.com/services/bip/piddy/boppidy
+---
+apiVersion: influxdata.com/v2alpha1
+kind: NotificationRule
+metadata:
+  name: rule-0
+spec:
+  endpointName: endpoint-0
+  every: 10m
+  messageTemplate: "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }"
+  groupBy:
+    - host
+  repeatInterval: 4h
+  notifyOnResolve: true
+  statusRules:
+    - currentLevel: CRIT
+`
+			template := newParsedTemplate(t, FromString(templateStr), EncodingYAML)
+
+			rules := template.notificationRules()
+			require.Len(t, rules, 1)
+
+			actual, ok := rules[0].toInfluxRule().(*rule.Slack)
+			require.True(t, ok)
+			assert.Equal(t, []string{"host"}, actual.GroupBy)
+			require.NotNil(t, actual.RepeatInterval)
+			assert.Equal(t, 4*time.Hour, actual.RepeatInterval.TimeDuration())
+			assert.True(t, actual.NotifyOnResolve)
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			templateWithValidEndpint := func(resource string) string {
 				return fmt.Sprintf(`
@@ -1423,8 +1423,11 @@ func ApplyWithResourceSkip(action ActionSkipResource) ApplyOptFn {
 		case KindCheckDeadman, KindCheckThreshold:
 			action.Kind = KindCheck
 		case KindNotificationEndpointHTTP,
+			KindNotificationEndpointOpsgenie,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSMTP,
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointTeams:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.ResourcesToSkip[action] = true
@@ -1441,8 +1444,11 @@ func ApplyWithKindSkip(action ActionSkipKind) ApplyOptFn {
 		case KindCheckDeadman, KindCheckThreshold:
 			action.Kind = KindCheck
 		case KindNotificationEndpointHTTP,
+			KindNotificationEndpointOpsgenie,
 			KindNotificationEndpointPagerDuty,
-			KindNotificationEndpointSlack:
+			KindNotificationEndpointSMTP,
+			KindNotificationEndpointSlack,
+			KindNotificationEndpointTeams:
 			action.Kind = KindNotificationEndpoint
 		}
 		opt.KindsToSkip[action.Kind] = true
@@ -2355,6 +2361,12 @@ func (s *Service) rollbackNotificationRules(ctx context.Context, userID influxdb
 				rr.EndpointID = endpointID
 			case *rule.Slack:
 				rr.EndpointID = endpointID
+			case *rule.Opsgenie:
+				rr.EndpointID = endpointID
+			case *rule.SMTP:
+				rr.EndpointID = endpointID
+			case *rule.Teams:
+				rr.EndpointID = endpointID
 			}
 			return r.existing
 		}
@@ -519,8 +519,11 @@ func (s *stateCoordinator) get(k Kind, metaName string) (interface{}, bool) {
 		return v, ok
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams:
 		v, ok := s.mEndpoints[metaName]
 		return v, ok
 	case KindNotificationRule:
@@ -602,8 +605,11 @@ func (s *stateCoordinator) addObjectForRemoval(k Kind, metaName string, id influ
 		}
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams:
 		s.mEndpoints[metaName] = &stateEndpoint{
 			id:             id,
 			parserEndpoint: &notificationEndpoint{identity: newIdentity},
@@ -664,8 +670,11 @@ func (s *stateCoordinator) getObjectIDSetter(k Kind, metaName string) (func(infl
 		}, ok
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointTeams:
 		r, ok := s.mEndpoints[metaName]
 		return func(id influxdb.ID) {
 			r.id = id
@@ -1265,6 +1274,14 @@ func (r *stateRule) diffRule() DiffNotificationRule {
 	case *rule.PagerDuty:
 		assignBase(p.Base)
 		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.Opsgenie:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
+	case *rule.SMTP:
+		assignBase(p.Base)
+	case *rule.Teams:
+		assignBase(p.Base)
+		sum.Old.MessageTemplate = p.MessageTemplate
 	}
 
 	return sum
@@ -1334,6 +1351,12 @@ func (r *stateRule) toInfluxRule() influxdb.NotificationRule {
 		e.EndpointID = r.associatedEndpoint.ID()
 	case *rule.Slack:
 		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Opsgenie:
+		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.SMTP:
+		e.EndpointID = r.associatedEndpoint.ID()
+	case *rule.Teams:
+		e.EndpointID = r.associatedEndpoint.ID()
 	}
 
 	return influxRule
@@ -1,8 +1,11 @@
 package predicate
 
 import (
+	"bufio"
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxql"
@@ -19,6 +22,7 @@ type buffer [3]struct {
 // such a statement `(a = "a" or b!="b") and c ! =~/efg/`
 // to the predicate node
 type parser struct {
+	r         *bufio.Reader
 	sc        *influxql.Scanner
 	i         int // buffer index
 	n         int // buffer size
@@ -26,6 +30,16 @@ type parser struct {
 	buf       buffer
 }
 
+func newParser(sts string) *parser {
+	// The scanner reads from r directly as r is already buffered, which
+	// allows skipping the whitespace preceding a regex in scanRegex.
+	r := bufio.NewReader(strings.NewReader(sts))
+	return &parser{
+		r:  r,
+		sc: influxql.NewScanner(r),
+	}
+}
+
 // scan returns the next token from the underlying scanner.
 // If a token has been unscanned then read that instead.
 func (p *parser) scan() (tok influxql.Token, pos influxql.Pos, lit string) {
@@ -62,94 +76,107 @@ func (p *parser) scanIgnoreWhitespace() (tok influxql.Token, pos influxql.Pos, l
 	return
 }
 
-// Parse the predicate statement.
+// Parse the predicate statement. AND takes precedence over OR, and
+// parentheses group expressions.
 func Parse(sts string) (n Node, err error) {
 	if sts == "" {
 		return nil, nil
 	}
-	p := new(parser)
-	p.sc = influxql.NewScanner(strings.NewReader(sts))
-	return p.parseLogicalNode()
+	p := newParser(sts)
+	n, err = p.parseOrNode()
+	if err != nil {
+		return n, err
+	}
+	switch tok, pos, _ := p.scanIgnoreWhitespace(); tok {
+	case influxql.EOF:
+		return n, nil
+	case influxql.RPAREN:
+		return n, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "extra ) seen",
+		}
+	default:
+		return n, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+		}
+	}
 }
 
-func (p *parser) parseLogicalNode() (Node, error) {
-	n := new(LogicalNode)
-	for {
-		tok, pos, _ := p.scanIgnoreWhitespace()
-		switch tok {
-		case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
-			p.unscan()
-			tr, err := p.parseTagRuleNode()
-			if err != nil {
-				return *n, err
-			}
-			if n.Children[0] == nil {
-				n.Children[0] = tr
-			} else {
-				n.Children[1] = tr
-			}
-		case influxql.AND:
-			n.Operator = LogicalAnd
-			if n.Children[1] == nil {
-				continue
-			}
-			var n1 Node
-			var err error
-			if tokNext := p.peekTok(); tokNext == influxql.LPAREN {
-				n1, err = p.parseLogicalNode()
-			} else {
-				n1, err = p.parseTagRuleNode()
-			}
-			if err != nil {
-				return *n, err
-			}
-			n = &LogicalNode{
-				Children: [2]Node{*n, n1},
-				Operator: LogicalAnd,
-			}
-		case influxql.OR:
-			return *n, &influxdb.Error{
+// parseOrNode parses the expressions joined by OR.
+func (p *parser) parseOrNode() (Node, error) {
+	n, err := p.parseAndNode()
+	if err != nil {
+		return n, err
+	}
+	for p.peekTok() == influxql.OR {
+		p.scanIgnoreWhitespace()
+		n1, err := p.parseAndNode()
+		if err != nil {
+			return n, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalOr,
+		}
+	}
+	return n, nil
+}
+
+// parseAndNode parses the expressions joined by AND.
+func (p *parser) parseAndNode() (Node, error) {
+	n, err := p.parsePrimaryNode()
+	if err != nil {
+		return n, err
+	}
+	for p.peekTok() == influxql.AND {
+		p.scanIgnoreWhitespace()
+		n1, err := p.parsePrimaryNode()
+		if err != nil {
+			return n, err
+		}
+		n = LogicalNode{
+			Children: [2]Node{n, n1},
+			Operator: LogicalAnd,
+		}
+	}
+	return n, nil
+}
+
+// parsePrimaryNode parses a tag rule or a parenthesized expression.
+func (p *parser) parsePrimaryNode() (Node, error) {
+	tok, pos, _ := p.scanIgnoreWhitespace()
+	switch tok {
+	case influxql.NUMBER, influxql.INTEGER, influxql.NAME, influxql.IDENT:
+		p.unscan()
+		return p.parseTagRuleNode()
+	case influxql.LPAREN:
+		p.openParen++
+		n, err := p.parseOrNode()
+		if err != nil {
+			return n, err
+		}
+		if tok, _, _ := p.scanIgnoreWhitespace(); tok != influxql.RPAREN {
+			return n, &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  fmt.Sprintf("the logical operator OR is not supported yet at position %d", pos.Char),
-			}
-		case influxql.LPAREN:
-			p.openParen++
-			currParen := p.openParen
-			n1, err := p.parseLogicalNode()
-			if err != nil {
-				return *n, err
-			}
-			if p.openParen != currParen-1 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  "extra ( seen",
-				}
+				Msg:  "extra ( seen",
 			}
-			if n.Children[0] == nil {
-				n.Children[0] = n1
-			} else {
-				n.Children[1] = n1
-			}
-		case influxql.RPAREN:
-			p.openParen--
-			fallthrough
-		case influxql.EOF:
-			if p.openParen < 0 {
-				return *n, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  "extra ) seen",
-				}
-			}
-			if n.Children[1] == nil {
-				return n.Children[0], nil
-			}
-			return *n, nil
-		default:
-			return *n, &influxdb.Error{
+		}
+		p.openParen--
+		return n, nil
+	case influxql.EOF:
+		if p.openParen > 0 {
+			return nil, &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+				Msg:  "extra ( seen",
 			}
 		}
+		fallthrough
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad logical expression, at position %d", pos.Char),
+		}
 	}
 }
 
@@ -178,12 +205,11 @@ func (p *parser) parseTagRuleNode() (TagRuleNode, error) {
 		n.Operator = influxdb.NotEqual
 		goto scanRegularTagValue
 	case influxql.EQREGEX:
-		fallthrough
+		n.Operator = influxdb.RegexEqual
+		return p.parseRegexValue(*n)
 	case influxql.NEQREGEX:
-		return *n, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Msg:  fmt.Sprintf("operator: %q at position: %d is not supported yet", tok.String(), pos.Char),
-		}
+		n.Operator = influxdb.NotRegexEqual
+		return p.parseRegexValue(*n)
 	default:
 		return *n, &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -220,6 +246,41 @@ scanRegularTagValue:
 	}
 }
 
+// parseRegexValue scans the regex literal of a regex tag rule.
+func (p *parser) parseRegexValue(n TagRuleNode) (TagRuleNode, error) {
+	tok, pos, lit := p.scanRegex()
+	if tok != influxql.REGEX {
+		return n, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad regex, at position %d", pos.Char),
+		}
+	}
+	if _, err := regexp.Compile(lit); err != nil {
+		return n, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("bad regex /%s/, at position %d: %v", lit, pos.Char, err),
+		}
+	}
+	n.Value = lit
+	return n, nil
+}
+
+// scanRegex scans a regex literal. It must directly follow the scan of the
+// regex operator, when the scanner holds no runes past the operator.
+func (p *parser) scanRegex() (tok influxql.Token, pos influxql.Pos, lit string) {
+	for {
+		ch, _, err := p.r.ReadRune()
+		if err != nil {
+			break
+		}
+		if !unicode.IsSpace(ch) {
+			_ = p.r.UnreadRune()
+			break
+		}
+	}
+	return p.sc.ScanRegex()
+}
+
 // peekRune returns the next rune that would be read by the scanner.
 func (p *parser) peekTok() influxql.Token {
 	tok, _, _ := p.scanIgnoreWhitespace()
@@ -1,13 +1,11 @@
 package predicate
 
 import (
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb/v2"
 	influxtesting "github.com/influxdata/influxdb/v2/testing"
-	"github.com/influxdata/influxql"
 )
 
 func TestParseNode(t *testing.T) {
@@ -39,9 +37,42 @@ func TestParseNode(t *testing.T) {
 		},
 		{
 			str: ` abc="opq" Or gender="male" OR temp=1123`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "opq"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "gender", Value: "male"}},
+				}},
+				TagRuleNode{Tag: influxdb.Tag{Key: "temp", Value: "1123"}},
+			}},
+		},
+		{
+			str: `_measurement="cpu" and host="a" or host="b" and _field="usage"`,
+			node: LogicalNode{Operator: LogicalOr, Children: [2]Node{
+				LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "_measurement", Value: "cpu"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: "a"}},
+				}},
+				LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: "b"}},
+					TagRuleNode{Tag: influxdb.Tag{Key: "_field", Value: "usage"}},
+				}},
+			}},
+		},
+		{
+			str: `_measurement="cpu" and (host=~/^web/ or host!~ /\.local$/)`,
+			node: LogicalNode{Operator: LogicalAnd, Children: [2]Node{
+				TagRuleNode{Tag: influxdb.Tag{Key: "_measurement", Value: "cpu"}},
+				LogicalNode{Operator: LogicalOr, Children: [2]Node{
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.RegexEqual},
+					TagRuleNode{Tag: influxdb.Tag{Key: "host", Value: `\.local$`}, Operator: influxdb.NotRegexEqual},
+				}},
+			}},
+		},
+		{
+			str: `abc="opq" or`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  "the logical operator OR is not supported yet at position 11",
+				Msg:  "bad logical expression, at position 13",
 			},
 		},
 		{
@@ -132,17 +163,25 @@ func TestParseTagRule(t *testing.T) {
 			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: "false"}, Operator: influxdb.Equal},
 		},
 		{
-			str: `abc!~/^payments\./`,
+			str:  `abc!~/^payments\./`,
+			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: `^payments\.`}, Operator: influxdb.NotRegexEqual},
+		},
+		{
+			str:  `abc =~   /^payments\./`,
+			node: TagRuleNode{Tag: influxdb.Tag{Key: "abc", Value: `^payments\.`}, Operator: influxdb.RegexEqual},
+		},
+		{
+			str: `abc=~"payments"`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "!~" at position: 3 is not supported yet`,
+				Msg:  `bad regex, at position 4`,
 			},
 		},
 		{
-			str: `abc=~/^payments\./`,
+			str: `abc=~/payments(/`,
 			err: &influxdb.Error{
 				Code: influxdb.EInvalid,
-				Msg:  `operator: "=~" at position: 3 is not supported yet`,
+				Msg:  "bad regex /payments(/, at position 4: error parsing regexp: missing closing ): `payments(`",
 			},
 		},
 		{
@@ -154,9 +193,7 @@ func TestParseTagRule(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		p := new(parser)
-		p.sc = influxql.NewScanner(strings.NewReader(c.str))
-		tr, err := p.parseTagRuleNode()
+		tr, err := newParser(c.str).parseTagRuleNode()
 		influxtesting.ErrorsEqual(t, err, c.err)
 		if c.err == nil {
 			if diff := cmp.Diff(tr, c.node); diff != "" {
@@ -13,6 +13,11 @@
 // Other goroutines and memory usage is at the will of the specific
 // resource strategy that the Controller is using.
 //
+// Queries are queued by their priority class (see query.Priority), each
+// of which may reserve some of the concurrency and memory of the
+// Controller. Within a class, queries are shared fairly between
+// organizations.
+//
 // The Controller also provides visibility into the lifetime of the query
 // and its current resource usage.
 package control
@@ -43,6 +48,9 @@ import (
 // orgLabel is the metric label to use in the controller
 const orgLabel = "org"
 
+// priorityLabel is the metric label of the priority class of a query.
+const priorityLabel = "priority"
+
 // Controller provides a central location to manage all incoming queries.
 // The controller is responsible for compiling, queueing, and executing queries.
 type Controller struct {
@@ -50,7 +58,7 @@ type Controller struct {
 	lastID     uint64
 	queriesMu  sync.RWMutex
 	queries    map[QueryID]*Query
-	queryQueue chan *Query
+	queryQueue *scheduler
 	wg         sync.WaitGroup
 	shutdown   bool
 	done       chan struct{}
@@ -90,9 +98,17 @@ type Config struct {
 	MaxMemoryBytes int64
 
 	// QueueSize is the number of queries that are allowed to be awaiting execution before new queries are
-	// rejected.
+	// rejected. Each priority class has a queue of this size unless its ClassConfig sets another size.
 	QueueSize int
-	Logger    *zap.Logger
+
+	// PriorityClasses configures the priority classes. The priority of a query is read
+	// off its context with query.PriorityFromContext.
+	//
+	// A class without a configuration reserves no resources; its queries run in the
+	// concurrency and memory that is not reserved by any class.
+	PriorityClasses map[query.Priority]ClassConfig
+
+	Logger *zap.Logger
 	// MetricLabelKeys is a list of labels to add to the metrics produced by the controller.
 	// The value for a given key will be read off the context.
 	// The context value must be a string or an implementation of the Stringer interface.
@@ -101,6 +117,23 @@ type Config struct {
 	ExecutorDependencies []flux.Dependency
 }
 
+// ClassConfig reserves resources of the controller for a priority class.
+type ClassConfig struct {
+	// ReservedConcurrency is the number of the ConcurrencyQuota slots that only
+	// execute queries of the class. Queries of the class also execute in the slots
+	// that are not reserved by any class once their reserved slots are in use.
+	ReservedConcurrency int
+
+	// ReservedMemoryBytes is the amount of MaxMemoryBytes that is only given to
+	// queries of the class. Queries of the class use their reserved memory before
+	// the memory that is not reserved by any class.
+	ReservedMemoryBytes int64
+
+	// QueueSize is the number of queries of the class that are allowed to be
+	// awaiting execution. If this is unset, then the Config.QueueSize will be used.
+	QueueSize int
+}
+
 // complete will fill in the defaults, validate the configuration, and
 // return the new Config.
 func (c *Config) complete() (Config, error) {
@@ -136,6 +169,55 @@ func (c *Config) validate(isComplete bool) error {
 	if c.QueueSize <= 0 {
 		return errors.New("QueueSize must be positive")
 	}
+	return c.validateClasses()
+}
+
+func (c *Config) validateClasses() error {
+	var (
+		concurrency int
+		memory      int64
+	)
+	for p, cc := range c.PriorityClasses {
+		if !p.Valid() {
+			return fmt.Errorf("unknown priority class %q", p)
+		}
+		if cc.ReservedConcurrency < 0 {
+			return fmt.Errorf("ReservedConcurrency of priority class %q must not be negative", p)
+		}
+		if cc.ReservedMemoryBytes < 0 {
+			return fmt.Errorf("ReservedMemoryBytes of priority class %q must not be negative", p)
+		}
+		if cc.QueueSize < 0 {
+			return fmt.Errorf("QueueSize of priority class %q must not be negative", p)
+		}
+		concurrency += cc.ReservedConcurrency
+		memory += cc.ReservedMemoryBytes
+	}
+
+	if concurrency > c.ConcurrencyQuota {
+		return fmt.Errorf("the ReservedConcurrency of the priority classes must not exceed the ConcurrencyQuota: %d > %d", concurrency, c.ConcurrencyQuota)
+	}
+	if concurrency == c.ConcurrencyQuota {
+		// No slot is left for the classes which do not reserve any.
+		for _, p := range query.Priorities {
+			if c.PriorityClasses[p].ReservedConcurrency == 0 {
+				return fmt.Errorf("priority class %q cannot execute queries since all of the ConcurrencyQuota is reserved by other classes", p)
+			}
+		}
+	}
+
+	if memory > 0 {
+		if c.MaxMemoryBytes == 0 {
+			return errors.New("MaxMemoryBytes must be set to reserve memory for priority classes")
+		}
+		initial := c.InitialMemoryBytesQuotaPerQuery
+		if initial == 0 {
+			initial = c.MemoryBytesQuotaPerQuery
+		}
+		if unused := c.MaxMemoryBytes - int64(c.ConcurrencyQuota)*initial; memory > unused {
+			return fmt.Errorf("the ReservedMemoryBytes of the priority classes must not exceed MaxMemoryBytes - ConcurrencyQuota * InitialMemoryBytesQuotaPerQuery: %d > %d", memory, unused)
+		}
+	}
 	return nil
 }
 
@@ -151,7 +233,7 @@ func New(config Config) (*Controller, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid controller config")
 	}
-	c.MetricLabelKeys = append(c.MetricLabelKeys, orgLabel)
+	c.MetricLabelKeys = append(c.MetricLabelKeys, orgLabel, priorityLabel)
 	logger := c.Logger
 	if logger == nil {
 		logger = zap.NewNop()
@@ -162,6 +244,15 @@ func New(config Config) (*Controller, error) {
 		zap.Int64("memory_bytes_quota_per_query", c.MemoryBytesQuotaPerQuery),
 		zap.Int64("max_memory_bytes", c.MaxMemoryBytes),
 		zap.Int("queue_size", c.QueueSize))
+	for _, p := range query.Priorities {
+		if cc, ok := c.PriorityClasses[p]; ok {
+			logger.Info("Reserving query resources for priority class",
+				zap.String("priority", string(p)),
+				zap.Int("reserved_concurrency", cc.ReservedConcurrency),
+				zap.Int64("reserved_memory_bytes", cc.ReservedMemoryBytes),
+				zap.Int("queue_size", cc.QueueSize))
+		}
+	}
 
 	mm := &memoryManager{
 		initialBytesQuotaPerQuery: c.InitialMemoryBytesQuotaPerQuery,
@@ -169,13 +260,23 @@ func New(config Config) (*Controller, error) {
 	}
 	if c.MaxMemoryBytes > 0 {
 		mm.unusedMemoryBytes = c.MaxMemoryBytes - (int64(c.ConcurrencyQuota) * c.InitialMemoryBytesQuotaPerQuery)
+		for p, cc := range c.PriorityClasses {
+			if cc.ReservedMemoryBytes > 0 {
+				if mm.reservedMemoryBytes == nil {
+					mm.reservedMemoryBytes = make(map[query.Priority]*int64)
+				}
+				reserved := cc.ReservedMemoryBytes
+				mm.reservedMemoryBytes[p] = &reserved
+				mm.unusedMemoryBytes -= reserved
+			}
+		}
 	} else {
 		mm.unlimited = true
 	}
 	ctrl := &Controller{
 		config:       c,
 		queries:      make(map[QueryID]*Query),
-		queryQueue:   make(chan *Query, c.QueueSize),
+		queryQueue:   newScheduler(c),
 		done:         make(chan struct{}),
 		abort:        make(chan struct{}),
 		memory:       mm,
@@ -203,6 +304,8 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
 	ctx = context.WithValue(ctx, orgLabel, req.OrganizationID.String()) //lint:ignore SA1029 this is a temporary ignore until we have time to create an appropriate type
+	// Set the priority label value for controller metrics
+	ctx = context.WithValue(ctx, priorityLabel, string(query.PriorityFromContext(ctx))) //lint:ignore SA1029 this is a temporary ignore until we have time to create an appropriate type
 	// The controller injects the dependencies for each incoming request.
 	for _, dep := range c.dependencies {
 		ctx = dep.Inject(ctx)
@@ -263,6 +366,8 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 	}
 	compileLabelValues[len(compileLabelValues)-1] = string(ct)
 
+	org, _ := ctx.Value(orgLabel).(string)
+
 	cctx, cancel := context.WithCancel(ctx)
 	parentSpan, parentCtx := tracing.StartSpanFromContextWithPromMetrics(
 		cctx,
@@ -272,6 +377,8 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 	)
 	q := &Query{
 		id:                 id,
+		priority:           query.PriorityFromContext(ctx),
+		org:                org,
 		labelValues:        labelValues,
 		compileLabelValues: compileLabelValues,
 		state:              Created,
@@ -363,9 +470,7 @@ func (c *Controller) enqueueQuery(q *Query) error {
 		}
 	}
 
-	select {
-	case c.queryQueue <- q:
-	default:
+	if !c.queryQueue.push(q) {
 		return &flux.Error{
 			Code: codes.ResourceExhausted,
 			Msg:  "queue length exceeded",
@@ -377,12 +482,12 @@ func (c *Controller) enqueueQuery(q *Query) error {
 
 func (c *Controller) processQueryQueue() {
 	for {
-		select {
-		case <-c.done:
+		q, ok := c.queryQueue.next(c.done)
+		if !ok {
 			return
-		case q := <-c.queryQueue:
-			c.executeQuery(q)
 		}
+		c.executeQuery(q)
+		c.queryQueue.release(q)
 	}
 }
 
@@ -521,6 +626,12 @@ func (c *Controller) GetUsedMemoryBytes() int64 {
 type Query struct {
 	id QueryID
 
+	// priority is the priority class of the query and org the
+	// organization it is charged to when the controller shares
+	// resources within the class.
+	priority query.Priority
+	org      string
+
 	labelValues        []string
 	compileLabelValues []string
 
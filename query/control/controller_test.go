@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
@@ -20,6 +21,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/query"
 	_ "github.com/influxdata/influxdb/v2/query/builtin"
 	"github.com/influxdata/influxdb/v2/query/control"
@@ -77,8 +79,9 @@ func validateRequestTotals(t testing.TB, reg *prometheus.Registry, success, comp
 			metrics,
 			"query_control_requests_total",
 			map[string]string{
-				"result": name,
-				"org":    "",
+				"result":   name,
+				"org":      "",
+				"priority": "system",
 			},
 		)
 		var got int
@@ -105,7 +108,8 @@ func validateUnusedMemory(t testing.TB, reg *prometheus.Registry, c control.Conf
 		metrics,
 		"query_control_memory_unused_bytes",
 		map[string]string{
-			"org": "",
+			"org":      "",
+			"priority": "system",
 		},
 	)
 	var got int64
@@ -820,6 +824,258 @@ func TestController_QueueSize(t *testing.T) {
 	}
 }
 
+func TestController_PriorityReservedConcurrency(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.QueueSize = 3
+	config.PriorityClasses = map[query.Priority]control.ClassConfig{
+		query.PriorityInteractive: {ReservedConcurrency: 1},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	// This channel blocks program execution until we are done
+	// with running the test.
+	done := make(chan struct{})
+	defer close(done)
+
+	executing := make(chan query.Priority, 4)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- query.PriorityFromContext(ctx)
+					// Block until test is finished
+					<-done
+				},
+			}, nil
+		},
+	}
+	start := func(p query.Priority) {
+		ctx := query.ContextWithPriority(context.Background(), p)
+		q, err := ctrl.Query(ctx, makeRequest(compiler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+	}
+
+	// The task queries may only use the slot which is not reserved.
+	for i := 0; i < 3; i++ {
+		start(query.PriorityTask)
+	}
+	if got := <-executing; got != query.PriorityTask {
+		t.Fatalf("unexpected priority executing: got %s want %s", got, query.PriorityTask)
+	}
+	select {
+	case got := <-executing:
+		t.Fatalf("unexpected %s query executing in the reserved slot", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The interactive query executes in its reserved slot.
+	start(query.PriorityInteractive)
+	select {
+	case got := <-executing:
+		if got != query.PriorityInteractive {
+			t.Fatalf("unexpected priority executing: got %s want %s", got, query.PriorityInteractive)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("interactive query did not execute in its reserved slot")
+	}
+}
+
+func TestController_PriorityFairShare(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 1
+	config.QueueSize = 3
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	orgA, orgB := platform.ID(1), platform.ID(2)
+	block := make(chan struct{})
+	executing := make(chan platform.ID, 4)
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					executing <- query.RequestFromContext(ctx).OrganizationID
+					<-block
+				},
+			}, nil
+		},
+	}
+	ctx := query.ContextWithPriority(context.Background(), query.PriorityTask)
+	start := func(orgID platform.ID) {
+		q, err := ctrl.Query(ctx, &query.Request{
+			OrganizationID: orgID,
+			Compiler:       compiler,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for range q.Results() {
+				// discard the results
+			}
+			q.Done()
+		}()
+	}
+
+	// Occupy the only slot with a query of org A and queue
+	// two more of org A before one of org B.
+	start(orgA)
+	<-executing
+	start(orgA)
+	start(orgA)
+	start(orgB)
+	close(block)
+
+	var got []platform.ID
+	for i := 0; i < 3; i++ {
+		select {
+		case id := <-executing:
+			got = append(got, id)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for queued queries")
+		}
+	}
+	if want := []platform.ID{orgB, orgA, orgA}; !cmp.Equal(want, got) {
+		t.Fatalf("unexpected execution order: -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestConfig_PriorityClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes map[query.Priority]control.ClassConfig
+		maxMem  int64
+		wantErr bool
+	}{
+		{
+			name: "reserved",
+			classes: map[query.Priority]control.ClassConfig{
+				query.PriorityInteractive: {ReservedConcurrency: 1, ReservedMemoryBytes: 1024, QueueSize: 5},
+				query.PriorityCheck:       {ReservedConcurrency: 1},
+			},
+			maxMem: 4096,
+		},
+		{
+			name: "unknown class",
+			classes: map[query.Priority]control.ClassConfig{
+				"batch": {ReservedConcurrency: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "too much concurrency",
+			classes: map[query.Priority]control.ClassConfig{
+				query.PriorityInteractive: {ReservedConcurrency: 2},
+				query.PriorityTask:        {ReservedConcurrency: 2},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no shared concurrency",
+			classes: map[query.Priority]control.ClassConfig{
+				query.PriorityInteractive: {ReservedConcurrency: 3},
+			},
+			wantErr: true,
+		},
+		{
+			name: "memory without max",
+			classes: map[query.Priority]control.ClassConfig{
+				query.PriorityInteractive: {ReservedMemoryBytes: 1024},
+			},
+			wantErr: true,
+		},
+		{
+			name: "too much memory",
+			classes: map[query.Priority]control.ClassConfig{
+				query.PriorityInteractive: {ReservedMemoryBytes: 2048},
+			},
+			maxMem:  4096,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := config
+			config.ConcurrencyQuota = 3
+			config.MaxMemoryBytes = tt.maxMem
+			config.PriorityClasses = tt.classes
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: got %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestController_PriorityReservedMemory(t *testing.T) {
+	config := config
+	config.ConcurrencyQuota = 2
+	config.MemoryBytesQuotaPerQuery = 1024
+	config.InitialMemoryBytesQuotaPerQuery = 256
+	config.MaxMemoryBytes = 1280
+	config.PriorityClasses = map[query.Priority]control.ClassConfig{
+		query.PriorityInteractive: {ReservedMemoryBytes: 512},
+	}
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := func(bytes int64) flux.Compiler {
+		return &mock.Compiler{
+			CompileFn: func(ctx context.Context) (flux.Program, error) {
+				return &mock.Program{
+					ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+						if err := alloc.Account(int(bytes)); err != nil {
+							q.SetErr(err)
+						}
+					},
+				}, nil
+			},
+		}
+	}
+	run := func(p query.Priority, bytes int64) error {
+		ctx := query.ContextWithPriority(context.Background(), p)
+		q, err := ctrl.Query(ctx, makeRequest(compiler(bytes)))
+		if err != nil {
+			return err
+		}
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+		return q.Err()
+	}
+
+	// Only 256 bytes beyond the initial memory are shared, so a task
+	// cannot use the memory reserved for interactive queries.
+	if err := run(query.PriorityTask, 768); err == nil {
+		t.Fatal("expected task query to exceed the shared memory")
+	}
+	if err := run(query.PriorityInteractive, 768); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := ctrl.GetUnusedMemoryBytes(), int64(768); got != want {
+		t.Fatalf("unexpected unused memory: got %d want %d", got, want)
+	}
+}
+
 // Test that rapidly starting and canceling the query and then calling done will correctly
 // cancel the query and not result in a race condition.
 func TestController_CancelDone(t *testing.T) {
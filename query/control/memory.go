@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/influxdb/v2/query"
 )
 
 type memoryManager struct {
@@ -23,29 +24,33 @@ type memoryManager struct {
 	// when unlimited is set to false.
 	unusedMemoryBytes int64
 
+	// reservedMemoryBytes is the unused memory reserved for each
+	// priority class. Queries of a class take memory from their
+	// reservation before unusedMemoryBytes.
+	reservedMemoryBytes map[query.Priority]*int64
+
 	// unlimited indicates that the memory manager should indicate
 	// there is an unlimited amount of free memory available.
 	unlimited bool
 }
 
+// getUnusedMemoryBytes returns the unused memory, including the
+// memory reserved for priority classes.
 func (m *memoryManager) getUnusedMemoryBytes() int64 {
-	return atomic.LoadInt64(&m.unusedMemoryBytes)
-}
-
-func (m *memoryManager) trySetUnusedMemoryBytes(old, new int64) bool {
-	return atomic.CompareAndSwapInt64(&m.unusedMemoryBytes, old, new)
-}
-
-func (m *memoryManager) addUnusedMemoryBytes(amount int64) int64 {
-	return atomic.AddInt64(&m.unusedMemoryBytes, amount)
+	unused := atomic.LoadInt64(&m.unusedMemoryBytes)
+	for _, reserved := range m.reservedMemoryBytes {
+		unused += atomic.LoadInt64(reserved)
+	}
+	return unused
 }
 
 // createAllocator will construct an allocator and memory manager
 // for the given query.
 func (c *Controller) createAllocator(q *Query) {
 	q.memoryManager = &queryMemoryManager{
-		m:     c.memory,
-		limit: c.memory.initialBytesQuotaPerQuery,
+		m:        c.memory,
+		reserved: c.memory.reservedMemoryBytes[q.priority],
+		limit:    c.memory.initialBytesQuotaPerQuery,
 	}
 	q.alloc = &memory.Allocator{
 		// Use an anonymous function to ensure the value is copied.
@@ -56,9 +61,15 @@ func (c *Controller) createAllocator(q *Query) {
 
 // queryMemoryManager is a memory manager for a specific query.
 type queryMemoryManager struct {
-	m     *memoryManager
-	limit int64
-	given int64
+	m *memoryManager
+	// reserved is the memory reserved for the priority class
+	// of the query, if any.
+	reserved *int64
+	limit    int64
+	given    int64
+	// givenReserved is the part of given that was taken from
+	// the reserved memory.
+	givenReserved int64
 }
 
 // RequestMemory will determine if the query can be given more memory
@@ -77,15 +88,37 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		return 0, errors.New("query hit hard limit")
 	}
 
+	if q.m.unlimited {
+		given := q.giveMemory(want, math.MaxInt64)
+		q.limit += given
+		q.given += given
+		return given, nil
+	}
+
+	// Prefer the memory reserved for the priority class so the
+	// memory shared by all classes is left to the others.
+	if q.reserved != nil {
+		if given, ok := q.take(q.reserved, want); ok {
+			q.givenReserved += given
+			return given, nil
+		}
+	}
+	if given, ok := q.take(&q.m.unusedMemoryBytes, want); ok {
+		return given, nil
+	}
+	// We do not have the capacity for this query to
+	// be given more memory.
+	return 0, errors.New("not enough capacity")
+}
+
+// take will reserve memory for the query out of the unused
+// bytes of pool. It returns false if the pool does not have
+// the wanted number of bytes.
+func (q *queryMemoryManager) take(pool *int64, want int64) (int64, bool) {
 	for {
-		unused := int64(math.MaxInt64)
-		if !q.m.unlimited {
-			unused = q.m.getUnusedMemoryBytes()
-			if unused < want {
-				// We do not have the capacity for this query to
-				// be given more memory.
-				return 0, errors.New("not enough capacity")
-			}
+		unused := atomic.LoadInt64(pool)
+		if unused < want {
+			return 0, false
 		}
 
 		// The memory allocator will only request the bare amount of
@@ -95,19 +128,17 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 		given := q.giveMemory(want, unused)
 
 		// Reserve this memory for our own use.
-		if !q.m.unlimited {
-			if !q.m.trySetUnusedMemoryBytes(unused, unused-given) {
-				// The unused value has changed so someone may have taken
-				// the memory that we wanted. Retry.
-				continue
-			}
+		if !atomic.CompareAndSwapInt64(pool, unused, unused-given) {
+			// The unused value has changed so someone may have taken
+			// the memory that we wanted. Retry.
+			continue
 		}
 
 		// Successfully reserved the memory so update our own internal
 		// counter for the limit.
 		q.limit += given
 		q.given += given
-		return given, nil
+		return given, true
 	}
 }
 
@@ -150,8 +181,12 @@ func (q *queryMemoryManager) FreeMemory(bytes int64) {
 // memory manager.
 func (q *queryMemoryManager) Release() {
 	if !q.m.unlimited {
-		q.m.addUnusedMemoryBytes(q.given)
+		if q.givenReserved > 0 {
+			atomic.AddInt64(q.reserved, q.givenReserved)
+		}
+		atomic.AddInt64(&q.m.unusedMemoryBytes, q.given-q.givenReserved)
 	}
 	q.limit = q.m.initialBytesQuotaPerQuery
 	q.given = 0
+	q.givenReserved = 0
 }
@@ -0,0 +1,192 @@
+package control
+
+import (
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+// scheduler holds the queued queries and decides which of them runs next.
+//
+// Each priority class has its own queue and may reserve some of the
+// concurrency quota. The slots that are not reserved are shared by all
+// classes, with the higher classes served first. Within a class, the next
+// query is taken from the organization running the fewest queries of that
+// class, and of those from the one served least recently, so a single
+// organization cannot take over the class.
+type scheduler struct {
+	mu      sync.Mutex
+	classes map[query.Priority]*classQueue
+	// order holds the classes from highest to lowest priority.
+	order []*classQueue
+	// shared is the number of unused shared slots.
+	shared int
+	// wake is closed and replaced whenever a query may be ready to run.
+	wake chan struct{}
+}
+
+func newScheduler(c Config) *scheduler {
+	s := &scheduler{
+		classes: make(map[query.Priority]*classQueue, len(query.Priorities)),
+		shared:  c.ConcurrencyQuota,
+		wake:    make(chan struct{}),
+	}
+	for _, p := range query.Priorities {
+		cc := c.PriorityClasses[p]
+		cq := &classQueue{
+			reserved: cc.ReservedConcurrency,
+			size:     cc.QueueSize,
+			queued:   make(map[string][]*Query),
+			running:  make(map[string]int),
+			served:   make(map[string]uint64),
+		}
+		if cq.size == 0 {
+			cq.size = c.QueueSize
+		}
+		s.shared -= cq.reserved
+		s.classes[p] = cq
+		s.order = append(s.order, cq)
+	}
+	return s
+}
+
+// classQueue holds the queued queries of a priority class.
+type classQueue struct {
+	// reserved is the number of slots only used by the class.
+	reserved int
+	// size is the maximum number of queued queries.
+	size int
+
+	// orgs holds the organizations with queued queries in the order
+	// their first query was queued.
+	orgs    []string
+	queued  map[string][]*Query
+	nQueued int
+
+	running  map[string]int
+	nRunning int
+
+	// served is when an organization with queued or running queries
+	// was last served, as a value of seq.
+	served map[string]uint64
+	seq    uint64
+}
+
+// push queues q. It returns false if the queue of its class is full.
+func (s *scheduler) push(q *Query) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cq := s.classes[q.priority]
+	if cq.nQueued >= cq.size {
+		return false
+	}
+	if len(cq.queued[q.org]) == 0 {
+		cq.orgs = append(cq.orgs, q.org)
+	}
+	cq.queued[q.org] = append(cq.queued[q.org], q)
+	cq.nQueued++
+	s.notify()
+	return true
+}
+
+// next waits for the next query to run. It returns false once done is
+// closed. The query must be given back with release once it is done.
+func (s *scheduler) next(done <-chan struct{}) (*Query, bool) {
+	for {
+		s.mu.Lock()
+		q := s.pop()
+		wake := s.wake
+		s.mu.Unlock()
+
+		if q != nil {
+			return q, true
+		}
+		select {
+		case <-wake:
+		case <-done:
+			return nil, false
+		}
+	}
+}
+
+// pop takes the next query that has a free slot. s.mu must be held.
+func (s *scheduler) pop() *Query {
+	for _, cq := range s.order {
+		if cq.nQueued == 0 {
+			continue
+		}
+		if cq.nRunning >= cq.reserved {
+			if s.shared == 0 {
+				continue
+			}
+			s.shared--
+		}
+		return cq.pop()
+	}
+	return nil
+}
+
+// pop takes the first query of the organization running the fewest
+// queries, breaking ties by serving the organization that waited longest.
+func (cq *classQueue) pop() *Query {
+	i := 0
+	for j, org := range cq.orgs {
+		if cq.less(org, cq.orgs[i]) {
+			i = j
+		}
+	}
+	org := cq.orgs[i]
+
+	queued := cq.queued[org]
+	q := queued[0]
+	queued[0] = nil
+	if queued = queued[1:]; len(queued) > 0 {
+		cq.queued[org] = queued
+	} else {
+		delete(cq.queued, org)
+		cq.orgs = append(cq.orgs[:i], cq.orgs[i+1:]...)
+	}
+	cq.nQueued--
+
+	cq.running[org]++
+	cq.nRunning++
+	cq.seq++
+	cq.served[org] = cq.seq
+	return q
+}
+
+// less reports whether org a is served before org b.
+func (cq *classQueue) less(a, b string) bool {
+	if cq.running[a] != cq.running[b] {
+		return cq.running[a] < cq.running[b]
+	}
+	return cq.served[a] < cq.served[b]
+}
+
+// release frees the slot of a query returned by next.
+func (s *scheduler) release(q *Query) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cq := s.classes[q.priority]
+	if cq.running[q.org]--; cq.running[q.org] == 0 {
+		delete(cq.running, q.org)
+		if len(cq.queued[q.org]) == 0 {
+			delete(cq.served, q.org)
+		}
+	}
+	cq.nRunning--
+	// The class uses its reserved slots first, so the slot is shared
+	// unless fewer queries than reserved are left.
+	if cq.nRunning >= cq.reserved {
+		s.shared++
+	}
+	s.notify()
+}
+
+// notify wakes the goroutines waiting in next. s.mu must be held.
+func (s *scheduler) notify() {
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
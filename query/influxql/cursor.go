@@ -33,6 +33,29 @@ type varRefCursor struct {
 // createVarRefCursor creates a new cursor from a variable reference using the sources
 // in the transpilerState.
 func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error) {
+	expr, err := t.fieldSource(&ast.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_field"},
+		},
+		Right: &ast.StringLiteral{
+			Value: ref.Val,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &varRefCursor{
+		expr: expr,
+		ref:  ref,
+	}, nil
+}
+
+// fieldSource reads the single measurement source in the transpilerState
+// within the time range of the condition and filters on the measurement name
+// and the given field predicate. A nil predicate will read every field.
+func (t *transpilerState) fieldSource(field ast.Expression) (ast.Expression, error) {
 	if len(t.stmt.Sources) != 1 {
 		// TODO(jsternberg): Support multiple sources.
 		return nil, errors.New("unimplemented: only one source is allowed")
@@ -95,7 +118,25 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 		},
 	}
 
-	expr := &ast.PipeExpression{
+	var body ast.Expression = &ast.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_measurement"},
+		},
+		Right: &ast.StringLiteral{
+			Value: mm.Name,
+		},
+	}
+	if field != nil {
+		body = &ast.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     body,
+			Right:    field,
+		}
+	}
+
+	return &ast.PipeExpression{
 		Argument: range_,
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
@@ -114,39 +155,13 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 										Name: "r",
 									},
 								}},
-								Body: &ast.LogicalExpression{
-									Operator: ast.AndOperator,
-									Left: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_measurement"},
-										},
-										Right: &ast.StringLiteral{
-											Value: mm.Name,
-										},
-									},
-									Right: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_field"},
-										},
-										Right: &ast.StringLiteral{
-											Value: ref.Val,
-										},
-									},
-								},
+								Body: body,
 							},
 						},
 					},
 				},
 			},
 		},
-	}
-	return &varRefCursor{
-		expr: expr,
-		ref:  ref,
 	}, nil
 }
 
@@ -179,3 +194,52 @@ type pipeCursor struct {
 }
 
 func (c *pipeCursor) Expr() ast.Expression { return c.expr }
+
+// wildcardCursor contains a cursor for every field matched by a wildcard or a regex.
+// Each field is read into its own table and the field name is kept in the field column.
+type wildcardCursor struct {
+	expr ast.Expression
+	arg  influxql.Expr
+}
+
+// createWildcardCursor creates a new cursor that reads all of the fields
+// matching the wildcard or regex.
+func createWildcardCursor(t *transpilerState, arg influxql.Expr) (cursor, error) {
+	var field ast.Expression
+	if re, ok := arg.(*influxql.RegexLiteral); ok {
+		field = &ast.BinaryExpression{
+			Operator: ast.RegexpMatchOperator,
+			Left: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "r"},
+				Property: &ast.Identifier{Name: "_field"},
+			},
+			Right: &ast.RegexpLiteral{
+				Value: re.Val,
+			},
+		}
+	}
+
+	expr, err := t.fieldSource(field)
+	if err != nil {
+		return nil, err
+	}
+	return &wildcardCursor{
+		expr: expr,
+		arg:  arg,
+	}, nil
+}
+
+func (c *wildcardCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *wildcardCursor) Keys() []influxql.Expr {
+	return []influxql.Expr{c.arg}
+}
+
+func (c *wildcardCursor) Value(expr influxql.Expr) (string, bool) {
+	if expr == c.arg {
+		return execute.DefaultValueColLabel, true
+	}
+	return "", false
+}
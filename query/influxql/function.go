@@ -14,13 +14,50 @@ func isTransformation(expr influxql.Expr) bool {
 	if call, ok := expr.(*influxql.Call); ok {
 		switch call.Name {
 		// TODO(ethan): more to be added here.
-		case "difference", "derivative", "cumulative_sum", "elapsed":
+		case "difference", "non_negative_difference", "derivative", "non_negative_derivative",
+			"moving_average", "cumulative_sum", "elapsed":
 			return true
 		}
 	}
 	return false
 }
 
+// isTopBottom returns true if the call is a top() or bottom() selector.
+func isTopBottom(call *influxql.Call) bool {
+	return call.Name == "top" || call.Name == "bottom"
+}
+
+// isWildcard returns true if the function reads every field matching a wildcard
+// or regex instead of a single field.
+func isWildcard(call *influxql.Call) bool {
+	switch fieldArg(call).(type) {
+	case *influxql.Wildcard, *influxql.RegexLiteral:
+		return true
+	default:
+		return false
+	}
+}
+
+// nestedAggregate returns the aggregate that is nested within a transformation.
+func nestedAggregate(call *influxql.Call) (*influxql.Call, bool) {
+	if !isTransformation(call) {
+		return nil, false
+	}
+	inner, ok := call.Args[0].(*influxql.Call)
+	return inner, ok
+}
+
+// fieldArg returns the argument of the function call that refers to the fields
+// that are read. This is a variable reference, a wildcard or a regex.
+func fieldArg(call *influxql.Call) influxql.Expr {
+	if arg, ok := call.Args[0].(*influxql.Call); ok {
+		// This is either the distinct() within count() or an aggregate
+		// nested within a transformation.
+		return fieldArg(arg)
+	}
+	return call.Args[0]
+}
+
 // function contains the prototype for invoking a function.
 // TODO(jsternberg): This should do a lot more heavy lifting, but it mostly just
 // pre-validates that we know the function exists. The cursor creation should be
@@ -31,7 +68,8 @@ type function struct {
 }
 
 // parseFunction parses a call AST and creates the function for it.
-func parseFunction(expr *influxql.Call) (*function, error) {
+// The validation and the error messages follow the ones used by influxdb 1.x.
+func parseFunction(expr *influxql.Call, stmt *influxql.SelectStatement) (*function, error) {
 	switch expr.Name {
 	case "count":
 		if exp, got := 1, len(expr.Args); exp != got {
@@ -39,76 +77,171 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 		}
 
 		switch ref := expr.Args[0].(type) {
-		case *influxql.VarRef:
-			return &function{
-				Ref:  ref,
-				call: expr,
-			}, nil
 		case *influxql.Call:
 			if ref.Name == "distinct" {
-				return nil, errors.New("unimplemented: count(distinct)")
+				return parseDistinct(expr, ref.Args)
 			}
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		case *influxql.Distinct:
-			return nil, errors.New("unimplemented: count(distinct)")
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
+			// Rewrite count(distinct value) as count(distinct(value)) so the
+			// distinct call is handled the same way for both forms.
+			call := ref.NewCall()
+			expr.Args[0] = call
+			return parseDistinct(expr, call.Args)
 		default:
-			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+			return parseSymbol(expr, expr.Args[0])
 		}
-	case "min", "max", "sum", "first", "last", "mean", "median", "difference", "stddev", "spread":
+	case "min", "max", "sum", "first", "last", "mean", "median", "stddev", "spread":
 		if exp, got := 1, len(expr.Args); exp != got {
 			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 		}
-
-		switch ref := expr.Args[0].(type) {
-		case *influxql.VarRef:
-			return &function{
-				Ref:  ref,
-				call: expr,
-			}, nil
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
-		default:
-			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
-		}
+		return parseSymbol(expr, expr.Args[0])
 	case "percentile":
 		if exp, got := 2, len(expr.Args); exp != got {
 			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 		}
 
-		var functionRef *influxql.VarRef
-
-		switch ref := expr.Args[0].(type) {
-		case *influxql.VarRef:
-			functionRef = ref
-		case *influxql.Wildcard:
-			return nil, errors.New("unimplemented: wildcard function")
-		case *influxql.RegexLiteral:
-			return nil, errors.New("unimplemented: wildcard regex function")
-		default:
-			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
-		}
-
 		switch expr.Args[1].(type) {
 		case *influxql.IntegerLiteral:
 		case *influxql.NumberLiteral:
 		default:
 			return nil, fmt.Errorf("expected float argument in %s()", expr.Name)
 		}
+		return parseSymbol(expr, expr.Args[0])
+	case "top", "bottom":
+		if exp, got := 2, len(expr.Args); got < exp {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, exp, got)
+		}
 
+		limit, ok := expr.Args[len(expr.Args)-1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected integer as last argument in %s(), found %s", expr.Name, expr.Args[len(expr.Args)-1])
+		} else if limit.Val <= 0 {
+			return nil, fmt.Errorf("limit (%d) in %s function must be at least 1", limit.Val, expr.Name)
+		} else if stmt.Limit > 0 && int(limit.Val) > stmt.Limit {
+			return nil, fmt.Errorf("limit (%d) in %s function can not be larger than the LIMIT (%d) in the select statement", limit.Val, expr.Name, stmt.Limit)
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected first argument to be a field in %s(), found %s", expr.Name, expr.Args[0])
+		}
+
+		for _, arg := range expr.Args[1 : len(expr.Args)-1] {
+			if _, ok := arg.(*influxql.VarRef); !ok {
+				return nil, fmt.Errorf("only fields or tags are allowed in %s(), found %s", expr.Name, arg)
+			}
+		}
 		return &function{
-			Ref:  functionRef,
+			Ref:  ref,
 			call: expr,
 		}, nil
+	case "derivative", "non_negative_derivative":
+		if min, max, got := 1, 2, len(expr.Args); got > max || got < min {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+		}
+
+		if len(expr.Args) == 2 {
+			switch arg := expr.Args[1].(type) {
+			case *influxql.DurationLiteral:
+				if arg.Val <= 0 {
+					return nil, fmt.Errorf("duration argument must be positive, got %s", influxql.FormatDuration(arg.Val))
+				}
+			default:
+				return nil, fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+			}
+		}
+		return parseTransformation(expr, stmt)
+	case "difference", "non_negative_difference":
+		if exp, got := 1, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+		return parseTransformation(expr, stmt)
+	case "moving_average":
+		if exp, got := 2, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+
+		arg, ok := expr.Args[1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("second argument for %s must be an integer, got %T", expr.Name, expr.Args[1])
+		} else if arg.Val <= 1 {
+			return nil, fmt.Errorf("%s window must be greater than 1, got %d", expr.Name, arg.Val)
+		}
+		return parseTransformation(expr, stmt)
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", expr.Name)
 	}
+}
+
+// parseSymbol parses the field argument of a function. The argument may be a
+// variable reference, a wildcard or a regex.
+func parseSymbol(expr *influxql.Call, arg influxql.Expr) (*function, error) {
+	switch ref := arg.(type) {
+	case *influxql.VarRef:
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case *influxql.Wildcard:
+		if ref.Type == influxql.TAG {
+			return nil, fmt.Errorf("unable to use tag as wildcard in %s()", expr.Name)
+		}
+		return &function{call: expr}, nil
+	case *influxql.RegexLiteral:
+		return &function{call: expr}, nil
+	default:
+		return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+	}
+}
+
+// parseDistinct parses the distinct() call used as the argument to count().
+func parseDistinct(expr *influxql.Call, args []influxql.Expr) (*function, error) {
+	if len(args) == 0 {
+		return nil, errors.New("distinct function requires at least one argument")
+	} else if len(args) != 1 {
+		return nil, errors.New("distinct function can only have one argument")
+	}
 
+	ref, ok := args[0].(*influxql.VarRef)
+	if !ok {
+		return nil, errors.New("expected field argument in distinct()")
+	}
+	return &function{
+		Ref:  ref,
+		call: expr,
+	}, nil
+}
+
+// parseTransformation parses the first argument of a transformation. When the statement
+// has a GROUP BY interval, the argument must be an aggregate that is evaluated
+// over each interval. Otherwise, the transformation is applied to the raw field.
+func parseTransformation(expr *influxql.Call, stmt *influxql.SelectStatement) (*function, error) {
+	// err checked in caller
+	interval, _ := stmt.GroupByInterval()
+
+	switch arg := expr.Args[0].(type) {
+	case *influxql.Call:
+		if interval == 0 {
+			return nil, fmt.Errorf("%s aggregate requires a GROUP BY interval", expr.Name)
+		}
+
+		fn, err := parseFunction(arg, stmt)
+		if err != nil {
+			return nil, err
+		} else if isTransformation(arg) || isTopBottom(arg) {
+			return nil, fmt.Errorf("unimplemented: %s() nested within %s()", arg.Name, expr.Name)
+		}
+		return &function{
+			Ref:  fn.Ref,
+			call: expr,
+		}, nil
+	default:
+		if interval > 0 {
+			return nil, fmt.Errorf("aggregate function required inside the call to %s", expr.Name)
+		}
+		return parseSymbol(expr, arg)
+	}
 }
 
 // createFunctionCursor creates a new cursor that calls a function on one of the columns
@@ -119,7 +252,7 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		parent: in,
 	}
 	switch call.Name {
-	case "count", "min", "max", "sum", "first", "last", "mean", "difference", "stddev", "spread":
+	case "min", "max", "sum", "first", "last", "mean", "stddev", "spread":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
 			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
@@ -134,6 +267,88 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = value
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "count":
+		arg := fieldArg(call)
+		value, ok := in.Value(arg)
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", arg)
+		}
+		expr := in.Expr()
+		if distinct, ok := call.Args[0].(*influxql.Call); ok && distinct.Name == "distinct" {
+			expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: value}))
+		}
+		cur.expr = pipe(expr, "count")
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{arg: {}}
+	case "derivative", "non_negative_derivative":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The unit defaults to the interval when used with an aggregate
+		// and to one second otherwise.
+		unit := time.Second
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		} else if interval, _ := t.stmt.GroupByInterval(); interval > 0 {
+			unit = interval
+		}
+		args := []*ast.Property{
+			property("unit", &ast.DurationLiteral{Values: durationLiteral(unit)}),
+		}
+		if call.Name == "non_negative_derivative" {
+			args = append(args, property("nonNegative", &ast.BooleanLiteral{Value: true}))
+		}
+		cur.expr = pipe(in.Expr(), "derivative", args...)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "difference", "non_negative_difference":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		var args []*ast.Property
+		if call.Name == "non_negative_difference" {
+			args = append(args, property("nonNegative", &ast.BooleanLiteral{Value: true}))
+		}
+		cur.expr = pipe(in.Expr(), "difference", args...)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "moving_average":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		n := call.Args[1].(*influxql.IntegerLiteral)
+		cur.expr = pipe(in.Expr(), "movingAverage", property("n", &ast.IntegerLiteral{Value: n.Val}))
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "top", "bottom":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		// The tags were already used to select a single value for each tag value
+		// when grouping and are now regular columns in the table.
+		if len(call.Args) > 2 {
+			tags := make(map[influxql.VarRef]struct{}, len(call.Args)-2)
+			for _, arg := range call.Args[1 : len(call.Args)-1] {
+				tags[*arg.(*influxql.VarRef)] = struct{}{}
+			}
+			cur.parent = &tagsCursor{cursor: in, tags: tags}
+		}
+
+		// The selected points are returned in time order.
+		n := call.Args[len(call.Args)-1].(*influxql.IntegerLiteral)
+		cur.expr = pipe(
+			pipe(in.Expr(), call.Name, property("n", &ast.IntegerLiteral{Value: n.Val})),
+			"sort",
+			property("columns", stringArray(execute.DefaultTimeColLabel)),
+		)
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	case "elapsed":
 		// TODO(ethan): https://github.com/influxdata/influxdb/issues/10733 to enable this.
 		value, ok := in.Value(call.Args[0])
@@ -317,7 +532,7 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 					Name: execute.DefaultStartColLabel,
 				},
 			}
-		} else if isTransformation(call) || influxql.IsSelector(call) {
+		} else if isTransformation(call) || (influxql.IsSelector(call) && !isWildcard(call)) {
 			timeValue = &ast.MemberExpression{
 				Object: &ast.Identifier{
 					Name: "r",
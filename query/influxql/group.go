@@ -18,9 +18,11 @@ type groupInfo struct {
 }
 
 type groupVisitor struct {
-	calls []*function
-	refs  []*influxql.VarRef
-	err   error
+	stmt     *influxql.SelectStatement
+	calls    []*function
+	refs     []*influxql.VarRef
+	wildcard influxql.Expr
+	err      error
 }
 
 func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
@@ -32,7 +34,7 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 	switch expr := n.(type) {
 	case *influxql.Call:
 		// TODO(jsternberg): Identify math functions so we visit their arguments instead of recording them.
-		fn, err := parseFunction(expr)
+		fn, err := parseFunction(expr, v.stmt)
 		if err != nil {
 			v.err = err
 			return nil
@@ -48,11 +50,10 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 		}
 		v.refs = append(v.refs, expr)
 		return nil
-	case *influxql.Wildcard:
-		v.err = errors.New("unimplemented: field wildcard")
-		return nil
-	case *influxql.RegexLiteral:
-		v.err = errors.New("unimplemented: field regex wildcard")
+	case *influxql.Wildcard, *influxql.RegexLiteral:
+		if v.wildcard == nil {
+			v.wildcard = expr.(influxql.Expr)
+		}
 		return nil
 	}
 	return v
@@ -60,12 +61,46 @@ func (v *groupVisitor) Visit(n influxql.Node) influxql.Visitor {
 
 // identifyGroups will identify the groups for creating data access cursors.
 func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
-	v := &groupVisitor{}
+	v := &groupVisitor{stmt: stmt}
 	influxql.Walk(v, stmt.Fields)
 	if v.err != nil {
 		return nil, v.err
 	}
 
+	// The top and bottom selectors must be the only function in the query.
+	if len(v.calls) > 1 {
+		for _, fn := range v.calls {
+			if isTopBottom(fn.call) {
+				return nil, fmt.Errorf("selector function %s() cannot be combined with other functions", fn.call.Name)
+			}
+		}
+	}
+
+	// Wildcards in the fields cannot be mixed with an aggregate.
+	if v.wildcard != nil {
+		for _, fn := range v.calls {
+			if !influxql.IsSelector(fn.call) || isWildcard(fn.call) {
+				return nil, errors.New("mixing aggregate and non-aggregate queries is not supported")
+			}
+		}
+		if _, ok := v.wildcard.(*influxql.RegexLiteral); ok {
+			return nil, errors.New("unimplemented: field regex wildcard")
+		}
+		return nil, errors.New("unimplemented: field wildcard")
+	}
+
+	// A function on a wildcard produces one column for every field
+	// so it can only be combined with other functions.
+	for _, fn := range v.calls {
+		if !isWildcard(fn.call) {
+			continue
+		} else if len(v.refs) > 0 {
+			return nil, errors.New("mixing aggregate and non-aggregate queries is not supported")
+		} else if len(v.calls) > 1 {
+			return nil, errors.New("unimplemented: wildcard function combined with other functions")
+		}
+	}
+
 	// Attempt to take the calls and variables and put them into groups.
 	if len(v.refs) > 0 {
 		// If any of the calls are not selectors, we have an error message.
@@ -100,9 +135,11 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 	}
 
 	// If there is exactly one group and that contains a selector or a transformation function,
-	// then mark it does not need normalization.
+	// then mark it does not need normalization. A selector over a wildcard is normalized
+	// so the values for each field end up in the same row.
 	if len(groups) == 1 {
-		groups[0].needNormalization = !isTransformation(groups[0].call) && !influxql.IsSelector(groups[0].call)
+		call := groups[0].call
+		groups[0].needNormalization = !isTransformation(call) && (!influxql.IsSelector(call) || isWildcard(call))
 	}
 	return groups, nil
 }
@@ -112,12 +149,19 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	// TODO(jsternberg): Determine which of these cursors are from fields and which are tags.
 	var cursors []cursor
 	if gr.call != nil {
-		ref, ok := gr.call.Args[0].(*influxql.VarRef)
-		if !ok {
+		var (
+			cur cursor
+			err error
+		)
+		switch arg := fieldArg(gr.call).(type) {
+		case *influxql.VarRef:
+			cur, err = createVarRefCursor(t, arg)
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			cur, err = createWildcardCursor(t, arg)
+		default:
 			// TODO(jsternberg): This should be validated and figured out somewhere else.
 			return nil, fmt.Errorf("first argument to %q must be a variable", gr.call.Name)
 		}
-		cur, err := createVarRefCursor(t, ref)
 		if err != nil {
 			return nil, err
 		}
@@ -244,33 +288,37 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 	// If a function call is present, evaluate the function call.
 	if gr.call != nil {
-		c, err := createFunctionCursor(t, gr.call, cur, gr.needNormalization || interval > 0)
-		if err != nil {
-			return nil, err
-		}
-		cur = c
+		if inner, ok := nestedAggregate(gr.call); ok {
+			// Evaluate the aggregate for each interval and then apply
+			// the transformation to the aggregated values.
+			c, err := createFunctionCursor(t, inner, cur, true)
+			if err != nil {
+				return nil, err
+			}
+			c, err = createFunctionCursor(t, gr.call, undoWindow(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c
+		} else {
+			c, err := createFunctionCursor(t, gr.call, cur, gr.needNormalization || interval > 0)
+			if err != nil {
+				return nil, err
+			}
+			cur = c
 
-		// If there was a window operation, we now need to undo that and sort by the start column
-		// so they stay in the same table and are joined in the correct order.
-		if interval > 0 {
-			cur = &pipeCursor{
-				expr: &ast.PipeExpression{
-					Argument: cur.Expr(),
-					Call: &ast.CallExpression{
-						Callee: &ast.Identifier{Name: "window"},
-						Arguments: []ast.Expression{
-							&ast.ObjectExpression{
-								Properties: []*ast.Property{{
-									Key:   &ast.Identifier{Name: "every"},
-									Value: &ast.Identifier{Name: "inf"},
-								}},
-							},
-						},
-					},
-				},
-				cursor: cur,
+			// If there was a window operation, we now need to undo that and sort by the start column
+			// so they stay in the same table and are joined in the correct order.
+			if interval > 0 {
+				cur = undoWindow(cur)
 			}
 		}
+
+		// A function on a wildcard has evaluated each field in its own table.
+		// Pivot the fields so each of them is a column named after the function.
+		if isWildcard(gr.call) {
+			cur = pivotFields(t, gr.call, cur)
+		}
 	} else {
 		// If we do not have a function, but we have a field option,
 		// return the appropriate error message if there is something wrong with the flux.
@@ -290,6 +338,67 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	return cur, nil
 }
 
+// undoWindow merges the windows created by the group so the results of every
+// window end up in the same table.
+func undoWindow(in cursor) cursor {
+	return &pipeCursor{
+		expr: &ast.PipeExpression{
+			Argument: in.Expr(),
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "window"},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{{
+							Key:   &ast.Identifier{Name: "every"},
+							Value: &ast.Identifier{Name: "inf"},
+						}},
+					},
+				},
+			},
+		},
+		cursor: in,
+	}
+}
+
+// pivotFields turns the table for each field read by a wildcard function into
+// a column. The columns are named after the function and the field
+// in the same way as influxdb 1.x does, such as max_value.
+func pivotFields(t *transpilerState, call *influxql.Call, in cursor) cursor {
+	prefix := call.Name
+	for _, f := range t.stmt.Fields {
+		if f.Expr == call && f.Alias != "" {
+			prefix = f.Alias
+		}
+	}
+
+	expr := pipe(in.Expr(), "group",
+		property("columns", stringArray("_field")),
+		property("mode", &ast.StringLiteral{Value: "except"}),
+	)
+	expr = pipe(expr, "map",
+		property("fn", predicate(&ast.ObjectExpression{
+			With: &ast.Identifier{Name: "r"},
+			Properties: []*ast.Property{{
+				Key: &ast.Identifier{Name: "_field"},
+				Value: &ast.BinaryExpression{
+					Operator: ast.AdditionOperator,
+					Left:     &ast.StringLiteral{Value: prefix + "_"},
+					Right:    member("r", "_field"),
+				},
+			}},
+		})),
+	)
+	expr = pipe(expr, "pivot",
+		property("rowKey", stringArray(execute.DefaultTimeColLabel)),
+		property("columnKey", stringArray("_field")),
+		property("valueColumn", &ast.StringLiteral{Value: execute.DefaultValueColLabel}),
+	)
+	return &pipeCursor{
+		expr:   expr,
+		cursor: in,
+	}
+}
+
 func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	var windowEvery time.Duration
 	var windowStart time.Time
@@ -381,6 +490,21 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 		}
 	}
 
+	// The tags passed to top() or bottom() select the top value for each distinct
+	// tag value. Include them in the group so the values can be selected for
+	// each of them and then combined.
+	groupKeys := tags
+	if gr.call != nil && isTopBottom(gr.call) {
+		groupKeys = make([]ast.Expression, len(tags), len(tags)+len(gr.call.Args)-2)
+		copy(groupKeys, tags)
+		for _, arg := range gr.call.Args[1 : len(gr.call.Args)-1] {
+			ref := arg.(*influxql.VarRef)
+			groupKeys = append(groupKeys, &ast.StringLiteral{
+				Value: ref.Val,
+			})
+		}
+	}
+
 	// Perform the grouping by the tags we found. There is always a group by because
 	// there is always something to group in influxql.
 	// TODO(jsternberg): A wildcard will skip this step.
@@ -399,7 +523,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 									Name: "columns",
 								},
 								Value: &ast.ArrayExpression{
-									Elements: tags,
+									Elements: groupKeys,
 								},
 							},
 							{
@@ -432,7 +556,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 								Name: "columns",
 							},
 							Value: &ast.ArrayExpression{
-								Elements: append(tags,
+								Elements: append(groupKeys,
 									&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
 									&ast.StringLiteral{Value: execute.DefaultValueColLabel}),
 							},
@@ -480,6 +604,24 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 			cursor: in,
 		}
 	}
+
+	// Select the value for each of the top() or bottom() tags and then
+	// group the results back together.
+	if len(groupKeys) > len(tags) {
+		selector := "max"
+		if gr.call.Name == "bottom" {
+			selector = "min"
+		}
+		in = &pipeCursor{
+			expr: pipe(
+				pipe(in.Expr(), selector),
+				"group",
+				property("columns", &ast.ArrayExpression{Elements: tags}),
+				property("mode", &ast.StringLiteral{Value: "by"}),
+			),
+			cursor: in,
+		}
+	}
 	return in, nil
 }
 
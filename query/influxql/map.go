@@ -33,27 +33,55 @@ func (c *mapCursor) Value(expr influxql.Expr) (string, bool) {
 // using the column names.
 func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 	columns := t.stmt.ColumnNames()
-	if len(columns) != len(t.stmt.Fields) {
-		// TODO(jsternberg): This scenario should not be possible. Replace the use of ColumnNames with a more
-		// statically verifiable list of columns when we process the fields from the select statement instead
-		// of doing this in the future.
-		panic("number of columns does not match the number of fields")
+	properties := make([]*ast.Property, 0, len(columns))
+	mapColumn := func(expr influxql.Expr, column string) error {
+		fieldName, err := t.mapField(expr, in, false)
+		if err != nil {
+			return err
+		} else if lit, ok := fieldName.(*ast.StringLiteral); ok && lit.Value == column {
+			// The column already has the correct name.
+			return nil
+		}
+		properties = append(properties, &ast.Property{
+			Key:   fieldName.(ast.PropertyKey),
+			Value: &ast.StringLiteral{Value: column},
+		})
+		return nil
 	}
 
-	properties := make([]*ast.Property, 0, len(t.stmt.Fields))
-	for i, f := range t.stmt.Fields {
+	// The column names include an extra column for each tag that is selected
+	// by top() or bottom() so the index into the columns is tracked separately.
+	i := 0
+	for _, f := range t.stmt.Fields {
+		column := columns[i]
+		i++
+
 		if ref, ok := f.Expr.(*influxql.VarRef); ok && ref.Val == "time" {
 			// Skip past any time columns.
 			continue
 		}
-		fieldName, err := t.mapField(f.Expr, in, false)
-		if err != nil {
+
+		if call, ok := f.Expr.(*influxql.Call); ok {
+			if isWildcard(call) {
+				// The columns for a wildcard function were named when the fields were pivoted.
+				continue
+			} else if isTopBottom(call) && t.stmt.Target == nil {
+				for _, arg := range call.Args[1 : len(call.Args)-1] {
+					if err := mapColumn(arg, columns[i]); err != nil {
+						return nil, err
+					}
+					i++
+				}
+			}
+		}
+
+		if err := mapColumn(f.Expr, column); err != nil {
 			return nil, err
 		}
-		properties = append(properties, &ast.Property{
-			Key:   fieldName.(ast.PropertyKey),
-			Value: &ast.StringLiteral{Value: columns[i]},
-		})
+	}
+
+	if len(properties) == 0 {
+		return &mapCursor{expr: in.Expr()}, nil
 	}
 	return &mapCursor{
 		expr: &ast.PipeExpression{
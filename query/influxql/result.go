@@ -1,19 +1,27 @@
 package influxql
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb/v2/models"
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format used to encode time values; defaults to RFC3339Nano.
+	TimeFormat TimeFormat
+	// Pretty indents the JSON output when set.
+	Pretty bool
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -28,9 +36,129 @@ type MultiResultEncoder struct{}
 //      TODO(jsternberg): This function currently requires the first column to be a time field, but this isn't
 //      a strict requirement and will be lifted when we begin to work on transpiling meta queries.
 func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
-	resp := Response{}
+	resp := newResponse(results, e.TimeFormat)
+
 	wc := &iocounter.Writer{Writer: w}
+	enc := json.NewEncoder(wc)
+	if e.Pretty {
+		enc.SetIndent("", "    ")
+	}
+	err := enc.Encode(resp)
+	return wc.Count(), err
+}
+
+func NewMultiResultEncoder() *MultiResultEncoder {
+	return new(MultiResultEncoder)
+}
+
+// CSVMultiResultEncoder encodes results as InfluxQL CSV format.
+type CSVMultiResultEncoder struct {
+	// TimeFormat is the format used to encode time values. The default
+	// RFC3339Nano format is encoded as nanoseconds to match influxdb 1.X.
+	TimeFormat TimeFormat
+}
+
+// Encode writes a collection of results to the influxdb 1.X csv response format.
+// Each series is written with its measurement name and tag set as the first two
+// columns. A new header is written whenever the columns change and statements
+// are separated by an empty line.
+func (e *CSVMultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	timeFormat := e.TimeFormat
+	if timeFormat == RFC3339Nano {
+		timeFormat = Nanosecond
+	}
+	resp := newResponse(results, timeFormat)
+
+	wc := &iocounter.Writer{Writer: w}
+	cw := csv.NewWriter(wc)
+	if resp.Err != "" {
+		_ = cw.Write([]string{"error"})
+		_ = cw.Write([]string{resp.Err})
+		cw.Flush()
+		return wc.Count(), cw.Error()
+	}
+
+	var (
+		columns       []string
+		printedHeader bool
+		statementID   = -1
+	)
+	for _, result := range resp.Results {
+		if result.StatementID != statementID {
+			// Skip over results with no series so we do not
+			// print an empty separator.
+			if len(result.Series) == 0 && result.Err == "" {
+				continue
+			}
+			statementID = result.StatementID
+			if printedHeader {
+				_ = cw.Write(nil)
+			}
+			printedHeader = false
+		}
+
+		if result.Err != "" {
+			_ = cw.Write([]string{"error"})
+			_ = cw.Write([]string{result.Err})
+			continue
+		}
 
+		for _, row := range result.Series {
+			if !printedHeader || !reflect.DeepEqual(columns[2:], row.Columns) {
+				if printedHeader {
+					_ = cw.Write(nil)
+				}
+				columns = append(columns[:0], "name", "tags")
+				columns = append(columns, row.Columns...)
+				if err := cw.Write(columns); err != nil {
+					return wc.Count(), err
+				}
+				printedHeader = true
+			}
+
+			columns[0] = row.Name
+			columns[1] = ""
+			if len(row.Tags) > 0 {
+				hashKey := models.NewTags(row.Tags).HashKey()
+				columns[1] = string(hashKey[1:])
+			}
+			for _, values := range row.Values {
+				for i, value := range values {
+					columns[i+2] = formatCSVValue(value)
+				}
+				if err := cw.Write(columns); err != nil {
+					return wc.Count(), err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return wc.Count(), cw.Error()
+}
+
+// formatCSVValue formats a single value in a row for the csv response.
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// newResponse reads all of the results into the influxdb 1.X response structure.
+func newResponse(results flux.ResultIterator, timeFormat TimeFormat) Response {
+	resp := Response{}
 	for results.More() {
 		res := results.Next()
 		name := res.Name()
@@ -154,7 +282,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = formatTime(execute.Time(vs.Value(i)).Time(), timeFormat)
 							}
 						}
 					default:
@@ -181,10 +309,25 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 	if err := results.Err(); err != nil && resp.Err == "" {
 		resp.error(err)
 	}
-
-	err := json.NewEncoder(wc).Encode(resp)
-	return wc.Count(), err
+	return resp
 }
-func NewMultiResultEncoder() *MultiResultEncoder {
-	return new(MultiResultEncoder)
+
+// formatTime converts a timestamp to the representation requested by the time format.
+func formatTime(t time.Time, format TimeFormat) interface{} {
+	switch format {
+	case Hour:
+		return t.UnixNano() / int64(time.Hour)
+	case Minute:
+		return t.UnixNano() / int64(time.Minute)
+	case Second:
+		return t.UnixNano() / int64(time.Second)
+	case Millisecond:
+		return t.UnixNano() / int64(time.Millisecond)
+	case Microsecond:
+		return t.UnixNano() / int64(time.Microsecond)
+	case Nanosecond:
+		return t.UnixNano()
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
 }
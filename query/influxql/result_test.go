@@ -106,6 +106,80 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 	}
 }
 
+func TestMultiResultEncoder_TimeFormat(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		enc  flux.MultiResultEncoder
+		out  string
+	}{
+		{
+			name: "Epoch",
+			enc:  &influxql.MultiResultEncoder{TimeFormat: influxql.Second},
+			out: `{"results":[{"statement_id":0,"series":[{"name":"m0","tags":{"host":"server01"},"columns":["time","value"],"values":[[1527152400,2]]}]}]}
+`,
+		},
+		{
+			name: "CSV",
+			enc:  &influxql.CSVMultiResultEncoder{},
+			out: `name,tags,time,value
+m0,host=server01,1527152400000000000,2
+`,
+		},
+		{
+			name: "CSV Epoch",
+			enc:  &influxql.CSVMultiResultEncoder{TimeFormat: influxql.Millisecond},
+			out: `name,tags,time,value
+m0,host=server01,1527152400000,2
+`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			in := flux.NewSliceResultIterator(
+				[]flux.Result{&executetest.Result{
+					Nm: "0",
+					Tbls: []*executetest.Table{{
+						KeyCols: []string{"_measurement", "host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "host", Type: flux.TString},
+							{Label: "value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{ts("2018-05-24T09:00:00Z"), "m0", "server01", float64(2)},
+						},
+					}},
+				}},
+			)
+
+			var buf bytes.Buffer
+			n, err := tt.enc.Encode(&buf, in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got, exp := buf.String(), tt.out; got != exp {
+				t.Fatalf("unexpected output:\nexp=%s\ngot=%s", exp, got)
+			}
+			if g, w := n, int64(len(tt.out)); g != w {
+				t.Errorf("unexpected encoding count -want/+got:\n%s", cmp.Diff(w, g))
+			}
+		})
+	}
+}
+
+func TestCSVMultiResultEncoder_Error(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &influxql.CSVMultiResultEncoder{}
+	if _, err := enc.Encode(&buf, &resultErrorIterator{Error: "expected"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, exp := buf.String(), "error\nexpected\n"; got != exp {
+		t.Fatalf("unexpected output:\nexp=%s\ngot=%s", exp, got)
+	}
+}
+
 type resultErrorIterator struct {
 	Error string
 }
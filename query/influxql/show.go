@@ -0,0 +1,357 @@
+package influxql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxql"
+)
+
+// metaSource returns the expression that reads the bucket mapped to the database
+// of a meta query. Meta queries do not factor in retention policies so the default
+// retention policy is always used.
+func (t *transpilerState) metaSource(db string) (ast.Expression, error) {
+	if db == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errDatabaseNameRequired
+		}
+		db = t.config.DefaultDatabase
+	}
+
+	expr, err := t.from(&influxql.Measurement{Database: db})
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
+	// urgent to implement this functionality so we can use the default range.
+	return pipe(expr, "range", property("start", &ast.DurationLiteral{
+		Values: []ast.Duration{{
+			Magnitude: -1,
+			Unit:      "h",
+		}},
+	})), nil
+}
+
+// metaFilter filters the input to the measurements in sources and to the series
+// that match the condition. The time is removed from the condition because meta
+// queries always read the default range.
+func (t *transpilerState) metaFilter(expr ast.Expression, sources influxql.Sources, cond influxql.Expr) (ast.Expression, error) {
+	if len(sources) > 0 {
+		var filterExpr ast.Expression
+		for i := len(sources) - 1; i >= 0; i-- {
+			mm, ok := sources[i].(*influxql.Measurement)
+			if !ok {
+				return nil, fmt.Errorf("unsupported source type: %T", sources[i])
+			}
+
+			var e ast.Expression
+			if mm.Regex != nil {
+				e = &ast.BinaryExpression{
+					Operator: ast.RegexpMatchOperator,
+					Left:     member("r", "_measurement"),
+					Right:    &ast.RegexpLiteral{Value: mm.Regex.Val},
+				}
+			} else {
+				e = &ast.BinaryExpression{
+					Operator: ast.EqualOperator,
+					Left:     member("r", "_measurement"),
+					Right:    &ast.StringLiteral{Value: mm.Name},
+				}
+			}
+
+			if filterExpr == nil {
+				filterExpr = e
+			} else {
+				filterExpr = &ast.LogicalExpression{
+					Operator: ast.OrOperator,
+					Left:     e,
+					Right:    filterExpr,
+				}
+			}
+		}
+		expr = pipe(expr, "filter", property("fn", predicate(filterExpr)))
+	}
+
+	if cond != nil {
+		valuer := influxql.NowValuer{Now: t.config.Now}
+		c, _, err := influxql.ConditionExpr(cond, &valuer)
+		if err != nil {
+			return nil, err
+		} else if c != nil {
+			filterExpr, err := t.mapField(c, metaCursor{}, true)
+			if err != nil {
+				return nil, err
+			}
+			expr = pipe(expr, "filter", property("fn", predicate(filterExpr)))
+		}
+	}
+	return expr, nil
+}
+
+// metaLimit applies the LIMIT and OFFSET clauses of a meta query.
+func metaLimit(expr ast.Expression, limit, offset int) ast.Expression {
+	if limit == 0 && offset == 0 {
+		return expr
+	}
+
+	n := int64(limit)
+	if n == 0 {
+		n = math.MaxInt64
+	}
+	args := []*ast.Property{property("n", &ast.IntegerLiteral{Value: n})}
+	if offset > 0 {
+		args = append(args, property("offset", &ast.IntegerLiteral{Value: int64(offset)}))
+	}
+	return pipe(expr, "limit", args...)
+}
+
+// metaName places the rows into a single series with the given name
+// so the results match the names used by 1.x.
+func metaName(expr ast.Expression, name string) ast.Expression {
+	expr = pipe(expr, "set",
+		property("key", &ast.StringLiteral{Value: "_measurement"}),
+		property("value", &ast.StringLiteral{Value: name}),
+	)
+	return pipe(expr, "group",
+		property("columns", stringArray("_measurement")),
+		property("mode", &ast.StringLiteral{Value: "by"}),
+	)
+}
+
+func (t *transpilerState) transpileShowMeasurements(ctx context.Context, stmt *influxql.ShowMeasurementsStatement) (ast.Expression, error) {
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources influxql.Sources
+	if stmt.Source != nil {
+		sources = influxql.Sources{stmt.Source}
+	}
+	if expr, err = t.metaFilter(expr, sources, stmt.Condition); err != nil {
+		return nil, err
+	}
+
+	expr = pipe(expr, "keep", property("columns", stringArray("_measurement")))
+	expr = pipe(expr, "group")
+	expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: "_measurement"}))
+	expr = pipe(expr, "sort")
+	expr = metaLimit(expr, stmt.Limit, stmt.Offset)
+	expr = pipe(expr, "rename", property("columns", &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("_value", &ast.StringLiteral{Value: "name"}),
+		},
+	}))
+	return metaName(expr, "measurements"), nil
+}
+
+func (t *transpilerState) transpileShowFieldKeys(ctx context.Context, stmt *influxql.ShowFieldKeysStatement) (ast.Expression, error) {
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+	if expr, err = t.metaFilter(expr, stmt.Sources, nil); err != nil {
+		return nil, err
+	}
+
+	// The storage engine does not expose the type of a field without reading
+	// its values, so only the field keys are returned.
+	expr = pipe(expr, "keep", property("columns", stringArray("_measurement", "_field")))
+	expr = pipe(expr, "group",
+		property("columns", stringArray("_measurement")),
+		property("mode", &ast.StringLiteral{Value: "by"}),
+	)
+	expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: "_field"}))
+	expr = pipe(expr, "sort")
+	expr = metaLimit(expr, stmt.Limit, stmt.Offset)
+	return pipe(expr, "rename", property("columns", &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("_value", &ast.StringLiteral{Value: "fieldKey"}),
+		},
+	})), nil
+}
+
+func (t *transpilerState) transpileShowTagKeys(ctx context.Context, stmt *influxql.ShowTagKeysStatement) (ast.Expression, error) {
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+	if expr, err = t.metaFilter(expr, stmt.Sources, stmt.Condition); err != nil {
+		return nil, err
+	}
+
+	// The keys of each table are the tags of the series along with the columns
+	// that are always present in the group key. Remove those so only the tag keys remain.
+	expr = pipe(expr, "keys")
+	expr = pipe(expr, "keep", property("columns", stringArray("_measurement", "_value")))
+	expr = pipe(expr, "group",
+		property("columns", stringArray("_measurement")),
+		property("mode", &ast.StringLiteral{Value: "by"}),
+	)
+	expr = pipe(expr, "distinct")
+	expr = pipe(expr, "filter", property("fn", predicate(&ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left:     member("r", "_value"),
+		Right:    &ast.RegexpLiteral{Value: regexp.MustCompile("^_")},
+	})))
+	expr = pipe(expr, "sort")
+	expr = metaLimit(expr, stmt.Limit, stmt.Offset)
+	return pipe(expr, "rename", property("columns", &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("_value", &ast.StringLiteral{Value: "tagKey"}),
+		},
+	})), nil
+}
+
+// seriesExpr reduces the input to a single row for every series. Each of the
+// returned tables has the measurement and the tags of the series as its group key.
+func seriesExpr(expr ast.Expression) ast.Expression {
+	expr = pipe(expr, "first")
+	expr = pipe(expr, "drop", property("columns", stringArray("_start", "_stop", "_field", "_time", "_value")))
+	return pipe(expr, "limit", property("n", &ast.IntegerLiteral{Value: 1}))
+}
+
+func (t *transpilerState) transpileShowSeries(ctx context.Context, stmt *influxql.ShowSeriesStatement) (ast.Expression, error) {
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+	if expr, err = t.metaFilter(expr, stmt.Sources, stmt.Condition); err != nil {
+		return nil, err
+	}
+
+	// Flux cannot construct the series key from an unknown set of tag columns.
+	// Each series is returned as its own series in the response instead, which
+	// carries the measurement name and the tags of the series.
+	expr = seriesExpr(expr)
+	expr = pipe(expr, "duplicate",
+		property("column", &ast.StringLiteral{Value: "_measurement"}),
+		property("as", &ast.StringLiteral{Value: "key"}),
+	)
+	return metaLimit(expr, stmt.Limit, stmt.Offset), nil
+}
+
+func (t *transpilerState) transpileShowMeasurementCardinality(ctx context.Context, stmt *influxql.ShowMeasurementCardinalityStatement) (ast.Expression, error) {
+	if len(stmt.Dimensions) > 0 {
+		return nil, fmt.Errorf("unimplemented: GROUP BY in SHOW MEASUREMENT CARDINALITY")
+	}
+
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+	if expr, err = t.metaFilter(expr, stmt.Sources, stmt.Condition); err != nil {
+		return nil, err
+	}
+
+	// The cardinality is always exact because it is computed from the data.
+	expr = pipe(expr, "keep", property("columns", stringArray("_measurement")))
+	expr = pipe(expr, "group")
+	expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: "_measurement"}))
+	expr = pipe(expr, "count")
+	expr = pipe(expr, "rename", property("columns", &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("_value", &ast.StringLiteral{Value: "count"}),
+		},
+	}))
+	return metaLimit(expr, stmt.Limit, stmt.Offset), nil
+}
+
+func (t *transpilerState) transpileShowSeriesCardinality(ctx context.Context, stmt *influxql.ShowSeriesCardinalityStatement) (ast.Expression, error) {
+	if len(stmt.Dimensions) > 0 {
+		return nil, fmt.Errorf("unimplemented: GROUP BY in SHOW SERIES CARDINALITY")
+	}
+
+	expr, err := t.metaSource(stmt.Database)
+	if err != nil {
+		return nil, err
+	}
+	if expr, err = t.metaFilter(expr, stmt.Sources, stmt.Condition); err != nil {
+		return nil, err
+	}
+
+	// The cardinality is always exact because it is computed from the data.
+	expr = seriesExpr(expr)
+	expr = pipe(expr, "group")
+	expr = pipe(expr, "count", property("column", &ast.StringLiteral{Value: "_measurement"}))
+	expr = pipe(expr, "rename", property("columns", &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("_measurement", &ast.StringLiteral{Value: "count"}),
+		},
+	}))
+	return metaLimit(expr, stmt.Limit, stmt.Offset), nil
+}
+
+// metaCursor resolves the variable references within the condition of a meta query.
+// Every reference is a tag except for the special _name reference to the measurement.
+type metaCursor struct{}
+
+func (metaCursor) Expr() ast.Expression {
+	panic("unimplemented")
+}
+
+func (metaCursor) Keys() []influxql.Expr {
+	panic("unimplemented")
+}
+
+func (metaCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	if ref.Val == "_name" {
+		return "_measurement", true
+	}
+	return ref.Val, true
+}
+
+// pipe pipes the expression into a call to the named function with the given arguments.
+func pipe(arg ast.Expression, name string, args ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{
+		Callee: &ast.Identifier{Name: name},
+	}
+	if len(args) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{Properties: args},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: arg,
+		Call:     call,
+	}
+}
+
+func property(key string, value ast.Expression) *ast.Property {
+	return &ast.Property{
+		Key:   &ast.Identifier{Name: key},
+		Value: value,
+	}
+}
+
+func member(object, property string) *ast.MemberExpression {
+	return &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: object},
+		Property: &ast.Identifier{Name: property},
+	}
+}
+
+func predicate(body ast.Expression) *ast.FunctionExpression {
+	return &ast.FunctionExpression{
+		Params: []*ast.Property{{
+			Key: &ast.Identifier{Name: "r"},
+		}},
+		Body: body,
+	}
+}
+
+func stringArray(values ...string) *ast.ArrayExpression {
+	elements := make([]ast.Expression, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, &ast.StringLiteral{Value: v})
+	}
+	return &ast.ArrayExpression{Elements: elements}
+}
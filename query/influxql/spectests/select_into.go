@@ -0,0 +1,52 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT value INTO db0..cpu_copy FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "_stop", "_field"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "_time", "_value"])
+	|> rename(columns: {_value: "value"})
+	|> set(key: "_measurement", value: "cpu_copy")
+	|> `+fmt.Sprintf(`to(bucketID: "%s"`, bucketID.String())+`, tagColumns: [], fieldFn: (r) => ({"value": r["value"]}))
+	|> group()
+	|> count(column: "_time")
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, written: r._time}))
+	|> set(key: "_measurement", value: "result")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT mean(value) INTO db0..cpu_mean FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(5m), host`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "_stop", "_field", "host"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "host", "_time", "_value"])
+	|> window(every: 5m)
+	|> mean()
+	|> map(fn: (r) => ({r with _time: r._start}))
+	|> window(every: inf)
+	|> rename(columns: {_value: "mean"})
+	|> set(key: "_measurement", value: "cpu_mean")
+	|> `+fmt.Sprintf(`to(bucketID: "%s"`, bucketID.String())+`, tagColumns: ["host"], fieldFn: (r) => ({"mean": r["mean"]}))
+	|> group()
+	|> count(column: "_time")
+	|> map(fn: (r) => ({_time: 1970-01-01T00:00:00Z, written: r._time}))
+	|> set(key: "_measurement", value: "result")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
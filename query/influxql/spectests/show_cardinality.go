@@ -0,0 +1,36 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENT CARDINALITY ON db0`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> count()
+	|> rename(columns: {_value: "count"})
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SHOW SERIES CARDINALITY ON db0 FROM cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> first()
+	|> drop(columns: ["_start", "_stop", "_field", "_time", "_value"])
+	|> limit(n: 1)
+	|> group()
+	|> count(column: "_measurement")
+	|> rename(columns: {_measurement: "count"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
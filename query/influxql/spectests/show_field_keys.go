@@ -0,0 +1,21 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW FIELD KEYS ON db0 FROM cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> keep(columns: ["_measurement", "_field"])
+	|> group(columns: ["_measurement"], mode: "by")
+	|> distinct(column: "_field")
+	|> sort()
+	|> rename(columns: {_value: "fieldKey"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,21 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW SERIES ON db0 FROM cpu WHERE host = 'server01'`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> filter(fn: (r) => r["host"] == "server01")
+	|> first()
+	|> drop(columns: ["_start", "_stop", "_field", "_time", "_value"])
+	|> limit(n: 1)
+	|> duplicate(column: "_measurement", as: "key")
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,40 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT top(value, 3) FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "_stop", "_field"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "_time", "_value"])
+	|> top(n: 3)
+	|> sort(columns: ["_time"])
+	|> rename(columns: {_value: "top"})
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT bottom(value, host, 2) FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "_stop", "_field", "host"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "host", "_time", "_value"])
+	|> min()
+	|> group(columns: ["_measurement", "_start", "_stop", "_field"], mode: "by")
+	|> bottom(n: 2)
+	|> sort(columns: ["_time"])
+	|> rename(columns: {_value: "bottom"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
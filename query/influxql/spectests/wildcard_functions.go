@@ -0,0 +1,43 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		AggregateTest(func(name string) (stmt, want string) {
+			return fmt.Sprintf(`SELECT %s(*) FROM db0..cpu`, name),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> group(columns: ["_measurement", "_start", "_stop", "_field"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "_time", "_value"])
+	|> ` + name + `()
+	|> map(fn: (r) => ({r with _time: 1970-01-01T00:00:00Z}))
+	|> group(columns: ["_field"], mode: "except")
+	|> map(fn: (r) => ({r with _field: "` + name + `_" + r._field}))
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> yield(name: "0")
+`
+		}),
+		SelectorTest(func(name string) (stmt, want string) {
+			return fmt.Sprintf(`SELECT %s(/val/) FROM db0..cpu`, name),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String()) + `
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field =~ /val/)
+	|> group(columns: ["_measurement", "_start", "_stop", "_field"], mode: "by")
+	|> keep(columns: ["_measurement", "_start", "_stop", "_field", "_time", "_value"])
+	|> ` + name + `()
+	|> drop(columns: ["_time"])
+	|> map(fn: (r) => ({r with _time: 1970-01-01T00:00:00Z}))
+	|> group(columns: ["_field"], mode: "except")
+	|> map(fn: (r) => ({r with _field: "` + name + `_" + r._field}))
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> yield(name: "0")
+`
+		}),
+	)
+}
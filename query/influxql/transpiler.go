@@ -3,12 +3,14 @@ package influxql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxql"
 )
@@ -123,6 +125,18 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 		return t.transpileShowDatabases(ctx, stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
 		return t.transpileShowRetentionPolicies(ctx, stmt)
+	case *influxql.ShowMeasurementsStatement:
+		return t.transpileShowMeasurements(ctx, stmt)
+	case *influxql.ShowFieldKeysStatement:
+		return t.transpileShowFieldKeys(ctx, stmt)
+	case *influxql.ShowTagKeysStatement:
+		return t.transpileShowTagKeys(ctx, stmt)
+	case *influxql.ShowSeriesStatement:
+		return t.transpileShowSeries(ctx, stmt)
+	case *influxql.ShowMeasurementCardinalityStatement:
+		return t.transpileShowMeasurementCardinality(ctx, stmt)
+	case *influxql.ShowSeriesCardinalityStatement:
+		return t.transpileShowSeriesCardinality(ctx, stmt)
 	default:
 		return nil, fmt.Errorf("unknown statement type %T", s)
 	}
@@ -131,111 +145,18 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *influxql.ShowTagValuesStatement) (ast.Expression, error) {
 	// While the ShowTagValuesStatement contains a sources section and those sources are measurements, they do
 	// not actually contain the database and we do not factor in retention policies. So we are always going to use
-	// the default retention policy when evaluating which bucket we are querying and we do not have to consult
-	// the sources in the statement.
-	if stmt.Database == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errDatabaseNameRequired
-		}
-		stmt.Database = t.config.DefaultDatabase
-	}
-
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
+	// the default retention policy when evaluating which bucket we are querying.
+	expr, err := t.metaSource(stmt.Database)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
-	// urgent to implement this functionality so we can use the default range.
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "range",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DurationLiteral{
-								Values: []ast.Duration{{
-									Magnitude: -1,
-									Unit:      "h",
-								}},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// If we have a list of sources, look through it and add each of the measurement names.
-	measurementNames := make([]string, 0, len(stmt.Sources))
-	for _, source := range stmt.Sources {
-		mm := source.(*influxql.Measurement)
-		measurementNames = append(measurementNames, mm.Name)
-	}
-
-	if len(measurementNames) > 0 {
-		var filterExpr ast.Expression = &ast.BinaryExpression{
-			Operator: ast.EqualOperator,
-			Left: &ast.MemberExpression{
-				Object:   &ast.Identifier{Name: "r"},
-				Property: &ast.Identifier{Name: "_measurement"},
-			},
-			Right: &ast.StringLiteral{
-				Value: measurementNames[len(measurementNames)-1],
-			},
-		}
-		for i := len(measurementNames) - 2; i >= 0; i-- {
-			filterExpr = &ast.LogicalExpression{
-				Operator: ast.OrOperator,
-				Left: &ast.BinaryExpression{
-					Operator: ast.EqualOperator,
-					Left: &ast.MemberExpression{
-						Object:   &ast.Identifier{Name: "r"},
-						Property: &ast.Identifier{Name: "_measurement"},
-					},
-					Right: &ast.StringLiteral{
-						Value: measurementNames[i],
-					},
-				},
-				Right: filterExpr,
-			}
-		}
-		expr = &ast.PipeExpression{
-			Argument: expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "filter",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{Name: "fn"},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{
-										{
-											Key: &ast.Identifier{Name: "r"},
-										},
-									},
-									Body: filterExpr,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
+	// If we have a list of sources, filter to each of the measurements and
+	// then apply the condition filter for the where clause.
+	if expr, err = t.metaFilter(expr, stmt.Sources, stmt.Condition); err != nil {
+		return nil, err
 	}
 
-	// TODO(jsternberg): Add the condition filter for the where clause.
-
 	// Create the key values op spec from the
 	var keyColumns []ast.Expression
 	switch expr := stmt.TagKeyExpr.(type) {
@@ -646,109 +567,183 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 	if err != nil {
 		return nil, err
 	}
+
+	// Write the results to the target measurement when using SELECT INTO.
+	if t.stmt.Target != nil {
+		return t.into(cur)
+	}
 	return cur, nil
 }
 
+// into writes the selected columns to the target of the select statement as fields
+// and returns the number of points that were written in the same way as influxdb 1.x.
+func (t *transpilerState) into(in cursor) (cursor, error) {
+	target := t.stmt.Target.Measurement
+	bucket, err := t.bucket(target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every column that was selected is written as a field.
+	var fields []*ast.Property
+	for i, column := range t.stmt.ColumnNames() {
+		switch expr := t.stmt.Fields[i].Expr.(type) {
+		case *influxql.VarRef:
+			if expr.Val == "time" {
+				continue
+			}
+		case *influxql.Call:
+			if isWildcard(expr) {
+				return nil, errors.New("unimplemented: wildcard function with SELECT INTO")
+			}
+		}
+		fields = append(fields, &ast.Property{
+			Key: &ast.StringLiteral{Value: column},
+			Value: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "r"},
+				Property: &ast.StringLiteral{Value: column},
+			},
+		})
+	}
+
+	// The tags in the GROUP BY are kept as tags.
+	var tags []string
+	for _, d := range t.stmt.Dimensions {
+		if ref, ok := d.Expr.(*influxql.VarRef); ok {
+			tags = append(tags, ref.Val)
+		}
+	}
+
+	expr := in.Expr()
+	if target.Name != "" {
+		expr = pipe(expr, "set",
+			property("key", &ast.StringLiteral{Value: "_measurement"}),
+			property("value", &ast.StringLiteral{Value: target.Name}),
+		)
+	}
+	expr = pipe(expr, "to",
+		bucket,
+		property("tagColumns", stringArray(tags...)),
+		property("fieldFn", predicate(&ast.ObjectExpression{Properties: fields})),
+	)
+
+	// Count the points that were written and report them at the epoch.
+	expr = pipe(pipe(expr, "group"), "count",
+		property("column", &ast.StringLiteral{Value: execute.DefaultTimeColLabel}),
+	)
+	var written ast.Expression = member("r", execute.DefaultTimeColLabel)
+	if len(fields) > 1 {
+		// Each row is written as one point for every field.
+		written = &ast.BinaryExpression{
+			Operator: ast.MultiplicationOperator,
+			Left:     written,
+			Right:    &ast.IntegerLiteral{Value: int64(len(fields))},
+		}
+	}
+	expr = pipe(expr, "map",
+		property("fn", predicate(&ast.ObjectExpression{
+			Properties: []*ast.Property{
+				property(execute.DefaultTimeColLabel, &ast.DateTimeLiteral{Value: time.Unix(0, 0).UTC()}),
+				property("written", written),
+			},
+		})),
+	)
+	return &mapCursor{expr: metaName(expr, "result")}, nil
+}
+
 func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
 	// TODO(jsternberg): Actually evaluate the type against the schema.
 	return influxql.Tag
 }
 
 func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error) {
-	var args []ast.Expression
+	bucket, err := t.bucket(m)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: "from",
+		},
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: []*ast.Property{bucket},
+			},
+		},
+	}, nil
+}
+
+// bucket returns the property that identifies the bucket for the database
+// and retention policy of the measurement.
+func (t *transpilerState) bucket(m *influxql.Measurement) (*ast.Property, error) {
 	// Use the bucket inteasd of dbrp mapping if it exists.
 	if t.config.Bucket != "" {
-		args = []ast.Expression{
-			&ast.ObjectExpression{
-				Properties: []*ast.Property{
-					{
-						Key: &ast.Identifier{
-							Name: "bucket",
-						},
-						Value: &ast.StringLiteral{
-							Value: t.config.Bucket,
-						},
-					},
-				},
+		return &ast.Property{
+			Key: &ast.Identifier{
+				Name: "bucket",
+			},
+			Value: &ast.StringLiteral{
+				Value: t.config.Bucket,
 			},
+		}, nil
+	}
+
+	if t.dbrpMappingSvc == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to transpile: db and rp mappings need to be created by some way",
 		}
-	} else {
-		if t.dbrpMappingSvc == nil {
+	}
+	db, rp := m.Database, m.RetentionPolicy
+	if db == "" {
+		if t.config.DefaultDatabase == "" {
 			return nil, &influxdb.Error{
-				Code: influxdb.EInternal,
-				Msg:  "unable to transpile: db and rp mappings need to be created by some way",
-			}
-		}
-		db, rp := m.Database, m.RetentionPolicy
-		if db == "" {
-			if t.config.DefaultDatabase == "" {
-				return nil, &influxdb.Error{
-					Code: influxdb.EInvalid,
-					Msg:  "unable to transpile: database is required",
-				}
+				Code: influxdb.EInvalid,
+				Msg:  "unable to transpile: database is required",
 			}
-			db = t.config.DefaultDatabase
 		}
-		if rp == "" {
-			if t.config.DefaultRetentionPolicy != "" {
-				rp = t.config.DefaultRetentionPolicy
-			}
+		db = t.config.DefaultDatabase
+	}
+	if rp == "" {
+		if t.config.DefaultRetentionPolicy != "" {
+			rp = t.config.DefaultRetentionPolicy
 		}
+	}
 
-		var filter influxdb.DBRPMappingFilter
-		filter.Cluster = &t.config.Cluster
-		if db != "" {
-			filter.Database = &db
-		}
-		if rp != "" {
-			filter.RetentionPolicy = &rp
-		}
-		defaultRP := rp == ""
-		filter.Default = &defaultRP
-		mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
-		if err != nil {
-			if !t.config.FallbackToDBRP {
-				return nil, err
-			}
-			// use `db/rp` naming convention
-			args = []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "bucket",
-							},
-							Value: &ast.StringLiteral{
-								Value: fmt.Sprintf("%s/%s", db, rp),
-							},
-						},
-					},
-				},
-			}
-		} else {
-			// use mapping bucket id
-			args = []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "bucketID",
-							},
-							Value: &ast.StringLiteral{
-								Value: mapping.BucketID.String(),
-							},
-						},
-					},
-				},
-			}
+	var filter influxdb.DBRPMappingFilter
+	filter.Cluster = &t.config.Cluster
+	if db != "" {
+		filter.Database = &db
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	defaultRP := rp == ""
+	filter.Default = &defaultRP
+	mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
+	if err != nil {
+		if !t.config.FallbackToDBRP {
+			return nil, err
 		}
+		// use `db/rp` naming convention
+		return &ast.Property{
+			Key: &ast.Identifier{
+				Name: "bucket",
+			},
+			Value: &ast.StringLiteral{
+				Value: fmt.Sprintf("%s/%s", db, rp),
+			},
+		}, nil
 	}
 
-	return &ast.CallExpression{
-		Callee: &ast.Identifier{
-			Name: "from",
+	// use mapping bucket id
+	return &ast.Property{
+		Key: &ast.Identifier{
+			Name: "bucketID",
+		},
+		Value: &ast.StringLiteral{
+			Value: mapping.BucketID.String(),
 		},
-		Arguments: args,
 	}, nil
 }
 
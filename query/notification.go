@@ -0,0 +1,21 @@
+package query
+
+import (
+	"context"
+)
+
+type notificationRuleContextKey struct{}
+
+// ContextWithNotificationRule returns a new context whose queries are those of
+// the task of a notification rule. Only these queries may send notifications
+// through protocols other than HTTP, e.g. email through smtp:// urls.
+func ContextWithNotificationRule(ctx context.Context) context.Context {
+	return context.WithValue(ctx, notificationRuleContextKey{}, true)
+}
+
+// IsNotificationRule returns true if the queries made with ctx are those of the
+// task of a notification rule.
+func IsNotificationRule(ctx context.Context) bool {
+	ok, _ := ctx.Value(notificationRuleContextKey{}).(bool)
+	return ok
+}
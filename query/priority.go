@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+)
+
+// Priority is the class of a query. The query controller keeps a separate
+// queue for each class and may reserve concurrency and memory for it.
+type Priority string
+
+const (
+	// PriorityInteractive is the class of queries made by users, e.g.
+	// through the HTTP API by dashboards.
+	PriorityInteractive Priority = "interactive"
+	// PriorityTask is the class of queries run by tasks.
+	PriorityTask Priority = "task"
+	// PriorityCheck is the class of queries run by the tasks of checks.
+	PriorityCheck Priority = "check"
+	// PrioritySystem is the class of queries made by influxdb itself. It is
+	// the class of any query whose context has no priority.
+	PrioritySystem Priority = "system"
+)
+
+// Priorities lists the priority classes, from highest to lowest.
+var Priorities = []Priority{
+	PrioritySystem,
+	PriorityInteractive,
+	PriorityCheck,
+	PriorityTask,
+}
+
+// Valid returns true if p is one of the priority classes.
+func (p Priority) Valid() bool {
+	for _, pp := range Priorities {
+		if p == pp {
+			return true
+		}
+	}
+	return false
+}
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a new context whose queries run with priority p.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// PriorityFromContext returns the priority of the queries made with ctx,
+// which is PrioritySystem unless it has been set with ContextWithPriority.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return p
+	}
+	return PrioritySystem
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb/v2"
+)
+
+// ScannedValuesMetadataKey is the key of the flux statistics metadata
+// counting the values the storage sources of a query read.
+const ScannedValuesMetadataKey = "influxdb/scanned-values"
+
+// WriteStatistics counts the points a query writes, by bucket.
+// It is safe for concurrent use.
+type WriteStatistics struct {
+	mu     sync.Mutex
+	points map[platform.ID]int64
+}
+
+// NewWriteStatistics returns empty write statistics.
+func NewWriteStatistics() *WriteStatistics {
+	return &WriteStatistics{points: make(map[platform.ID]int64)}
+}
+
+// Add counts n points written to the bucket bucketID.
+func (s *WriteStatistics) Add(bucketID platform.ID, n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.points[bucketID] += n
+}
+
+// PointsWritten returns the number of points written, by bucket.
+func (s *WriteStatistics) PointsWritten() map[platform.ID]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	points := make(map[platform.ID]int64, len(s.points))
+	for id, n := range s.points {
+		points[id] = n
+	}
+	return points
+}
+
+type writeStatisticsContextKey struct{}
+
+// ContextWithWriteStatistics returns a new context whose queries count the
+// points they write in s.
+func ContextWithWriteStatistics(ctx context.Context, s *WriteStatistics) context.Context {
+	return context.WithValue(ctx, writeStatisticsContextKey{}, s)
+}
+
+// WriteStatisticsFromContext returns the write statistics of the queries made
+// with ctx, or nil if they are not counted.
+func WriteStatisticsFromContext(ctx context.Context) *WriteStatistics {
+	s, _ := ctx.Value(writeStatisticsContextKey{}).(*WriteStatistics)
+	return s
+}
+
+// ScannedValues returns the number of values the storage sources of a query
+// read, according to the metadata of its statistics.
+func ScannedValues(stats flux.Statistics) int64 {
+	var n int64
+	for _, v := range stats.Metadata[ScannedValuesMetadataKey] {
+		switch v := v.(type) {
+		case int64:
+			n += v
+		case int:
+			n += int64(v)
+		}
+	}
+	return n
+}
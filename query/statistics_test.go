@@ -0,0 +1,109 @@
+package query_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+func TestWriteStatistics(t *testing.T) {
+	if ws := query.WriteStatisticsFromContext(context.Background()); ws != nil {
+		t.Fatalf("expected no write statistics, got %v", ws)
+	}
+
+	ws := query.NewWriteStatistics()
+	ctx := query.ContextWithWriteStatistics(context.Background(), ws)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			query.WriteStatisticsFromContext(ctx).Add(platform.ID(1+i%2), 5)
+		}(i)
+	}
+	wg.Wait()
+
+	exp := map[platform.ID]int64{1: 25, 2: 25}
+	if diff := cmp.Diff(exp, ws.PointsWritten()); diff != "" {
+		t.Fatalf("unexpected points written -want/+got:\n%s", diff)
+	}
+}
+
+func TestWriteRecorder(t *testing.T) {
+	if r := query.WriteRecorderFromContext(context.Background()); r != nil {
+		t.Fatalf("expected no write recorder, got %v", r)
+	}
+
+	points := []models.Point{
+		models.MustNewPoint("cpu", nil, models.Fields{"usage": 0.5}, time.Unix(0, 0)),
+		models.MustNewPoint("cpu", nil, models.Fields{"usage": 0.7}, time.Unix(1, 0)),
+		models.MustNewPoint("cpu", nil, models.Fields{"usage": 0.9}, time.Unix(2, 0)),
+	}
+	tests := []struct {
+		name          string
+		max           int
+		want          []models.Point
+		wantTruncated bool
+	}{
+		{
+			name: "all points",
+			max:  3,
+			want: points,
+		},
+		{
+			name:          "truncated",
+			max:           2,
+			want:          points[:2],
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := query.NewWriteRecorder(tt.max)
+			ctx := query.ContextWithWriteRecorder(context.Background(), r)
+
+			for _, p := range points {
+				if err := query.WriteRecorderFromContext(ctx).WritePoints(ctx, []models.Point{p}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := r.Points()
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d points recorded, got %d", len(tt.want), len(got))
+			}
+			for i := range tt.want {
+				if got[i].String() != tt.want[i].String() {
+					t.Fatalf("unexpected point %d: got %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+			if got := r.Truncated(); got != tt.wantTruncated {
+				t.Fatalf("unexpected truncated: got %t, want %t", got, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestScannedValues(t *testing.T) {
+	stats := flux.Statistics{
+		Metadata: flux.Metadata{
+			query.ScannedValuesMetadataKey: []interface{}{10, int64(32)},
+			"influxdb/scanned-bytes":       []interface{}{1024},
+		},
+	}
+	if got := query.ScannedValues(stats); got != 42 {
+		t.Fatalf("expected 42 scanned values, got %d", got)
+	}
+	if got := query.ScannedValues(flux.Statistics{}); got != 0 {
+		t.Fatalf("expected no scanned values, got %d", got)
+	}
+}
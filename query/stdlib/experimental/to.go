@@ -165,6 +165,10 @@ func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode,
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	deps := influxdb.GetStorageDependencies(a.Context()).ToDeps
+	// dry runs record the points instead of writing them.
+	if r := query.WriteRecorderFromContext(a.Context()); r != nil {
+		deps.PointsWriter = r
+	}
 
 	t, err := NewToTransformation(a.Context(), d, cache, s, deps)
 	if err != nil {
@@ -2,15 +2,21 @@ package influxdb
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/influxdata/flux"
+	fluxhttp "github.com/influxdata/flux/dependencies/http"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/prom"
+	"github.com/influxdata/influxdb/v2/notification/smtp"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// maxResponseBody matches the response size limit of the default flux http client.
+const maxResponseBody = 100 * 1024 * 1024
+
 type key int
 
 const dependenciesKey key = iota
@@ -73,6 +79,7 @@ func NewDependencies(
 	metricLabelKeys []string,
 ) (Dependencies, error) {
 	fdeps := flux.NewDefaultDependencies()
+	fdeps.Deps.HTTPClient = newHTTPClient(fdeps)
 	fdeps.Deps.SecretService = query.FromSecretService(ss)
 	deps := Dependencies{FluxDeps: fdeps}
 	bucketLookupSvc := query.FromBucketService(bucketSvc)
@@ -98,3 +105,30 @@ func NewDependencies(
 	}
 	return deps, nil
 }
+
+// newHTTPClient returns the client used by the flux http package. On top of the
+// flux defaults, the queries of notification rule tasks can send email through
+// smtp:// and smtps:// urls, which notification rules for SMTP endpoints use.
+func newHTTPClient(deps flux.Deps) fluxhttp.Client {
+	client := fluxhttp.NewDefaultClient(deps.Deps.URLValidator)
+	notification := fluxhttp.NewDefaultClient(deps.Deps.URLValidator)
+	smtp.RegisterProtocols(notification.Transport.(*http.Transport), deps.Deps.URLValidator)
+	return httpClient{
+		query:        fluxhttp.LimitHTTPBody(*client, maxResponseBody),
+		notification: fluxhttp.LimitHTTPBody(*notification, maxResponseBody),
+	}
+}
+
+// httpClient sends the requests of the queries of notification rule tasks with
+// the notification client, and those of any other query with the query client.
+type httpClient struct {
+	query        fluxhttp.Client
+	notification fluxhttp.Client
+}
+
+func (c httpClient) Do(req *http.Request) (*http.Response, error) {
+	if query.IsNotificationRule(req.Context()) {
+		return c.notification.Do(req)
+	}
+	return c.query.Do(req)
+}
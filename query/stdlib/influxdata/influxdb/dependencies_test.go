@@ -0,0 +1,51 @@
+package influxdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+func TestHTTPClient_SMTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{
+			name:    "query",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+		{
+			name: "notification rule",
+			ctx:  query.ContextWithNotificationRule(context.Background()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHTTPClient(flux.NewDefaultDependencies())
+
+			// the smtp round tripper answers GET requests without dialing
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, "smtp://smtp.example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusMethodNotAllowed {
+				t.Errorf("unexpected status: %s", resp.Status)
+			}
+		})
+	}
+}
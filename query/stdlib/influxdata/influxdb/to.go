@@ -242,6 +242,10 @@ func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode,
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	deps := GetStorageDependencies(a.Context()).ToDeps
+	// dry runs record the points instead of writing them.
+	if r := query.WriteRecorderFromContext(a.Context()); r != nil {
+		deps.PointsWriter = r
+	}
 	t, err := NewToTransformation(a.Context(), d, cache, s, deps)
 	if err != nil {
 		return nil, nil, err
@@ -681,7 +685,13 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			}
 		}
 
-		return t.buf.WritePoints(ctx, points)
+		if err := t.buf.WritePoints(ctx, points); err != nil {
+			return err
+		}
+		if ws := query.WriteStatisticsFromContext(ctx); ws != nil {
+			ws.Add(t.BucketID, int64(len(points)))
+		}
+		return nil
 	})
 }
 
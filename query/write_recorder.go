@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+// WriteRecorder records the points a query writes with to() in place of the
+// storage engine, so that a query can be executed without writing data.
+// It is safe for concurrent use.
+type WriteRecorder struct {
+	mu        sync.Mutex
+	max       int
+	points    []models.Point
+	truncated bool
+}
+
+// NewWriteRecorder returns a recorder without points, which records the first
+// max points written and drops the others.
+func NewWriteRecorder(max int) *WriteRecorder {
+	return &WriteRecorder{max: max}
+}
+
+// WritePoints records points, up to the maximum of the recorder.
+func (r *WriteRecorder) WritePoints(_ context.Context, points []models.Point) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n := r.max - len(r.points); len(points) > n {
+		points = points[:n]
+		r.truncated = true
+	}
+	// the writers of to() reuse the slice once written, not the points.
+	r.points = append(r.points, points...)
+	return nil
+}
+
+// Truncated returns true if points were dropped because more than the
+// maximum of the recorder were written.
+func (r *WriteRecorder) Truncated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.truncated
+}
+
+// Points returns the points recorded, in the order they were written.
+func (r *WriteRecorder) Points() []models.Point {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	points := make([]models.Point, len(r.points))
+	copy(points, r.points)
+	return points
+}
+
+type writeRecorderContextKey struct{}
+
+// ContextWithWriteRecorder returns a new context whose queries write to r
+// instead of the storage engine.
+func ContextWithWriteRecorder(ctx context.Context, r *WriteRecorder) context.Context {
+	return context.WithValue(ctx, writeRecorderContextKey{}, r)
+}
+
+// WriteRecorderFromContext returns the recorder the queries made with ctx
+// write to, or nil if they write to the storage engine.
+func WriteRecorderFromContext(ctx context.Context) *WriteRecorder {
+	r, _ := ctx.Value(writeRecorderContextKey{}).(*WriteRecorder)
+	return r
+}
@@ -0,0 +1,111 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ErrQuotaNotFound is the error message for a missing quota.
+const ErrQuotaNotFound = "quota not found"
+
+// ops for quotas.
+const (
+	OpFindQuotaByID = "FindQuotaByID"
+	OpFindQuotas    = "FindQuotas"
+	OpCreateQuota   = "CreateQuota"
+	OpUpdateQuota   = "UpdateQuota"
+	OpDeleteQuota   = "DeleteQuota"
+)
+
+// Quota limits the writes and queries of an organization or, when
+// AuthorizationID is set, of the requests made with a single authorization.
+// A zero limit is unlimited.
+type Quota struct {
+	ID              ID  `json:"id,omitempty"`
+	OrgID           ID  `json:"orgID"`
+	AuthorizationID *ID `json:"authorizationID,omitempty"`
+
+	// PointsPerSecond is the number of points that may be written per second.
+	PointsPerSecond int64 `json:"pointsPerSecond"`
+	// BytesPerSecond is the number of request bytes that may be written per second.
+	BytesPerSecond int64 `json:"bytesPerSecond"`
+	// ConcurrentQueries is the number of queries that may run at the same time.
+	ConcurrentQueries int64 `json:"concurrentQueries"`
+	// QueriesPerMinute is the number of queries that may be started per minute.
+	QueriesPerMinute int64 `json:"queriesPerMinute"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the quota is missing its organization or has a
+// negative limit.
+func (q *Quota) Valid() error {
+	if !q.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "quota requires a valid orgID",
+		}
+	}
+	if q.AuthorizationID != nil && !q.AuthorizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "quota authorizationID is invalid",
+		}
+	}
+	if q.PointsPerSecond < 0 || q.BytesPerSecond < 0 || q.ConcurrentQueries < 0 || q.QueriesPerMinute < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "quota limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// QuotaUpdate is the set of limits to change on a quota.
+type QuotaUpdate struct {
+	PointsPerSecond   *int64 `json:"pointsPerSecond,omitempty"`
+	BytesPerSecond    *int64 `json:"bytesPerSecond,omitempty"`
+	ConcurrentQueries *int64 `json:"concurrentQueries,omitempty"`
+	QueriesPerMinute  *int64 `json:"queriesPerMinute,omitempty"`
+}
+
+// Apply applies the update to q.
+func (u QuotaUpdate) Apply(q *Quota) {
+	if u.PointsPerSecond != nil {
+		q.PointsPerSecond = *u.PointsPerSecond
+	}
+	if u.BytesPerSecond != nil {
+		q.BytesPerSecond = *u.BytesPerSecond
+	}
+	if u.ConcurrentQueries != nil {
+		q.ConcurrentQueries = *u.ConcurrentQueries
+	}
+	if u.QueriesPerMinute != nil {
+		q.QueriesPerMinute = *u.QueriesPerMinute
+	}
+}
+
+// QuotaFilter represents a set of filters that restrict the returned quotas.
+type QuotaFilter struct {
+	OrgID           *ID
+	AuthorizationID *ID
+}
+
+// QuotaService manages the write and query quotas of organizations and
+// authorizations.
+type QuotaService interface {
+	// FindQuotaByID returns a single quota by ID.
+	FindQuotaByID(ctx context.Context, id ID) (*Quota, error)
+
+	// FindQuotas returns the quotas matching filter.
+	FindQuotas(ctx context.Context, filter QuotaFilter) ([]*Quota, error)
+
+	// CreateQuota creates a quota and sets its ID. An organization or
+	// authorization has at most one quota.
+	CreateQuota(ctx context.Context, q *Quota) error
+
+	// UpdateQuota updates the limits of a quota.
+	UpdateQuota(ctx context.Context, id ID, upd QuotaUpdate) (*Quota, error)
+
+	// DeleteQuota removes a quota.
+	DeleteQuota(ctx context.Context, id ID) error
+}
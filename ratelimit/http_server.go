@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// PrefixQuotas is the route prefix of the quota API.
+const PrefixQuotas = "/api/v2/quotas"
+
+// QuotaHandler serves the quota API.
+type QuotaHandler struct {
+	chi.Router
+	api      *kithttp.API
+	log      *zap.Logger
+	quotaSvc influxdb.QuotaService
+}
+
+// Prefix provides the route prefix.
+func (h *QuotaHandler) Prefix() string {
+	return PrefixQuotas
+}
+
+// NewHTTPQuotaHandler returns a handler serving the quotas of qs.
+func NewHTTPQuotaHandler(log *zap.Logger, qs influxdb.QuotaService) *QuotaHandler {
+	h := &QuotaHandler{
+		api:      kithttp.NewAPI(kithttp.WithLog(log)),
+		log:      log,
+		quotaSvc: qs,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Post("/", h.handlePostQuota)
+		r.Get("/", h.handleGetQuotas)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.handleGetQuota)
+			r.Patch("/", h.handlePatchQuota)
+			r.Delete("/", h.handleDeleteQuota)
+		})
+	})
+
+	h.Router = r
+	return h
+}
+
+type quotaResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Quota
+}
+
+func newQuotaResponse(q *influxdb.Quota) *quotaResponse {
+	return &quotaResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("%s/%s", PrefixQuotas, q.ID),
+		},
+		Quota: *q,
+	}
+}
+
+type quotasResponse struct {
+	Links  map[string]string `json:"links"`
+	Quotas []*quotaResponse  `json:"quotas"`
+}
+
+func newQuotasResponse(qs []*influxdb.Quota) *quotasResponse {
+	res := &quotasResponse{
+		Links: map[string]string{
+			"self": PrefixQuotas,
+		},
+		Quotas: make([]*quotaResponse, 0, len(qs)),
+	}
+	for _, q := range qs {
+		res.Quotas = append(res.Quotas, newQuotaResponse(q))
+	}
+	return res
+}
+
+// handlePostQuota is the HTTP handler for the POST /api/v2/quotas route.
+func (h *QuotaHandler) handlePostQuota(w http.ResponseWriter, r *http.Request) {
+	var q influxdb.Quota
+	if err := h.api.DecodeJSON(r.Body, &q); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := q.Valid(); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.quotaSvc.CreateQuota(r.Context(), &q); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quota created", zap.String("quota", fmt.Sprint(q)))
+
+	h.api.Respond(w, r, http.StatusCreated, newQuotaResponse(&q))
+}
+
+// handleGetQuota is the HTTP handler for the GET /api/v2/quotas/:id route.
+func (h *QuotaHandler) handleGetQuota(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	q, err := h.quotaSvc.FindQuotaByID(r.Context(), *id)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, newQuotaResponse(q))
+}
+
+// handleGetQuotas is the HTTP handler for the GET /api/v2/quotas route.
+func (h *QuotaHandler) handleGetQuotas(w http.ResponseWriter, r *http.Request) {
+	var filter influxdb.QuotaFilter
+	qp := r.URL.Query()
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.OrgID = id
+	}
+
+	if authID := qp.Get("authorizationID"); authID != "" {
+		id, err := influxdb.IDFromString(authID)
+		if err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+		filter.AuthorizationID = id
+	}
+
+	qs, err := h.quotaSvc.FindQuotas(r.Context(), filter)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, newQuotasResponse(qs))
+}
+
+// handlePatchQuota is the HTTP handler for the PATCH /api/v2/quotas/:id route.
+func (h *QuotaHandler) handlePatchQuota(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var upd influxdb.QuotaUpdate
+	if err := h.api.DecodeJSON(r.Body, &upd); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	q, err := h.quotaSvc.UpdateQuota(r.Context(), *id, upd)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quota updated", zap.String("quota", fmt.Sprint(q)))
+
+	h.api.Respond(w, r, http.StatusOK, newQuotaResponse(q))
+}
+
+// handleDeleteQuota is the HTTP handler for the DELETE /api/v2/quotas/:id route.
+func (h *QuotaHandler) handleDeleteQuota(w http.ResponseWriter, r *http.Request) {
+	id, err := influxdb.IDFromString(chi.URLParam(r, "id"))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.quotaSvc.DeleteQuota(r.Context(), *id); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	h.log.Debug("Quota deleted", zap.String("quotaID", id.String()))
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
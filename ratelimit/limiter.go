@@ -0,0 +1,291 @@
+// Package ratelimit enforces the write and query quotas of organizations and
+// authorizations.
+//
+// Quotas are token buckets that may go into debt: a request is admitted while
+// the buckets of its quotas hold tokens and is charged for the points and
+// bytes it actually wrote once it is done. A tenant writing large batches is
+// therefore throttled on its following requests rather than rejected outright.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// DefaultRefreshInterval is how often the quotas are reloaded from the
+// quota service.
+const DefaultRefreshInterval = 10 * time.Second
+
+// Limits enforced by the limiter, as reported in errors and metrics.
+const (
+	LimitPointsPerSecond   = "points_per_second"
+	LimitBytesPerSecond    = "bytes_per_second"
+	LimitConcurrentQueries = "concurrent_queries"
+	LimitQueriesPerMinute  = "queries_per_minute"
+)
+
+// LimitedError is returned when a request exceeds a quota.
+type LimitedError struct {
+	// Limit is the exceeded limit, one of the Limit constants.
+	Limit string
+	// RetryAfter is how long to wait before the quota admits requests again.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *LimitedError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s", e.Limit)
+}
+
+// Limiter enforces the quotas of influxdb.QuotaService.
+type Limiter struct {
+	log    *zap.Logger
+	quotas influxdb.QuotaService
+
+	// RefreshInterval is how often quotas are reloaded; changes to quotas
+	// take effect after at most this long.
+	RefreshInterval time.Duration
+	now             func() time.Time
+
+	metrics *metrics
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	byOrg    map[influxdb.ID]*influxdb.Quota
+	byAuth   map[influxdb.ID]*influxdb.Quota
+	states   map[influxdb.ID]*state // by quota ID
+}
+
+// NewLimiter returns a limiter enforcing the quotas of s.
+func NewLimiter(log *zap.Logger, s influxdb.QuotaService) *Limiter {
+	return &Limiter{
+		log:             log,
+		quotas:          s,
+		RefreshInterval: DefaultRefreshInterval,
+		now:             time.Now,
+		metrics:         newMetrics(),
+		states:          map[influxdb.ID]*state{},
+	}
+}
+
+// state is the usage of a single quota.
+type state struct {
+	points, bytes, queries bucket
+	running                int64
+}
+
+// bucket is a token bucket which may go into debt.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill, up to burst.
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+}
+
+// wait returns how long it takes for the bucket to hold need tokens.
+func (b *bucket) wait(need, rate float64) time.Duration {
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / rate * float64(time.Second))
+}
+
+// Write is a write admitted by the limiter. Its usage is charged to its
+// quotas by Finish.
+type Write struct {
+	l      *Limiter
+	orgID  influxdb.ID
+	quotas []*influxdb.Quota
+	usage  usage
+}
+
+// StartWrite admits a write to orgID made with authID, which may be nil. It
+// returns a *LimitedError if the points or bytes quota of the organization or
+// authorization is exhausted.
+func (l *Limiter) StartWrite(ctx context.Context, orgID influxdb.ID, authID *influxdb.ID) (*Write, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	quotas := l.find(ctx, orgID, authID)
+	now := l.now()
+	for _, q := range quotas {
+		st := l.state(q)
+		if q.PointsPerSecond > 0 {
+			rate := float64(q.PointsPerSecond)
+			st.points.refill(now, rate, rate)
+			if d := st.points.wait(1, rate); d > 0 {
+				return nil, l.limited(orgID, LimitPointsPerSecond, d)
+			}
+		}
+		if q.BytesPerSecond > 0 {
+			rate := float64(q.BytesPerSecond)
+			st.bytes.refill(now, rate, rate)
+			if d := st.bytes.wait(1, rate); d > 0 {
+				return nil, l.limited(orgID, LimitBytesPerSecond, d)
+			}
+		}
+	}
+
+	return &Write{l: l, orgID: orgID, quotas: quotas}, nil
+}
+
+// Context returns a context in which the points written through a
+// PointsWriter are counted against the write.
+func (w *Write) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, usageKey, &w.usage)
+}
+
+// Finish charges the points counted by the write and the given number of
+// request bytes to the quotas of the write.
+func (w *Write) Finish(bytes int64) {
+	points := w.usage.load()
+	w.l.metrics.writtenPoints.WithLabelValues(w.orgID.String()).Add(float64(points))
+	w.l.metrics.writtenBytes.WithLabelValues(w.orgID.String()).Add(float64(bytes))
+
+	w.l.mu.Lock()
+	defer w.l.mu.Unlock()
+
+	now := w.l.now()
+	for _, q := range w.quotas {
+		st := w.l.state(q)
+		if q.PointsPerSecond > 0 {
+			rate := float64(q.PointsPerSecond)
+			st.points.refill(now, rate, rate)
+			st.points.tokens -= float64(points)
+		}
+		if q.BytesPerSecond > 0 {
+			rate := float64(q.BytesPerSecond)
+			st.bytes.refill(now, rate, rate)
+			st.bytes.tokens -= float64(bytes)
+		}
+	}
+}
+
+// StartQuery admits a query of orgID made with authID, which may be nil. It
+// returns a *LimitedError if the organization or authorization runs too many
+// queries. The returned function must be called once the query is done.
+func (l *Limiter) StartQuery(ctx context.Context, orgID influxdb.ID, authID *influxdb.ID) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	quotas := l.find(ctx, orgID, authID)
+	now := l.now()
+	for _, q := range quotas {
+		st := l.state(q)
+		if q.ConcurrentQueries > 0 && st.running >= q.ConcurrentQueries {
+			return nil, l.limited(orgID, LimitConcurrentQueries, time.Second)
+		}
+		if q.QueriesPerMinute > 0 {
+			rate := float64(q.QueriesPerMinute) / 60
+			st.queries.refill(now, rate, float64(q.QueriesPerMinute))
+			if d := st.queries.wait(1, rate); d > 0 {
+				return nil, l.limited(orgID, LimitQueriesPerMinute, d)
+			}
+		}
+	}
+
+	for _, q := range quotas {
+		st := l.state(q)
+		st.running++
+		if q.QueriesPerMinute > 0 {
+			st.queries.tokens--
+		}
+	}
+
+	org := orgID.String()
+	l.metrics.queries.WithLabelValues(org).Inc()
+	l.metrics.runningQueries.WithLabelValues(org).Inc()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.metrics.runningQueries.WithLabelValues(org).Dec()
+
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, q := range quotas {
+				l.state(q).running--
+			}
+		})
+	}, nil
+}
+
+func (l *Limiter) limited(orgID influxdb.ID, limit string, retryAfter time.Duration) error {
+	l.metrics.limitedRequests.WithLabelValues(orgID.String(), limit).Inc()
+	return &LimitedError{Limit: limit, RetryAfter: retryAfter}
+}
+
+// find returns the quotas of orgID and authID, reloading the quotas when
+// they are stale. l.mu must be held.
+func (l *Limiter) find(ctx context.Context, orgID influxdb.ID, authID *influxdb.ID) []*influxdb.Quota {
+	if l.byOrg == nil || l.now().Sub(l.loadedAt) >= l.RefreshInterval {
+		l.load(ctx)
+	}
+
+	var quotas []*influxdb.Quota
+	if q, ok := l.byOrg[orgID]; ok {
+		quotas = append(quotas, q)
+	}
+	if authID != nil {
+		if q, ok := l.byAuth[*authID]; ok {
+			quotas = append(quotas, q)
+		}
+	}
+	return quotas
+}
+
+// load reloads the quotas, keeping the previous ones if that fails.
+func (l *Limiter) load(ctx context.Context) {
+	l.loadedAt = l.now()
+
+	quotas, err := l.quotas.FindQuotas(ctx, influxdb.QuotaFilter{})
+	if err != nil {
+		l.log.Error("Failed to load quotas", zap.Error(err))
+		return
+	}
+
+	byOrg := make(map[influxdb.ID]*influxdb.Quota)
+	byAuth := make(map[influxdb.ID]*influxdb.Quota)
+	ids := make(map[influxdb.ID]bool, len(quotas))
+	for _, q := range quotas {
+		ids[q.ID] = true
+		if q.AuthorizationID != nil {
+			byAuth[*q.AuthorizationID] = q
+		} else {
+			byOrg[q.OrgID] = q
+		}
+	}
+	l.byOrg, l.byAuth = byOrg, byAuth
+
+	// forget the usage of deleted quotas, unless queries they admitted
+	// still run.
+	for id, st := range l.states {
+		if !ids[id] && st.running == 0 {
+			delete(l.states, id)
+		}
+	}
+}
+
+// state returns the usage of q. l.mu must be held.
+func (l *Limiter) state(q *influxdb.Quota) *state {
+	st, ok := l.states[q.ID]
+	if !ok {
+		st = &state{}
+		l.states[q.ID] = st
+	}
+	return st
+}
@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+var (
+	orgID  = influxdb.ID(0x1000)
+	authID = influxdb.ID(0x2000)
+)
+
+// newTestLimiter returns a limiter of quotas whose clock is advanced by the
+// returned function.
+func newTestLimiter(t *testing.T, quotas ...*influxdb.Quota) (*Limiter, func(time.Duration)) {
+	s := mock.NewQuotaService()
+	s.FindQuotasF = func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+		return quotas, nil
+	}
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(zaptest.NewLogger(t), s)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func limit(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	le, ok := err.(*LimitedError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return le.Limit
+}
+
+func TestLimiter_Write(t *testing.T) {
+	ctx := context.Background()
+	l, advance := newTestLimiter(t,
+		&influxdb.Quota{ID: 1, OrgID: orgID, PointsPerSecond: 100},
+		&influxdb.Quota{ID: 2, OrgID: orgID, AuthorizationID: &authID, BytesPerSecond: 1000},
+	)
+
+	write := func(auth *influxdb.ID, points, bytes int64) error {
+		w, err := l.StartWrite(ctx, orgID, auth)
+		if err != nil {
+			return err
+		}
+		wctx := w.Context(ctx)
+		u := wctx.Value(usageKey).(*usage)
+		u.points += points
+		w.Finish(bytes)
+		return nil
+	}
+
+	// a write larger than the quota is admitted and puts the quota in debt
+	if err := write(nil, 150, 10); err != nil {
+		t.Fatal(err)
+	}
+	w, err := l.StartWrite(ctx, orgID, nil)
+	if got := limit(t, err); got != LimitPointsPerSecond || w != nil {
+		t.Fatalf("expected the points limit, got %q", got)
+	}
+	if d := err.(*LimitedError).RetryAfter; d != 510*time.Millisecond {
+		t.Fatalf("unexpected retry after %v", d)
+	}
+
+	advance(time.Second)
+	if err := write(&authID, 10, 2000); err != nil {
+		t.Fatal(err)
+	}
+	// the bytes quota applies to the authorization only
+	advance(time.Second)
+	if got := limit(t, write(&authID, 1, 1)); got != LimitBytesPerSecond {
+		t.Fatalf("expected the bytes limit, got %q", got)
+	}
+	if err := write(nil, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// writes to other organizations are not limited
+	if _, err := l.StartWrite(ctx, influxdb.ID(0x3000), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiter_Query(t *testing.T) {
+	ctx := context.Background()
+	l, advance := newTestLimiter(t,
+		&influxdb.Quota{ID: 1, OrgID: orgID, ConcurrentQueries: 2, QueriesPerMinute: 3},
+	)
+
+	done1, err := l.StartQuery(ctx, orgID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done2, err := l.StartQuery(ctx, orgID, &authID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.StartQuery(ctx, orgID, nil); limit(t, err) != LimitConcurrentQueries {
+		t.Fatalf("expected the concurrent queries limit, got %v", err)
+	}
+
+	done1()
+	done1() // done is idempotent
+	done3, err := l.StartQuery(ctx, orgID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done2()
+	done3()
+
+	_, err = l.StartQuery(ctx, orgID, nil)
+	if limit(t, err) != LimitQueriesPerMinute {
+		t.Fatalf("expected the queries per minute limit, got %v", err)
+	}
+	if d := err.(*LimitedError).RetryAfter; d != 20*time.Second {
+		t.Fatalf("unexpected retry after %v", d)
+	}
+
+	advance(20 * time.Second)
+	if _, err := l.StartQuery(ctx, orgID, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiter_Refresh(t *testing.T) {
+	ctx := context.Background()
+	quota := &influxdb.Quota{ID: 1, OrgID: orgID, ConcurrentQueries: 1}
+
+	s := mock.NewQuotaService()
+	var loads int
+	s.FindQuotasF = func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+		loads++
+		if loads > 1 {
+			return nil, nil
+		}
+		return []*influxdb.Quota{quota}, nil
+	}
+	now := time.Now()
+	l := NewLimiter(zaptest.NewLogger(t), s)
+	l.now = func() time.Time { return now }
+
+	done, err := l.StartQuery(ctx, orgID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.StartQuery(ctx, orgID, nil); limit(t, err) != LimitConcurrentQueries {
+		t.Fatalf("expected the concurrent queries limit, got %v", err)
+	}
+
+	// the quota is deleted; it stops applying once the quotas are reloaded
+	now = now.Add(l.RefreshInterval)
+	if _, err := l.StartQuery(ctx, orgID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected quotas to be loaded twice, got %d", loads)
+	}
+	done()
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	limitedRequests *prometheus.CounterVec
+	writtenPoints   *prometheus.CounterVec
+	writtenBytes    *prometheus.CounterVec
+	queries         *prometheus.CounterVec
+	runningQueries  *prometheus.GaugeVec
+}
+
+func newMetrics() *metrics {
+	const namespace = "ratelimit"
+
+	return &metrics{
+		limitedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "limited_requests_total",
+			Help:      "Number of requests rejected because a quota was exceeded, by organization and limit.",
+		}, []string{"org", "limit"}),
+
+		writtenPoints: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "written_points_total",
+			Help:      "Number of points written by admitted writes, by organization.",
+		}, []string{"org"}),
+
+		writtenBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "written_bytes_total",
+			Help:      "Number of request bytes of admitted writes, by organization.",
+		}, []string{"org"}),
+
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queries_total",
+			Help:      "Number of admitted queries, by organization.",
+		}, []string{"org"}),
+
+		runningQueries: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "running_queries",
+			Help:      "Number of admitted queries currently running, by organization.",
+		}, []string{"org"}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (l *Limiter) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		l.metrics.limitedRequests,
+		l.metrics.writtenPoints,
+		l.metrics.writtenBytes,
+		l.metrics.queries,
+		l.metrics.runningQueries,
+	}
+}
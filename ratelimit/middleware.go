@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+)
+
+// Middleware enforces the quotas of a Limiter on HTTP requests. Requests are
+// charged to the organization and authorization of the token they are made
+// with; requests made with a session are charged to the organization named by
+// the org or orgID query parameter.
+type Middleware struct {
+	Limiter             *Limiter
+	OrganizationService influxdb.OrganizationService
+	ErrorHandler        influxdb.HTTPErrorHandler
+}
+
+// Writes returns a middleware enforcing the points and bytes quotas.
+func (m *Middleware) Writes() kithttp.Middleware {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			orgID, authID, ok := m.requester(ctx, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			write, err := m.Limiter.StartWrite(ctx, orgID, authID)
+			if err != nil {
+				m.handleError(ctx, err, w)
+				return
+			}
+
+			body := &countingReader{ReadCloser: r.Body}
+			r.Body = body
+			defer func() {
+				write.Finish(body.count())
+			}()
+
+			next.ServeHTTP(w, r.WithContext(write.Context(ctx)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Queries returns a middleware enforcing the concurrent queries and queries
+// per minute quotas.
+func (m *Middleware) Queries() kithttp.Middleware {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			orgID, authID, ok := m.requester(ctx, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			done, err := m.Limiter.StartQuery(ctx, orgID, authID)
+			if err != nil {
+				m.handleError(ctx, err, w)
+				return
+			}
+			defer done()
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// requester returns the organization and authorization a request is
+// charged to, if any.
+func (m *Middleware) requester(ctx context.Context, r *http.Request) (influxdb.ID, *influxdb.ID, bool) {
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return 0, nil, false
+	}
+	if a, ok := auth.(*influxdb.Authorization); ok {
+		id := a.ID
+		return a.OrgID, &id, true
+	}
+
+	if m.OrganizationService == nil {
+		return 0, nil, false
+	}
+
+	var filter influxdb.OrganizationFilter
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return 0, nil, false
+		}
+		return *id, nil, true
+	} else if org := qp.Get("org"); org != "" {
+		if id, err := influxdb.IDFromString(org); err == nil {
+			return *id, nil, true
+		}
+		filter.Name = &org
+	} else {
+		return 0, nil, false
+	}
+
+	o, err := m.OrganizationService.FindOrganization(ctx, filter)
+	if err != nil {
+		return 0, nil, false
+	}
+	return o.ID, nil, true
+}
+
+func (m *Middleware) handleError(ctx context.Context, err error, w http.ResponseWriter) {
+	if le, ok := err.(*LimitedError); ok {
+		secs := int(math.Ceil(le.RetryAfter.Seconds()))
+		if secs < 1 {
+			secs = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+		err = &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Msg:  le.Error(),
+		}
+	}
+	m.ErrorHandler.HandleHTTPError(ctx, err, w)
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+func (r *countingReader) count() int64 {
+	return atomic.LoadInt64(&r.n)
+}
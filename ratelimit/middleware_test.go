@@ -0,0 +1,55 @@
+package ratelimit_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/ratelimit"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMiddleware_Writes(t *testing.T) {
+	orgID, authID := influxdb.ID(0x1000), influxdb.ID(0x2000)
+
+	quotas := mock.NewQuotaService()
+	quotas.FindQuotasF = func(ctx context.Context, filter influxdb.QuotaFilter) ([]*influxdb.Quota, error) {
+		return []*influxdb.Quota{{ID: 1, OrgID: orgID, AuthorizationID: &authID, BytesPerSecond: 10}}, nil
+	}
+	m := &ratelimit.Middleware{
+		Limiter:      ratelimit.NewLimiter(zaptest.NewLogger(t), quotas),
+		ErrorHandler: kithttp.ErrorHandler(0),
+	}
+	h := m.Writes()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	write := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/write", strings.NewReader("m f=1 1\nm f=2 2\n"))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{ID: authID, OrgID: orgID}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := write(); w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	w := write()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+)
+
+type contextKey string
+
+const usageKey contextKey = "usage"
+
+// usage counts the points written by a write.
+type usage struct {
+	points int64
+}
+
+func (u *usage) load() int64 {
+	return atomic.LoadInt64(&u.points)
+}
+
+// PointsWriter counts the points written in the context of a Write, so they
+// are charged to its quotas.
+type PointsWriter struct {
+	storage.PointsWriter
+}
+
+// NewPointsWriter returns a PointsWriter writing to w.
+func NewPointsWriter(w storage.PointsWriter) *PointsWriter {
+	return &PointsWriter{PointsWriter: w}
+}
+
+// WritePoints writes points to the underlying writer and counts them.
+func (w *PointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	if u, ok := ctx.Value(usageKey).(*usage); ok {
+		atomic.AddInt64(&u.points, int64(len(points)))
+	}
+	return w.PointsWriter.WritePoints(ctx, points)
+}
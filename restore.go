@@ -0,0 +1,13 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+)
+
+// RestoreService represents the data restore functions of InfluxDB.
+type RestoreService interface {
+	// RestoreBucket loads a backed up TSM file holding the data of the bucket src
+	// into the bucket bucketID of orgID. The series of the bucket are added to the index.
+	RestoreBucket(ctx context.Context, orgID, bucketID ID, src BackupManifestBucket, r io.Reader) error
+}
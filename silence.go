@@ -0,0 +1,151 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrSilenceNotFound is the error message for a missing silence.
+const ErrSilenceNotFound = "silence not found"
+
+// ops for silences.
+const (
+	OpFindSilenceByID = "FindSilenceByID"
+	OpFindSilences    = "FindSilences"
+	OpCreateSilence   = "CreateSilence"
+	OpUpdateSilence   = "UpdateSilence"
+	OpDeleteSilence   = "DeleteSilence"
+)
+
+// SilenceState is the state of a silence at a point in time.
+type SilenceState string
+
+// states of a silence.
+const (
+	SilencePending SilenceState = "pending"
+	SilenceActive  SilenceState = "active"
+	SilenceExpired SilenceState = "expired"
+)
+
+// Silence mutes the notifications of the statuses matching all of its
+// matchers from StartsAt until EndsAt, e.g. during a planned maintenance.
+// Notification rules still log the muted notifications as suppressed.
+type Silence struct {
+	ID       ID        `json:"id,omitempty"`
+	OrgID    ID        `json:"orgID"`
+	Matchers []TagRule `json:"matchers"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	// CreatedBy is the user that created the silence.
+	CreatedBy ID     `json:"createdBy,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the silence has no organization, no valid
+// matchers or an empty time range.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires a valid orgID",
+		}
+	}
+	if len(s.Matchers) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires at least one matcher",
+		}
+	}
+	for _, m := range s.Matchers {
+		if err := m.Valid(); err != nil {
+			return err
+		}
+		if m.Operator == RegexEqual || m.Operator == NotRegexEqual {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("silence matcher %q has an invalid regular expression: %s", m.Key, err.Error()),
+				}
+			}
+		}
+	}
+	if s.StartsAt.IsZero() || s.EndsAt.IsZero() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence requires a start and end time",
+		}
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence must end after it starts",
+		}
+	}
+	return nil
+}
+
+// State returns the state of the silence at t.
+func (s *Silence) State(t time.Time) SilenceState {
+	switch {
+	case t.Before(s.StartsAt):
+		return SilencePending
+	case t.Before(s.EndsAt):
+		return SilenceActive
+	default:
+		return SilenceExpired
+	}
+}
+
+// SilenceUpdate is the set of fields to change on a silence.
+type SilenceUpdate struct {
+	Matchers []TagRule  `json:"matchers,omitempty"`
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"`
+	Comment  *string    `json:"comment,omitempty"`
+}
+
+// Apply applies the update to s.
+func (u SilenceUpdate) Apply(s *Silence) {
+	if u.Matchers != nil {
+		s.Matchers = u.Matchers
+	}
+	if u.StartsAt != nil {
+		s.StartsAt = *u.StartsAt
+	}
+	if u.EndsAt != nil {
+		s.EndsAt = *u.EndsAt
+	}
+	if u.Comment != nil {
+		s.Comment = *u.Comment
+	}
+}
+
+// SilenceFilter represents a set of filters that restrict the returned silences.
+type SilenceFilter struct {
+	OrgID *ID
+	// Expired restricts the silences to the expired ones when true and to the
+	// pending and active ones when false.
+	Expired *bool
+}
+
+// SilenceService manages the silences of notifications.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns the silences matching filter.
+	FindSilences(ctx context.Context, filter SilenceFilter) ([]*Silence, error)
+
+	// CreateSilence creates a silence and sets its ID.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a silence, e.g. to end it early.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence.
+	DeleteSilence(ctx context.Context, id ID) error
+}
@@ -0,0 +1,88 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestSilenceValid(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	matcher := influxdb.TagRule{Tag: influxdb.Tag{Key: "host", Value: "db01"}, Operator: influxdb.Equal}
+	tests := []struct {
+		name    string
+		silence influxdb.Silence
+		wantErr string
+	}{
+		{
+			name: "valid silence",
+			silence: influxdb.Silence{
+				OrgID:    1,
+				Matchers: []influxdb.TagRule{matcher, {Tag: influxdb.Tag{Key: "region", Value: "us-.*"}, Operator: influxdb.RegexEqual}},
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+			},
+		},
+		{
+			name:    "requires an org",
+			silence: influxdb.Silence{Matchers: []influxdb.TagRule{matcher}, StartsAt: start, EndsAt: start.Add(time.Hour)},
+			wantErr: "silence requires a valid orgID",
+		},
+		{
+			name:    "requires a matcher",
+			silence: influxdb.Silence{OrgID: 1, StartsAt: start, EndsAt: start.Add(time.Hour)},
+			wantErr: "silence requires at least one matcher",
+		},
+		{
+			name: "invalid regular expression",
+			silence: influxdb.Silence{
+				OrgID:    1,
+				Matchers: []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "db(01"}, Operator: influxdb.NotRegexEqual}},
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+			},
+			wantErr: "silence matcher \"host\" has an invalid regular expression: error parsing regexp: missing closing ): `db(01`",
+		},
+		{
+			name:    "requires an end",
+			silence: influxdb.Silence{OrgID: 1, Matchers: []influxdb.TagRule{matcher}, StartsAt: start},
+			wantErr: "silence requires a start and end time",
+		},
+		{
+			name:    "ends before it starts",
+			silence: influxdb.Silence{OrgID: 1, Matchers: []influxdb.TagRule{matcher}, StartsAt: start, EndsAt: start},
+			wantErr: "silence must end after it starts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.silence.Valid()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Fatalf("unexpected error: got %q want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSilenceState(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := influxdb.Silence{StartsAt: start, EndsAt: start.Add(time.Hour)}
+
+	if got := s.State(start.Add(-time.Second)); got != influxdb.SilencePending {
+		t.Errorf("expected pending before the start, got %s", got)
+	}
+	if got := s.State(start); got != influxdb.SilenceActive {
+		t.Errorf("expected active at the start, got %s", got)
+	}
+	if got := s.State(start.Add(time.Hour)); got != influxdb.SilenceExpired {
+		t.Errorf("expected expired at the end, got %s", got)
+	}
+}
@@ -0,0 +1,524 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/kv"
+	_ "github.com/mattn/go-sqlite3" // register the sqlite3 database/sql driver
+	"go.uber.org/zap"
+)
+
+// check that *KVStore implement kv.SchemaStore interface.
+var _ kv.SchemaStore = (*KVStore)(nil)
+
+// DefaultFilename is the default sqlite metadata database filename.
+const DefaultFilename = "influxd.sqlite"
+
+// cursorBatchSize is the number of pairs a forward cursor reads per query.
+const cursorBatchSize = 1000
+
+// schema creates the tables holding the kv buckets and their key/value pairs.
+// Keys are BLOBs, which sqlite orders with memcmp, matching the byte ordering
+// of the other kv.Store implementations.
+const schema = `
+CREATE TABLE IF NOT EXISTS kv_buckets (
+	name BLOB NOT NULL PRIMARY KEY
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS kv_pairs (
+	bucket BLOB NOT NULL,
+	key    BLOB NOT NULL,
+	value  BLOB NOT NULL,
+	PRIMARY KEY (bucket, key)
+) WITHOUT ROWID;
+`
+
+// KVStore is a kv.Store backed by an sqlite database. Metadata kept in it can
+// be inspected and backed up with the standard sqlite tooling.
+type KVStore struct {
+	path string
+	db   *sql.DB
+	log  *zap.Logger
+
+	// mu serializes update transactions; sqlite allows a single writer and
+	// waiting here avoids busy errors when upgrading read locks.
+	mu sync.Mutex
+}
+
+// NewKVStore returns an instance of KVStore with the database file at
+// the provided path.
+func NewKVStore(log *zap.Logger, path string) *KVStore {
+	return &KVStore{
+		path: path,
+		log:  log,
+	}
+}
+
+// Open creates the sqlite database file if it doesn't exist, opens it and
+// ensures the kv schema exists.
+func (s *KVStore) Open(ctx context.Context) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// Ensure the required directory structure exists.
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("unable to create directory %s: %v", s.path, err)
+	}
+
+	db, err := sql.Open("sqlite3", s.path+"?_busy_timeout=5000&_journal_mode=WAL&_synchronous=FULL")
+	if err != nil {
+		return fmt.Errorf("unable to open sqlite file %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return fmt.Errorf("unable to create sqlite schema %v", err)
+	}
+	s.db = db
+
+	s.log.Info("Resources opened", zap.String("path", s.path))
+	return nil
+}
+
+// Close the connection to the sqlite database.
+func (s *KVStore) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// Flush removes all keys within each bucket.
+func (s *KVStore) Flush(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, _ = s.db.ExecContext(ctx, `DELETE FROM kv_pairs`)
+}
+
+// View opens up a view transaction against the store.
+func (s *KVStore) View(ctx context.Context, fn func(tx kv.Tx) error) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.do(ctx, false, fn)
+}
+
+// Update opens up an update transaction against the store.
+func (s *KVStore) Update(ctx context.Context, fn func(tx kv.Tx) error) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.do(ctx, true, fn)
+}
+
+// do runs fn in a transaction, committing it when fn succeeds. View
+// transactions are always rolled back.
+func (s *KVStore) do(ctx context.Context, writable bool, fn func(tx kv.Tx) error) error {
+	sqlTx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: !writable})
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&Tx{tx: sqlTx, ctx: ctx, writable: writable}); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+
+	if !writable {
+		return sqlTx.Rollback()
+	}
+	return sqlTx.Commit()
+}
+
+// CreateBucket creates a bucket in the underlying sqlite store if it
+// does not already exist
+func (s *KVStore) CreateBucket(ctx context.Context, name []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx, `INSERT OR IGNORE INTO kv_buckets (name) VALUES (?)`, blob(name))
+	return err
+}
+
+// DeleteBucket deletes a bucket and all of its keys in the underlying
+// sqlite store if it exists
+func (s *KVStore) DeleteBucket(ctx context.Context, name []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.do(ctx, true, func(tx kv.Tx) error {
+		sqlTx := tx.(*Tx).tx
+		if _, err := sqlTx.ExecContext(ctx, `DELETE FROM kv_pairs WHERE bucket = ?`, blob(name)); err != nil {
+			return err
+		}
+		_, err := sqlTx.ExecContext(ctx, `DELETE FROM kv_buckets WHERE name = ?`, blob(name))
+		return err
+	})
+}
+
+// Backup copies a consistent snapshot of the database to a writer, in sqlite format.
+func (s *KVStore) Backup(ctx context.Context, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	dir, err := ioutil.TempDir("", "influxd-sqlite-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, DefaultFilename)
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		return fmt.Errorf("unable to snapshot sqlite database %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Tx is a light wrapper around an sql transaction. It implements kv.Tx.
+type Tx struct {
+	tx       *sql.Tx
+	ctx      context.Context
+	writable bool
+}
+
+// Context returns the context for the transaction.
+func (tx *Tx) Context() context.Context {
+	return tx.ctx
+}
+
+// WithContext sets the context for the transaction.
+func (tx *Tx) WithContext(ctx context.Context) {
+	tx.ctx = ctx
+}
+
+// Bucket retrieves the bucket named b.
+func (tx *Tx) Bucket(b []byte) (kv.Bucket, error) {
+	var exists int
+	err := tx.tx.QueryRowContext(tx.ctx, `SELECT 1 FROM kv_buckets WHERE name = ?`, blob(b)).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("bucket %q: %w", string(b), kv.ErrBucketNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bucket{
+		tx:   tx,
+		name: append([]byte(nil), b...),
+	}, nil
+}
+
+// Bucket implements kv.Bucket.
+type Bucket struct {
+	tx   *Tx
+	name []byte
+}
+
+// Get retrieves the value at the provided key.
+func (b *Bucket) Get(key []byte) ([]byte, error) {
+	var val []byte
+	err := b.tx.tx.QueryRowContext(b.tx.ctx,
+		`SELECT value FROM kv_pairs WHERE bucket = ? AND key = ?`, b.name, blob(key)).Scan(&val)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, kv.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// GetBatch retrieves the values for the provided keys.
+func (b *Bucket) GetBatch(keys ...[]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for idx, key := range keys {
+		val, err := b.Get(key)
+		if err == kv.ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values[idx] = val
+	}
+
+	return values, nil
+}
+
+// Put sets the value at the provided key.
+func (b *Bucket) Put(key []byte, value []byte) error {
+	if !b.tx.writable {
+		return kv.ErrTxNotWritable
+	}
+
+	_, err := b.tx.tx.ExecContext(b.tx.ctx,
+		`INSERT OR REPLACE INTO kv_pairs (bucket, key, value) VALUES (?, ?, ?)`, b.name, blob(key), blob(value))
+	return err
+}
+
+// Delete removes the provided key.
+func (b *Bucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return kv.ErrTxNotWritable
+	}
+
+	_, err := b.tx.tx.ExecContext(b.tx.ctx,
+		`DELETE FROM kv_pairs WHERE bucket = ? AND key = ?`, b.name, blob(key))
+	return err
+}
+
+// ForwardCursor retrieves a cursor for iterating through the entries
+// in the key value store in a given direction (ascending / descending).
+// Like the bolt store, the first entry is the first key greater than or
+// equal to seek, or the last key when descending without a seek.
+func (b *Bucket) ForwardCursor(seek []byte, opts ...kv.CursorOption) (kv.ForwardCursor, error) {
+	config := kv.NewCursorConfig(opts...)
+
+	if config.Prefix != nil && !bytes.HasPrefix(seek, config.Prefix) {
+		return nil, fmt.Errorf("seek bytes %q not prefixed with %q: %w", string(seek), string(config.Prefix), kv.ErrSeekMissingPrefix)
+	}
+
+	c := &ForwardCursor{
+		bucket: b,
+		config: config,
+	}
+
+	var key, value []byte
+	if len(seek) == 0 && config.Direction == kv.CursorDescending {
+		key, value, c.err = b.last()
+	} else {
+		key, value, c.err = b.seek(seek)
+	}
+
+	if key == nil {
+		c.done = true
+		return c, nil
+	}
+
+	c.last = key
+	// only remember first seeked item if not skipped
+	if !config.SkipFirst {
+		c.pairs = []kv.Pair{{Key: key, Value: value}}
+	}
+
+	return c, nil
+}
+
+// Cursor retrieves a cursor for iterating through the entries
+// in the key value store.
+func (b *Bucket) Cursor(opts ...kv.CursorHint) (kv.Cursor, error) {
+	return &Cursor{
+		bucket: b,
+	}, nil
+}
+
+// seek returns the first pair with a key greater than or equal to key.
+func (b *Bucket) seek(key []byte) ([]byte, []byte, error) {
+	return b.one(`SELECT key, value FROM kv_pairs WHERE bucket = ? AND key >= ? ORDER BY key ASC LIMIT 1`, b.name, blob(key))
+}
+
+// first returns the first pair in the bucket.
+func (b *Bucket) first() ([]byte, []byte, error) {
+	return b.one(`SELECT key, value FROM kv_pairs WHERE bucket = ? ORDER BY key ASC LIMIT 1`, b.name)
+}
+
+// last returns the last pair in the bucket.
+func (b *Bucket) last() ([]byte, []byte, error) {
+	return b.one(`SELECT key, value FROM kv_pairs WHERE bucket = ? ORDER BY key DESC LIMIT 1`, b.name)
+}
+
+// after returns the first pair with a key greater than key.
+func (b *Bucket) after(key []byte) ([]byte, []byte, error) {
+	return b.one(`SELECT key, value FROM kv_pairs WHERE bucket = ? AND key > ? ORDER BY key ASC LIMIT 1`, b.name, blob(key))
+}
+
+// before returns the last pair with a key less than key.
+func (b *Bucket) before(key []byte) ([]byte, []byte, error) {
+	return b.one(`SELECT key, value FROM kv_pairs WHERE bucket = ? AND key < ? ORDER BY key DESC LIMIT 1`, b.name, blob(key))
+}
+
+// one returns the pair selected by query, or nils if there is none.
+func (b *Bucket) one(query string, args ...interface{}) ([]byte, []byte, error) {
+	var key, value []byte
+	err := b.tx.tx.QueryRowContext(b.tx.ctx, query, args...).Scan(&key, &value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+// batch returns up to cursorBatchSize pairs following key in direction.
+func (b *Bucket) batch(key []byte, direction kv.CursorDirection) ([]kv.Pair, error) {
+	query := `SELECT key, value FROM kv_pairs WHERE bucket = ? AND key > ? ORDER BY key ASC LIMIT ?`
+	if direction == kv.CursorDescending {
+		query = `SELECT key, value FROM kv_pairs WHERE bucket = ? AND key < ? ORDER BY key DESC LIMIT ?`
+	}
+
+	rows, err := b.tx.tx.QueryContext(b.tx.ctx, query, b.name, blob(key), cursorBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := make([]kv.Pair, 0, cursorBatchSize)
+	for rows.Next() {
+		var p kv.Pair
+		if err := rows.Scan(&p.Key, &p.Value); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs, rows.Err()
+}
+
+// Cursor is a struct for iterating through the entries
+// in the key value store.
+type Cursor struct {
+	bucket *Bucket
+
+	// key the cursor is positioned at, nil before positioning or past
+	// either end of the bucket.
+	key []byte
+	err error
+}
+
+// Seek seeks for the first key that matches the prefix provided.
+func (c *Cursor) Seek(prefix []byte) ([]byte, []byte) {
+	return c.move(c.bucket.seek(prefix))
+}
+
+// First retrieves the first key value pair in the bucket.
+func (c *Cursor) First() ([]byte, []byte) {
+	return c.move(c.bucket.first())
+}
+
+// Last retrieves the last key value pair in the bucket.
+func (c *Cursor) Last() ([]byte, []byte) {
+	return c.move(c.bucket.last())
+}
+
+// Next retrieves the next key in the bucket.
+func (c *Cursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+	return c.move(c.bucket.after(c.key))
+}
+
+// Prev retrieves the previous key in the bucket.
+func (c *Cursor) Prev() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+	return c.move(c.bucket.before(c.key))
+}
+
+func (c *Cursor) move(k, v []byte, err error) ([]byte, []byte) {
+	if err != nil {
+		c.err = err
+		c.key = nil
+		return nil, nil
+	}
+	c.key = k
+	return k, v
+}
+
+// Err returns the error, if any, encountered reading from the database.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// ForwardCursor iterates through the entries of a bucket in one direction,
+// reading them from the database in batches.
+type ForwardCursor struct {
+	bucket *Bucket
+	config kv.CursorConfig
+
+	// pairs read but not yet returned and the last key read
+	pairs []kv.Pair
+	last  []byte
+
+	done   bool
+	closed bool
+	err    error
+}
+
+// Next retrieves the next key in the bucket.
+func (c *ForwardCursor) Next() ([]byte, []byte) {
+	if c.closed || c.err != nil {
+		return nil, nil
+	}
+
+	if len(c.pairs) == 0 && !c.done {
+		c.pairs, c.err = c.bucket.batch(c.last, c.config.Direction)
+		if c.err != nil {
+			return nil, nil
+		}
+		if len(c.pairs) < cursorBatchSize {
+			c.done = true
+		}
+		if len(c.pairs) > 0 {
+			c.last = c.pairs[len(c.pairs)-1].Key
+		}
+	}
+
+	if len(c.pairs) == 0 {
+		return nil, nil
+	}
+
+	p := c.pairs[0]
+	c.pairs = c.pairs[1:]
+	if c.config.Prefix != nil && !bytes.HasPrefix(p.Key, c.config.Prefix) {
+		c.pairs, c.done = nil, true
+		return nil, nil
+	}
+	return p.Key, p.Value
+}
+
+// Err returns the error, if any, encountered reading from the database.
+func (c *ForwardCursor) Err() error {
+	return c.err
+}
+
+// Close sets the closed to closed
+func (c *ForwardCursor) Close() error {
+	c.closed = true
+	c.pairs = nil
+
+	return nil
+}
+
+// blob returns b as a non-nil slice, as the driver binds nil slices as NULL.
+func blob(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
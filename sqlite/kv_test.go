@@ -0,0 +1,230 @@
+package sqlite_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/sqlite"
+	platformtesting "github.com/influxdata/influxdb/v2/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func NewTestKVStore(t *testing.T) (*sqlite.KVStore, func(), error) {
+	dir, err := ioutil.TempDir("", "influxdata-platform-sqlite-")
+	if err != nil {
+		return nil, nil, errors.New("unable to create temporary sqlite directory")
+	}
+
+	s := sqlite.NewKVStore(zaptest.NewLogger(t), filepath.Join(dir, sqlite.DefaultFilename))
+	if err := s.Open(context.TODO()); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+
+	close := func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+
+	return s, close, nil
+}
+
+func initKVStore(f platformtesting.KVStoreFields, t *testing.T) (kv.Store, func()) {
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	mustCreateBucket(t, s, f.Bucket)
+
+	err = s.Update(context.Background(), func(tx kv.Tx) error {
+		b, err := tx.Bucket(f.Bucket)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range f.Pairs {
+			if err := b.Put(p.Key, p.Value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to put keys: %v", err)
+	}
+	return s, func() {
+		closeFn()
+	}
+}
+
+func TestKVStore(t *testing.T) {
+	platformtesting.KVStore(initKVStore, t)
+}
+
+func TestKVStore_Migrations(t *testing.T) {
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	if err := all.Up(ctx, zaptest.NewLogger(t), s); err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := migration.NewMigrator(zaptest.NewLogger(t), s, all.Migrations[:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := migrator.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		if m.State != migration.UpMigrationState {
+			t.Errorf("expected migration %q to be applied, got %v", m.Name, m.State)
+		}
+	}
+}
+
+func TestKVStore_DeleteBucket(t *testing.T) {
+	s, closeFn := initKVStore(platformtesting.KVStoreFields{
+		Bucket: []byte("bucket"),
+		Pairs:  []kv.Pair{{Key: []byte("hello"), Value: []byte("world")}},
+	}, t)
+	defer closeFn()
+
+	ctx := context.Background()
+	store := s.(*sqlite.KVStore)
+	if err := store.DeleteBucket(ctx, []byte("bucket")); err != nil {
+		t.Fatal(err)
+	}
+	// deleting a missing bucket is not an error
+	if err := store.DeleteBucket(ctx, []byte("bucket")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := store.View(ctx, func(tx kv.Tx) error {
+		_, err := tx.Bucket([]byte("bucket"))
+		return err
+	})
+	if !errors.Is(err, kv.ErrBucketNotFound) {
+		t.Fatalf("expected bucket not found, got %v", err)
+	}
+
+	// a recreated bucket does not contain the keys of the deleted one
+	mustCreateBucket(t, store, []byte("bucket"))
+	err = store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		_, err = b.Get([]byte("hello"))
+		return err
+	})
+	if err != kv.ErrKeyNotFound {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+}
+
+func TestKVStore_ForwardCursorBatches(t *testing.T) {
+	var pairs []kv.Pair
+	for i := 0; i < 2500; i++ {
+		pairs = append(pairs, kv.Pair{Key: []byte(fmt.Sprintf("%05d", i)), Value: []byte("v")})
+	}
+	s, closeFn := initKVStore(platformtesting.KVStoreFields{Bucket: []byte("bucket"), Pairs: pairs}, t)
+	defer closeFn()
+
+	for _, direction := range []kv.CursorDirection{kv.CursorAscending, kv.CursorDescending} {
+		err := s.View(context.Background(), func(tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("bucket"))
+			if err != nil {
+				return err
+			}
+
+			cur, err := b.ForwardCursor(nil, kv.WithCursorDirection(direction))
+			if err != nil {
+				return err
+			}
+			defer cur.Close()
+
+			var n int
+			var prev []byte
+			for k, _ := cur.Next(); k != nil; k, _ = cur.Next() {
+				if prev != nil && (bytes.Compare(prev, k) < 0) != (direction == kv.CursorAscending) {
+					t.Fatalf("key %s out of order after %s", k, prev)
+				}
+				prev = k
+				n++
+			}
+			if n != len(pairs) {
+				t.Fatalf("expected %d keys, got %d", len(pairs), n)
+			}
+			return cur.Err()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKVStore_Backup(t *testing.T) {
+	s, closeFn := initKVStore(platformtesting.KVStoreFields{
+		Bucket: []byte("bucket"),
+		Pairs:  []kv.Pair{{Key: []byte("hello"), Value: []byte("world")}},
+	}, t)
+	defer closeFn()
+
+	dir, err := ioutil.TempDir("", "influxdata-platform-sqlite-backup-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	if err := s.Backup(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "backup.sqlite")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the backup is a plain sqlite database
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var value string
+	if err := db.QueryRow(`SELECT value FROM kv_pairs WHERE bucket = ? AND key = ?`, []byte("bucket"), []byte("hello")).Scan(&value); err != nil {
+		t.Fatal(err)
+	}
+	if value != "world" {
+		t.Fatalf("expected backed up value world, got %q", value)
+	}
+}
+
+func mustCreateBucket(t testing.TB, store kv.SchemaStore, bucket []byte) {
+	t.Helper()
+
+	migrationName := fmt.Sprintf("create bucket %q", string(bucket))
+
+	if err := migration.CreateBuckets(migrationName, bucket).Up(context.Background(), store); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"go.uber.org/multierr"
+)
+
+// NewBackupManifestFile describes the backup file at path.
+func NewBackupManifestFile(path string) (influxdb.BackupManifestFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return influxdb.BackupManifestFile{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return influxdb.BackupManifestFile{}, err
+	}
+
+	return influxdb.BackupManifestFile{
+		FileName: filepath.Base(path),
+		Size:     n,
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// backupPrefix returns the prefix of the TSM keys matching filter, or nil if
+// the filter does not restrict the keys.
+func backupPrefix(filter influxdb.BackupFilter) ([]byte, error) {
+	switch {
+	case filter.BucketID != nil && filter.OrgID == nil:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "backup of a bucket requires its organization",
+		}
+	case filter.BucketID != nil:
+		return models.EscapeMeasurement(tsdb.EncodeNameSlice(*filter.OrgID, *filter.BucketID)), nil
+	case filter.OrgID != nil:
+		return models.EscapeMeasurement(tsdb.EncodeNameSlice(*filter.OrgID, 0)[:8]), nil
+	}
+	return nil, nil
+}
+
+// filterBackup removes the files of the snapshot in dir that do not match
+// filter and rewrites the TSM files holding keys of other buckets.
+func filterBackup(dir string, filter influxdb.BackupFilter) error {
+	prefix, err := backupPrefix(filter)
+	if err != nil {
+		return err
+	}
+
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range fis {
+		path := filepath.Join(dir, fi.Name())
+		if filepath.Ext(path) != "."+tsm1.TSMFileExtension {
+			continue
+		}
+		tombstone := tombstonePath(path)
+
+		// Files that have not changed since the previous backup are left
+		// out; a tombstone written since still has to be shipped.
+		if filter.Since != nil && !fi.ModTime().After(*filter.Since) {
+			deleted := modifiedSince(tombstone, *filter.Since)
+			if !deleted || prefix == nil {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+				if !deleted {
+					if err := removeIfExists(tombstone); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			// The tombstone may reference keys of other buckets, so the
+			// file is shipped again without the data deleted since.
+		}
+
+		if prefix == nil {
+			continue
+		}
+		if err := filterTSMFile(path, prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterTSMFile rewrites the TSM file at path to hold only the keys starting
+// with prefix. Deleted data is dropped rather than shipped with the tombstone,
+// as the tombstone may reference keys of other buckets.
+func filterTSMFile(path string, prefix []byte) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer func() {
+		if r != nil {
+			err = multierr.Append(err, r.Close())
+		}
+	}()
+
+	if !r.OverlapsKeyPrefixRange(prefix, prefix) {
+		if err := r.Close(); err != nil {
+			return err
+		}
+		r = nil
+		return multierr.Append(os.Remove(path), removeIfExists(tombstonePath(path)))
+	}
+
+	if min, max := r.KeyRange(); bytes.HasPrefix(min, prefix) && bytes.HasPrefix(max, prefix) && !r.HasTombstones() {
+		return nil
+	}
+
+	tmp := path + "." + tsm1.TmpTSMFileExtension
+	fd, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	w, err := tsm1.NewTSMWriter(fd)
+	if err != nil {
+		fd.Close()
+		return err
+	}
+
+	var written bool
+	itr := r.Iterator(prefix)
+	for itr.Next() {
+		if !bytes.HasPrefix(itr.Key(), prefix) {
+			break
+		}
+		// The writer references the key until the index is written, after
+		// the reader is closed.
+		key := append([]byte(nil), itr.Key()...)
+		values, err := r.ReadAll(key)
+		if err != nil {
+			return multierr.Append(err, w.Remove())
+		}
+		for len(values) > 0 {
+			n := len(values)
+			if n > tsm1.MaxPointsPerBlock {
+				n = tsm1.MaxPointsPerBlock
+			}
+			if err := w.Write(key, values[:n]); err != nil {
+				return multierr.Append(err, w.Remove())
+			}
+			values = values[n:]
+			written = true
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return multierr.Append(err, w.Remove())
+	}
+
+	if err := r.Close(); err != nil {
+		return multierr.Append(err, w.Remove())
+	}
+	r = nil
+
+	if !written {
+		return multierr.Append(w.Remove(), multierr.Append(os.Remove(path), removeIfExists(tombstonePath(path))))
+	}
+	if err := w.WriteIndex(); err != nil {
+		return multierr.Append(err, w.Remove())
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	// The snapshot holds hard links to the live files, so the rewritten
+	// file replaces the link rather than the content of the file.
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := removeIfExists(tombstonePath(path)); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// FilterBackupFile rewrites the backed up TSM file at path to hold only the data
+// of the bucket b, applying the deletes recorded in its tombstone. The file is
+// removed if it holds no data of the bucket.
+func FilterBackupFile(path string, b influxdb.BackupManifestBucket) error {
+	return filterTSMFile(path, models.EscapeMeasurement(tsdb.EncodeNameSlice(b.OrgID, b.BucketID)))
+}
+
+// tsmBuckets returns the organizations and buckets with data in the TSM file at path.
+func tsmBuckets(path string) ([]influxdb.BackupManifestBucket, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	defer r.Close()
+
+	var buckets []influxdb.BackupManifestBucket
+	itr := r.Iterator(nil)
+	for itr.Next() {
+		name := models.ParseName(itr.Key())
+		if len(name) < influxdb.IDLength {
+			continue
+		}
+		org, bucket := tsdb.DecodeNameSlice(name)
+		buckets = append(buckets, influxdb.BackupManifestBucket{OrgID: org, BucketID: bucket})
+
+		// Skip the remaining keys of the bucket.
+		next := models.EscapeMeasurement(tsdb.EncodeNameSlice(org, bucket))
+		itr = r.Iterator(incrementPrefix(next))
+	}
+	if err := itr.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].OrgID != buckets[j].OrgID {
+			return buckets[i].OrgID < buckets[j].OrgID
+		}
+		return buckets[i].BucketID < buckets[j].BucketID
+	})
+	return buckets, nil
+}
+
+// backupManifestFiles describes every file of the snapshot in dir.
+func backupManifestFiles(dir string) ([]influxdb.BackupManifestFile, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]influxdb.BackupManifestFile, 0, len(fis))
+	for _, fi := range fis {
+		path := filepath.Join(dir, fi.Name())
+		mf, err := NewBackupManifestFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Ext(path) == "."+tsm1.TSMFileExtension {
+			if mf.Buckets, err = tsmBuckets(path); err != nil {
+				return nil, fmt.Errorf("failed to read buckets of %s: %v", fi.Name(), err)
+			}
+		}
+		files = append(files, mf)
+	}
+	return files, nil
+}
+
+// incrementPrefix returns the smallest key greater than every key starting with prefix.
+func incrementPrefix(prefix []byte) []byte {
+	next := append([]byte(nil), prefix...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next[:i+1]
+		}
+	}
+	return nil
+}
+
+func tombstonePath(tsmPath string) string {
+	return strings.TrimSuffix(tsmPath, filepath.Ext(tsmPath)) + ".tombstone"
+}
+
+// modifiedSince returns true if the file at path exists and was modified after since.
+func modifiedSince(path string, since time.Time) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.ModTime().After(since)
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestEngine_CreateBackup(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	otherOrg, otherBucket := influxdb.ID(0x4141414141414141), influxdb.ID(0x4242424242424242)
+	sameOrgBucket := influxdb.ID(0x3333333333333333)
+
+	var points []models.Point
+	for _, b := range []influxdb.BackupManifestBucket{
+		{OrgID: engine.org, BucketID: engine.bucket},
+		{OrgID: engine.org, BucketID: sameOrgBucket},
+		{OrgID: otherOrg, BucketID: otherBucket},
+	} {
+		for i := 0; i < 3; i++ {
+			points = append(points, models.MustNewPoint(
+				tsdb.EncodeNameString(b.OrgID, b.BucketID),
+				models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+				map[string]interface{}{"value": float64(i)},
+				time.Unix(int64(i), 0),
+			))
+		}
+	}
+	if err := engine.Engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, bucketID := engine.org, engine.bucket
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		filter  influxdb.BackupFilter
+		buckets []influxdb.BackupManifestBucket
+		wantErr bool
+	}{
+		{
+			name: "all buckets",
+			buckets: []influxdb.BackupManifestBucket{
+				{OrgID: engine.org, BucketID: engine.bucket},
+				{OrgID: engine.org, BucketID: sameOrgBucket},
+				{OrgID: otherOrg, BucketID: otherBucket},
+			},
+		},
+		{
+			name:   "organization",
+			filter: influxdb.BackupFilter{OrgID: &orgID},
+			buckets: []influxdb.BackupManifestBucket{
+				{OrgID: engine.org, BucketID: engine.bucket},
+				{OrgID: engine.org, BucketID: sameOrgBucket},
+			},
+		},
+		{
+			name:    "bucket",
+			filter:  influxdb.BackupFilter{OrgID: &orgID, BucketID: &bucketID},
+			buckets: []influxdb.BackupManifestBucket{{OrgID: engine.org, BucketID: engine.bucket}},
+		},
+		{
+			name:   "since the last write",
+			filter: influxdb.BackupFilter{Since: &future},
+		},
+		{
+			name:    "bucket without organization",
+			filter:  influxdb.BackupFilter{BucketID: &bucketID},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := engine.CreateBackup(context.Background(), tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateBackup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer os.RemoveAll(engine.InternalBackupPath(m.BackupID))
+
+			var buckets []influxdb.BackupManifestBucket
+			for _, f := range m.Files {
+				if filepath.Ext(f.FileName) != "."+tsm1.TSMFileExtension {
+					continue
+				}
+				buckets = append(buckets, f.Buckets...)
+				if f.Size == 0 || len(f.Checksum) != 64 {
+					t.Errorf("unexpected size or checksum of %s: %d %q", f.FileName, f.Size, f.Checksum)
+				}
+				checkBackupFile(t, filepath.Join(engine.InternalBackupPath(m.BackupID), f.FileName), len(f.Buckets)*3)
+			}
+			if !reflect.DeepEqual(buckets, tt.buckets) {
+				t.Errorf("unexpected buckets:\n\twant=%v\n\tgot=%v", tt.buckets, buckets)
+			}
+		})
+	}
+}
+
+func TestEngine_CreateBackup_DeleteSinceBase(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	var points []models.Point
+	for i := 0; i < 3; i++ {
+		points = append(points, models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": float64(i)},
+			time.Unix(int64(i), 0),
+		))
+	}
+	if err := engine.Engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, bucketID := engine.org, engine.bucket
+	base, err := engine.CreateBackup(context.Background(), influxdb.BackupFilter{OrgID: &orgID, BucketID: &bucketID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(engine.InternalBackupPath(base.BackupID))
+
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	if err := engine.DeleteBucketRange(context.Background(), orgID, bucketID, 0, int64(time.Second)-1); err != nil {
+		t.Fatal(err)
+	}
+
+	// the TSM file did not change since the base backup, but its tombstone
+	// did, so it is shipped again without the deleted value.
+	m, err := engine.CreateBackup(context.Background(), influxdb.BackupFilter{OrgID: &orgID, BucketID: &bucketID, Since: &since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(engine.InternalBackupPath(m.BackupID))
+
+	var tsmFiles int
+	for _, f := range m.Files {
+		switch filepath.Ext(f.FileName) {
+		case "." + tsm1.TSMFileExtension:
+			tsmFiles++
+			checkBackupFile(t, filepath.Join(engine.InternalBackupPath(m.BackupID), f.FileName), 2)
+		case ".tombstone":
+			t.Errorf("unexpected tombstone %s in bucket backup", f.FileName)
+		}
+	}
+	if tsmFiles != 1 {
+		t.Fatalf("expected the TSM file to be shipped again, got %d TSM files", tsmFiles)
+	}
+}
+
+// checkBackupFile verifies the TSM file at path holds n values.
+func checkBackupFile(t *testing.T, path string, n int) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var got int
+	itr := r.Iterator(nil)
+	for itr.Next() {
+		values, err := r.ReadAll(itr.Key())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got += len(values)
+	}
+	if got != n {
+		t.Errorf("expected %d values in %s, got %d", n, filepath.Base(path), got)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -656,7 +655,8 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb
 }
 
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
-// deleted must be in [min, max], and the key must match the predicate if provided.
+// deleted must be in [min, max], and the key must match the predicate if provided. The delete
+// reports its progress to the influxdb.DeleteProgress of ctx and stops early if ctx is done.
 func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -666,6 +666,9 @@ func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID
 	if e.closing == nil {
 		return ErrEngineClosed
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	var predData []byte
 	var err error
@@ -696,37 +699,49 @@ func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID in
 	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
 }
 
-// CreateBackup creates a "snapshot" of all TSM data in the Engine.
+// CreateBackup creates a "snapshot" of the TSM data in the Engine matching filter.
 //   1) Snapshot the cache to ensure the backup includes all data written before now.
 //   2) Create hard links to all TSM files, in a new directory within the engine root directory.
-//   3) Return a unique backup ID (invalid after the process terminates) and list of files.
-func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
+//   3) Remove the files written before filter.Since and rewrite the files holding
+//      data of other organizations or buckets than those of filter.
+//   4) Return a manifest with a unique backup ID (invalid after the process terminates) and the files.
+func (e *Engine) CreateBackup(ctx context.Context, filter influxdb.BackupFilter) (*influxdb.BackupManifest, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
 	if e.closing == nil {
-		return 0, nil, ErrEngineClosed
+		return nil, ErrEngineClosed
+	}
+
+	if _, err := backupPrefix(filter); err != nil {
+		return nil, err
 	}
 
+	createdAt := time.Now().UTC()
 	if err := e.engine.WriteSnapshot(ctx, tsm1.CacheStatusBackup); err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	id, snapshotPath, err := e.engine.FileStore.CreateSnapshot(ctx)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
-	fileInfos, err := ioutil.ReadDir(snapshotPath)
-	if err != nil {
-		return 0, nil, err
+	if err := filterBackup(snapshotPath, filter); err != nil {
+		return nil, multierr.Append(err, os.RemoveAll(snapshotPath))
 	}
-	filenames := make([]string, len(fileInfos))
-	for i, fi := range fileInfos {
-		filenames[i] = fi.Name()
+
+	files, err := backupManifestFiles(snapshotPath)
+	if err != nil {
+		return nil, multierr.Append(err, os.RemoveAll(snapshotPath))
 	}
 
-	return id, filenames, nil
+	return &influxdb.BackupManifest{
+		BackupID:  id,
+		CreatedAt: createdAt,
+		Filter:    filter,
+		Files:     files,
+	}, nil
 }
 
 // FetchBackupFile writes a given backup file to the provided writer.
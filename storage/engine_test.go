@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/predicate"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/v2/tsdb"
@@ -352,6 +353,60 @@ func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 
 }
 
+func TestEngine_DeleteBucket_ParsedPredicate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	p := func(m, f, host string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: f, models.MeasurementTagKey: m, "host": host}),
+			map[string]interface{}{f: 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("cpu", "usage", "web-1"),
+		p("cpu", "usage", "web-2"),
+		p("cpu", "usage", "db-1"),
+		p("cpu", "typo", "db-1"),
+		p("mem", "used", "web-1"),
+		p("mem", "used", "db-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		predicate string
+		exp       int64
+	}{
+		{predicate: `_measurement="cpu" and _field="typo"`, exp: 5},
+		{predicate: `host="web-2" or (_measurement="mem" and host!~/^web-/)`, exp: 3},
+		{predicate: `host=~/^web-/`, exp: 1},
+	} {
+		node, err := predicate.Parse(tt.predicate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pred, err := predicate.New(node)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := engine.DeleteBucketRangePredicate(context.Background(), engine.org, engine.bucket,
+			math.MinInt64, math.MaxInt64, pred); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := engine.SeriesCardinality(); got != tt.exp {
+			t.Fatalf("%s: got %d series, exp %d series in index", tt.predicate, got, tt.exp)
+		}
+	}
+}
+
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"go.uber.org/multierr"
+)
+
+// restoreBatchSize is the number of series added to the index at once when restoring a bucket.
+const restoreBatchSize = 10000
+
+// RestoreBucket loads the backed up TSM file read from r into the bucket
+// bucketID of orgID. Every key of the file must belong to the bucket src and
+// is moved to the restored bucket. The file is added as a new generation and
+// its series are added to the index partitions they belong to; other buckets
+// are left untouched.
+func (e *Engine) RestoreBucket(ctx context.Context, orgID, bucketID influxdb.ID, src influxdb.BackupManifestBucket, r io.Reader) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	f, err := ioutil.TempFile(e.engine.Path(), "restore-*."+tsm1.CompactionTempExtension)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		return multierr.Append(err, f.Close())
+	}
+
+	tr, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid TSM file",
+			Err:  err,
+		}
+	}
+	defer tr.Close()
+
+	from := models.EscapeMeasurement(tsdb.EncodeNameSlice(src.OrgID, src.BucketID))
+	to := models.EscapeMeasurement(tsdb.EncodeNameSlice(orgID, bucketID))
+
+	return e.engine.Import(ctx, func(w tsm1.TSMWriter) error {
+		collection := &tsdb.SeriesCollection{}
+		var prev []byte
+
+		itr := tr.Iterator(nil)
+		for itr.Next() {
+			key := itr.Key()
+			if !bytes.HasPrefix(key, from) {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "TSM file holds data of another bucket than " + src.BucketID.String(),
+				}
+			}
+			key = append(append(make([]byte, 0, len(to)+len(key)-len(from)), to...), key[len(from):]...)
+
+			for _, entry := range itr.Entries() {
+				_, block, err := tr.ReadBytes(&entry, nil)
+				if err != nil {
+					return err
+				}
+				if err := w.WriteBlock(key, entry.MinTime, entry.MaxTime, block); err != nil {
+					return err
+				}
+			}
+
+			// Keys of the same series only differ by field and are adjacent.
+			seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+			if bytes.Equal(seriesKey, prev) {
+				continue
+			}
+			prev = seriesKey
+
+			name, tags := models.ParseKeyBytes(seriesKey)
+			collection.Keys = append(collection.Keys, seriesKey)
+			collection.Names = append(collection.Names, name)
+			collection.Tags = append(collection.Tags, tags)
+			collection.Types = append(collection.Types, fieldTypeFromBlockType(itr.Type()))
+
+			if collection.Length() == restoreBatchSize {
+				if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
+					return err
+				}
+				collection = &tsdb.SeriesCollection{}
+			}
+		}
+		if err := itr.Err(); err != nil {
+			return err
+		}
+
+		if collection.Length() > 0 {
+			return e.index.CreateSeriesListIfNotExists(collection)
+		}
+		return nil
+	})
+}
+
+func fieldTypeFromBlockType(typ byte) models.FieldType {
+	switch typ {
+	case tsm1.BlockFloat64:
+		return models.Float
+	case tsm1.BlockInteger:
+		return models.Integer
+	case tsm1.BlockUnsigned:
+		return models.Unsigned
+	case tsm1.BlockBoolean:
+		return models.Boolean
+	case tsm1.BlockString:
+		return models.String
+	default:
+		return models.Empty
+	}
+}
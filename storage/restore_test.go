@@ -0,0 +1,93 @@
+package storage_test
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestEngine_RestoreBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	var points []models.Point
+	for _, host := range []string{"a", "b"} {
+		points = append(points, models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 0),
+		))
+	}
+	if err := engine.Engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, bucketID := engine.org, engine.bucket
+	m, err := engine.CreateBackup(context.Background(), influxdb.BackupFilter{OrgID: &orgID, BucketID: &bucketID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(engine.InternalBackupPath(m.BackupID))
+
+	var path string
+	for _, f := range m.Files {
+		if filepath.Ext(f.FileName) == "."+tsm1.TSMFileExtension {
+			path = filepath.Join(engine.InternalBackupPath(m.BackupID), f.FileName)
+		}
+	}
+	if path == "" {
+		t.Fatal("expected a TSM file in the backup")
+	}
+
+	src := influxdb.BackupManifestBucket{OrgID: engine.org, BucketID: engine.bucket}
+	restore := func(dst, src influxdb.BackupManifestBucket) error {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return engine.RestoreBucket(context.Background(), dst.OrgID, dst.BucketID, src, f)
+	}
+
+	t.Run("into another organization", func(t *testing.T) {
+		dst := influxdb.BackupManifestBucket{OrgID: 0x4141414141414141, BucketID: 0x4242424242424242}
+		if err := restore(dst, src); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, exp := engine.SeriesCardinality(), int64(4); got != exp {
+			t.Fatalf("got %d series, exp %d series in index", got, exp)
+		}
+
+		itr, err := engine.TagValues(context.Background(), dst.OrgID, dst.BucketID, "host", math.MinInt64, math.MaxInt64, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, exp := cursors.StringIteratorToSlice(itr), []string{"a", "b"}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("got tag values %v, exp %v", got, exp)
+		}
+	})
+
+	t.Run("data of another bucket", func(t *testing.T) {
+		dst := influxdb.BackupManifestBucket{OrgID: engine.org, BucketID: 0x4343434343434343}
+		other := influxdb.BackupManifestBucket{OrgID: engine.org, BucketID: 0x4444444444444444}
+		if err := restore(dst, other); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("expected invalid error, got %v", err)
+		}
+		if got, exp := engine.SeriesCardinality(), int64(4); got != exp {
+			t.Fatalf("got %d series, exp %d series in index", got, exp)
+		}
+	})
+}
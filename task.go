@@ -61,7 +61,14 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Timezone        string                 `json:"timezone,omitempty"` // Timezone is the IANA time zone Cron is evaluated in, UTC if empty
 	Offset          time.Duration          `json:"offset,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`         // Retry is how many times a failed run is retried
+	CatchUp         string                 `json:"catchUp,omitempty"`       // CatchUp is the policy for the runs missed while the task was not scheduled, the runs missed while influxd was down are skipped if empty
+	MaxMissedRuns   int64                  `json:"maxMissedRuns,omitempty"` // MaxMissedRuns caps how many missed runs are caught up, all if 0
+	DependsOn       []ID                   `json:"dependsOn,omitempty"`     // DependsOn are the upstream tasks whose runs must succeed before the runs of this task
+	Params          []TaskParam            `json:"params,omitempty"`        // Params are the parameters the script reads from the params record
+	Version         int                    `json:"version,omitempty"`       // Version is the version of the script of the task, incremented when the script changes
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
@@ -86,6 +93,12 @@ func (t *Task) EffectiveCron() string {
 	return ""
 }
 
+// Location returns the time zone the cron schedule of the task is evaluated in.
+// It is UTC if the timezone option was not specified.
+func (t *Task) Location() (*time.Location, error) {
+	return time.LoadLocation(t.Timezone)
+}
+
 // Run is a record createId when a run of a task is scheduled.
 type Run struct {
 	ID           ID        `json:"id,omitempty"`
@@ -96,14 +109,49 @@ type Run struct {
 	StartedAt    time.Time `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
 	FinishedAt   time.Time `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	RetryOf      ID        `json:"retryOf,omitempty"`     // RetryOf is the failed run this run retries
+	Retry        int       `json:"retry,omitempty"`       // Retry counts the retries of the scheduled time, it is 0 for the first attempt
+	Stats        *RunStats `json:"stats,omitempty"`       // Stats are the statistics of the query of the run, once it has run
+	TaskVersion  int       `json:"taskVersion,omitempty"` // TaskVersion is the version of the task the run executes
 	Log          []Log     `json:"log,omitempty"`
+
+	// Params are the values of parameters the run was forced with, replacing
+	// the values of the task.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// RunStats are the statistics the query controller collected for the query of a run.
+type RunStats struct {
+	RowsRead      int64        `json:"rowsRead"`                // RowsRead is the number of values read from storage
+	PointsWritten map[ID]int64 `json:"pointsWritten,omitempty"` // PointsWritten is the number of points written with to(), by bucket ID
+	QueryDuration Duration     `json:"queryDuration"`           // QueryDuration is the total time spent compiling, queueing and executing the query
+	MaxAllocated  int64        `json:"maxAllocated"`            // MaxAllocated is the peak memory allocated by the query, in bytes
 }
 
+// TotalPointsWritten returns the number of points written to all buckets.
+func (s *RunStats) TotalPointsWritten() int64 {
+	var n int64
+	for _, p := range s.PointsWritten {
+		n += p
+	}
+	return n
+}
+
+// Levels of run log entries.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
 // Log represents a link to a log resource
 type Log struct {
-	RunID   ID     `json:"runID,omitempty"`
-	Time    string `json:"time"`
-	Message string `json:"message"`
+	RunID   ID                `json:"runID,omitempty"`
+	Time    string            `json:"time"`
+	Level   string            `json:"level,omitempty"` // Level is one of the LogLevel constants, entries without level are info
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"` // Fields are the structured context of the entry
 }
 
 func (l Log) String() string {
@@ -146,7 +194,8 @@ type TaskService interface {
 
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
-	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+	// The values of params replace those of the parameters of the task for the run, params may be nil.
+	ForceRun(ctx context.Context, taskID ID, scheduledFor int64, params map[string]interface{}) (*Run, error)
 }
 
 // TaskCreate is the set of values to create a task.
@@ -158,6 +207,8 @@ type TaskCreate struct {
 	OrganizationID ID                     `json:"orgID,omitempty"`
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
+	DependsOn      []ID                   `json:"dependsOn,omitempty"`
+	Params         []TaskParam            `json:"params,omitempty"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
 }
 
@@ -179,6 +230,12 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// DependsOn replaces the upstream tasks of the task, an empty list removes them.
+	DependsOn *[]ID `json:"dependsOn,omitempty"`
+
+	// Params replaces the parameters of the task, an empty list removes them.
+	Params *[]TaskParam `json:"params,omitempty"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -201,6 +258,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Timezone is the IANA time zone Cron is evaluated in.
+		Timezone string `json:"timezone,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
 		Every options.Duration `json:"every,omitempty"`
@@ -212,6 +272,15 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		// CatchUp is the policy for the runs missed while the task was not scheduled.
+		CatchUp string `json:"catchUp,omitempty"`
+
+		MaxMissedRuns *int64 `json:"maxMissedRuns,omitempty"`
+
+		DependsOn *[]ID `json:"dependsOn,omitempty"`
+
+		Params *[]TaskParam `json:"params,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -220,6 +289,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
 	t.Options.Cron = jo.Cron
+	t.Options.Timezone = jo.Timezone
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
 		offset := *jo.Offset
@@ -227,6 +297,10 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Concurrency = jo.Concurrency
 	t.Options.Retry = jo.Retry
+	t.Options.CatchUp = jo.CatchUp
+	t.Options.MaxMissedRuns = jo.MaxMissedRuns
+	t.DependsOn = jo.DependsOn
+	t.Params = jo.Params
 	t.Flux = jo.Flux
 	t.Status = jo.Status
 	return nil
@@ -242,6 +316,9 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Timezone is the IANA time zone Cron is evaluated in.
+		Timezone string `json:"timezone,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		Every options.Duration `json:"every,omitempty"`
 
@@ -251,9 +328,19 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		// CatchUp is the policy for the runs missed while the task was not scheduled.
+		CatchUp string `json:"catchUp,omitempty"`
+
+		MaxMissedRuns *int64 `json:"maxMissedRuns,omitempty"`
+
+		DependsOn *[]ID `json:"dependsOn,omitempty"`
+
+		Params *[]TaskParam `json:"params,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
+	jo.Timezone = t.Options.Timezone
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
 	if t.Options.Offset != nil {
@@ -262,6 +349,10 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 	}
 	jo.Concurrency = t.Options.Concurrency
 	jo.Retry = t.Options.Retry
+	jo.CatchUp = t.Options.CatchUp
+	jo.MaxMissedRuns = t.Options.MaxMissedRuns
+	jo.DependsOn = t.DependsOn
+	jo.Params = t.Params
 	jo.Flux = t.Flux
 	jo.Status = t.Status
 	return json.Marshal(jo)
@@ -279,7 +370,7 @@ func (t *TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid, the largest unit supported is h", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.DependsOn == nil && t.Params == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
@@ -335,6 +426,21 @@ func (t *TaskUpdate) UpdateFlux(parser FluxLanguageService, oldFlux string) (err
 	if t.Options.Cron != "" {
 		op["cron"] = &ast.StringLiteral{Value: t.Options.Cron}
 	}
+	if t.Options.Timezone != "" {
+		op["timezone"] = &ast.StringLiteral{Value: t.Options.Timezone}
+	} else if !t.Options.Every.IsZero() {
+		// a time zone only applies to cron schedules.
+		toDelete["timezone"] = struct{}{}
+	}
+	if t.Options.CatchUp != "" {
+		op["catchUp"] = &ast.StringLiteral{Value: t.Options.CatchUp}
+	}
+	if t.Options.MaxMissedRuns != nil {
+		op["maxMissedRuns"] = &ast.IntegerLiteral{Value: *t.Options.MaxMissedRuns}
+	} else if t.Options.CatchUp != "" && t.Options.CatchUp != options.CatchUpAll {
+		// a cap only applies to catching up all missed runs.
+		toDelete["maxMissedRuns"] = struct{}{}
+	}
 	if t.Options.Offset != nil {
 		if !t.Options.Offset.IsZero() {
 			op["offset"] = &t.Options.Offset.Node
@@ -364,6 +470,16 @@ func (t *TaskUpdate) UpdateFlux(parser FluxLanguageService, oldFlux string) (err
 						delete(op, "name")
 						p.Value = name
 					}
+				case "timezone":
+					if tz, ok := op["timezone"]; ok {
+						delete(op, "timezone")
+						p.Value = tz
+					}
+				case "catchUp", "maxMissedRuns":
+					if v, ok := op[k]; ok {
+						delete(op, k)
+						p.Value = v
+					}
 				case "offset":
 					if offset, ok := op["offset"]; ok && t.Options.Offset != nil {
 						delete(op, "offset")
@@ -458,12 +574,36 @@ type RunFilter struct {
 	// Task ID is required for listing runs.
 	Task ID
 
-	After      *ID
-	Limit      int
+	After *ID
+	Limit int
+	// AfterTime and BeforeTime are RFC3339 times the runs are scheduled
+	// after and before, unbounded if empty.
 	AfterTime  string
 	BeforeTime string
 }
 
+// ScheduledRange returns the times parsed from AfterTime and BeforeTime, the
+// zero time for a bound that is not set.
+func (f RunFilter) ScheduledRange() (after, before time.Time, err error) {
+	if f.AfterTime != "" {
+		if after, err = time.Parse(time.RFC3339, f.AfterTime); err != nil {
+			return after, before, &Error{Code: EInvalid, Msg: "afterTime is not an RFC3339 time", Err: err}
+		}
+	}
+	if f.BeforeTime != "" {
+		if before, err = time.Parse(time.RFC3339, f.BeforeTime); err != nil {
+			return after, before, &Error{Code: EInvalid, Msg: "beforeTime is not an RFC3339 time", Err: err}
+		}
+	}
+	return after, before, nil
+}
+
+// ScheduledWithin returns whether a run scheduled for scheduledFor is
+// scheduled after after and before before, the bounds of ScheduledRange.
+func ScheduledWithin(scheduledFor, after, before time.Time) bool {
+	return (after.IsZero() || scheduledFor.After(after)) && (before.IsZero() || scheduledFor.Before(before))
+}
+
 // LogFilter represents a set of filters that restrict the returned log results.
 type LogFilter struct {
 	// Task ID is required.
@@ -490,6 +630,8 @@ const (
 	RunFail
 	RunCanceled
 	RunScheduled
+	// RunRetried is the status of a failed run which is retried.
+	RunRetried
 )
 
 func (r RunStatus) String() string {
@@ -504,6 +646,8 @@ func (r RunStatus) String() string {
 		return "canceled"
 	case RunScheduled:
 		return "scheduled"
+	case RunRetried:
+		return "retried"
 	}
 	panic(fmt.Sprintf("unknown RunStatus: %d", r))
 }
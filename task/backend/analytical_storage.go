@@ -21,7 +21,12 @@ const (
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
+	retryOfField      = "retryOf"
+	retryField        = "retry"
 	logField          = "logs"
+	statsField        = "stats"
+	taskVersionField  = "taskVersion"
+	paramsField       = "params"
 
 	taskIDTag = "taskID"
 	statusTag = "status"
@@ -147,11 +152,25 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 		return runs, n, err
 	}
 
+	after, before, err := filter.ScheduledRange()
+	if err != nil {
+		return runs, n, err
+	}
+
 	filterPart := ""
 	if filter.After != nil {
 		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r.runID > %q)`, filter.After.String())
 	}
 
+	// scheduledFor is recorded as an RFC3339 time in UTC, which sorts as a string.
+	schedulePart := ""
+	if !after.IsZero() {
+		schedulePart += fmt.Sprintf(`|> filter(fn: (r) => r.scheduledFor > %q)`, after.UTC().Format(time.RFC3339))
+	}
+	if !before.IsZero() {
+		schedulePart += fmt.Sprintf(`|> filter(fn: (r) => r.scheduledFor < %q)`, before.UTC().Format(time.RFC3339))
+	}
+
 	// the data will be stored for 7 days in the system bucket so pulling 14d's is sufficient.
 	runsScript := fmt.Sprintf(`from(bucketID: %q)
 	  |> range(start: -14d)
@@ -159,11 +178,12 @@ func (as *AnalyticalStorage) FindRuns(ctx context.Context, filter influxdb.RunFi
 	  |> filter(fn: (r) => r._measurement == "runs" and r.taskID == %q)
 	  %s
 	  |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+	  %s
 	  |> group(columns: ["taskID"])
 	  |> sort(columns:["scheduledFor"], desc: true)
 	  |> limit(n:%d)
 
-	  `, sb.ID.String(), filter.Task.String(), filterPart, filter.Limit-len(runs))
+	  `, sb.ID.String(), filter.Task.String(), filterPart, schedulePart, filter.Limit-len(runs))
 
 	// At this point we are behind authorization
 	// so we are faking a read only permission to the org's system bucket
@@ -287,7 +307,7 @@ func (as *AnalyticalStorage) FindRunByID(ctx context.Context, taskID, runID infl
 	}
 	defer ittr.Release()
 
-	re := &runReader{}
+	re := &runReader{log: as.log.With(zap.String("component", "run-reader"), zap.String("taskID", taskID.String()))}
 	for ittr.More() {
 		err := ittr.Next().Tables().Do(re.readTable)
 		if err != nil {
@@ -332,9 +352,7 @@ func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxd
 		return run, err
 	}
 
-	sf := run.ScheduledFor
-
-	return as.ForceRun(ctx, taskID, sf.Unix())
+	return as.TaskControlService.CreateRetryRun(ctx, run, time.Now().UTC())
 }
 
 type runReader struct {
@@ -390,6 +408,23 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.ScheduledFor = scheduled.UTC()
+			case retryOfField:
+				if cr.Strings(j).ValueString(i) != "" {
+					id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+					if err != nil {
+						re.log.Info("Failed to parse retryOf", zap.Error(err))
+						continue
+					}
+					r.RetryOf = *id
+				}
+			case retryField:
+				if col.Type == flux.TInt && cr.Ints(j).IsValid(i) {
+					r.Retry = int(cr.Ints(j).Value(i))
+				}
+			case taskVersionField:
+				if col.Type == flux.TInt && cr.Ints(j).IsValid(i) {
+					r.TaskVersion = int(cr.Ints(j).Value(i))
+				}
 			case statusTag:
 				r.Status = cr.Strings(j).ValueString(i)
 			case finishedAtField:
@@ -399,6 +434,23 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.FinishedAt = finished.UTC()
+			case statsField:
+				statsBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
+				if len(statsBytes) != 0 {
+					var stats influxdb.RunStats
+					if err := json.Unmarshal(statsBytes, &stats); err != nil {
+						re.log.Info("Failed to parse stats data", zap.Error(err), zap.ByteString("stats_bytes", statsBytes))
+						continue
+					}
+					r.Stats = &stats
+				}
+			case paramsField:
+				paramsBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
+				if len(paramsBytes) != 0 {
+					if err := json.Unmarshal(paramsBytes, &r.Params); err != nil {
+						re.log.Info("Failed to parse params data", zap.Error(err), zap.ByteString("params_bytes", paramsBytes))
+					}
+				}
 			case logField:
 				logBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
 				if len(logBytes) != 0 {
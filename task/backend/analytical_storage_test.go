@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
@@ -118,6 +119,114 @@ func TestDeduplicateRuns(t *testing.T) {
 	}
 }
 
+func TestRetriedRunLink(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), logger, store); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(logger, store)
+
+	ab := newAnalyticalBackend(t, svc, svc)
+	defer ab.Close(t)
+
+	mockTS := &mock.TaskService{
+		FindTaskByIDFn: func(context.Context, influxdb.ID) (*influxdb.Task, error) {
+			return &influxdb.Task{ID: 1, OrganizationID: 20}, nil
+		},
+		FindRunsFn: func(context.Context, influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+			return nil, 0, nil
+		},
+	}
+	mockTCS := &mock.TaskControlService{
+		FinishRunFn: func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+			return &influxdb.Run{ID: 3, TaskID: 1, Status: "failed", ScheduledFor: time.Now(), StartedAt: time.Now().Add(1), FinishedAt: time.Now().Add(2), RetryOf: 2, Retry: 1}, nil
+		},
+	}
+	mockBS := mock.NewBucketService()
+
+	svcStack := backend.NewAnalyticalStorage(zaptest.NewLogger(t), mockTS, mockBS, mockTCS, ab.PointsWriter(), ab.QueryService())
+
+	if _, err := svcStack.FinishRun(context.Background(), 1, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, _, err := svcStack.FindRuns(context.Background(), influxdb.RunFilter{Task: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run but got %d", len(runs))
+	}
+
+	if runs[0].RetryOf != 2 || runs[0].Retry != 1 {
+		t.Fatalf("expected retry 1 of run 2, got retry %d of run %s", runs[0].Retry, runs[0].RetryOf)
+	}
+}
+
+func TestRunStatsRecorded(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), logger, store); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(logger, store)
+
+	ab := newAnalyticalBackend(t, svc, svc)
+	defer ab.Close(t)
+
+	stats := &influxdb.RunStats{
+		RowsRead:      120,
+		PointsWritten: map[influxdb.ID]int64{30: 12, 31: 3},
+		QueryDuration: influxdb.Duration{Duration: 1500 * time.Millisecond},
+		MaxAllocated:  4096,
+	}
+	logs := []influxdb.Log{
+		{RunID: 3, Time: time.Now().UTC().Format(time.RFC3339Nano), Level: influxdb.LogLevelInfo, Message: "Query statistics", Fields: map[string]string{"rowsRead": "120"}},
+		{RunID: 3, Time: time.Now().UTC().Format(time.RFC3339Nano), Level: influxdb.LogLevelError, Message: "query failed"},
+	}
+
+	mockTS := &mock.TaskService{
+		FindTaskByIDFn: func(context.Context, influxdb.ID) (*influxdb.Task, error) {
+			return &influxdb.Task{ID: 1, OrganizationID: 20}, nil
+		},
+		FindRunsFn: func(context.Context, influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+			return nil, 0, nil
+		},
+	}
+	mockTCS := &mock.TaskControlService{
+		FinishRunFn: func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+			return &influxdb.Run{ID: 3, TaskID: 1, Status: "failed", ScheduledFor: time.Now(), StartedAt: time.Now().Add(1), FinishedAt: time.Now().Add(2), Stats: stats, Log: logs}, nil
+		},
+	}
+	mockBS := mock.NewBucketService()
+
+	svcStack := backend.NewAnalyticalStorage(zaptest.NewLogger(t), mockTS, mockBS, mockTCS, ab.PointsWriter(), ab.QueryService())
+
+	if _, err := svcStack.FinishRun(context.Background(), 1, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, _, err := svcStack.FindRuns(context.Background(), influxdb.RunFilter{Task: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run but got %d", len(runs))
+	}
+
+	if diff := cmp.Diff(stats, runs[0].Stats); diff != "" {
+		t.Fatalf("unexpected run stats -want/+got:\n%s", diff)
+	}
+	if diff := cmp.Diff(logs, runs[0].Log); diff != "" {
+		t.Fatalf("unexpected run logs -want/+got:\n%s", diff)
+	}
+}
+
 type analyticalBackend struct {
 	queryController *control.Controller
 	rootDir         string
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
@@ -40,16 +41,16 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 				continue
 			}
 
-			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
-				LatestCompleted: &latestCompleted,
-				LatestScheduled: &latestCompleted,
-			})
+			task, err := skipMissedRuns(ctx, ts, task, latestCompleted)
 			if err != nil {
 				log.Error("Failed to set latestCompleted", zap.Error(err))
 				continue
 			}
 
-			coord.TaskCreated(ctx, task)
+			if err := coord.TaskCreated(ctx, task); err != nil {
+				log.Error("Failed to schedule task", zap.String("taskID", task.ID.String()), zap.Error(err))
+				continue
+			}
 		}
 
 		tasks, _, err = ts.FindTasks(ctx, influxdb.TaskFilter{
@@ -63,12 +64,30 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 	return nil
 }
 
+// skipMissedRuns moves the task forward to latestCompleted, skipping the runs
+// it missed while influxd was down, unless it sets the catchUp option. A task
+// that sets it resumes from when it was last scheduled, and catches up the
+// runs missed since as its catch-up policy says.
+func skipMissedRuns(ctx context.Context, ts TaskService, task *influxdb.Task, latestCompleted time.Time) (*influxdb.Task, error) {
+	if task.CatchUp != "" {
+		return task, nil
+	}
+	return ts.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{
+		LatestCompleted: &latestCompleted,
+		LatestScheduled: &latestCompleted,
+	})
+}
+
 type TaskResumer func(ctx context.Context, id influxdb.ID, runID influxdb.ID) error
 
+// RetryResumer starts the queued retries of the failed runs of the task id once
+// they are due.
+type RetryResumer func(ctx context.Context, id influxdb.ID) error
+
 // TaskNotifyCoordinatorOfExisting lists all tasks by the provided task service and for
 // each task it calls the provided coordinators task created method
 // TODO(docmerlin): this is temporary untill the executor queue is persistent
-func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs TaskControlService, coord Coordinator, exec TaskResumer, log *zap.Logger) error {
+func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs TaskControlService, coord Coordinator, exec TaskResumer, retries RetryResumer, log *zap.Logger) error {
 	// If we missed a Create Action
 	tasks, _, err := ts.FindTasks(ctx, influxdb.TaskFilter{})
 	if err != nil {
@@ -82,16 +101,15 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 				continue
 			}
 
-			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
-				LatestCompleted: &latestCompleted,
-				LatestScheduled: &latestCompleted,
-			})
+			task, err := skipMissedRuns(ctx, ts, task, latestCompleted)
 			if err != nil {
 				log.Error("Failed to set latestCompleted", zap.Error(err))
 				continue
 			}
 
-			coord.TaskCreated(ctx, task)
+			if err := coord.TaskCreated(ctx, task); err != nil {
+				log.Error("Failed to schedule task", zap.String("taskID", task.ID.String()), zap.Error(err))
+			}
 			runs, err := tcs.CurrentlyRunning(ctx, task.ID)
 			if err != nil {
 				return err
@@ -101,6 +119,9 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 					return err
 				}
 			}
+			if err := retries(ctx, task.ID); err != nil {
+				return err
+			}
 		}
 
 		tasks, _, err = ts.FindTasks(ctx, influxdb.TaskFilter{
@@ -113,3 +134,33 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 
 	return nil
 }
+
+// ResumeRunning resumes the runs of the task taskID still marked running, left
+// by a process that will not finish them. The runs that cannot be resumed are
+// failed, so that they do not stay running forever.
+func ResumeRunning(ctx context.Context, tcs TaskControlService, taskID influxdb.ID, exec TaskResumer, log *zap.Logger) error {
+	runs, err := tcs.CurrentlyRunning(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		rerr := exec(ctx, r.TaskID, r.ID)
+		if rerr == nil {
+			continue
+		}
+
+		log.Info("Failing run that could not be resumed", zap.String("taskID", r.TaskID.String()), zap.String("runID", r.ID.String()), zap.Error(rerr))
+		now := time.Now().UTC()
+		if err := tcs.AddRunLog(ctx, r.TaskID, r.ID, now, fmt.Sprintf("Failed to resume run: %v", rerr)); err != nil {
+			return err
+		}
+		if err := tcs.UpdateRunState(ctx, r.TaskID, r.ID, now, influxdb.RunFail); err != nil {
+			return err
+		}
+		if _, err := tcs.FinishRun(ctx, r.TaskID, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,10 @@ var _ Executor = (*executor.Executor)(nil)
 // DefaultLimit is the maximum number of tasks that a given taskd server can own
 const DefaultLimit = 1000
 
+var now = func() time.Time {
+	return time.Now().UTC()
+}
+
 // Executor is an abstraction of the task executor with only the functions needed by the coordinator
 type Executor interface {
 	ManualRun(ctx context.Context, id influxdb.ID, runID influxdb.ID) (executor.Promise, error)
@@ -67,7 +71,9 @@ func WithLimitOpt(i int) CoordinatorOption {
 	}
 }
 
-// NewSchedulableTask transforms an influxdb task to a schedulable task type
+// NewSchedulableTask transforms an influxdb task to a schedulable task type.
+// The runs the task missed since it was last scheduled, e.g. while influxd was
+// down, are caught up as its catchUp and maxMissedRuns options say.
 func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 
 	if task.Cron == "" && task.Every == "" {
@@ -81,9 +87,17 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 		ts = task.LatestScheduled
 	}
 
+	loc, err := task.Location()
+	if err != nil {
+		return SchedulableTask{}, err
+	}
+
 	var sch scheduler.Schedule
-	var err error
-	sch, ts, err = scheduler.NewSchedule(effCron, ts)
+	sch, ts, err = scheduler.NewScheduleInLocation(effCron, loc, ts)
+	if err != nil {
+		return SchedulableTask{}, err
+	}
+	ts, err = sch.CatchUpFrom(ts, now(), task.Offset, task.CatchUp, task.MaxMissedRuns)
 	if err != nil {
 		return SchedulableTask{}, err
 	}
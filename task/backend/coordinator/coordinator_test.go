@@ -116,6 +116,63 @@ func TestNewSchedulableTask(t *testing.T) {
 		t.Fatalf("expected SchedulableTask's LatestScheduled to equal %s but it was %s", now.Truncate(time.Second), schedulableT.LastScheduled())
 	}
 
+	// 02:00 in Berlin is 00:00 UTC in summer.
+	summer := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	taskThree := &influxdb.Task{ID: one, CreatedAt: summer, Cron: "0 2 * * *", Timezone: "Europe/Berlin", LatestCompleted: summer}
+	schedulableT, err = NewSchedulableTask(taskThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := schedulableT.Schedule().Next(summer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := summer.Add(24 * time.Hour); !next.Equal(exp) {
+		t.Fatalf("expected the next run of the task to be scheduled for %s but it was %s", exp, next)
+	}
+
+	taskFour := &influxdb.Task{ID: one, CreatedAt: now, Cron: "0 2 * * *", Timezone: "Nowhere/Special", LatestCompleted: now}
+	if _, err := NewSchedulableTask(taskFour); err == nil {
+		t.Fatal("expected an error for a task with an unknown timezone")
+	}
+}
+
+func TestNewSchedulableTask_CatchUp(t *testing.T) {
+	down := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	up := down.Add(5*time.Hour + 30*time.Minute)
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return up }
+
+	for _, test := range []struct {
+		name          string
+		catchUp       string
+		maxMissedRuns int64
+		want          time.Time
+	}{
+		{name: "all", want: down},
+		{name: "all capped", catchUp: "all", maxMissedRuns: 3, want: down.Add(2 * time.Hour)},
+		{name: "latest", catchUp: "latest", want: down.Add(4 * time.Hour)},
+		{name: "skip", catchUp: "skip", want: down.Add(5 * time.Hour)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			task := &influxdb.Task{
+				ID:              1,
+				CreatedAt:       down.Add(-time.Hour),
+				Every:           "1h",
+				CatchUp:         test.catchUp,
+				MaxMissedRuns:   test.maxMissedRuns,
+				LatestCompleted: down,
+				LatestScheduled: down,
+			}
+			st, err := NewSchedulableTask(task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !st.LastScheduled().Equal(test.want) {
+				t.Fatalf("expected SchedulableTask's LatestScheduled to equal %s but it was %s", test.want, st.LastScheduled())
+			}
+		})
+	}
 }
 
 func Test_Coordinator_Scheduler_Methods(t *testing.T) {
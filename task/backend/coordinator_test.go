@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ var (
 	two   = influxdb.ID(2)
 	three = influxdb.ID(3)
 	four  = influxdb.ID(4)
+	five  = influxdb.ID(5)
 
 	aTime = time.Now().UTC()
 
@@ -22,12 +24,14 @@ var (
 	taskTwo   = &influxdb.Task{ID: two, Status: "active"}
 	taskThree = &influxdb.Task{ID: three, Status: "inactive"}
 	taskFour  = &influxdb.Task{ID: four}
+	taskFive  = &influxdb.Task{ID: five, Status: "active", CatchUp: "all"}
 
 	allTasks = map[influxdb.ID]*influxdb.Task{
 		one:   taskOne,
 		two:   taskTwo,
 		three: taskThree,
 		four:  taskFour,
+		five:  taskFive,
 	}
 )
 
@@ -39,7 +43,7 @@ func Test_NotifyCoordinatorOfCreated(t *testing.T) {
 			pageOne: []*influxdb.Task{taskOne},
 			otherPages: map[influxdb.ID][]*influxdb.Task{
 				one:   []*influxdb.Task{taskTwo, taskThree},
-				three: []*influxdb.Task{taskFour},
+				three: []*influxdb.Task{taskFour, taskFive},
 			},
 		}
 	)
@@ -54,6 +58,7 @@ func Test_NotifyCoordinatorOfCreated(t *testing.T) {
 		t.Errorf("expected nil, found %q", err)
 	}
 
+	// the runs taskTwo missed are skipped, taskFive catches them up
 	if diff := cmp.Diff([]update{
 		{two, influxdb.TaskUpdate{LatestCompleted: &aTime, LatestScheduled: &aTime}},
 	}, tasks.updates); diff != "" {
@@ -62,6 +67,7 @@ func Test_NotifyCoordinatorOfCreated(t *testing.T) {
 
 	if diff := cmp.Diff([]*influxdb.Task{
 		taskTwo,
+		taskFive,
 	}, coordinator.tasks); diff != "" {
 		t.Errorf("unexpected tasks sent to coordinator %v", diff)
 	}
@@ -110,3 +116,65 @@ func (t *taskService) FindTasks(_ context.Context, filter influxdb.TaskFilter) (
 	tasks := t.otherPages[*filter.After]
 	return tasks, len(tasks), nil
 }
+
+// runControl is a task control service recording the runs failed.
+type runControl struct {
+	TaskControlService
+
+	running  []*influxdb.Run
+	failed   []influxdb.ID
+	finished []influxdb.ID
+}
+
+func (c *runControl) CurrentlyRunning(_ context.Context, _ influxdb.ID) ([]*influxdb.Run, error) {
+	return c.running, nil
+}
+
+func (c *runControl) AddRunLog(_ context.Context, _, _ influxdb.ID, _ time.Time, _ string) error {
+	return nil
+}
+
+func (c *runControl) UpdateRunState(_ context.Context, _, runID influxdb.ID, _ time.Time, state influxdb.RunStatus) error {
+	if state == influxdb.RunFail {
+		c.failed = append(c.failed, runID)
+	}
+	return nil
+}
+
+func (c *runControl) FinishRun(_ context.Context, _, runID influxdb.ID) (*influxdb.Run, error) {
+	c.finished = append(c.finished, runID)
+	return nil, nil
+}
+
+func Test_ResumeRunning(t *testing.T) {
+	tcs := &runControl{
+		running: []*influxdb.Run{
+			{ID: one, TaskID: four},
+			{ID: two, TaskID: four},
+		},
+	}
+
+	var resumed []influxdb.ID
+	resume := func(_ context.Context, _ influxdb.ID, runID influxdb.ID) error {
+		if runID == two {
+			return errors.New("task not found")
+		}
+		resumed = append(resumed, runID)
+		return nil
+	}
+
+	if err := ResumeRunning(context.Background(), tcs, four, resume, zaptest.NewLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]influxdb.ID{one}, resumed); diff != "" {
+		t.Errorf("unexpected runs resumed %v", diff)
+	}
+	// the run that cannot be resumed is failed instead of staying running.
+	if diff := cmp.Diff([]influxdb.ID{two}, tcs.failed); diff != "" {
+		t.Errorf("unexpected runs failed %v", diff)
+	}
+	if diff := cmp.Diff([]influxdb.ID{two}, tcs.finished); diff != "" {
+		t.Errorf("unexpected runs finished %v", diff)
+	}
+}
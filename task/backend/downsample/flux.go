@@ -0,0 +1,134 @@
+package downsample
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/flux"
+)
+
+// TaskName returns the name of the task materializing a policy from src into dst.
+func TaskName(src, dst *influxdb.Bucket) string {
+	return fmt.Sprintf("Downsample %s to %s", src.Name, dst.Name)
+}
+
+// GenerateFlux returns the script of the task materializing policy p from src into dst.
+//
+// The task is scheduled every window and offset by the lag of the policy. Because
+// now() is the scheduled time of a run, each run aggregates exactly the previous
+// window; late data arriving within the lag is still included.
+func GenerateFlux(src, dst *influxdb.Bucket, p influxdb.DownsamplePolicy) string {
+	return ast.Format(GenerateFluxAST(src, dst, p))
+}
+
+// GenerateFluxAST returns the flux AST of the task materializing policy p from src into dst.
+func GenerateFluxAST(src, dst *influxdb.Bucket, p influxdb.DownsamplePolicy) *ast.File {
+	body := []ast.Statement{
+		taskOption(TaskName(src, dst), p),
+		flux.DefineVariable("data", flux.Pipe(
+			flux.Call(flux.Identifier("from"), flux.Object(
+				flux.Property("bucketID", flux.String(src.ID.String())),
+			)),
+			flux.Call(flux.Identifier("range"), flux.Object(
+				flux.Property("start", flux.Negative(taskEvery())),
+			)),
+		)),
+	}
+
+	// Fields listed by an aggregate are excluded from the one that omits its fields.
+	var listed []string
+	for _, a := range p.Aggregates {
+		listed = append(listed, a.Fields...)
+	}
+
+	for _, a := range p.Aggregates {
+		var calls []*ast.CallExpression
+		if len(a.Fields) > 0 {
+			calls = append(calls, fieldFilter(ast.EqualOperator, ast.OrOperator, a.Fields))
+		} else if len(listed) > 0 {
+			calls = append(calls, fieldFilter(ast.NotEqualOperator, ast.AndOperator, listed))
+		}
+		calls = append(calls,
+			flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+				flux.Property("every", taskEvery()),
+				flux.Property("fn", flux.Identifier(a.Function)),
+				flux.Property("createEmpty", flux.Bool(false)),
+			)),
+			flux.Call(flux.Identifier("to"), flux.Object(
+				flux.Property("bucketID", flux.String(dst.ID.String())),
+				flux.Property("orgID", flux.String(dst.OrgID.String())),
+			)),
+		)
+		body = append(body, flux.ExpressionStatement(flux.Pipe(flux.Identifier("data"), calls...)))
+	}
+
+	return flux.File("", nil, body)
+}
+
+func taskOption(name string, p influxdb.DownsamplePolicy) ast.Statement {
+	props := []*ast.Property{
+		flux.Property("name", flux.String(name)),
+		flux.Property("every", durationLiteral(p.Every.Duration)),
+	}
+	if p.Lag.Duration > 0 {
+		props = append(props, flux.Property("offset", durationLiteral(p.Lag.Duration)))
+	}
+	return flux.DefineTaskOption(flux.Object(props...))
+}
+
+func taskEvery() *ast.MemberExpression {
+	return &ast.MemberExpression{
+		Object:   flux.Identifier("task"),
+		Property: flux.Identifier("every"),
+	}
+}
+
+// fieldFilter returns a filter comparing _field against each of fields with
+// op and joining the comparisons with join.
+func fieldFilter(op ast.OperatorKind, join ast.LogicalOperatorKind, fields []string) *ast.CallExpression {
+	var expr ast.Expression
+	for _, f := range fields {
+		cmp := &ast.BinaryExpression{
+			Operator: op,
+			Left: &ast.MemberExpression{
+				Object:   flux.Identifier("r"),
+				Property: flux.Identifier("_field"),
+			},
+			Right: flux.String(f),
+		}
+		if expr == nil {
+			expr = cmp
+			continue
+		}
+		expr = &ast.LogicalExpression{Operator: join, Left: expr, Right: cmp}
+	}
+	return flux.Call(flux.Identifier("filter"), flux.Object(
+		flux.Property("fn", flux.Function(flux.FunctionParams("r"), expr)),
+	))
+}
+
+var durationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// durationLiteral returns the shortest duration literal for d.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	lit := &ast.DurationLiteral{}
+	for _, u := range durationUnits {
+		if m := d / u.d; m > 0 {
+			lit.Values = append(lit.Values, ast.Duration{Magnitude: int64(m), Unit: u.unit})
+			d -= m * u.d
+		}
+	}
+	return lit
+}
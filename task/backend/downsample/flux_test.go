@@ -0,0 +1,77 @@
+package downsample_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/backend/downsample"
+)
+
+func TestGenerateFlux(t *testing.T) {
+	src := &influxdb.Bucket{ID: 1, OrgID: 3, Name: "telegraf"}
+	dst := &influxdb.Bucket{ID: 2, OrgID: 3, Name: "telegraf_1h"}
+
+	tests := []struct {
+		name   string
+		policy influxdb.DownsamplePolicy
+		want   string
+	}{
+		{
+			name: "single aggregate",
+			policy: influxdb.DownsamplePolicy{
+				Every: influxdb.Duration{Duration: time.Hour},
+				Aggregates: []influxdb.DownsampleAggregate{
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "last"},
+				},
+			},
+			want: `option task = {name: "Downsample telegraf to telegraf_1h", every: 1h}
+
+data = from(bucketID: "0000000000000001")
+	|> range(start: -task.every)
+
+data
+	|> aggregateWindow(every: task.every, fn: last, createEmpty: false)
+	|> to(bucketID: "0000000000000002", orgID: "0000000000000003")`,
+		},
+		{
+			name: "aggregates per field type with lag",
+			policy: influxdb.DownsamplePolicy{
+				Every: influxdb.Duration{Duration: 90 * time.Minute},
+				Lag:   influxdb.Duration{Duration: 30 * time.Second},
+				Aggregates: []influxdb.DownsampleAggregate{
+					{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean", Fields: []string{"usage", "load"}},
+					{FieldType: influxdb.DownsampleFieldTypeString, Function: "last"},
+					{FieldType: influxdb.DownsampleFieldTypeInteger, Function: "sum", Fields: []string{"requests"}},
+				},
+			},
+			want: `option task = {name: "Downsample telegraf to telegraf_1h", every: 1h30m, offset: 30s}
+
+data = from(bucketID: "0000000000000001")
+	|> range(start: -task.every)
+
+data
+	|> filter(fn: (r) =>
+		(r._field == "usage" or r._field == "load"))
+	|> aggregateWindow(every: task.every, fn: mean, createEmpty: false)
+	|> to(bucketID: "0000000000000002", orgID: "0000000000000003")
+data
+	|> filter(fn: (r) =>
+		(r._field != "usage" and r._field != "load" and r._field != "requests"))
+	|> aggregateWindow(every: task.every, fn: last, createEmpty: false)
+	|> to(bucketID: "0000000000000002", orgID: "0000000000000003")
+data
+	|> filter(fn: (r) =>
+		(r._field == "requests"))
+	|> aggregateWindow(every: task.every, fn: sum, createEmpty: false)
+	|> to(bucketID: "0000000000000002", orgID: "0000000000000003")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := downsample.GenerateFlux(src, dst, tt.policy); got != tt.want {
+				t.Errorf("unexpected flux:\n\twant=\n%s\n\tgot=\n%s", tt.want, got)
+			}
+		})
+	}
+}
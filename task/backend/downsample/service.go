@@ -0,0 +1,306 @@
+// Package downsample materializes the downsample policies of buckets into
+// managed tasks.
+package downsample
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"go.uber.org/zap"
+)
+
+// TaskType is the type of the tasks managed on behalf of downsample policies.
+// Tasks of this type are not listed alongside user tasks.
+const TaskType = "downsample"
+
+// BucketService is a BucketService decorator that keeps a managed task for each
+// downsample policy of a bucket. Tasks are created, rewritten and deleted as the
+// policies of the bucket change, and their state is reported in the policy status.
+type BucketService struct {
+	influxdb.BucketService
+	log         *zap.Logger
+	taskService influxdb.TaskService
+}
+
+var _ influxdb.BucketService = (*BucketService)(nil)
+
+// NewBucketService constructs a bucket service managing downsample tasks with ts.
+func NewBucketService(log *zap.Logger, bs influxdb.BucketService, ts influxdb.TaskService) *BucketService {
+	return &BucketService{
+		BucketService: bs,
+		log:           log,
+		taskService:   ts,
+	}
+}
+
+// FindBucketByID returns a single bucket by ID.
+func (s *BucketService) FindBucketByID(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.setStatus(ctx, b)
+	return b, nil
+}
+
+// FindBucketByName returns a single bucket by name.
+func (s *BucketService) FindBucketByName(ctx context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+	b, err := s.BucketService.FindBucketByName(ctx, orgID, name)
+	if err != nil {
+		return nil, err
+	}
+	s.setStatus(ctx, b)
+	return b, nil
+}
+
+// FindBucket returns the first bucket that matches filter.
+func (s *BucketService) FindBucket(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+	b, err := s.BucketService.FindBucket(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.setStatus(ctx, b)
+	return b, nil
+}
+
+// FindBuckets returns a list of buckets that match filter and the total count of matching buckets.
+func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketFilter, opt ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+	bs, n, err := s.BucketService.FindBuckets(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, b := range bs {
+		s.setStatus(ctx, b)
+	}
+	return bs, n, nil
+}
+
+// CreateBucket creates the bucket and a task for each of its downsample policies.
+func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	policies := b.DownsamplePolicies
+	if len(policies) == 0 {
+		return s.BucketService.CreateBucket(ctx, b)
+	}
+	if err := influxdb.ValidDownsamplePolicies(b.ID, policies); err != nil {
+		return err
+	}
+	if err := s.authorizeDestinations(ctx, b, policies); err != nil {
+		return err
+	}
+
+	b.DownsamplePolicies = nil
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	policies, err := s.syncTasks(ctx, b, nil, policies)
+	if err != nil {
+		if derr := s.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+			s.log.Error("Failed to remove bucket after downsample task creation failed", zap.Stringer("bucketID", b.ID), zap.Error(derr))
+		}
+		return err
+	}
+
+	upd, err := s.BucketService.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{DownsamplePolicies: &policies})
+	if err != nil {
+		s.deleteTasks(ctx, policies)
+		if derr := s.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+			s.log.Error("Failed to remove bucket after downsample task creation failed", zap.Stringer("bucketID", b.ID), zap.Error(derr))
+		}
+		return err
+	}
+	*b = *upd
+	s.setStatus(ctx, b)
+	return nil
+}
+
+// UpdateBucket updates the bucket and rewrites the tasks of its downsample policies.
+// Tasks of policies no longer attached to the bucket are deleted.
+func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	if upd.DownsamplePolicies == nil && upd.Name == nil {
+		b, err := s.BucketService.UpdateBucket(ctx, id, upd)
+		if err != nil {
+			return nil, err
+		}
+		s.setStatus(ctx, b)
+		return b, nil
+	}
+
+	from, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	to := *from
+	if upd.Name != nil {
+		to.Name = *upd.Name
+	}
+	policies := from.DownsamplePolicies
+	if upd.DownsamplePolicies != nil {
+		policies = *upd.DownsamplePolicies
+		if err := influxdb.ValidDownsamplePolicies(id, policies); err != nil {
+			return nil, err
+		}
+		if err := s.authorizeDestinations(ctx, &to, policies); err != nil {
+			return nil, err
+		}
+	}
+
+	policies, err = s.syncTasks(ctx, &to, from.DownsamplePolicies, policies)
+	if err != nil {
+		return nil, err
+	}
+	upd.DownsamplePolicies = &policies
+
+	b, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.setStatus(ctx, b)
+	return b, nil
+}
+
+// DeleteBucket removes the bucket and the tasks of its downsample policies.
+func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+	s.deleteTasks(ctx, b.DownsamplePolicies)
+	return nil
+}
+
+// authorizeDestinations ensures every destination bucket exists in the
+// organization of src and that the caller may read src and write them.
+func (s *BucketService) authorizeDestinations(ctx context.Context, src *influxdb.Bucket, policies []influxdb.DownsamplePolicy) error {
+	if src.ID.Valid() {
+		if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.BucketsResourceType, src.ID, src.OrgID); err != nil {
+			return err
+		}
+	}
+	for _, p := range policies {
+		dst, err := s.BucketService.FindBucketByID(ctx, p.DestinationBucketID)
+		if err != nil {
+			return err
+		}
+		if dst.OrgID != src.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "downsample destination must belong to the organization of the bucket",
+			}
+		}
+		if _, _, err := authorizer.AuthorizeWrite(ctx, influxdb.BucketsResourceType, dst.ID, dst.OrgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncTasks brings the managed tasks of src in line with policies. Tasks of
+// policies from the previous state are rewritten when the destination is
+// unchanged and deleted otherwise. The returned policies carry their task IDs.
+func (s *BucketService) syncTasks(ctx context.Context, src *influxdb.Bucket, from, policies []influxdb.DownsamplePolicy) ([]influxdb.DownsamplePolicy, error) {
+	existing := make(map[influxdb.ID]influxdb.ID, len(from))
+	for _, p := range from {
+		if p.TaskID.Valid() {
+			existing[p.DestinationBucketID] = p.TaskID
+		}
+	}
+
+	var created []influxdb.DownsamplePolicy
+	synced := make([]influxdb.DownsamplePolicy, 0, len(policies))
+	for _, p := range policies {
+		p.Status = nil
+
+		dst, err := s.BucketService.FindBucketByID(ctx, p.DestinationBucketID)
+		if err != nil {
+			s.deleteTasks(ctx, created)
+			return nil, err
+		}
+		script := GenerateFlux(src, dst, p)
+
+		if taskID, ok := existing[p.DestinationBucketID]; ok {
+			delete(existing, p.DestinationBucketID)
+			_, err := s.taskService.UpdateTask(ctx, taskID, influxdb.TaskUpdate{Flux: &script})
+			if err == nil {
+				p.TaskID = taskID
+				synced = append(synced, p)
+				continue
+			}
+			if influxdb.ErrorCode(err) != influxdb.ENotFound {
+				s.deleteTasks(ctx, created)
+				return nil, err
+			}
+			// the task was removed out of band, so it is created again below.
+		}
+
+		t, err := s.createTask(ctx, src, script)
+		if err != nil {
+			s.deleteTasks(ctx, created)
+			return nil, err
+		}
+		p.TaskID = t.ID
+		created = append(created, p)
+		synced = append(synced, p)
+	}
+
+	for _, taskID := range existing {
+		s.deleteTask(ctx, taskID)
+	}
+	return synced, nil
+}
+
+func (s *BucketService) createTask(ctx context.Context, src *influxdb.Bucket, script string) (*influxdb.Task, error) {
+	userID, err := icontext.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.taskService.CreateTask(ctx, influxdb.TaskCreate{
+		Type:           TaskType,
+		Flux:           script,
+		Description:    "Managed by the downsample policy of bucket " + src.ID.String(),
+		OrganizationID: src.OrgID,
+		OwnerID:        userID,
+	})
+}
+
+func (s *BucketService) deleteTasks(ctx context.Context, policies []influxdb.DownsamplePolicy) {
+	for _, p := range policies {
+		if p.TaskID.Valid() {
+			s.deleteTask(ctx, p.TaskID)
+		}
+	}
+}
+
+// deleteTask removes a managed task. Failures leave an orphaned task behind,
+// which is logged rather than failing the bucket operation.
+func (s *BucketService) deleteTask(ctx context.Context, id influxdb.ID) {
+	if err := s.taskService.DeleteTask(ctx, id); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		s.log.Error("Failed to delete downsample task", zap.Stringer("taskID", id), zap.Error(err))
+	}
+}
+
+// setStatus reports the state of the managed task of each policy of b.
+func (s *BucketService) setStatus(ctx context.Context, b *influxdb.Bucket) {
+	for i, p := range b.DownsamplePolicies {
+		if !p.TaskID.Valid() {
+			continue
+		}
+		t, err := s.taskService.FindTaskByID(ctx, p.TaskID)
+		if err != nil {
+			s.log.Debug("Failed to find downsample task", zap.Stringer("taskID", p.TaskID), zap.Error(err))
+			continue
+		}
+		b.DownsamplePolicies[i].Status = &influxdb.DownsampleStatus{
+			TaskStatus:      t.Status,
+			LatestCompleted: t.LatestCompleted,
+			LastRunStatus:   t.LastRunStatus,
+			LastRunError:    t.LastRunError,
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package downsample_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backend/downsample"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeServices struct {
+	buckets map[influxdb.ID]*influxdb.Bucket
+	tasks   map[influxdb.ID]*influxdb.Task
+	nextID  influxdb.ID
+
+	bucketSvc *mock.BucketService
+	taskSvc   *mock.TaskService
+}
+
+func newFakeServices() *fakeServices {
+	f := &fakeServices{
+		buckets: map[influxdb.ID]*influxdb.Bucket{
+			2: {ID: 2, OrgID: 9, Name: "dest"},
+			3: {ID: 3, OrgID: 9, Name: "other"},
+		},
+		tasks:  map[influxdb.ID]*influxdb.Task{},
+		nextID: 100,
+	}
+
+	f.bucketSvc = mock.NewBucketService()
+	f.bucketSvc.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		b, ok := f.buckets[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		cp := *b
+		cp.DownsamplePolicies = append([]influxdb.DownsamplePolicy(nil), b.DownsamplePolicies...)
+		return &cp, nil
+	}
+	f.bucketSvc.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+		b.ID = 1
+		cp := *b
+		f.buckets[b.ID] = &cp
+		return nil
+	}
+	f.bucketSvc.UpdateBucketFn = func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		b := f.buckets[id]
+		if upd.Name != nil {
+			b.Name = *upd.Name
+		}
+		if upd.DownsamplePolicies != nil {
+			b.DownsamplePolicies = *upd.DownsamplePolicies
+		}
+		return f.bucketSvc.FindBucketByIDFn(ctx, id)
+	}
+	f.bucketSvc.DeleteBucketFn = func(_ context.Context, id influxdb.ID) error {
+		delete(f.buckets, id)
+		return nil
+	}
+
+	f.taskSvc = mock.NewTaskService()
+	f.taskSvc.CreateTaskFn = func(_ context.Context, tc influxdb.TaskCreate) (*influxdb.Task, error) {
+		f.nextID++
+		t := &influxdb.Task{ID: f.nextID, Type: tc.Type, OrganizationID: tc.OrganizationID, OwnerID: tc.OwnerID, Flux: tc.Flux, Status: string(influxdb.TaskActive)}
+		f.tasks[t.ID] = t
+		return t, nil
+	}
+	f.taskSvc.UpdateTaskFn = func(_ context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
+		t, ok := f.tasks[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "task not found"}
+		}
+		t.Flux = *upd.Flux
+		return t, nil
+	}
+	f.taskSvc.DeleteTaskFn = func(_ context.Context, id influxdb.ID) error {
+		delete(f.tasks, id)
+		return nil
+	}
+	f.taskSvc.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		t, ok := f.tasks[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "task not found"}
+		}
+		return t, nil
+	}
+	return f
+}
+
+func policy(dest influxdb.ID) influxdb.DownsamplePolicy {
+	return influxdb.DownsamplePolicy{
+		DestinationBucketID: dest,
+		Every:               influxdb.Duration{Duration: time.Hour},
+		Aggregates: []influxdb.DownsampleAggregate{
+			{FieldType: influxdb.DownsampleFieldTypeFloat, Function: "mean", Fields: []string{"usage"}},
+			{FieldType: influxdb.DownsampleFieldTypeString, Function: "last"},
+		},
+	}
+}
+
+func TestBucketService(t *testing.T) {
+	ctx := icontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(true, nil))
+
+	t.Run("create materializes policies into tasks", func(t *testing.T) {
+		f := newFakeServices()
+		svc := downsample.NewBucketService(zaptest.NewLogger(t), f.bucketSvc, f.taskSvc)
+
+		b := &influxdb.Bucket{OrgID: 9, Name: "src", DownsamplePolicies: []influxdb.DownsamplePolicy{policy(2)}}
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(f.tasks) != 1 {
+			t.Fatalf("expected 1 task, got %d", len(f.tasks))
+		}
+		p := b.DownsamplePolicies[0]
+		task := f.tasks[p.TaskID]
+		if task == nil {
+			t.Fatalf("policy references unknown task %s", p.TaskID)
+		}
+		if task.Type != downsample.TaskType || task.OrganizationID != 9 || task.OwnerID != 2 {
+			t.Errorf("unexpected task: %+v", task)
+		}
+		if want := downsample.GenerateFlux(b, f.buckets[2], p); task.Flux != want {
+			t.Errorf("unexpected flux:\n\twant=\n%s\n\tgot=\n%s", want, task.Flux)
+		}
+		if p.Status == nil || p.Status.TaskStatus != string(influxdb.TaskActive) {
+			t.Errorf("expected status of active task, got %+v", p.Status)
+		}
+		if f.buckets[1].DownsamplePolicies[0].TaskID != p.TaskID {
+			t.Errorf("task ID was not persisted")
+		}
+	})
+
+	t.Run("create rejects invalid policies", func(t *testing.T) {
+		f := newFakeServices()
+		svc := downsample.NewBucketService(zaptest.NewLogger(t), f.bucketSvc, f.taskSvc)
+
+		f.buckets[4] = &influxdb.Bucket{ID: 4, OrgID: 10, Name: "foreign"}
+		for _, p := range []influxdb.DownsamplePolicy{policy(5), policy(4), {DestinationBucketID: 2}} {
+			b := &influxdb.Bucket{OrgID: 9, Name: "src", DownsamplePolicies: []influxdb.DownsamplePolicy{p}}
+			if err := svc.CreateBucket(ctx, b); err == nil {
+				t.Errorf("expected error for policy %+v", p)
+			}
+		}
+		if _, ok := f.buckets[1]; ok || len(f.tasks) != 0 {
+			t.Errorf("expected nothing to be created")
+		}
+	})
+
+	t.Run("update rewrites, creates and deletes tasks", func(t *testing.T) {
+		f := newFakeServices()
+		svc := downsample.NewBucketService(zaptest.NewLogger(t), f.bucketSvc, f.taskSvc)
+
+		b := &influxdb.Bucket{OrgID: 9, Name: "src", DownsamplePolicies: []influxdb.DownsamplePolicy{policy(2)}}
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		kept := b.DownsamplePolicies[0].TaskID
+
+		p := policy(3)
+		p.Lag = influxdb.Duration{Duration: time.Minute}
+		policies := []influxdb.DownsamplePolicy{p}
+		name := "renamed"
+		b, err := svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{Name: &name, DownsamplePolicies: &policies})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := f.tasks[kept]; ok {
+			t.Errorf("expected task of removed policy to be deleted")
+		}
+		if len(f.tasks) != 1 {
+			t.Fatalf("expected 1 task, got %d", len(f.tasks))
+		}
+		created := b.DownsamplePolicies[0].TaskID
+		if want := downsample.GenerateFlux(b, f.buckets[3], b.DownsamplePolicies[0]); f.tasks[created].Flux != want {
+			t.Errorf("unexpected flux:\n\twant=\n%s\n\tgot=\n%s", want, f.tasks[created].Flux)
+		}
+
+		name = "renamed again"
+		if b, err = svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{Name: &name}); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.DownsamplePolicies[0].TaskID; got != created {
+			t.Errorf("expected task %s to be kept, got %s", created, got)
+		}
+		if want := downsample.GenerateFlux(b, f.buckets[3], b.DownsamplePolicies[0]); f.tasks[created].Flux != want {
+			t.Errorf("expected task to be renamed:\n\twant=\n%s\n\tgot=\n%s", want, f.tasks[created].Flux)
+		}
+	})
+
+	t.Run("delete removes tasks", func(t *testing.T) {
+		f := newFakeServices()
+		svc := downsample.NewBucketService(zaptest.NewLogger(t), f.bucketSvc, f.taskSvc)
+
+		b := &influxdb.Bucket{OrgID: 9, Name: "src", DownsamplePolicies: []influxdb.DownsamplePolicy{policy(2), policy(3)}}
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(f.tasks) != 0 {
+			t.Errorf("expected tasks to be deleted, %d left", len(f.tasks))
+		}
+	})
+}
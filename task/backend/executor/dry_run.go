@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+var _ influxdb.TaskDryRunService = (*Executor)(nil)
+
+// maxDryRunPoints is how many of the points written by a dry run are returned.
+const maxDryRunPoints = 10000
+
+// DryRunTask executes the task of d for d.ScheduledFor the way its runs are
+// executed, except that the points the query writes are recorded into the
+// tables of d instead of being written. No run is created, and the task does
+// not need to be active. The tasks of notification rules cannot be dry run, as
+// their queries send the notifications.
+func (e *Executor) DryRunTask(ctx context.Context, d *influxdb.TaskDryRun) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	t, err := e.ts.FindTaskByID(ctx, d.TaskID)
+	if err != nil {
+		return err
+	}
+	if t.OrganizationID != d.OrgID {
+		return influxdb.ErrTaskNotFound
+	}
+	if rule.IsType(t.Type) {
+		return influxdb.ErrTaskDryRunNotificationRule
+	}
+
+	auth, err := e.taskAuthorization(ctx, t)
+	if err != nil {
+		return err
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+	ctx = query.ContextWithPriority(ctx, queryPriority(t))
+
+	// runs are scheduled for whole seconds.
+	d.ScheduledFor = time.Unix(d.ScheduledFor.Unix(), 0).UTC()
+
+	buildCompiler := e.systemBuildCompiler
+	if t.Type != influxdb.TaskSystemType {
+		buildCompiler = e.nonSystemBuildCompiler
+	}
+	extern, err := paramsExtern(t, nil)
+	if err != nil {
+		return err
+	}
+	compiler, err := buildCompiler(ctx, t.Flux, d.ScheduledFor, extern)
+	if err != nil {
+		return influxdb.ErrFluxParseError(err)
+	}
+
+	req := &query.Request{
+		Authorization:  auth,
+		OrganizationID: t.OrganizationID,
+		Compiler:       compiler,
+	}
+	req.WithReturnNoContent(true)
+
+	ws := query.NewWriteStatistics()
+	ctx = query.ContextWithWriteStatistics(ctx, ws)
+	rec := query.NewWriteRecorder(maxDryRunPoints)
+	ctx = query.ContextWithWriteRecorder(ctx, rec)
+
+	it, err := e.qs.Query(ctx, req)
+	if err != nil {
+		return influxdb.ErrQueryError(err)
+	}
+
+	var runErr error
+	for it.More() {
+		if err := exhaustResultIterators(it.Next()); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	it.Release()
+
+	if runErr != nil {
+		return influxdb.ErrRunExecutionError(runErr)
+	}
+	if it.Err() != nil {
+		return influxdb.ErrResultIteratorError(it.Err())
+	}
+
+	tables, err := dryRunTables(rec.Points())
+	if err != nil {
+		return influxdb.ErrRunExecutionError(err)
+	}
+
+	qs := it.Statistics()
+	d.TaskVersion = t.Version
+	d.Tables = tables
+	d.Truncated = rec.Truncated()
+	d.Stats = &influxdb.RunStats{
+		RowsRead:      query.ScannedValues(qs),
+		PointsWritten: ws.PointsWritten(),
+		QueryDuration: influxdb.Duration{Duration: qs.TotalDuration},
+		MaxAllocated:  qs.MaxAllocated,
+	}
+	return nil
+}
+
+// dryRunTables groups the points written by a dry run into a table by bucket
+// and series, in the order they were first written to, with the fields written
+// at the same time in one row. The points are exploded: their name is the
+// organization and bucket, and the measurement and field are tags.
+func dryRunTables(points []models.Point) ([]*influxdb.DryRunTable, error) {
+	var (
+		tables []*influxdb.DryRunTable
+		series = make(map[string]*influxdb.DryRunTable)
+		rows   = make(map[*influxdb.DryRunTable]map[int64]int)
+	)
+	for _, p := range points {
+		// the name is the organization and bucket IDs, 8 bytes each.
+		name := p.Name()
+		if len(name) != 16 {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+
+		var (
+			measurement string
+			seriesTags  models.Tags
+			tags        = make(map[string]string)
+		)
+		for _, tag := range p.Tags() {
+			switch string(tag.Key) {
+			case models.MeasurementTagKey:
+				measurement = string(tag.Value)
+			case models.FieldKeyTagKey:
+				continue
+			default:
+				tags[string(tag.Key)] = string(tag.Value)
+			}
+			seriesTags = append(seriesTags, tag)
+		}
+
+		key := string(name) + string(seriesTags.HashKey())
+		tbl, ok := series[key]
+		if !ok {
+			tbl = &influxdb.DryRunTable{
+				BucketID:    bucketID,
+				Measurement: measurement,
+				Tags:        tags,
+			}
+			tables = append(tables, tbl)
+			series[key] = tbl
+			rows[tbl] = make(map[int64]int)
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			return nil, err
+		}
+		i, ok := rows[tbl][p.UnixNano()]
+		if !ok {
+			i = len(tbl.Rows)
+			rows[tbl][p.UnixNano()] = i
+			tbl.Rows = append(tbl.Rows, influxdb.DryRunRow{
+				Time:   p.Time().UTC(),
+				Fields: make(map[string]interface{}, len(fields)),
+			})
+		}
+		for k, v := range fields {
+			tbl.Rows[i].Fields[k] = v
+		}
+	}
+
+	for _, tbl := range tables {
+		sort.SliceStable(tbl.Rows, func(i, j int) bool {
+			return tbl.Rows[i].Time.Before(tbl.Rows[j].Time)
+		})
+	}
+	return tables, nil
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	platformmock "github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestDryRunTask_NotificationRule(t *testing.T) {
+	ts := platformmock.NewTaskService()
+	ts.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, OrganizationID: 10, Type: "slack"}, nil
+	}
+	ex, _ := NewExecutor(zaptest.NewLogger(t), nil, nil, ts, nil)
+
+	err := ex.DryRunTask(context.Background(), &influxdb.TaskDryRun{TaskID: 1, OrgID: 10})
+	if err != influxdb.ErrTaskDryRunNotificationRule {
+		t.Fatalf("expected the dry run to be rejected, got %v", err)
+	}
+}
+
+func TestDryRunTables(t *testing.T) {
+	t0 := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// point returns the exploded point of a field written by to().
+	point := func(bucketID influxdb.ID, measurement, host, field string, v interface{}, ts time.Time) models.Point {
+		tags := models.NewTags(map[string]string{
+			models.MeasurementTagKey: measurement,
+			models.FieldKeyTagKey:    field,
+		})
+		if host != "" {
+			tags.Set([]byte("host"), []byte(host))
+		}
+		return models.MustNewPoint(tsdb.EncodeNameString(10, bucketID), tags, models.Fields{field: v}, ts)
+	}
+
+	tables, err := dryRunTables([]models.Point{
+		point(1, "cpu", "a", "usage", 0.5, t0.Add(time.Minute)),
+		point(1, "cpu", "a", "count", int64(2), t0.Add(time.Minute)),
+		point(1, "cpu", "b", "usage", 0.7, t0),
+		point(1, "cpu", "a", "usage", 0.6, t0),
+		point(2, "cpu", "a", "usage", 0.5, t0),
+		point(2, "mem", "", "used", int64(10), t0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*influxdb.DryRunTable{
+		{
+			BucketID:    1,
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "a"},
+			Rows: []influxdb.DryRunRow{
+				{Time: t0, Fields: map[string]interface{}{"usage": 0.6}},
+				{Time: t0.Add(time.Minute), Fields: map[string]interface{}{"usage": 0.5, "count": int64(2)}},
+			},
+		},
+		{
+			BucketID:    1,
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "b"},
+			Rows: []influxdb.DryRunRow{
+				{Time: t0, Fields: map[string]interface{}{"usage": 0.7}},
+			},
+		},
+		{
+			BucketID:    2,
+			Measurement: "cpu",
+			Tags:        map[string]string{"host": "a"},
+			Rows: []influxdb.DryRunRow{
+				{Time: t0, Fields: map[string]interface{}{"usage": 0.5}},
+			},
+		},
+		{
+			BucketID:    2,
+			Measurement: "mem",
+			Tags:        map[string]string{},
+			Rows: []influxdb.DryRunRow{
+				{Time: t0, Fields: map[string]interface{}{"used": int64(10)}},
+			},
+		},
+	}
+	if diff := cmp.Diff(exp, tables); diff != "" {
+		t.Fatalf("unexpected tables -want/+got:\n%s", diff)
+	}
+}
@@ -2,7 +2,9 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,8 @@ import (
 	icontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/kit/feature"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/notification/check"
+	"github.com/influxdata/influxdb/v2/notification/rule"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
@@ -22,6 +26,9 @@ import (
 const (
 	maxPromises       = 1000
 	defaultMaxWorkers = 100
+
+	defaultRetryInitialBackoff = 10 * time.Second
+	defaultRetryMaxBackoff     = 10 * time.Minute
 )
 
 var _ scheduler.Executor = (*Executor)(nil)
@@ -49,7 +56,8 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 	}
 }
 
-// LimitFunc is a function the executor will use to
+// LimitFunc is a function the executor will use to hold a run back until it
+// returns nil. A run is failed instead when the error has the code EConflict.
 type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
 type executorConfig struct {
@@ -57,6 +65,8 @@ type executorConfig struct {
 	systemBuildCompiler    CompilerBuilderFunc
 	nonSystemBuildCompiler CompilerBuilderFunc
 	flagger                feature.Flagger
+	retryInitialBackoff    time.Duration
+	retryMaxBackoff        time.Duration
 }
 
 type executorOption func(*executorConfig)
@@ -68,9 +78,20 @@ func WithMaxWorkers(n int) executorOption {
 	}
 }
 
+// WithRetryBackoff specifies how long the Executor waits before retrying a
+// failed run. The first retry waits initial, and each following retry of the
+// same run waits twice as long as the previous one, up to max.
+func WithRetryBackoff(initial, max time.Duration) executorOption {
+	return func(o *executorConfig) {
+		o.retryInitialBackoff = initial
+		o.retryMaxBackoff = max
+	}
+}
+
 // CompilerBuilderFunc is a function that yields a new flux.Compiler. The
-// context.Context provided can be assumed to be an authorized context.
-type CompilerBuilderFunc func(ctx context.Context, query string, now time.Time) (flux.Compiler, error)
+// context.Context provided can be assumed to be an authorized context. The
+// extern, a Flux file evaluated before the query, may be nil.
+type CompilerBuilderFunc func(ctx context.Context, query string, now time.Time, extern json.RawMessage) (flux.Compiler, error)
 
 // WithSystemCompilerBuilder is an Executor option that configures a
 // CompilerBuilderFunc to be used when compiling queries for System Tasks.
@@ -102,6 +123,8 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, t
 		maxWorkers:             defaultMaxWorkers,
 		systemBuildCompiler:    NewASTCompiler,
 		nonSystemBuildCompiler: NewASTCompiler,
+		retryInitialBackoff:    defaultRetryInitialBackoff,
+		retryMaxBackoff:        defaultRetryMaxBackoff,
 	}
 	for _, opt := range opts {
 		opt(cfg)
@@ -117,12 +140,17 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, t
 		currentPromises:        sync.Map{},
 		promiseQueue:           make(chan *promise, maxPromises),
 		workerLimit:            make(chan struct{}, cfg.maxWorkers),
-		limitFunc:              func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
 		systemBuildCompiler:    cfg.systemBuildCompiler,
 		nonSystemBuildCompiler: cfg.nonSystemBuildCompiler,
 		flagger:                cfg.flagger,
+		retryInitialBackoff:    cfg.retryInitialBackoff,
+		retryMaxBackoff:        cfg.retryMaxBackoff,
+		retries:                make(map[influxdb.ID]*time.Timer),
 	}
 
+	// runs wait for the upstream tasks they depend on by default.
+	e.limitFunc = DependencyLimit(e)
+
 	e.metrics = NewExecutorMetrics(e)
 
 	wm := &workerMaker{
@@ -159,9 +187,19 @@ type Executor struct {
 	nonSystemBuildCompiler CompilerBuilderFunc
 	systemBuildCompiler    CompilerBuilderFunc
 	flagger                feature.Flagger
+
+	retryInitialBackoff time.Duration
+	retryMaxBackoff     time.Duration
+
+	// retries are the timers starting the queued retries once they are due,
+	// by run ID.
+	retriesMu sync.Mutex
+	retries   map[influxdb.ID]*time.Timer
+	closed    bool
 }
 
-// SetLimitFunc sets the limit func for this task executor
+// SetLimitFunc sets the limit func for this task executor, replacing the
+// default DependencyLimit; combine them with MultiLimit to keep it.
 func (e *Executor) SetLimitFunc(l LimitFunc) {
 	e.limitFunc = l
 }
@@ -224,6 +262,96 @@ func (e *Executor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, runID i
 	return nil, influxdb.ErrRunNotFound
 }
 
+// retryRun queues a run retrying the failed run p and starts it once delay has passed.
+// The retry is due at its RunAt time, so that it is started by ResumeRetries if
+// the process stops before.
+func (e *Executor) retryRun(p *promise, delay time.Duration) {
+	// keep the authorizer of the failed run, but not its cancellation.
+	ctx := context.Background()
+	if auth, err := icontext.GetAuthorizer(p.ctx); err == nil {
+		ctx = icontext.SetAuthorizer(ctx, auth)
+	}
+
+	r, err := e.tcs.CreateRetryRun(ctx, p.run, time.Now().Add(delay))
+	if err != nil {
+		e.log.Error("Failed to queue retry", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+		return
+	}
+
+	e.startRetry(ctx, r)
+}
+
+// ResumeRetries starts the retries of the failed runs of the task id that are
+// still queued, e.g. by a process that stopped before they were due, once they
+// are due.
+func (e *Executor) ResumeRetries(ctx context.Context, id influxdb.ID) error {
+	runs, err := e.tcs.ManualRuns(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		if r.RetryOf.Valid() {
+			e.startRetry(ctx, r)
+		}
+	}
+	return nil
+}
+
+// startRetry starts the queued retry r once its RunAt time has passed, unless
+// it is already waiting to be started or the executor is closed.
+func (e *Executor) startRetry(ctx context.Context, r *influxdb.Run) {
+	e.retriesMu.Lock()
+	defer e.retriesMu.Unlock()
+
+	if _, ok := e.retries[r.ID]; ok || e.closed {
+		return
+	}
+
+	e.retries[r.ID] = time.AfterFunc(time.Until(r.RunAt), func() {
+		e.retriesMu.Lock()
+		delete(e.retries, r.ID)
+		e.retriesMu.Unlock()
+
+		run, err := e.tcs.StartManualRun(ctx, r.TaskID, r.ID)
+		if err != nil {
+			e.log.Error("Failed to start retry", zap.String("taskID", r.TaskID.String()), zap.String("runID", r.ID.String()), zap.Error(err))
+			return
+		}
+		if _, err := e.createPromise(ctx, run); err != nil {
+			e.log.Error("Failed to start retry", zap.String("taskID", r.TaskID.String()), zap.String("runID", r.ID.String()), zap.Error(err))
+			return
+		}
+		e.startWorker()
+		e.metrics.retriesCounter.WithLabelValues(r.TaskID.String()).Inc()
+	})
+}
+
+// Close stops starting the queued retries. They stay queued, to be started
+// by ResumeRetries once the process restarts.
+func (e *Executor) Close() {
+	e.retriesMu.Lock()
+	defer e.retriesMu.Unlock()
+
+	e.closed = true
+	for id, t := range e.retries {
+		t.Stop()
+		delete(e.retries, id)
+	}
+}
+
+// retryDelay returns how long to wait before the given retry of a run.
+func (e *Executor) retryDelay(retry int) time.Duration {
+	d := e.retryInitialBackoff
+	for i := 1; i < retry && d < e.retryMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > e.retryMaxBackoff {
+		d = e.retryMaxBackoff
+	}
+	return d
+}
+
 func (e *Executor) createRun(ctx context.Context, id influxdb.ID, scheduledFor time.Time, runAt time.Time) (*promise, error) {
 	r, err := e.tcs.CreateRun(ctx, id, scheduledFor.UTC(), runAt.UTC())
 	if err != nil {
@@ -268,6 +396,22 @@ func (e *Executor) startWorker() {
 	}
 }
 
+// requeue queues the promise p again once a second has passed, for a worker to
+// check its limits again, or ends it if it is canceled before.
+func (e *Executor) requeue(p *promise) {
+	go func() {
+		select {
+		case <-p.ctx.Done():
+			w := e.workerPool.Get().(*worker)
+			defer e.workerPool.Put(w)
+			w.cancel(p)
+		case <-time.After(time.Second):
+			e.promiseQueue <- p
+			e.startWorker()
+		}
+	}()
+}
+
 // Cancel a run of a specific task.
 func (e *Executor) Cancel(ctx context.Context, runID influxdb.ID) error {
 	// find the promise
@@ -292,30 +436,17 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 		return nil, err
 	}
 
-	var perm influxdb.PermissionSet
-	if e.flagger != nil && feature.UseUserPermission().Enabled(ctx, e.flagger) {
-		perm, err = e.ps.FindPermissionForUser(ctx, t.OwnerID)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if perm == nil {
-		perm = t.Authorization.Permissions
+	auth, err := e.taskAuthorization(ctx, t)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	// create promise
 	p := &promise{
-		run:  run,
-		task: t,
-		auth: &influxdb.Authorization{
-			Status:      influxdb.Active,
-			UserID:      t.OwnerID,
-			ID:          influxdb.ID(1),
-			OrgID:       t.OrganizationID,
-			Permissions: perm,
-		},
+		run:        run,
+		task:       t,
+		auth:       auth,
 		createdAt:  time.Now().UTC(),
 		done:       make(chan struct{}),
 		ctx:        ctx,
@@ -331,6 +462,31 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 	return p, nil
 }
 
+// taskAuthorization returns the authorization the queries of the task t
+// are executed with.
+func (e *Executor) taskAuthorization(ctx context.Context, t *influxdb.Task) (*influxdb.Authorization, error) {
+	var perm influxdb.PermissionSet
+	if e.flagger != nil && feature.UseUserPermission().Enabled(ctx, e.flagger) {
+		var err error
+		perm, err = e.ps.FindPermissionForUser(ctx, t.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if perm == nil {
+		perm = t.Authorization.Permissions
+	}
+
+	return &influxdb.Authorization{
+		Status:      influxdb.Active,
+		UserID:      t.OwnerID,
+		ID:          influxdb.ID(1),
+		OrgID:       t.OrganizationID,
+		Permissions: perm,
+	}, nil
+}
+
 type workerMaker struct {
 	e *Executor
 }
@@ -374,31 +530,27 @@ func (w *worker) work() {
 		}
 
 		// check to make sure we are below the limits.
-		for {
-			err := w.e.limitFunc(prom.task, prom.run)
-			if err == nil {
-				break
+		if err := w.e.limitFunc(prom.task, prom.run); err != nil {
+			if influxdb.ErrorCode(err) != influxdb.EConflict {
+				// add to the run log, once per reason to wait
+				if err.Error() != prom.limitErr {
+					prom.limitErr = err.Error()
+					w.addRunLog(prom, influxdb.LogLevelWarn, fmt.Sprintf("Task limit reached: %s", err.Error()), nil)
+				}
+
+				// wait without holding the worker, so that the runs this one
+				// waits for can execute.
+				w.e.requeue(prom)
+				continue
 			}
 
-			// add to the run log
-			w.e.tcs.AddRunLog(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), fmt.Sprintf("Task limit reached: %s", err.Error()))
-
-			// sleep
-			select {
-			// If done the promise was canceled
-			case <-prom.ctx.Done():
-				w.e.tcs.AddRunLog(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), "Run canceled")
-				w.e.tcs.UpdateRunState(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), influxdb.RunCanceled)
-				prom.err = influxdb.ErrRunCanceled
-				close(prom.done)
-				return
-			case <-time.After(time.Second):
-			}
+			// the run cannot be executed at all, fail it rather than waiting.
+			w.start(prom)
+			w.finish(prom, influxdb.RunFail, err)
+		} else {
+			w.executeQuery(prom)
 		}
 
-		// execute the promise
-		w.executeQuery(prom)
-
 		// close promise done channel and set appropriate error
 		close(prom.done)
 
@@ -407,13 +559,25 @@ func (w *worker) work() {
 	}
 }
 
+// cancel ends the promise p, canceled while it waited for the limits.
+func (w *worker) cancel(p *promise) {
+	w.addRunLog(p, influxdb.LogLevelWarn, "Run canceled", nil)
+	w.e.tcs.UpdateRunState(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.RunCanceled)
+	p.err = influxdb.ErrRunCanceled
+	close(p.done)
+
+	w.e.currentPromises.Delete(p.run.ID)
+}
+
 func (w *worker) start(p *promise) {
 	// trace
 	span, ctx := tracing.StartSpanFromContext(p.ctx)
 	defer span.Finish()
 
 	// add to run log
-	w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Started task from script: %q", p.task.Flux))
+	w.addRunLog(p, influxdb.LogLevelInfo, fmt.Sprintf("Started task from script: %q", p.task.Flux), map[string]string{
+		"taskVersion": strconv.Itoa(p.task.Version),
+	})
 	// update run status
 	w.e.tcs.UpdateRunState(ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.RunStarted)
 
@@ -428,8 +592,18 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 	span, ctx := tracing.StartSpanFromContext(p.ctx)
 	defer span.Finish()
 
+	// a failed run is retried unless it was canceled, it cannot succeed
+	// without user intervention, or the task has used all its retries.
+	retry := rs == influxdb.RunFail &&
+		!backend.IsUnrecoverable(err) &&
+		p.ctx.Err() == nil &&
+		int64(p.run.Retry) < p.task.Retry
+	if retry {
+		rs = influxdb.RunRetried
+	}
+
 	// add to run log
-	w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Completed(%s)", rs.String()))
+	w.addRunLog(p, influxdb.LogLevelInfo, fmt.Sprintf("Completed(%s)", rs.String()), map[string]string{"status": rs.String()})
 	// update run status
 	w.e.tcs.UpdateRunState(ctx, p.task.ID, p.run.ID, time.Now().UTC(), rs)
 
@@ -439,7 +613,7 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 
 	// log error
 	if err != nil {
-		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), err.Error())
+		w.addRunLog(p, influxdb.LogLevelError, err.Error(), nil)
 		w.e.log.Debug("Execution failed", zap.Error(err), zap.String("taskID", p.task.ID.String()))
 		w.e.metrics.LogError(p.task.Type, err)
 
@@ -450,7 +624,7 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 			// w.te.ts.UpdateTask(p.ctx, p.task.ID, influxdb.TaskUpdate{Status: &inactive})
 
 			// and add to run logs
-			w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Task encountered unrecoverable error, requires admin action: %v", err.Error()))
+			w.addRunLog(p, influxdb.LogLevelError, fmt.Sprintf("Task encountered unrecoverable error, requires admin action: %v", err.Error()), nil)
 			// add to metrics
 			w.e.metrics.LogUnrecoverableError(p.task.ID, err)
 		}
@@ -460,9 +634,22 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 		w.e.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
+	var delay time.Duration
+	if retry {
+		delay = w.e.retryDelay(p.run.Retry + 1)
+		w.addRunLog(p, influxdb.LogLevelWarn, fmt.Sprintf("Retrying in %s (retry %d of %d)", delay, p.run.Retry+1, p.task.Retry), map[string]string{
+			"delay": delay.String(),
+			"retry": strconv.Itoa(p.run.Retry + 1),
+		})
+	}
+
 	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
 		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
+
+	if retry {
+		w.e.retryRun(p, delay)
+	}
 }
 
 func (w *worker) executeQuery(p *promise) {
@@ -473,12 +660,21 @@ func (w *worker) executeQuery(p *promise) {
 	w.start(p)
 
 	ctx = icontext.SetAuthorizer(ctx, p.auth)
+	ctx = query.ContextWithPriority(ctx, queryPriority(p.task))
+	if rule.IsType(p.task.Type) {
+		ctx = query.ContextWithNotificationRule(ctx)
+	}
 
 	buildCompiler := w.systemBuildCompiler
 	if p.task.Type != influxdb.TaskSystemType {
 		buildCompiler = w.nonSystemBuildCompiler
 	}
-	compiler, err := buildCompiler(ctx, p.task.Flux, p.run.ScheduledFor)
+	extern, err := paramsExtern(p.task, p.run)
+	if err != nil {
+		w.finish(p, influxdb.RunFail, err)
+		return
+	}
+	compiler, err := buildCompiler(ctx, p.task.Flux, p.run.ScheduledFor, extern)
 	if err != nil {
 		w.finish(p, influxdb.RunFail, influxdb.ErrFluxParseError(err))
 		return
@@ -490,6 +686,11 @@ func (w *worker) executeQuery(p *promise) {
 		Compiler:       compiler,
 	}
 	req.WithReturnNoContent(true)
+
+	// count the points the query writes with to()
+	ws := query.NewWriteStatistics()
+	ctx = query.ContextWithWriteStatistics(ctx, ws)
+
 	it, err := w.e.qs.Query(ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
@@ -512,9 +713,15 @@ func (w *worker) executeQuery(p *promise) {
 	// log the trace id and whether or not it was sampled into the run log
 	if traceID, isSampled, ok := tracing.InfoFromSpan(span); ok {
 		msg := fmt.Sprintf("trace_id=%s is_sampled=%t", traceID, isSampled)
-		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), msg)
+		w.addRunLog(p, influxdb.LogLevelDebug, msg, map[string]string{
+			"traceID":   traceID,
+			"isSampled": strconv.FormatBool(isSampled),
+		})
 	}
 
+	// record what the query read and wrote
+	w.recordStats(p, it.Statistics(), ws)
+
 	if runErr != nil {
 		w.finish(p, influxdb.RunFail, influxdb.ErrRunExecutionError(runErr))
 		return
@@ -528,6 +735,52 @@ func (w *worker) executeQuery(p *promise) {
 	w.finish(p, influxdb.RunSuccess, nil)
 }
 
+// recordStats sets the statistics of the query of the run of p, and adds them
+// to its log.
+func (w *worker) recordStats(p *promise, qs flux.Statistics, ws *query.WriteStatistics) {
+	stats := &influxdb.RunStats{
+		RowsRead:      query.ScannedValues(qs),
+		PointsWritten: ws.PointsWritten(),
+		QueryDuration: influxdb.Duration{Duration: qs.TotalDuration},
+		MaxAllocated:  qs.MaxAllocated,
+	}
+	if err := w.e.tcs.SetRunStats(p.ctx, p.task.ID, p.run.ID, stats); err != nil {
+		w.e.log.Info("Failed to set run statistics", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+	}
+
+	fields := map[string]string{
+		"rowsRead":      strconv.FormatInt(stats.RowsRead, 10),
+		"pointsWritten": strconv.FormatInt(stats.TotalPointsWritten(), 10),
+		"queryDuration": stats.QueryDuration.String(),
+		"maxAllocated":  strconv.FormatInt(stats.MaxAllocated, 10),
+	}
+	for bucketID, n := range stats.PointsWritten {
+		fields["pointsWritten."+bucketID.String()] = strconv.FormatInt(n, 10)
+	}
+	w.addRunLog(p, influxdb.LogLevelInfo, "Query statistics", fields)
+}
+
+// addRunLog adds a structured entry to the log of the run of p.
+func (w *worker) addRunLog(p *promise, level, msg string, fields map[string]string) {
+	entry := influxdb.Log{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   level,
+		Message: msg,
+		Fields:  fields,
+	}
+	if err := w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, entry); err != nil {
+		w.e.log.Debug("Failed to add run log", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+	}
+}
+
+// queryPriority returns the priority class of the queries of a task.
+func queryPriority(t *influxdb.Task) query.Priority {
+	if check.IsType(t.Type) {
+		return query.PriorityCheck
+	}
+	return query.PriorityTask
+}
+
 // RunsActive returns the current number of workers, which is equivalent to
 // the number of runs actively running
 func (e *Executor) RunsActive() int {
@@ -556,6 +809,9 @@ type promise struct {
 	createdAt time.Time
 	startedAt time.Time
 
+	// limitErr is the latest reason the promise waited for the limits.
+	limitErr string
+
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
@@ -599,21 +855,23 @@ func exhaustResultIterators(res flux.Result) error {
 }
 
 // NewASTCompiler parses a Flux query string into an AST representatation.
-func NewASTCompiler(_ context.Context, query string, now time.Time) (flux.Compiler, error) {
+func NewASTCompiler(_ context.Context, query string, now time.Time, extern json.RawMessage) (flux.Compiler, error) {
 	pkg, err := runtime.ParseToJSON(query)
 	if err != nil {
 		return nil, err
 	}
 	return lang.ASTCompiler{
-		AST: pkg,
-		Now: now,
+		AST:    pkg,
+		Now:    now,
+		Extern: extern,
 	}, nil
 }
 
 // NewFluxCompiler wraps a Flux query string in a raw-query representation.
-func NewFluxCompiler(_ context.Context, query string, _ time.Time) (flux.Compiler, error) {
+func NewFluxCompiler(_ context.Context, query string, _ time.Time, extern json.RawMessage) (flux.Compiler, error) {
 	return lang.FluxCompiler{
-		Query: query,
+		Query:  query,
+		Extern: extern,
 		// TODO(brett): This mitigates an immediate problem where
 		// Checks/Notifications breaks when sending Now, and system Tasks do not
 		// break when sending Now. We are currently sending C+N through using
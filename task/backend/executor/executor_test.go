@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
@@ -20,6 +21,7 @@ import (
 	tracetest "github.com/influxdata/influxdb/v2/kit/tracing/testing"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	platformmock "github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/task/backend"
@@ -50,7 +52,7 @@ type tes struct {
 	tc      testCreds
 }
 
-func taskExecutorSystem(t *testing.T) tes {
+func taskExecutorSystem(t *testing.T, opts ...executorOption) tes {
 	var (
 		aqs = newFakeQueryService()
 		qs  = query.QueryServiceBridge{
@@ -73,7 +75,7 @@ func taskExecutorSystem(t *testing.T) tes {
 		})
 
 		tcs         = &taskControlService{TaskControlService: svc}
-		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs)
+		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs, opts...)
 	)
 	return tes{
 		svc:     aqs,
@@ -88,10 +90,13 @@ func taskExecutorSystem(t *testing.T) tes {
 func TestTaskExecutor(t *testing.T) {
 	t.Run("QuerySuccess", testQuerySuccess)
 	t.Run("QueryFailure", testQueryFailure)
+	t.Run("QueryRetry", testQueryRetry)
 	t.Run("ManualRun", testManualRun)
 	t.Run("ResumeRun", testResumingRun)
 	t.Run("WorkerLimit", testWorkerLimit)
 	t.Run("LimitFunc", testLimitFunc)
+	t.Run("LimitFuncFailure", testLimitFuncFailure)
+	t.Run("LimitFuncRequeue", testLimitFuncRequeue)
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
@@ -163,6 +168,26 @@ func testQuerySuccess(t *testing.T) {
 	if expectedMessage != run.Log[1].Message {
 		t.Errorf("expected %q, found %q", expectedMessage, run.Log[1].Message)
 	}
+	if run.Log[0].Level != influxdb.LogLevelInfo || run.Log[1].Level != influxdb.LogLevelDebug {
+		t.Errorf("unexpected run log levels %q and %q", run.Log[0].Level, run.Log[1].Level)
+	}
+
+	// the statistics of the query are recorded with the run and logged
+	expStats := &influxdb.RunStats{
+		RowsRead:      12,
+		PointsWritten: map[influxdb.ID]int64{fakeBucketID: 3},
+		QueryDuration: influxdb.Duration{Duration: 2 * time.Second},
+		MaxAllocated:  1024,
+	}
+	if diff := cmp.Diff(expStats, run.Stats); diff != "" {
+		t.Errorf("unexpected run stats -want/+got:\n%s", diff)
+	}
+	if statsLog := run.Log[2]; statsLog.Message != "Query statistics" ||
+		statsLog.Fields["rowsRead"] != "12" ||
+		statsLog.Fields["pointsWritten"] != "3" ||
+		statsLog.Fields["pointsWritten."+fakeBucketID.String()] != "3" {
+		t.Errorf("unexpected run statistics log %+v", statsLog)
+	}
 }
 
 func testQueryFailure(t *testing.T) {
@@ -201,6 +226,136 @@ func testQueryFailure(t *testing.T) {
 	}
 }
 
+func testQueryRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t, WithRetryBackoff(time.Millisecond, time.Millisecond))
+
+	script := fmt.Sprintf(fmtTestRetryScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Retry != 1 {
+		t.Fatalf("expected task to be retried once, got %d", task.Retry)
+	}
+
+	first, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+
+	<-first.Done()
+	if got := first.Error(); got == nil {
+		t.Fatal("got no error when I should have")
+	}
+	if status := tes.tcs.run.Status; status != "retried" {
+		t.Fatalf("expected failed run to be retried, got status %q", status)
+	}
+
+	// the retry runs the same query for the same scheduled time.
+	tes.svc.WaitForQueryLive(t, script)
+
+	var retry *promise
+	tes.ex.currentPromises.Range(func(_, v interface{}) bool {
+		if p := v.(*promise); p.ID() != first.ID() {
+			retry = p
+			return false
+		}
+		return true
+	})
+	if retry == nil {
+		t.Fatal("expected a promise for the retry")
+	}
+	if retry.run.RetryOf != first.ID() || retry.run.Retry != 1 {
+		t.Fatalf("expected retry 1 of run %s, got retry %d of run %s", first.ID(), retry.run.Retry, retry.run.RetryOf)
+	}
+	if !retry.run.ScheduledFor.Equal(time.Unix(123, 0)) {
+		t.Fatalf("expected retry to be scheduled for the same time, got %s", retry.run.ScheduledFor)
+	}
+
+	tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+
+	<-retry.Done()
+	if got := retry.Error(); got == nil {
+		t.Fatal("got no error when I should have")
+	}
+	// the task has used its retries so the run fails.
+	if status := tes.tcs.run.Status; status != "failed" {
+		t.Fatalf("expected retry to fail, got status %q", status)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	ex, _ := NewExecutor(zaptest.NewLogger(t), nil, nil, nil, nil, WithRetryBackoff(time.Second, 5*time.Second))
+	for retry, want := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		if got := ex.retryDelay(retry); got != want {
+			t.Errorf("unexpected delay of retry %d: got %s want %s", retry, got, want)
+		}
+	}
+}
+
+func TestResumeRetries(t *testing.T) {
+	var (
+		taskID = influxdb.ID(1)
+		failed = &influxdb.Run{ID: 2, TaskID: taskID, Status: "retried"}
+		forced = &influxdb.Run{ID: 3, TaskID: taskID, Status: "scheduled"}
+		retry  = &influxdb.Run{ID: 4, TaskID: taskID, Status: "scheduled", RetryOf: failed.ID, Retry: 1, RunAt: time.Now().Add(time.Hour)}
+	)
+
+	tcs := &platformmock.TaskControlService{
+		ManualRunsFn: func(_ context.Context, _ influxdb.ID) ([]*influxdb.Run, error) {
+			return []*influxdb.Run{forced, retry}, nil
+		},
+	}
+	ex, _ := NewExecutor(zaptest.NewLogger(t), nil, nil, nil, tcs)
+
+	retries := func() []influxdb.ID {
+		ex.retriesMu.Lock()
+		defer ex.retriesMu.Unlock()
+
+		var ids []influxdb.ID
+		for id := range ex.retries {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// resuming twice waits only once for the retry, and not for the forced run.
+	for i := 0; i < 2; i++ {
+		if err := ex.ResumeRetries(context.Background(), taskID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]influxdb.ID{retry.ID}, retries()); diff != "" {
+		t.Fatalf("unexpected retries waiting to start: %s", diff)
+	}
+
+	// closing stops waiting for the retry, which stays queued.
+	ex.Close()
+	if ids := retries(); len(ids) != 0 {
+		t.Fatalf("expected no retries waiting to start once closed, got %v", ids)
+	}
+	if err := ex.ResumeRetries(context.Background(), taskID); err != nil {
+		t.Fatal(err)
+	}
+	if ids := retries(); len(ids) != 0 {
+		t.Fatalf("expected no retries resumed once closed, got %v", ids)
+	}
+	if runs, _ := tcs.ManualRuns(context.Background(), taskID); len(runs) != 2 {
+		t.Fatalf("expected the retry to stay queued, got %d manual runs", len(runs))
+	}
+}
+
 func testManualRun(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -212,7 +367,7 @@ func testManualRun(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123)
+	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -360,6 +515,94 @@ func testLimitFunc(t *testing.T) {
 	}
 }
 
+func testLimitFuncRequeue(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t, WithMaxWorkers(1))
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	upstreamScript := fmt.Sprintf(fmtTestScript, t.Name()+"-upstream")
+	upstream, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: upstreamScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	downstreamScript := fmt.Sprintf(fmtTestScript, t.Name()+"-downstream")
+	downstream, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: downstreamScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the downstream run waits until the upstream run is done.
+	upstreamDone := make(chan struct{})
+	tes.ex.SetLimitFunc(func(task *influxdb.Task, _ *influxdb.Run) error {
+		if task.ID != downstream.ID {
+			return nil
+		}
+		select {
+		case <-upstreamDone:
+			return nil
+		default:
+			return influxdb.ErrTaskUpstreamPending(upstream.ID, time.Unix(123, 0))
+		}
+	})
+
+	second, err := tes.ex.PromisedExecute(ctx, scheduler.ID(downstream.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := tes.ex.PromisedExecute(ctx, scheduler.ID(upstream.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the waiting run does not hold the only worker, so the upstream run executes.
+	tes.svc.WaitForQueryLive(t, upstreamScript)
+	tes.svc.SucceedQuery(upstreamScript)
+	<-first.Done()
+	if got := first.Error(); got != nil {
+		t.Fatal(got)
+	}
+	close(upstreamDone)
+
+	tes.svc.WaitForQueryLive(t, downstreamScript)
+	tes.svc.SucceedQuery(downstreamScript)
+	<-second.Done()
+	if got := second.Error(); got != nil {
+		t.Fatal(got)
+	}
+}
+
+func testLimitFuncFailure(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upstreamErr := influxdb.ErrTaskUpstreamFailed(influxdb.ID(1), time.Unix(123, 0), "latest run failed")
+	tes.ex.SetLimitFunc(func(*influxdb.Task, *influxdb.Run) error {
+		return upstreamErr
+	})
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-promise.Done()
+
+	if got := promise.Error(); got == nil || got.Error() != upstreamErr.Error() {
+		t.Fatalf("expected the run to fail with the limit error, got %v", got)
+	}
+
+	if run := tes.tcs.run; run == nil || run.Status != influxdb.RunFail.String() {
+		t.Fatalf("expected failed run, got %+v", run)
+	}
+}
+
 func testMetrics(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -429,7 +672,7 @@ func testMetrics(t *testing.T) {
 
 	scheduledFor := int64(123)
 
-	r, err := tes.i.ForceRun(ctx, mt.ID, scheduledFor)
+	r, err := tes.i.ForceRun(ctx, mt.ID, scheduledFor, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -596,6 +839,23 @@ func TestPromiseFailure(t *testing.T) {
 
 }
 
+func TestQueryPriority(t *testing.T) {
+	for _, tt := range []struct {
+		taskType string
+		want     query.Priority
+	}{
+		{taskType: "", want: query.PriorityTask},
+		{taskType: influxdb.TaskSystemType, want: query.PriorityTask},
+		{taskType: "threshold", want: query.PriorityCheck},
+		{taskType: "deadman", want: query.PriorityCheck},
+		{taskType: "slack", want: query.PriorityTask},
+	} {
+		if got := queryPriority(&influxdb.Task{Type: tt.taskType}); got != tt.want {
+			t.Errorf("unexpected priority of %q task: got %s want %s", tt.taskType, got, tt.want)
+		}
+	}
+}
+
 type taskControlService struct {
 	backend.TaskControlService
 
@@ -2,7 +2,9 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/task/options"
@@ -50,3 +52,102 @@ func ConcurrencyLimit(exec *Executor, lang influxdb.FluxLanguageService) LimitFu
 		return nil
 	}
 }
+
+// DependencyLimit creates a limit func holding the runs of a task back until
+// its upstream tasks have completed their runs up to the time the run is
+// scheduled for. The run fails if the run of an upstream task for that time,
+// its latest run scheduled for at or before it, did not succeed or is no
+// longer recorded, or if an upstream task is inactive and will not complete
+// the runs. Deleted upstream tasks no longer hold runs back.
+func DependencyLimit(exec *Executor) LimitFunc {
+	return func(t *influxdb.Task, r *influxdb.Run) error {
+		ctx := influxdb.FindTaskWithoutAuth(context.Background())
+		for _, id := range t.DependsOn {
+			upstream, err := exec.ts.FindTaskByID(ctx, id)
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if upstream.LatestCompleted.Before(r.ScheduledFor) {
+				if upstream.Status != influxdb.TaskStatusActive {
+					return influxdb.ErrTaskUpstreamFailed(id, r.ScheduledFor, "task is inactive")
+				}
+				return influxdb.ErrTaskUpstreamPending(id, r.ScheduledFor)
+			}
+
+			// earlier runs of the upstream task may still be running or queued,
+			// such as retries or runs forced by a backfill.
+			running, err := exec.tcs.CurrentlyRunning(ctx, id)
+			if err != nil {
+				return err
+			}
+			manual, err := exec.tcs.ManualRuns(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, run := range append(running, manual...) {
+				if !run.ScheduledFor.After(r.ScheduledFor) {
+					return influxdb.ErrTaskUpstreamPending(id, r.ScheduledFor)
+				}
+			}
+
+			run, err := upstreamRun(ctx, exec.ts, id, r.ScheduledFor)
+			if err != nil {
+				return err
+			}
+			if run == nil {
+				// the upstream task may have no run for the time because it did
+				// not exist yet or skipped the runs it missed, otherwise the run
+				// is no longer recorded.
+				if r.ScheduledFor.Before(upstream.CreatedAt) || skipsMissedRuns(upstream) {
+					continue
+				}
+				return influxdb.ErrTaskUpstreamFailed(id, r.ScheduledFor, "no run is recorded for the time")
+			}
+			switch run.Status {
+			case influxdb.RunSuccess.String():
+			case influxdb.RunFail.String(), influxdb.RunCanceled.String():
+				return influxdb.ErrTaskUpstreamFailed(id, r.ScheduledFor, fmt.Sprintf("run scheduled for %s %s", run.ScheduledFor.UTC().Format(time.RFC3339), run.Status))
+			default:
+				// retried, or retrying.
+				return influxdb.ErrTaskUpstreamPending(id, r.ScheduledFor)
+			}
+		}
+		return nil
+	}
+}
+
+// upstreamRun returns the last attempt of the latest run of the task id
+// scheduled for at or before scheduledFor, or nil if there is none.
+func upstreamRun(ctx context.Context, ts influxdb.TaskService, id influxdb.ID, scheduledFor time.Time) (*influxdb.Run, error) {
+	runs, _, err := ts.FindRuns(ctx, influxdb.RunFilter{
+		Task: id,
+		// runs are scheduled for whole seconds.
+		BeforeTime: scheduledFor.Add(time.Second).UTC().Format(time.RFC3339),
+		Limit:      influxdb.TaskMaxPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *influxdb.Run
+	for _, run := range runs {
+		switch {
+		case run.ScheduledFor.After(scheduledFor):
+		case latest == nil, run.ScheduledFor.After(latest.ScheduledFor):
+			latest = run
+		case run.ScheduledFor.Equal(latest.ScheduledFor) && run.Retry > latest.Retry:
+			latest = run
+		}
+	}
+	return latest, nil
+}
+
+// skipsMissedRuns returns whether t may not run for some of the times it is
+// scheduled for, as its catchUp policy skips some of the runs it missed.
+func skipsMissedRuns(t *influxdb.Task) bool {
+	return t.CatchUp != options.CatchUpAll || t.MaxMissedRuns > 0
+}
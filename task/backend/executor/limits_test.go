@@ -2,11 +2,16 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"github.com/influxdata/influxdb/v2/task/options"
 )
 
 var (
@@ -56,3 +61,215 @@ func TestTaskConcurrency(t *testing.T) {
 	// TODO(lh): add testing around infinite concurrency once the task options
 	// are not setting a default concurrency to 1.
 }
+
+func TestTaskDependencies(t *testing.T) {
+	tes := taskExecutorSystem(t)
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+
+	upstream, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: tes.tc.OrgID,
+		OwnerID:        tes.tc.Auth.GetUserID(),
+		Flux:           fmt.Sprintf(fmtTestScript, t.Name()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the executor finds the finished runs of the upstream task in its history.
+	history := &runHistory{TaskService: tes.i}
+	tes.ex.ts = history
+
+	// deleted upstream tasks do not hold runs back.
+	downstream := &influxdb.Task{ID: upstream.ID + 1, DependsOn: []influxdb.ID{upstream.ID, influxdb.ID(0xdead)}}
+	scheduledFor := upstream.LatestCompleted.Add(time.Minute)
+	run := &influxdb.Run{ID: 1, TaskID: downstream.ID, ScheduledFor: scheduledFor}
+
+	dlFunc := DependencyLimit(tes.ex)
+	expectCode := func(code string) {
+		t.Helper()
+		err := dlFunc(downstream, run)
+		if code == "" && err != nil {
+			t.Fatal(err)
+		}
+		if code != "" && influxdb.ErrorCode(err) != code {
+			t.Fatalf("expected error code %q, got %v", code, err)
+		}
+	}
+
+	// the upstream task has not run for the scheduled time yet.
+	expectCode(influxdb.ETooManyRequests)
+
+	r, err := tes.tcs.CreateRun(ctx, upstream.ID, scheduledFor, scheduledFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := scheduledFor
+	if _, err := tes.i.UpdateTask(ctx, upstream.ID, influxdb.TaskUpdate{LatestCompleted: &latest}); err != nil {
+		t.Fatal(err)
+	}
+	// the run of the upstream task is still running.
+	expectCode(influxdb.ETooManyRequests)
+
+	if err := tes.tcs.UpdateRunState(ctx, upstream.ID, r.ID, time.Now(), influxdb.RunFail); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tes.tcs.FinishRun(ctx, upstream.ID, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	history.add(&influxdb.Run{ID: r.ID, TaskID: upstream.ID, ScheduledFor: scheduledFor, Status: influxdb.RunFail.String()})
+	expectCode(influxdb.EConflict)
+
+	// a retry of the run for the scheduled time succeeded.
+	history.add(&influxdb.Run{ID: r.ID + 1, TaskID: upstream.ID, ScheduledFor: scheduledFor, Status: influxdb.RunSuccess.String(), RetryOf: r.ID, Retry: 1})
+	expectCode("")
+
+	// later runs of the upstream task do not matter, nor does its latest run status.
+	history.add(&influxdb.Run{ID: r.ID + 2, TaskID: upstream.ID, ScheduledFor: scheduledFor.Add(time.Minute), Status: influxdb.RunFail.String()})
+	failed := influxdb.RunFail.String()
+	if _, err := tes.i.UpdateTask(ctx, upstream.ID, influxdb.TaskUpdate{LastRunStatus: &failed}); err != nil {
+		t.Fatal(err)
+	}
+	expectCode("")
+
+	// an inactive upstream task does not catch up with later runs.
+	inactive := influxdb.TaskStatusInactive
+	if _, err := tes.i.UpdateTask(ctx, upstream.ID, influxdb.TaskUpdate{Status: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	run.ScheduledFor = scheduledFor.Add(time.Minute)
+	expectCode(influxdb.EConflict)
+}
+
+func TestTaskDependencies_UpstreamRuns(t *testing.T) {
+	const catchUpScript = `
+option task = {
+			name: %q,
+			every: 1m,
+			catchUp: %q,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+	tests := []struct {
+		name    string
+		catchUp string
+		// runs returns the finished runs of the upstream task given the time
+		// the downstream run is scheduled for.
+		runs         func(upstream influxdb.ID, scheduledFor time.Time) []*influxdb.Run
+		beforeCreate bool
+		wantCode     string
+	}{
+		{
+			name:    "failed run older than a page of runs",
+			catchUp: options.CatchUpAll,
+			runs: func(upstream influxdb.ID, scheduledFor time.Time) []*influxdb.Run {
+				runs := []*influxdb.Run{{ID: 1, TaskID: upstream, ScheduledFor: scheduledFor, Status: influxdb.RunFail.String()}}
+				for i := 1; i <= influxdb.TaskMaxPageSize; i++ {
+					runs = append(runs, &influxdb.Run{ID: influxdb.ID(i + 1), TaskID: upstream, ScheduledFor: scheduledFor.Add(time.Duration(i) * time.Minute), Status: influxdb.RunSuccess.String()})
+				}
+				return runs
+			},
+			wantCode: influxdb.EConflict,
+		},
+		{
+			name:    "no run of a task skipping missed runs",
+			catchUp: options.CatchUpSkip,
+		},
+		{
+			name:     "no run of a task catching up with all missed runs",
+			catchUp:  options.CatchUpAll,
+			wantCode: influxdb.EConflict,
+		},
+		{
+			name:         "no run before the task was created",
+			catchUp:      options.CatchUpAll,
+			beforeCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tes := taskExecutorSystem(t)
+			ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+
+			upstream, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+				OrganizationID: tes.tc.OrgID,
+				OwnerID:        tes.tc.Auth.GetUserID(),
+				Flux:           fmt.Sprintf(catchUpScript, t.Name(), tt.catchUp),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scheduledFor := upstream.CreatedAt.Add(time.Minute).Truncate(time.Second)
+			if tt.beforeCreate {
+				scheduledFor = upstream.CreatedAt.Add(-time.Hour).Truncate(time.Second)
+			}
+			history := &runHistory{TaskService: tes.i}
+			if tt.runs != nil {
+				history.runs = tt.runs(upstream.ID, scheduledFor)
+			}
+			tes.ex.ts = history
+
+			latest := scheduledFor.Add(influxdb.TaskMaxPageSize * time.Minute)
+			if _, err := tes.i.UpdateTask(ctx, upstream.ID, influxdb.TaskUpdate{LatestCompleted: &latest}); err != nil {
+				t.Fatal(err)
+			}
+
+			downstream := &influxdb.Task{ID: upstream.ID + 1, DependsOn: []influxdb.ID{upstream.ID}}
+			err = DependencyLimit(tes.ex)(downstream, &influxdb.Run{ID: 1, TaskID: downstream.ID, ScheduledFor: scheduledFor})
+			if tt.wantCode == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCode != "" && influxdb.ErrorCode(err) != tt.wantCode {
+				t.Fatalf("expected error code %q, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+// runHistory is a task service finding the finished runs added to it, as the
+// analytical storage finds those recorded in the system bucket.
+type runHistory struct {
+	influxdb.TaskService
+
+	mu   sync.Mutex
+	runs []*influxdb.Run
+}
+
+func (h *runHistory) add(r *influxdb.Run) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.runs = append(h.runs, r)
+}
+
+func (h *runHistory) FindRuns(ctx context.Context, filter influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+	runs, _, err := h.TaskService.FindRuns(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	after, before, err := filter.ScheduledRange()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// like the analytical storage, return a page of the most recently
+	// scheduled runs.
+	var finished []*influxdb.Run
+	for _, r := range h.runs {
+		if r.TaskID == filter.Task && influxdb.ScheduledWithin(r.ScheduledFor, after, before) {
+			finished = append(finished, r)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].ScheduledFor.After(finished[j].ScheduledFor)
+	})
+	if n := filter.Limit - len(runs); len(finished) > n {
+		finished = finished[:n]
+	}
+	runs = append(runs, finished...)
+	return runs, len(runs), nil
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/options"
+)
+
+// paramsExtern returns the extern of the query of the run r of t, a Flux file
+// setting the params option to the values of the parameters of t, those of r
+// replacing the ones of t. It returns nil if t has no parameters, and r may be
+// nil to use the values of t.
+func paramsExtern(t *influxdb.Task, r *influxdb.Run) (json.RawMessage, error) {
+	if len(t.Params) == 0 {
+		return nil, nil
+	}
+
+	params := make([]influxdb.TaskParam, len(t.Params))
+	copy(params, t.Params)
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+
+	obj := &ast.ObjectExpression{}
+	for _, p := range params {
+		v := p.Value
+		if r != nil {
+			if rv, ok := r.Params[p.Name]; ok {
+				v = rv
+			}
+		}
+		if v == nil {
+			return nil, influxdb.ErrInvalidTaskParam(p.Name, "no value for the run")
+		}
+		v, err := p.ParseValue(v)
+		if err != nil {
+			return nil, influxdb.ErrInvalidTaskParam(p.Name, err.Error())
+		}
+		lit, err := paramLiteral(p.Type, v)
+		if err != nil {
+			return nil, influxdb.ErrInvalidTaskParam(p.Name, err.Error())
+		}
+		obj.Properties = append(obj.Properties, &ast.Property{
+			Key:   &ast.Identifier{Name: p.Name},
+			Value: lit,
+		})
+	}
+
+	file := &ast.File{
+		Body: []ast.Statement{
+			&ast.OptionStatement{
+				Assignment: &ast.VariableAssignment{
+					ID:   &ast.Identifier{Name: influxdb.TaskParamsIdent},
+					Init: obj,
+				},
+			},
+		},
+	}
+	return json.Marshal(file)
+}
+
+// paramLiteral returns the Flux literal of the value v of a parameter of type
+// typ, as parsed by TaskParam.ParseValue.
+func paramLiteral(typ string, v interface{}) (ast.Expression, error) {
+	switch typ {
+	case influxdb.TaskParamInt:
+		return &ast.IntegerLiteral{Value: v.(int64)}, nil
+	case influxdb.TaskParamFloat:
+		return &ast.FloatLiteral{Value: v.(float64)}, nil
+	case influxdb.TaskParamBool:
+		return &ast.BooleanLiteral{Value: v.(bool)}, nil
+	case influxdb.TaskParamDuration:
+		return options.ParseSignedDuration(v.(string))
+	case influxdb.TaskParamTime:
+		t, err := time.Parse(time.RFC3339Nano, v.(string))
+		if err != nil {
+			return nil, err
+		}
+		return &ast.DateTimeLiteral{Value: t}, nil
+	case influxdb.TaskParamRegexp:
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		return &ast.RegexpLiteral{Value: re}, nil
+	default:
+		return &ast.StringLiteral{Value: v.(string)}, nil
+	}
+}
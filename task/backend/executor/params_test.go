@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestParamsExtern(t *testing.T) {
+	params := []influxdb.TaskParam{
+		{Name: "threshold", Type: influxdb.TaskParamFloat, Value: 0.5},
+		{Name: "bucket", Type: influxdb.TaskParamString, Value: "telegraf"},
+		{Name: "host", Type: influxdb.TaskParamRegexp, Value: "^web-[0-9]+$"},
+		{Name: "every", Type: influxdb.TaskParamDuration, Value: "5m"},
+		// a value decoded from JSON.
+		{Name: "limit", Type: influxdb.TaskParamInt, Value: float64(10)},
+	}
+
+	tests := []struct {
+		name    string
+		params  []influxdb.TaskParam
+		run     *influxdb.Run
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no params",
+		},
+		{
+			name:   "task values",
+			params: params,
+			want:   "option params = {\n\tbucket: \"telegraf\",\n\tevery: 5m,\n\thost: /^web-[0-9]+$/,\n\tlimit: 10,\n\tthreshold: 0.5,\n}",
+		},
+		{
+			name:   "run values",
+			params: params,
+			run:    &influxdb.Run{Params: map[string]interface{}{"bucket": "other", "limit": int64(3)}},
+			want:   "option params = {\n\tbucket: \"other\",\n\tevery: 5m,\n\thost: /^web-[0-9]+$/,\n\tlimit: 3,\n\tthreshold: 0.5,\n}",
+		},
+		{
+			name:    "missing value",
+			params:  []influxdb.TaskParam{{Name: "bucket", Type: influxdb.TaskParamString}},
+			run:     &influxdb.Run{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			params:  []influxdb.TaskParam{{Name: "limit", Type: influxdb.TaskParamInt, Value: 1.5}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extern, err := paramsExtern(&influxdb.Task{Params: tt.params}, tt.run)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.want == "" {
+				if extern != nil {
+					t.Fatalf("expected no extern, got %s", extern)
+				}
+				return
+			}
+
+			file, err := ast.UnmarshalNode(extern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ast.Format(file); got != tt.want {
+				t.Fatalf("unexpected extern\ngot:  %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -173,7 +173,7 @@ var _ flux.Query = (*fakeQuery)(nil)
 
 func (q *fakeQuery) Done()                       {}
 func (q *fakeQuery) Cancel()                     { close(q.results) }
-func (q *fakeQuery) Statistics() flux.Statistics { return flux.Statistics{} }
+func (q *fakeQuery) Statistics() flux.Statistics { return fakeStatistics }
 func (q *fakeQuery) Results() <-chan flux.Result { return q.results }
 
 func (q *fakeQuery) Err() error {
@@ -198,11 +198,27 @@ func (q *fakeQuery) run(ctx context.Context) {
 	}
 
 	if q.forcedError == nil {
+		if ws := query.WriteStatisticsFromContext(ctx); ws != nil {
+			ws.Add(fakeBucketID, 3)
+		}
 		res := newFakeResult()
 		q.results <- res
 	}
 }
 
+// fakeStatistics are the statistics of the fake queries, which write 3 points
+// to fakeBucketID when they succeed.
+var (
+	fakeStatistics = flux.Statistics{
+		TotalDuration: 2 * time.Second,
+		MaxAllocated:  1024,
+		Metadata: flux.Metadata{
+			query.ScannedValuesMetadataKey: []interface{}{12},
+		},
+	}
+	fakeBucketID = influxdb.ID(0xbeef)
+)
+
 // fakeResult is a dumb implementation of flux.Result that always returns the same values.
 type fakeResult struct {
 	name  string
@@ -296,3 +312,11 @@ option task = {
 			every: 1m,
 }
 from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
+
+const fmtTestRetryScript = `
+option task = {
+			name: %q,
+			every: 1m,
+			retry: 1,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`
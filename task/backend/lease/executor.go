@@ -0,0 +1,51 @@
+package lease
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+var _ scheduler.Executor = (*Executor)(nil)
+
+// Executor is an executor only executing a run once its owner claimed the time
+// it is scheduled for with the lease of its task. The runs of the tasks whose
+// lease is held by another owner, or that were already claimed, are skipped.
+type Executor struct {
+	log   *zap.Logger
+	store *Store
+	owner influxdb.ID
+	ex    scheduler.Executor
+}
+
+// NewExecutor constructs an executor claiming the runs ex executes for owner
+// in st.
+func NewExecutor(log *zap.Logger, st *Store, owner influxdb.ID, ex scheduler.Executor) *Executor {
+	return &Executor{
+		log:   log,
+		store: st,
+		owner: owner,
+		ex:    ex,
+	}
+}
+
+// Execute claims scheduledFor for the task id, and executes the run if the
+// claim succeeds.
+func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	err := e.store.Claim(ctx, influxdb.ID(id), e.owner, scheduledFor)
+	switch err {
+	case nil:
+		return e.ex.Execute(ctx, id, scheduledFor, runAt)
+	case ErrLeaseNotHeld, ErrRunClaimed:
+		e.log.Debug("Skipping task run",
+			zap.String("taskID", influxdb.ID(id).String()),
+			zap.Time("scheduledFor", scheduledFor),
+			zap.String("reason", err.(*influxdb.Error).Msg))
+		return nil
+	default:
+		return err
+	}
+}
@@ -0,0 +1,326 @@
+package lease
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/backend/coordinator"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+// DefaultTTL is the default time a lease is held for without being renewed.
+const DefaultTTL = 30 * time.Second
+
+var _ scheduler.Scheduler = (*Manager)(nil)
+
+// TaskFinder lists the tasks to lease.
+type TaskFinder interface {
+	FindTasks(context.Context, influxdb.TaskFilter) ([]*influxdb.Task, int, error)
+}
+
+// Manager is a scheduler only scheduling the tasks it holds the lease of on
+// the scheduler it wraps. It renews the leases it holds, and acquires the
+// leases nobody holds until it holds its share of the active tasks among
+// the owners alive, releasing the leases it holds beyond that share.
+type Manager struct {
+	log   *zap.Logger
+	store *Store
+	owner influxdb.ID
+	sch   scheduler.Scheduler
+	tasks TaskFinder
+
+	// TTL is the time a lease is held for without being renewed.
+	TTL time.Duration
+	// RenewInterval is the interval at which leases are renewed and the
+	// active tasks are rebalanced. It must be shorter than TTL.
+	RenewInterval time.Duration
+	// ResumeRuns, when set, resumes or fails the runs still marked running
+	// of a task whose lease was taken over, which the owner that left them
+	// will not finish.
+	ResumeRuns func(ctx context.Context, taskID influxdb.ID) error
+
+	mu      sync.Mutex
+	stopped bool
+	// held maps the tasks scheduled on sch to the schedule they were
+	// scheduled with.
+	held map[influxdb.ID]schedule
+}
+
+// schedule is what a task is scheduled by.
+type schedule struct {
+	cron     string
+	offset   time.Duration
+	timezone string
+}
+
+func scheduleOf(t *influxdb.Task) schedule {
+	return schedule{
+		cron:     t.EffectiveCron(),
+		offset:   t.Offset,
+		timezone: t.Timezone,
+	}
+}
+
+// NewManager constructs a manager taking leases of the tasks of ts for owner
+// in st, and scheduling the tasks it holds the lease of on sch.
+func NewManager(log *zap.Logger, st *Store, owner influxdb.ID, sch scheduler.Scheduler, ts TaskFinder) *Manager {
+	return &Manager{
+		log:           log,
+		store:         st,
+		owner:         owner,
+		sch:           sch,
+		tasks:         ts,
+		TTL:           DefaultTTL,
+		RenewInterval: DefaultTTL / 3,
+		held:          make(map[influxdb.ID]schedule),
+	}
+}
+
+// Schedule schedules task if the manager holds or acquires its lease. A task
+// held by another owner is left for that owner, which picks up the changes
+// to the task when rebalancing.
+func (m *Manager) Schedule(task scheduler.Schedulable) error {
+	ctx := context.Background()
+	id := influxdb.ID(task.ID())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return nil
+	}
+
+	ok, takenOver, err := m.store.Acquire(ctx, id, m.owner, m.TTL)
+	if err != nil || !ok {
+		return err
+	}
+	if err := m.sch.Schedule(task); err != nil {
+		return err
+	}
+
+	var sch schedule
+	if t, ok := task.(coordinator.SchedulableTask); ok {
+		sch = scheduleOf(t.Task)
+	}
+	m.held[id] = sch
+	if takenOver {
+		m.resumeRuns(ctx, id)
+	}
+	return nil
+}
+
+// Release releases the task taskID and its lease.
+func (m *Manager) Release(taskID scheduler.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.held[influxdb.ID(taskID)]; !ok {
+		return m.sch.Release(taskID)
+	}
+	return m.release(context.Background(), influxdb.ID(taskID))
+}
+
+// Stop stops the scheduler the manager wraps if it can be stopped, and
+// expires all the leases the manager holds for other owners to take them
+// over right away, along with the runs left running.
+func (m *Manager) Stop() {
+	if s, ok := m.sch.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopped = true
+	ctx := context.Background()
+	for id := range m.held {
+		delete(m.held, id)
+		if err := m.store.Expire(ctx, id, m.owner); err != nil {
+			m.log.Error("Failed to expire task lease", zap.String("taskID", id.String()), zap.Error(err))
+		}
+	}
+	if err := m.store.Leave(ctx, m.owner); err != nil {
+		m.log.Error("Failed to leave task leasing", zap.Error(err))
+	}
+}
+
+// Run renews and rebalances the leases every RenewInterval until ctx is done.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.rebalance(ctx); err != nil && ctx.Err() == nil {
+			m.log.Error("Failed to rebalance task leases", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rebalance renews the leases held, releases the tasks whose lease was lost,
+// and acquires or releases leases until the manager holds its share of the
+// active tasks. Tasks held whose schedule changed are scheduled again.
+func (m *Manager) rebalance(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return nil
+	}
+
+	owners, err := m.store.Heartbeat(ctx, m.owner, m.TTL)
+	if err != nil {
+		return err
+	}
+
+	ids, err := m.store.Renew(ctx, m.owner, m.TTL)
+	if err != nil {
+		return err
+	}
+	renewed := make(map[influxdb.ID]bool, len(ids))
+	for _, id := range ids {
+		renewed[id] = true
+	}
+	for id := range m.held {
+		if !renewed[id] {
+			m.log.Info("Task lease lost", zap.String("taskID", id.String()))
+			delete(m.held, id)
+			if err := m.sch.Release(scheduler.ID(id)); err != nil {
+				return err
+			}
+		}
+	}
+
+	tasks, err := m.findActiveTasks(ctx)
+	if err != nil {
+		return err
+	}
+	leases, err := m.store.FindLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[influxdb.ID]*influxdb.Task, len(tasks))
+	for _, t := range tasks {
+		active[t.ID] = t
+	}
+	for _, id := range ids {
+		t, ok := active[id]
+		if !ok {
+			// the task was deleted or deactivated.
+			if err := m.release(ctx, id); err != nil {
+				return err
+			}
+			continue
+		}
+		if sch, ok := m.held[id]; !ok || sch != scheduleOf(t) {
+			if err := m.schedule(t); err != nil {
+				m.log.Error("Failed to schedule leased task", zap.String("taskID", id.String()), zap.Error(err))
+			}
+		}
+	}
+
+	share := (len(tasks) + owners - 1) / owners
+	now := m.store.Now()
+	for _, t := range tasks {
+		if len(m.held) >= share {
+			break
+		}
+		if _, ok := m.held[t.ID]; ok {
+			continue
+		}
+		if l, ok := leases[t.ID]; ok && l.OwnerID != m.owner && !l.Expired(now) {
+			continue
+		}
+
+		ok, takenOver, err := m.store.Acquire(ctx, t.ID, m.owner, m.TTL)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := m.schedule(t); err != nil {
+			m.log.Error("Failed to schedule leased task", zap.String("taskID", t.ID.String()), zap.Error(err))
+		}
+		if takenOver {
+			m.resumeRuns(ctx, t.ID)
+		}
+	}
+
+	if len(m.held) > share {
+		held := make([]influxdb.ID, 0, len(m.held))
+		for id := range m.held {
+			held = append(held, id)
+		}
+		sort.Slice(held, func(i, j int) bool { return held[i] > held[j] })
+		for _, id := range held[:len(held)-share] {
+			if err := m.release(ctx, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schedule schedules the leased task t on the wrapped scheduler. A task
+// whose lease is held but that cannot be scheduled keeps its lease so that
+// no other owner retries it.
+func (m *Manager) schedule(t *influxdb.Task) error {
+	m.held[t.ID] = scheduleOf(t)
+	st, err := coordinator.NewSchedulableTask(t)
+	if err != nil {
+		return err
+	}
+	return m.sch.Schedule(st)
+}
+
+// release releases the task id from the wrapped scheduler, then its lease.
+func (m *Manager) release(ctx context.Context, id influxdb.ID) error {
+	delete(m.held, id)
+	if err := m.sch.Release(scheduler.ID(id)); err != nil && err != influxdb.ErrTaskNotClaimed {
+		return err
+	}
+	return m.store.Release(ctx, id, m.owner)
+}
+
+// resumeRuns resumes the runs left running of the task id, whose lease was
+// taken over.
+func (m *Manager) resumeRuns(ctx context.Context, id influxdb.ID) {
+	if m.ResumeRuns == nil {
+		return
+	}
+	if err := m.ResumeRuns(ctx, id); err != nil {
+		m.log.Error("Failed to resume task runs", zap.String("taskID", id.String()), zap.Error(err))
+	}
+}
+
+func (m *Manager) findActiveTasks(ctx context.Context) ([]*influxdb.Task, error) {
+	status := string(influxdb.TaskActive)
+	filter := influxdb.TaskFilter{
+		Status: &status,
+		Limit:  influxdb.TaskMaxPageSize,
+	}
+
+	var all []*influxdb.Task
+	for {
+		tasks, _, err := m.tasks.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, tasks...)
+		if len(tasks) < filter.Limit {
+			return all, nil
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+}
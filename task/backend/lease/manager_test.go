@@ -0,0 +1,317 @@
+package lease
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backend/coordinator"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeScheduler records the tasks scheduled on it.
+type fakeScheduler struct {
+	mu        sync.Mutex
+	scheduled map[scheduler.ID]int
+	stopped   bool
+}
+
+func newFakeScheduler() *fakeScheduler {
+	return &fakeScheduler{scheduled: make(map[scheduler.ID]int)}
+}
+
+func (s *fakeScheduler) Schedule(task scheduler.Schedulable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scheduled[task.ID()]++
+	return nil
+}
+
+func (s *fakeScheduler) Release(taskID scheduler.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.scheduled, taskID)
+	return nil
+}
+
+func (s *fakeScheduler) Stop() {
+	s.stopped = true
+}
+
+// tasks returns the IDs of the tasks scheduled.
+func (s *fakeScheduler) tasks() []influxdb.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]influxdb.ID, 0, len(s.scheduled))
+	for id := range s.scheduled {
+		ids = append(ids, influxdb.ID(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// taskList is the tasks of the task service shared by the managers of a test.
+type taskList struct {
+	mu    sync.Mutex
+	tasks []*influxdb.Task
+}
+
+func (l *taskList) set(tasks ...*influxdb.Task) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tasks = tasks
+}
+
+func (l *taskList) service() *mock.TaskService {
+	ts := mock.NewTaskService()
+	ts.FindTasksFn = func(_ context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		var tasks []*influxdb.Task
+		for _, t := range l.tasks {
+			if filter.Status != nil && t.Status != *filter.Status {
+				continue
+			}
+			if filter.After != nil && t.ID <= *filter.After {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+		return tasks, len(tasks), nil
+	}
+	return ts
+}
+
+func activeTask(id influxdb.ID) *influxdb.Task {
+	return &influxdb.Task{
+		ID:        id,
+		Status:    string(influxdb.TaskActive),
+		Every:     "1m",
+		CreatedAt: start,
+	}
+}
+
+// node is a process leasing tasks.
+type node struct {
+	owner   influxdb.ID
+	store   *Store
+	sch     *fakeScheduler
+	manager *Manager
+	// resumed are the tasks whose runs left running were resumed.
+	resumed []influxdb.ID
+}
+
+func newNode(t *testing.T, st kv.Store, c *clock, owner influxdb.ID, tasks *taskList) *node {
+	s := newStore(st, c)
+	sch := newFakeScheduler()
+	m := NewManager(zaptest.NewLogger(t), s, owner, sch, tasks.service())
+	m.TTL = ttl
+	n := &node{owner: owner, store: s, sch: sch, manager: m}
+	m.ResumeRuns = func(_ context.Context, taskID influxdb.ID) error {
+		n.resumed = append(n.resumed, taskID)
+		return nil
+	}
+	return n
+}
+
+func (n *node) rebalance(t *testing.T) {
+	t.Helper()
+
+	if err := n.manager.rebalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertTasks(t *testing.T, n *node, want ...influxdb.ID) {
+	t.Helper()
+
+	got := n.sch.tasks()
+	if len(got) != len(want) {
+		t.Fatalf("owner %s scheduled %v, want %v", n.owner, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("owner %s scheduled %v, want %v", n.owner, got, want)
+		}
+	}
+}
+
+// assertResumed asserts the tasks whose runs n resumed since the last call.
+func assertResumed(t *testing.T, n *node, want ...influxdb.ID) {
+	t.Helper()
+
+	got := n.resumed
+	n.resumed = nil
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	if len(got) != len(want) {
+		t.Fatalf("owner %s resumed the runs of %v, want %v", n.owner, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("owner %s resumed the runs of %v, want %v", n.owner, got, want)
+		}
+	}
+}
+
+func TestManager_Rebalance(t *testing.T) {
+	st := newKVStore(t)
+	c := &clock{now: start}
+	tasks := &taskList{}
+	tasks.set(activeTask(1), activeTask(2), activeTask(3), activeTask(4))
+
+	a := newNode(t, st, c, ownerA, tasks)
+	b := newNode(t, st, c, ownerB, tasks)
+
+	// alone, a takes all the tasks, which were never leased.
+	a.rebalance(t)
+	assertTasks(t, a, 1, 2, 3, 4)
+	assertResumed(t, a, 1, 2, 3, 4)
+
+	// b joins, a releases the tasks beyond its share for b to take, and
+	// finishes the runs it is executing.
+	b.rebalance(t)
+	assertTasks(t, b)
+	a.rebalance(t)
+	assertTasks(t, a, 1, 2)
+	b.rebalance(t)
+	assertTasks(t, b, 3, 4)
+	assertResumed(t, b)
+
+	// a dies, b takes its tasks over once their leases expired.
+	c.now = c.now.Add(ttl / 2)
+	b.rebalance(t)
+	assertTasks(t, b, 3, 4)
+	c.now = c.now.Add(ttl)
+	b.rebalance(t)
+	assertTasks(t, b, 1, 2, 3, 4)
+	assertResumed(t, b, 1, 2)
+
+	// a comes back and finds its leases lost.
+	a.rebalance(t)
+	assertTasks(t, a)
+}
+
+func TestManager_TaskChanges(t *testing.T) {
+	st := newKVStore(t)
+	c := &clock{now: start}
+	tasks := &taskList{}
+	tasks.set(activeTask(1))
+
+	a := newNode(t, st, c, ownerA, tasks)
+	b := newNode(t, st, c, ownerB, tasks)
+	a.rebalance(t)
+	b.rebalance(t)
+	assertResumed(t, a, 1)
+
+	// a task created through b is scheduled by b.
+	created := activeTask(2)
+	tasks.set(activeTask(1), created)
+	st2, err := coordinator.NewSchedulableTask(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.manager.Schedule(st2); err != nil {
+		t.Fatal(err)
+	}
+	assertTasks(t, b, 2)
+
+	// a task updated through b, but held by a, is scheduled again by a.
+	updated := activeTask(1)
+	updated.Every = "5m"
+	tasks.set(updated, created)
+	st1, err := coordinator.NewSchedulableTask(updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.manager.Schedule(st1); err != nil {
+		t.Fatal(err)
+	}
+	assertTasks(t, b, 2)
+	a.rebalance(t)
+	assertTasks(t, a, 1)
+	if n := a.sch.scheduled[1]; n != 2 {
+		t.Fatalf("task scheduled %d times, want 2", n)
+	}
+
+	// a task deactivated is released by its owner.
+	inactive := activeTask(1)
+	inactive.Status = string(influxdb.TaskInactive)
+	tasks.set(inactive, created)
+	a.rebalance(t)
+	assertTasks(t, a)
+
+	// a stopped owner releases its leases right away.
+	b.manager.Stop()
+	if !b.sch.stopped {
+		t.Fatal("scheduler not stopped")
+	}
+	a.rebalance(t)
+	assertTasks(t, a, 2)
+	assertResumed(t, a, 2)
+}
+
+// countingExecutor counts the runs executed by task and time.
+type countingExecutor struct {
+	mu   sync.Mutex
+	runs map[scheduler.ID]map[time.Time]int
+}
+
+func (e *countingExecutor) Execute(_ context.Context, id scheduler.ID, scheduledFor time.Time, _ time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.runs[id] == nil {
+		e.runs[id] = make(map[time.Time]int)
+	}
+	e.runs[id][scheduledFor]++
+	return nil
+}
+
+func TestExecutor_Takeover(t *testing.T) {
+	ctx := context.Background()
+	st := newKVStore(t)
+	c := &clock{now: start}
+	tasks := &taskList{}
+	tasks.set(activeTask(1))
+
+	a := newNode(t, st, c, ownerA, tasks)
+	b := newNode(t, st, c, ownerB, tasks)
+	ex := &countingExecutor{runs: make(map[scheduler.ID]map[time.Time]int)}
+	exA := NewExecutor(zaptest.NewLogger(t), a.store, ownerA, ex)
+	exB := NewExecutor(zaptest.NewLogger(t), b.store, ownerB, ex)
+
+	a.rebalance(t)
+	b.rebalance(t)
+
+	execute := func(e *Executor, scheduledFor time.Time) {
+		t.Helper()
+		if err := e.Execute(ctx, 1, scheduledFor, scheduledFor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	execute(exA, start.Add(time.Minute))
+	execute(exB, start.Add(time.Minute))
+	execute(exA, start.Add(2*time.Minute))
+
+	// a stops renewing its lease, and b takes the task over scheduling it
+	// from a time a already executed.
+	c.now = c.now.Add(2 * ttl)
+	b.rebalance(t)
+	assertTasks(t, b, 1)
+	execute(exA, start.Add(3*time.Minute))
+	execute(exB, start.Add(2*time.Minute))
+	execute(exB, start.Add(3*time.Minute))
+
+	for i := 1; i <= 3; i++ {
+		scheduledFor := start.Add(time.Duration(i) * time.Minute)
+		if n := ex.runs[1][scheduledFor]; n != 1 {
+			t.Errorf("run scheduled for %s executed %d times, want 1", scheduledFor, n)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+// Package lease lets several processes sharing a kv store split the tasks to
+// schedule between them. Each process claims tasks with leases it keeps
+// renewing, and takes over the tasks whose lease expired when a peer dies.
+// A run of a task is only executed once the time it is scheduled for is
+// claimed with the lease of the task, so no time is executed twice.
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+var (
+	leaseBucket = []byte("taskleasesv1")
+	ownerBucket = []byte("taskleaseownersv1")
+)
+
+var (
+	// ErrLeaseNotHeld is used when a lease is held by another owner.
+	ErrLeaseNotHeld = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "task lease is held by another owner",
+	}
+
+	// ErrRunClaimed is used when claiming a time a task was already claimed for.
+	ErrRunClaimed = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "task run has already been claimed",
+	}
+)
+
+// Lease is the claim of an owner on the scheduling of a task.
+type Lease struct {
+	TaskID influxdb.ID `json:"taskID"`
+	// OwnerID is not valid once the lease was released.
+	OwnerID   influxdb.ID `json:"ownerID,omitempty"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+	// LastClaimed is the latest time a run of the task was claimed for,
+	// by any owner.
+	LastClaimed time.Time `json:"lastClaimed,omitempty"`
+}
+
+// Expired returns whether the lease expired at now.
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Store persists leases and the owners taking them in a kv store.
+type Store struct {
+	store kv.Store
+	Now   func() time.Time
+}
+
+// NewStore constructs a lease store backed by st.
+func NewStore(st kv.Store) *Store {
+	return &Store{
+		store: st,
+		Now:   func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Acquire takes or renews the lease of the task taskID for owner until ttl
+// from now. It returns false if the lease is held by another owner and did
+// not expire. takenOver is true when owner took the lease over from an owner
+// whose lease expired, or when the task was never leased: the runs of the
+// task left running are then not executed by anyone.
+func (s *Store) Acquire(ctx context.Context, taskID, owner influxdb.ID, ttl time.Duration) (acquired, takenOver bool, err error) {
+	now := s.Now()
+	err = s.store.Update(ctx, func(tx kv.Tx) error {
+		l, err := findLease(tx, taskID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case l == nil:
+			l = &Lease{TaskID: taskID}
+			takenOver = true
+		case l.OwnerID == owner:
+		case !l.Expired(now):
+			return nil
+		case l.OwnerID.Valid():
+			takenOver = true
+		}
+
+		l.OwnerID = owner
+		l.ExpiresAt = now.Add(ttl)
+		acquired = true
+		return putLease(tx, l)
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return acquired, takenOver, nil
+}
+
+// Renew extends the leases owner holds until ttl from now, and returns the
+// IDs of their tasks. An expired lease is still held by its owner until
+// another owner acquires it.
+func (s *Store) Renew(ctx context.Context, owner influxdb.ID, ttl time.Duration) ([]influxdb.ID, error) {
+	now := s.Now()
+	var ids []influxdb.ID
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		leases, err := findLeases(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		ids = ids[:0]
+		for _, l := range leases {
+			if l.OwnerID != owner {
+				continue
+			}
+			l.ExpiresAt = now.Add(ttl)
+			if err := putLease(tx, l); err != nil {
+				return err
+			}
+			ids = append(ids, l.TaskID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Release gives up the lease of the task taskID if owner holds it or it
+// expired. The lease of a task held by another owner is left as is. The
+// times claimed are kept for the next owner, and the runs owner is executing
+// are left to finish on owner.
+func (s *Store) Release(ctx context.Context, taskID, owner influxdb.ID) error {
+	now := s.Now()
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		l, err := findLease(tx, taskID)
+		if err != nil || l == nil {
+			return err
+		}
+		if l.OwnerID != owner && !l.Expired(now) {
+			return nil
+		}
+		l.OwnerID = 0
+		l.ExpiresAt = time.Time{}
+		return putLease(tx, l)
+	})
+}
+
+// Expire ends the lease of the task taskID now if owner holds it, so that
+// another owner takes it over right away, along with the runs owner left
+// running.
+func (s *Store) Expire(ctx context.Context, taskID, owner influxdb.ID) error {
+	now := s.Now()
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		l, err := findLease(tx, taskID)
+		if err != nil || l == nil || l.OwnerID != owner {
+			return err
+		}
+		l.ExpiresAt = now
+		return putLease(tx, l)
+	})
+}
+
+// Claim records that owner executes the task taskID for scheduledFor. It
+// fails with ErrLeaseNotHeld if owner does not hold the lease of the task, and
+// with ErrRunClaimed if a run of the task was already claimed for
+// scheduledFor or a later time.
+func (s *Store) Claim(ctx context.Context, taskID, owner influxdb.ID, scheduledFor time.Time) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		l, err := findLease(tx, taskID)
+		if err != nil {
+			return err
+		}
+		if l == nil || l.OwnerID != owner {
+			return ErrLeaseNotHeld
+		}
+		if !scheduledFor.After(l.LastClaimed) {
+			return ErrRunClaimed
+		}
+
+		l.LastClaimed = scheduledFor.UTC()
+		return putLease(tx, l)
+	})
+}
+
+// FindLeases returns all the leases, by task ID.
+func (s *Store) FindLeases(ctx context.Context) (map[influxdb.ID]*Lease, error) {
+	leases := make(map[influxdb.ID]*Lease)
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		ls, err := findLeases(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, l := range ls {
+			leases[l.TaskID] = l
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// Heartbeat records that owner is alive until ttl from now, forgets the
+// owners that stopped sending heartbeats, and returns the number of owners
+// alive.
+func (s *Store) Heartbeat(ctx context.Context, owner influxdb.ID, ttl time.Duration) (int, error) {
+	now := s.Now()
+	alive := 0
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(ownerBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		var expired [][]byte
+		alive = 0
+		err = walk(ctx, b, func(k, v []byte) error {
+			var expiresAt time.Time
+			if err := json.Unmarshal(v, &expiresAt); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			if !now.Before(expiresAt) {
+				expired = append(expired, k)
+			} else if string(k) != string(encodeID(owner)) {
+				alive++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return influxdb.ErrUnexpectedTaskBucketErr(err)
+			}
+		}
+
+		v, err := json.Marshal(now.Add(ttl))
+		if err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		if err := b.Put(encodeID(owner), v); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		alive++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return alive, nil
+}
+
+// Leave forgets owner, which stops taking leases.
+func (s *Store) Leave(ctx context.Context, owner influxdb.ID) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(ownerBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		if err := b.Delete(encodeID(owner)); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		return nil
+	})
+}
+
+func findLease(tx kv.Tx, taskID influxdb.ID) (*Lease, error) {
+	b, err := tx.Bucket(leaseBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	v, err := b.Get(encodeID(taskID))
+	if kv.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	l := &Lease{}
+	if err := json.Unmarshal(v, l); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return l, nil
+}
+
+func findLeases(ctx context.Context, tx kv.Tx) ([]*Lease, error) {
+	b, err := tx.Bucket(leaseBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	var leases []*Lease
+	err = walk(ctx, b, func(k, v []byte) error {
+		l := &Lease{}
+		if err := json.Unmarshal(v, l); err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		leases = append(leases, l)
+		return nil
+	})
+	return leases, err
+}
+
+func putLease(tx kv.Tx, l *Lease) error {
+	b, err := tx.Bucket(leaseBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	v, err := json.Marshal(l)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+	if err := b.Put(encodeID(l.TaskID), v); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+func walk(ctx context.Context, b kv.Bucket, fn kv.VisitFunc) error {
+	c, err := b.ForwardCursor(nil)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return kv.WalkCursor(ctx, c, fn)
+}
+
+func encodeID(id influxdb.ID) []byte {
+	// IDs of tasks and owners are valid, so encoding cannot fail.
+	b, _ := id.Encode()
+	return b
+}
@@ -0,0 +1,252 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	ownerA = influxdb.ID(0xa)
+	ownerB = influxdb.ID(0xb)
+	ttl    = 30 * time.Second
+)
+
+var start = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// clock is the time of the lease stores of a test.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newKVStore(t *testing.T) kv.Store {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// newStore returns a lease store backed by st whose time is c.
+func newStore(st kv.Store, c *clock) *Store {
+	s := NewStore(st)
+	s.Now = c.Now
+	return s
+}
+
+func TestStore_Acquire(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	mustAcquire := func(owner influxdb.ID, want, wantTakenOver bool) {
+		t.Helper()
+		ok, takenOver, err := s.Acquire(ctx, 1, owner, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != want || takenOver != wantTakenOver {
+			t.Fatalf("acquired by %s: got %v taken over %v, want %v taken over %v", owner, ok, takenOver, want, wantTakenOver)
+		}
+	}
+
+	// a task never leased may have runs left running.
+	mustAcquire(ownerA, true, true)
+	mustAcquire(ownerB, false, false)
+	// renewing
+	mustAcquire(ownerA, true, false)
+
+	c.now = c.now.Add(ttl - time.Second)
+	mustAcquire(ownerB, false, false)
+
+	// the lease renewed a second ago expires after ttl.
+	c.now = c.now.Add(time.Second)
+	mustAcquire(ownerB, true, true)
+	mustAcquire(ownerA, false, false)
+
+	// a released lease is acquired, not taken over.
+	if err := s.Release(ctx, 1, ownerB); err != nil {
+		t.Fatal(err)
+	}
+	mustAcquire(ownerA, true, false)
+
+	leases, err := s.FindLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := leases[1]; l == nil || l.OwnerID != ownerA || !l.ExpiresAt.Equal(c.now.Add(ttl)) {
+		t.Fatalf("unexpected lease %+v", l)
+	}
+}
+
+func TestStore_Renew(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	for _, id := range []influxdb.ID{1, 2, 3} {
+		owner := ownerA
+		if id == 2 {
+			owner = ownerB
+		}
+		if _, _, err := s.Acquire(ctx, id, owner, ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// an expired lease not taken over is still held.
+	c.now = c.now.Add(2 * ttl)
+	ids, err := s.Renew(ctx, ownerA, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("got renewed tasks %v, want [1 3]", ids)
+	}
+
+	if ok, _, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || ok {
+		t.Fatalf("renewed lease acquired by another owner: %v, %v", ok, err)
+	}
+	if ok, _, err := s.Acquire(ctx, 2, ownerA, ttl); err != nil || !ok {
+		t.Fatalf("expired lease not acquired: %v, %v", ok, err)
+	}
+}
+
+func TestStore_Release(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	if _, _, err := s.Acquire(ctx, 1, ownerA, ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	// releasing the lease of another owner leaves it as is.
+	if err := s.Release(ctx, 1, ownerB); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || ok {
+		t.Fatalf("lease released by another owner: %v, %v", ok, err)
+	}
+
+	if err := s.Claim(ctx, 1, ownerA, start); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Release(ctx, 1, ownerA); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || !ok {
+		t.Fatalf("released lease not acquired: %v, %v", ok, err)
+	}
+	// the times claimed before the release carry over.
+	if err := s.Claim(ctx, 1, ownerB, start); err != ErrRunClaimed {
+		t.Fatalf("got %v, want %v", err, ErrRunClaimed)
+	}
+
+	// releasing a missing lease is not an error.
+	if err := s.Release(ctx, 2, ownerA); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Expire(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	if _, _, err := s.Acquire(ctx, 1, ownerA, ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	// expiring the lease of another owner leaves it as is.
+	if err := s.Expire(ctx, 1, ownerB); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || ok {
+		t.Fatalf("lease expired by another owner: %v, %v", ok, err)
+	}
+
+	if err := s.Expire(ctx, 1, ownerA); err != nil {
+		t.Fatal(err)
+	}
+	if ok, takenOver, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || !ok || !takenOver {
+		t.Fatalf("expired lease not taken over: %v, %v, %v", ok, takenOver, err)
+	}
+}
+
+func TestStore_Claim(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	if err := s.Claim(ctx, 1, ownerA, start); err != ErrLeaseNotHeld {
+		t.Fatalf("got %v, want %v", err, ErrLeaseNotHeld)
+	}
+
+	if _, _, err := s.Acquire(ctx, 1, ownerA, ttl); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Claim(ctx, 1, ownerA, start); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Claim(ctx, 1, ownerA, start); err != ErrRunClaimed {
+		t.Fatalf("got %v, want %v", err, ErrRunClaimed)
+	}
+	if err := s.Claim(ctx, 1, ownerB, start.Add(time.Minute)); err != ErrLeaseNotHeld {
+		t.Fatalf("got %v, want %v", err, ErrLeaseNotHeld)
+	}
+
+	// the times claimed carry over to the owner taking over the lease.
+	c.now = c.now.Add(ttl)
+	if ok, _, err := s.Acquire(ctx, 1, ownerB, ttl); err != nil || !ok {
+		t.Fatalf("expired lease not acquired: %v, %v", ok, err)
+	}
+	if err := s.Claim(ctx, 1, ownerB, start); err != ErrRunClaimed {
+		t.Fatalf("got %v, want %v", err, ErrRunClaimed)
+	}
+	if err := s.Claim(ctx, 1, ownerB, start.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Heartbeat(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{now: start}
+	s := newStore(newKVStore(t), c)
+
+	heartbeat := func(owner influxdb.ID, want int) {
+		t.Helper()
+		n, err := s.Heartbeat(ctx, owner, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Fatalf("got %d owners alive, want %d", n, want)
+		}
+	}
+
+	heartbeat(ownerA, 1)
+	heartbeat(ownerB, 2)
+	heartbeat(ownerA, 2)
+
+	c.now = c.now.Add(ttl)
+	heartbeat(ownerA, 1)
+	heartbeat(ownerB, 2)
+
+	if err := s.Leave(ctx, ownerB); err != nil {
+		t.Fatal(err)
+	}
+	heartbeat(ownerA, 1)
+}
@@ -112,13 +112,13 @@ func (s *CoordinatingTaskService) RetryRun(ctx context.Context, taskID, runID in
 }
 
 // ForceRun create the forced run in the task system and publish to the pubSub.
-func (s *CoordinatingTaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *CoordinatingTaskService) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 	t, err := s.TaskService.FindTaskByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := s.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	r, err := s.TaskService.ForceRun(ctx, taskID, scheduledFor, params)
 	if err != nil {
 		return r, err
 	}
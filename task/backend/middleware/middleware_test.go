@@ -97,7 +97,7 @@ func inmemTaskService() influxdb.TaskService {
 			}
 			return rtn, len(rtn), nil
 		},
-		ForceRunFn: func(ctx context.Context, id influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+		ForceRunFn: func(ctx context.Context, id influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
 			mu.Lock()
 			defer mu.Unlock()
 			t, ok := tasks[id]
@@ -217,7 +217,7 @@ func TestCoordinatingTaskService_ForceRun(t *testing.T) {
 	}
 
 	manualRunTime := time.Now().Unix()
-	if _, err = middleware.ForceRun(context.Background(), task.ID, manualRunTime); err != nil {
+	if _, err = middleware.ForceRun(context.Background(), task.ID, manualRunTime, nil); err != nil {
 		t.Fatal(err)
 	}
 
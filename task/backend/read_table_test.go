@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"io/ioutil"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/v2"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -37,3 +40,50 @@ func TestReadTable(t *testing.T) {
 		t.Fatalf("got error from iterator %v", itr.Err())
 	}
 }
+
+func TestReadTable_Stats(t *testing.T) {
+	encoded := []byte(`#group,false,false,true,true,false,true,false,false,false,false,false,false,false
+#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,string,string,string,string,string,string,string,string
+#default,_result,,,,,,,,,,,,
+,result,table,_start,_stop,_time,taskID,finishedAt,logs,runID,scheduledFor,startedAt,stats,status
+,,0,2020-07-23T20:06:24Z,2020-07-23T20:11:24Z,2020-07-23T20:06:30Z,0432e57782b51000,2020-07-23T20:06:31Z,"[{""runID"":""04341baa937a1000"",""time"":""2020-07-23T20:06:30Z"",""level"":""info"",""message"":""Query statistics"",""fields"":{""rowsRead"":""42""}}]",04341baa937a1000,2020-07-23T20:06:30Z,2020-07-23T20:06:30Z,"{""rowsRead"":42,""pointsWritten"":{""0432e57782b51001"":7},""queryDuration"":""1.5s"",""maxAllocated"":1024}",success
+`)
+
+	decoder := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{})
+	itr, err := decoder.Decode(ioutil.NopCloser(bytes.NewReader(encoded)))
+	if err != nil {
+		t.Fatalf("got error decoding csv: %v", err)
+	}
+
+	defer itr.Release()
+	re := &runReader{log: zaptest.NewLogger(t)}
+
+	for itr.More() {
+		err := itr.Next().Tables().Do(re.readTable)
+		if err != nil {
+			t.Fatalf("received error in runs table: %v", err)
+		}
+	}
+
+	if itr.Err() != nil {
+		t.Fatalf("got error from iterator %v", itr.Err())
+	}
+
+	if len(re.runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(re.runs))
+	}
+	run := re.runs[0]
+	bucketID, _ := influxdb.IDFromString("0432e57782b51001")
+	exp := &influxdb.RunStats{
+		RowsRead:      42,
+		PointsWritten: map[influxdb.ID]int64{*bucketID: 7},
+		QueryDuration: influxdb.Duration{Duration: 1500 * time.Millisecond},
+		MaxAllocated:  1024,
+	}
+	if diff := cmp.Diff(exp, run.Stats); diff != "" {
+		t.Fatalf("unexpected run stats -want/+got:\n%s", diff)
+	}
+	if len(run.Log) != 1 || run.Log[0].Level != influxdb.LogLevelInfo || run.Log[0].Fields["rowsRead"] != "42" {
+		t.Fatalf("unexpected run log %+v", run.Log)
+	}
+}
@@ -49,6 +49,13 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	fields[finishedAtField] = run.FinishedAt.Format(time.RFC3339Nano)
 	fields[scheduledForField] = run.ScheduledFor.Format(time.RFC3339)
 	fields[requestedAtField] = run.RequestedAt.Format(time.RFC3339)
+	if run.RetryOf.Valid() {
+		fields[retryOfField] = run.RetryOf.String()
+		fields[retryField] = int64(run.Retry)
+	}
+	if run.TaskVersion > 0 {
+		fields[taskVersionField] = int64(run.TaskVersion)
+	}
 
 	startedAt := run.StartedAt
 	if startedAt.IsZero() {
@@ -61,6 +68,22 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	}
 	fields[logField] = string(logBytes)
 
+	if len(run.Params) > 0 {
+		paramsBytes, err := json.Marshal(run.Params)
+		if err != nil {
+			return err
+		}
+		fields[paramsField] = string(paramsBytes)
+	}
+
+	if run.Stats != nil {
+		statsBytes, err := json.Marshal(run.Stats)
+		if err != nil {
+			return err
+		}
+		fields[statsField] = string(statsBytes)
+	}
+
 	point, err := models.NewPoint("runs", tags, fields, startedAt)
 	if err != nil {
 		return err
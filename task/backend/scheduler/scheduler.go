@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"context"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -68,31 +70,202 @@ func NewSchedule(unparsed string, lastScheduledAt time.Time) (Schedule, time.Tim
 		err := every.Parse(everyString)
 		if err != nil {
 			// We cannot align a invalid time
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// drop nanoseconds
 		lastScheduledAt = time.Unix(lastScheduledAt.UTC().Unix(), 0).UTC()
 		everyDur, err := every.DurationFrom(lastScheduledAt)
 		if err != nil {
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// and align
 		lastScheduledAt = lastScheduledAt.Truncate(everyDur).Truncate(time.Second)
 	}
 
-	return Schedule{c}, lastScheduledAt, err
+	return Schedule{cron: c}, lastScheduledAt, err
+}
+
+// NewScheduleInLocation is like NewSchedule, but a cron expression is matched
+// against the wall clock of loc rather than UTC. The times of the schedule are
+// still returned in UTC. A nil loc is UTC, and @every schedules ignore loc.
+func NewScheduleInLocation(unparsed string, loc *time.Location, lastScheduledAt time.Time) (Schedule, time.Time, error) {
+	sch, lastScheduledAt, err := NewSchedule(unparsed, lastScheduledAt)
+	if err != nil {
+		return sch, lastScheduledAt, err
+	}
+	if loc != nil && loc != time.UTC && !strings.HasPrefix(strings.TrimSpace(unparsed), "@every ") {
+		sch.loc = loc
+		sch.hourly = firesEveryHour(sch.cron)
+	}
+	return sch, lastScheduledAt, nil
 }
 
 // Schedule is an object a valid schedule of runs
 type Schedule struct {
 	cron cron.Parsed
+	loc  *time.Location
+	// hourly is whether the cron expression fires in every hour of the days
+	// it fires on.
+	hourly bool
 }
 
 // Next returns the next time after from that a schedule should trigger on.
 func (s Schedule) Next(from time.Time) (time.Time, error) {
-	return cron.Parsed(s.cron).Next(from)
+	if s.loc == nil {
+		return cron.Parsed(s.cron).Next(from)
+	}
+
+	// A wall clock time skipped by a daylight saving time transition triggers
+	// at the end of the transition. One repeated by it triggers only once,
+	// unless the schedule fires every hour: like the wildcard jobs of classic
+	// cron, it then triggers at every instant the wall clock matches, so that
+	// it keeps firing through the repeated hour.
+	wall := wallClock(from.In(s.loc))
+	if s.hourly {
+		// the wall clock reads earlier times again once it is set back.
+		_, offset := from.In(s.loc).Zone()
+		if _, later := from.Add(24 * time.Hour).In(s.loc).Zone(); later < offset {
+			wall = wall.Add(-time.Duration(offset-later) * time.Second)
+		}
+	}
+
+	// the first instants of the wall clock times increase with them, so the
+	// earliest instant after from is found once they are past it.
+	var earliest time.Time
+	for {
+		next, err := cron.Parsed(s.cron).Next(wall)
+		if err != nil {
+			return time.Time{}, err
+		}
+		instants := instantsOf(next, s.loc)
+		if !earliest.IsZero() && !instants[0].Before(earliest) {
+			return earliest.UTC(), nil
+		}
+		if !s.hourly {
+			instants = instants[:1]
+		}
+		for _, t := range instants {
+			if t.After(from) && (earliest.IsZero() || t.Before(earliest)) {
+				earliest = t
+			}
+		}
+		wall = next
+	}
+}
+
+// firesEveryHour returns whether c fires in every hour of the first day it
+// fires on.
+func firesEveryHour(c cron.Parsed) bool {
+	first, err := c.Next(time.Unix(0, 0).UTC())
+	if err != nil {
+		return false
+	}
+	day := first.Truncate(24 * time.Hour)
+	for h := 0; h < 24; h++ {
+		start := day.Add(time.Duration(h) * time.Hour)
+		next, err := c.Next(start.Add(-time.Second))
+		if err != nil || !next.Before(start.Add(time.Hour)) {
+			return false
+		}
+	}
+	return true
+}
+
+// CatchUpFrom returns the time to schedule from instead of lastScheduled for
+// the missed times of the schedule, the ones after lastScheduled already due
+// before now once delayed by offset, to be executed as policy, one of the
+// options.CatchUp policies, says. CatchUpAll keeps at most maxMissed of the
+// latest missed times if maxMissed is positive, all of them otherwise.
+func (s Schedule) CatchUpFrom(lastScheduled, now time.Time, offset time.Duration, policy string, maxMissed int64) (time.Time, error) {
+	var keep int64
+	switch policy {
+	case options.CatchUpSkip:
+		keep = 0
+	case options.CatchUpLatest:
+		keep = 1
+	default:
+		if maxMissed <= 0 {
+			return lastScheduled, nil
+		}
+		keep = maxMissed
+	}
+
+	// the latest keep+1 missed times, the oldest of them is the time to
+	// schedule from if more than keep times were missed.
+	var (
+		latest []time.Time
+		missed int64
+	)
+	for t := lastScheduled; ; missed++ {
+		next, err := s.Next(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !next.Add(offset).Before(now) {
+			break
+		}
+		if int64(len(latest)) <= keep {
+			latest = append(latest, next)
+		} else {
+			latest[missed%(keep+1)] = next
+		}
+		t = next
+	}
+	if missed <= keep {
+		return lastScheduled, nil
+	}
+	return latest[(missed-keep-1)%(keep+1)], nil
+}
+
+// wallClock returns the wall clock time of t as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// instantsOf returns the instants the wall clock of loc reads wall, a UTC
+// time, in order: two if wall is repeated by a daylight saving time
+// transition, or the end of the transition if it skips wall.
+func instantsOf(wall time.Time, loc *time.Location) []time.Time {
+	// the instants are less than a day away from wall, so the offsets of loc a
+	// day before and after wall are the ones around any transition.
+	var instants []time.Time
+	minOffset, maxOffset := math.MaxInt32, math.MinInt32
+	for _, probe := range []time.Time{wall.Add(-24 * time.Hour), wall, wall.Add(24 * time.Hour)} {
+		_, offset := probe.In(loc).Zone()
+		if offset < minOffset {
+			minOffset = offset
+		}
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+
+		t := wall.Add(-time.Duration(offset) * time.Second)
+		if !wallClock(t.In(loc)).Equal(wall) {
+			continue
+		}
+		i := sort.Search(len(instants), func(i int) bool { return !instants[i].Before(t) })
+		if i == len(instants) || !instants[i].Equal(t) {
+			instants = append(instants[:i], append([]time.Time{t}, instants[i:]...)...)
+		}
+	}
+	if len(instants) > 0 {
+		return instants
+	}
+
+	// wall is in a gap, search the first instant the wall clock is past it.
+	lo := wall.Add(-time.Duration(maxOffset) * time.Second).Unix()
+	hi := wall.Add(-time.Duration(minOffset) * time.Second).Unix()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if wallClock(time.Unix(mid, 0).In(loc)).After(wall) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return []time.Time{time.Unix(lo, 0)}
 }
 
 // ValidSchedule returns an error if the cron string is invalid.
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/influxdata/cron"
+	"github.com/influxdata/influxdb/v2/task/options"
 
 	"github.com/benbjohnson/clock"
 )
@@ -666,3 +667,201 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestNewScheduleInLocation_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(s string) time.Time {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		name     string
+		unparsed string
+		loc      *time.Location
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "utc",
+			unparsed: "0 2 * * *",
+			from:     utc("2020-03-27T12:00:00Z"),
+			want:     []time.Time{utc("2020-03-28T02:00:00Z"), utc("2020-03-29T02:00:00Z")},
+		},
+		{
+			name:     "standard and daylight saving time",
+			unparsed: "0 9 * * *",
+			loc:      newYork,
+			from:     utc("2020-03-07T00:00:00Z"),
+			want:     []time.Time{utc("2020-03-07T14:00:00Z"), utc("2020-03-08T13:00:00Z"), utc("2020-03-09T13:00:00Z")},
+		},
+		{
+			name:     "skipped wall clock time runs at the end of the gap",
+			unparsed: "30 2 * * *",
+			loc:      berlin,
+			from:     utc("2020-03-27T12:00:00Z"),
+			want:     []time.Time{utc("2020-03-28T01:30:00Z"), utc("2020-03-29T01:00:00Z"), utc("2020-03-30T00:30:00Z")},
+		},
+		{
+			name:     "repeated wall clock time runs once",
+			unparsed: "30 2 * * *",
+			loc:      berlin,
+			from:     utc("2020-10-24T12:00:00Z"),
+			want:     []time.Time{utc("2020-10-25T00:30:00Z"), utc("2020-10-26T01:30:00Z")},
+		},
+		{
+			name:     "from the repeated wall clock time",
+			unparsed: "30 2 * * *",
+			loc:      berlin,
+			from:     utc("2020-10-25T01:10:00Z"),
+			want:     []time.Time{utc("2020-10-26T01:30:00Z")},
+		},
+		{
+			name:     "sub-hourly schedule runs through the repeated hour",
+			unparsed: "*/15 * * * *",
+			loc:      berlin,
+			from:     utc("2020-10-25T00:30:00Z"),
+			want: []time.Time{
+				utc("2020-10-25T00:45:00Z"), utc("2020-10-25T01:00:00Z"), utc("2020-10-25T01:15:00Z"),
+				utc("2020-10-25T01:30:00Z"), utc("2020-10-25T01:45:00Z"), utc("2020-10-25T02:00:00Z"),
+			},
+		},
+		{
+			name:     "sub-hourly schedule from the repeated hour",
+			unparsed: "*/15 * * * *",
+			loc:      berlin,
+			from:     utc("2020-10-25T01:10:00Z"),
+			want:     []time.Time{utc("2020-10-25T01:15:00Z"), utc("2020-10-25T01:30:00Z")},
+		},
+		{
+			name:     "hourly schedule runs in the repeated hour",
+			unparsed: "0 * * * *",
+			loc:      berlin,
+			from:     utc("2020-10-24T23:30:00Z"),
+			want:     []time.Time{utc("2020-10-25T00:00:00Z"), utc("2020-10-25T01:00:00Z"), utc("2020-10-25T02:00:00Z")},
+		},
+		{
+			name:     "sub-hourly schedule runs once for the skipped hour",
+			unparsed: "*/15 * * * *",
+			loc:      berlin,
+			from:     utc("2020-03-29T00:30:00Z"),
+			want:     []time.Time{utc("2020-03-29T00:45:00Z"), utc("2020-03-29T01:00:00Z"), utc("2020-03-29T01:15:00Z")},
+		},
+		{
+			name:     "every ignores the location",
+			unparsed: "@every 1h",
+			loc:      berlin,
+			from:     utc("2020-03-29T00:00:00Z"),
+			want:     []time.Time{utc("2020-03-29T01:00:00Z"), utc("2020-03-29T02:00:00Z")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch, _, err := NewScheduleInLocation(tt.unparsed, tt.loc, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := tt.from
+			for _, want := range tt.want {
+				got, err := sch.Next(from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(want) || got.Location() != time.UTC {
+					t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+}
+
+func TestSchedule_CatchUpFrom(t *testing.T) {
+	sch, _, err := NewSchedule("@every 1h", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the runs of 01:00 to 05:00 were missed, the one of 06:00 is due now.
+	lastScheduled := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2020, 6, 1, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		policy    string
+		maxMissed int64
+		offset    time.Duration
+		want      time.Time
+	}{
+		{
+			name: "default catches up all",
+			want: lastScheduled,
+		},
+		{
+			name:   "all",
+			policy: options.CatchUpAll,
+			want:   lastScheduled,
+		},
+		{
+			name:      "all capped",
+			policy:    options.CatchUpAll,
+			maxMissed: 2,
+			want:      time.Date(2020, 6, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "all under the cap",
+			policy:    options.CatchUpAll,
+			maxMissed: 10,
+			want:      lastScheduled,
+		},
+		{
+			name:   "latest",
+			policy: options.CatchUpLatest,
+			want:   time.Date(2020, 6, 1, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "skip",
+			policy: options.CatchUpSkip,
+			want:   time.Date(2020, 6, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "skip with offset",
+			policy: options.CatchUpSkip,
+			offset: 90 * time.Minute,
+			want:   time.Date(2020, 6, 1, 4, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sch.CatchUpFrom(lastScheduled, now, tt.offset, tt.policy, tt.maxMissed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("CatchUpFrom() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nothing missed", func(t *testing.T) {
+		got, err := sch.CatchUpFrom(now, now, 0, options.CatchUpSkip, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(now) {
+			t.Fatalf("CatchUpFrom() = %s, want %s", got, now)
+		}
+	})
+}
@@ -28,4 +28,14 @@ type TaskControlService interface {
 
 	// AddRunLog adds a log line to the run.
 	AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+
+	// AddRunLogEntry adds a structured log entry to the run.
+	AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, entry influxdb.Log) error
+
+	// SetRunStats sets the statistics of the query of the run.
+	SetRunStats(ctx context.Context, taskID, runID influxdb.ID, stats *influxdb.RunStats) error
+
+	// CreateRetryRun queues a manual run retrying the failed run, with the same scheduled for time,
+	// due to be started at runAt.
+	CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error)
 }
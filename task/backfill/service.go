@@ -0,0 +1,394 @@
+// Package backfill runs tasks over past time ranges. Backfills are persisted
+// in the kv store and executed one at a time by a Worker, which forces a run
+// of the task for every time it is scheduled for in the range and reports
+// their progress.
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/snowflake"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+)
+
+var backfillBucket = []byte("taskbackfillsv1")
+
+const (
+	// DefaultConcurrency is the number of runs executed at a time when a
+	// backfill does not specify it.
+	DefaultConcurrency = 1
+	// MaxConcurrency is the maximum number of runs a backfill may execute at a time.
+	MaxConcurrency = 10
+	// MaxRuns is the maximum number of runs of a single backfill.
+	MaxRuns = 100000
+)
+
+var (
+	// ErrBackfillNotFound is used when the backfill cannot be found.
+	ErrBackfillNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "backfill not found",
+	}
+
+	// ErrBackfillFinished is used when canceling a backfill that already finished.
+	ErrBackfillFinished = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "backfill has already finished",
+	}
+)
+
+var _ influxdb.BackfillService = (*Service)(nil)
+
+// Service persists backfills in a kv store.
+type Service struct {
+	store       kv.Store
+	taskService influxdb.TaskService
+	IDGen       influxdb.IDGenerator
+	Now         func() time.Time
+
+	// queued is signaled when a backfill is created, to wake up the worker.
+	queued chan struct{}
+}
+
+// NewService constructs a backfill service backed by st, which looks up the
+// tasks to backfill in ts.
+func NewService(st kv.Store, ts influxdb.TaskService) *Service {
+	return &Service{
+		store:       st,
+		taskService: ts,
+		IDGen:       snowflake.NewDefaultIDGenerator(),
+		Now:         func() time.Time { return time.Now().UTC() },
+		queued:      make(chan struct{}, 1),
+	}
+}
+
+// CreateBackfill queues b, setting its ID, organization, status and creation
+// time. Stop is moved back to the latest time the task could have run for by
+// now, and the number of runs in the range is stored as the total progress.
+func (s *Service) CreateBackfill(ctx context.Context, b *influxdb.Backfill) error {
+	t, err := s.taskService.FindTaskByID(influxdb.FindTaskWithoutAuth(ctx), b.TaskID)
+	if err != nil {
+		return err
+	}
+
+	now := s.Now()
+	if latest := now.Add(-t.Offset); b.Stop.After(latest) {
+		b.Stop = latest
+	}
+	b.Start, b.Stop = b.Start.UTC(), b.Stop.UTC()
+	if !b.Start.Before(b.Stop) {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "backfill start must be before stop and in the past",
+		}
+	}
+
+	if b.Concurrency == 0 {
+		b.Concurrency = DefaultConcurrency
+	}
+	if b.Concurrency < 0 || b.Concurrency > MaxConcurrency {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("backfill concurrency must be between 1 and %d", MaxConcurrency),
+		}
+	}
+
+	times, err := scheduledTimes(t, b.Start, b.Stop, nil)
+	if err != nil {
+		return err
+	}
+	if len(times) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task is not scheduled for any time in the backfill range",
+		}
+	}
+
+	b.ID = s.IDGen.ID()
+	b.OrgID = t.OrganizationID
+	b.Status = influxdb.BackfillQueued
+	b.Progress = influxdb.BackfillProgress{Total: int64(len(times))}
+	b.Error = ""
+	b.CreatedAt = now
+	b.StartedAt, b.FinishedAt = nil, nil
+
+	if err := s.store.Update(ctx, func(tx kv.Tx) error {
+		return putBackfill(tx, b)
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// FindBackfillByID returns a single backfill by ID.
+func (s *Service) FindBackfillByID(ctx context.Context, id influxdb.ID) (*influxdb.Backfill, error) {
+	var b *influxdb.Backfill
+	err := s.store.View(ctx, func(tx kv.Tx) (err error) {
+		b, err = findBackfillByID(tx, id)
+		return err
+	})
+	return b, err
+}
+
+// FindBackfills returns the backfills matching filter, oldest first.
+func (s *Service) FindBackfills(ctx context.Context, filter influxdb.BackfillFilter) ([]*influxdb.Backfill, error) {
+	backfills := []*influxdb.Backfill{}
+	err := s.store.View(ctx, func(tx kv.Tx) error {
+		return walkBackfills(ctx, tx, func(b *influxdb.Backfill) error {
+			if filterBackfill(b, filter) {
+				backfills = append(backfills, b)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backfills, nil
+}
+
+// CancelBackfill cancels a queued backfill right away. A running backfill is
+// marked as canceling and canceled by the worker running it.
+func (s *Service) CancelBackfill(ctx context.Context, id influxdb.ID) error {
+	_, err := s.update(ctx, id, func(b *influxdb.Backfill) error {
+		switch b.Status {
+		case influxdb.BackfillQueued:
+			now := s.Now()
+			b.Status = influxdb.BackfillCanceled
+			b.FinishedAt = &now
+		case influxdb.BackfillRunning:
+			b.Status = influxdb.BackfillCanceling
+		case influxdb.BackfillCanceling:
+		default:
+			return ErrBackfillFinished
+		}
+		return nil
+	})
+	return err
+}
+
+// update applies fn to the backfill id and stores the result.
+func (s *Service) update(ctx context.Context, id influxdb.ID, fn func(b *influxdb.Backfill) error) (*influxdb.Backfill, error) {
+	var b *influxdb.Backfill
+	err := s.store.Update(ctx, func(tx kv.Tx) (err error) {
+		if b, err = findBackfillByID(tx, id); err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+		return putBackfill(tx, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// start marks the oldest queued backfill as running and returns it, or nil
+// if no backfill is queued.
+func (s *Service) start(ctx context.Context) (*influxdb.Backfill, error) {
+	var next *influxdb.Backfill
+	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		err := walkBackfills(ctx, tx, func(b *influxdb.Backfill) error {
+			if next == nil && b.Status == influxdb.BackfillQueued {
+				next = b
+			}
+			return nil
+		})
+		if err != nil || next == nil {
+			return err
+		}
+
+		now := s.Now()
+		next.Status = influxdb.BackfillRunning
+		if next.StartedAt == nil {
+			next.StartedAt = &now
+		}
+		return putBackfill(tx, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// requeue queues the backfills left running by a previous process again, to
+// be resumed after their completed runs, and cancels the ones being canceled.
+func (s *Service) requeue(ctx context.Context) error {
+	return s.store.Update(ctx, func(tx kv.Tx) error {
+		var backfills []*influxdb.Backfill
+		err := walkBackfills(ctx, tx, func(b *influxdb.Backfill) error {
+			if b.Status == influxdb.BackfillRunning || b.Status == influxdb.BackfillCanceling {
+				backfills = append(backfills, b)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, b := range backfills {
+			if b.Status == influxdb.BackfillCanceling {
+				now := s.Now()
+				b.Status = influxdb.BackfillCanceled
+				b.FinishedAt = &now
+			} else {
+				b.Status = influxdb.BackfillQueued
+			}
+			if err := putBackfill(tx, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// scheduledTimes returns the times in [start, stop] t is scheduled for
+// according to its every or cron option, skipping the ones not after after
+// if it is not nil.
+func scheduledTimes(t *influxdb.Task, start, stop time.Time, after *time.Time) ([]time.Time, error) {
+	loc, err := t.Location()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task has an invalid timezone",
+			Err:  err,
+		}
+	}
+
+	sch, next, err := scheduler.NewScheduleInLocation(t.EffectiveCron(), loc, start.Add(-time.Second))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task has an invalid schedule",
+			Err:  err,
+		}
+	}
+
+	var times []time.Time
+	for {
+		if next, err = sch.Next(next); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "failed to compute the next scheduled time of the task",
+				Err:  err,
+			}
+		}
+		if next.After(stop) {
+			return times, nil
+		}
+		if next.Before(start) || (after != nil && !next.After(*after)) {
+			continue
+		}
+		if len(times) == MaxRuns {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("backfill would run the task more than %d times", MaxRuns),
+			}
+		}
+		times = append(times, next)
+	}
+}
+
+func filterBackfill(b *influxdb.Backfill, filter influxdb.BackfillFilter) bool {
+	if filter.TaskID != nil && b.TaskID != *filter.TaskID {
+		return false
+	}
+	if filter.OrgID != nil && b.OrgID != *filter.OrgID {
+		return false
+	}
+	if filter.Status != nil && b.Status != *filter.Status {
+		return false
+	}
+	return true
+}
+
+func findBackfillByID(tx kv.Tx, id influxdb.ID) (*influxdb.Backfill, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	bkt, err := tx.Bucket(backfillBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bkt.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, ErrBackfillNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeBackfill(v)
+}
+
+func walkBackfills(ctx context.Context, tx kv.Tx, fn func(b *influxdb.Backfill) error) error {
+	bkt, err := tx.Bucket(backfillBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := bkt.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+
+	return kv.WalkCursor(ctx, cur, func(_, v []byte) error {
+		b, err := decodeBackfill(v)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+func putBackfill(tx kv.Tx, b *influxdb.Backfill) error {
+	encodedID, err := b.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(b)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	bkt, err := tx.Bucket(backfillBucket)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(encodedID, v)
+}
+
+func decodeBackfill(v []byte) (*influxdb.Backfill, error) {
+	b := new(influxdb.Backfill)
+	if err := json.Unmarshal(v, b); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "failed to decode backfill",
+			Err:  err,
+		}
+	}
+	return b, nil
+}
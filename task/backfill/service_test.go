@@ -0,0 +1,262 @@
+package backfill_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backfill"
+	"go.uber.org/zap/zaptest"
+)
+
+var (
+	orgID     = influxdb.ID(0x1000)
+	taskID    = influxdb.ID(0x2000)
+	otherID   = influxdb.ID(0x3000)
+	createdAt = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// newService returns a service backfilling the tasks of ts, which returns
+// task for any ID if ts is nil.
+func newService(t *testing.T, ts *mock.TaskService, task *influxdb.Task) *backfill.Service {
+	t.Helper()
+
+	store := inmem.NewKVStore()
+	if err := all.Up(context.Background(), zaptest.NewLogger(t), store); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts == nil {
+		ts = mock.NewTaskService()
+	}
+	ts.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		tt := *task
+		tt.ID = id
+		return &tt, nil
+	}
+
+	s := backfill.NewService(store, ts)
+	s.IDGen = &mock.MockIDGenerator{Count: 1}
+	s.Now = func() time.Time { return createdAt }
+	return s
+}
+
+func hourly() *influxdb.Task {
+	return &influxdb.Task{OrganizationID: orgID, Every: "1h"}
+}
+
+func TestService_CreateBackfill(t *testing.T) {
+	ctx := context.Background()
+	start := createdAt.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		task     *influxdb.Task
+		backfill influxdb.Backfill
+		exp      *influxdb.Backfill
+		code     string
+	}{
+		{
+			name:     "every",
+			task:     hourly(),
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start.Add(-30 * time.Minute), Stop: start.Add(3 * time.Hour)},
+			exp: &influxdb.Backfill{
+				ID:          1,
+				TaskID:      taskID,
+				OrgID:       orgID,
+				Start:       start.Add(-30 * time.Minute),
+				Stop:        start.Add(3 * time.Hour),
+				Concurrency: 1,
+				Status:      influxdb.BackfillQueued,
+				Progress:    influxdb.BackfillProgress{Total: 4},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name:     "cron",
+			task:     &influxdb.Task{OrganizationID: orgID, Cron: "0 */6 * * *"},
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt, Concurrency: 3},
+			exp: &influxdb.Backfill{
+				ID:          1,
+				TaskID:      taskID,
+				OrgID:       orgID,
+				Start:       start,
+				Stop:        createdAt,
+				Concurrency: 3,
+				Status:      influxdb.BackfillQueued,
+				Progress:    influxdb.BackfillProgress{Total: 5},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			// 02:00 in New York is 06:00 UTC in summer.
+			name:     "cron in timezone",
+			task:     &influxdb.Task{OrganizationID: orgID, Cron: "0 2 * * *", Timezone: "America/New_York"},
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start.Add(5 * time.Hour), Stop: start.Add(7 * time.Hour)},
+			exp: &influxdb.Backfill{
+				ID:          1,
+				TaskID:      taskID,
+				OrgID:       orgID,
+				Start:       start.Add(5 * time.Hour),
+				Stop:        start.Add(7 * time.Hour),
+				Concurrency: 1,
+				Status:      influxdb.BackfillQueued,
+				Progress:    influxdb.BackfillProgress{Total: 1},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name:     "stop after offset",
+			task:     &influxdb.Task{OrganizationID: orgID, Every: "1h", Offset: 90 * time.Minute},
+			backfill: influxdb.Backfill{TaskID: taskID, Start: createdAt.Add(-4 * time.Hour), Stop: createdAt.Add(time.Hour)},
+			exp: &influxdb.Backfill{
+				ID:          1,
+				TaskID:      taskID,
+				OrgID:       orgID,
+				Start:       createdAt.Add(-4 * time.Hour),
+				Stop:        createdAt.Add(-90 * time.Minute),
+				Concurrency: 1,
+				Status:      influxdb.BackfillQueued,
+				Progress:    influxdb.BackfillProgress{Total: 3},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name:     "start after stop",
+			task:     hourly(),
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start, Stop: start.Add(-time.Hour)},
+			code:     influxdb.EInvalid,
+		},
+		{
+			name:     "start in the future",
+			task:     hourly(),
+			backfill: influxdb.Backfill{TaskID: taskID, Start: createdAt.Add(time.Hour), Stop: createdAt.Add(2 * time.Hour)},
+			code:     influxdb.EInvalid,
+		},
+		{
+			name:     "no scheduled time",
+			task:     hourly(),
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start.Add(time.Minute), Stop: start.Add(59 * time.Minute)},
+			code:     influxdb.EInvalid,
+		},
+		{
+			name:     "concurrency",
+			task:     hourly(),
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt, Concurrency: backfill.MaxConcurrency + 1},
+			code:     influxdb.EInvalid,
+		},
+		{
+			name:     "too many runs",
+			task:     &influxdb.Task{OrganizationID: orgID, Every: "1s"},
+			backfill: influxdb.Backfill{TaskID: taskID, Start: createdAt.Add(-48 * time.Hour), Stop: createdAt},
+			code:     influxdb.EInvalid,
+		},
+		{
+			name:     "no schedule",
+			task:     &influxdb.Task{OrganizationID: orgID},
+			backfill: influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt},
+			code:     influxdb.EInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newService(t, nil, tt.task)
+
+			b := tt.backfill
+			err := s.CreateBackfill(ctx, &b)
+			if tt.code != "" {
+				if code := influxdb.ErrorCode(err); code != tt.code {
+					t.Fatalf("expected error code %q, got %v", tt.code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.FindBackfillByID(ctx, b.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Fatalf("unexpected backfill -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_FindBackfills(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t, nil, hourly())
+
+	for _, id := range []influxdb.ID{taskID, otherID, taskID} {
+		b := &influxdb.Backfill{TaskID: id, Start: createdAt.Add(-time.Hour), Stop: createdAt}
+		if err := s.CreateBackfill(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.CancelBackfill(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	queued, canceled := influxdb.BackfillQueued, influxdb.BackfillCanceled
+	tests := []struct {
+		name   string
+		filter influxdb.BackfillFilter
+		ids    []influxdb.ID
+	}{
+		{name: "all", ids: []influxdb.ID{1, 2, 3}},
+		{name: "org", filter: influxdb.BackfillFilter{OrgID: &orgID}, ids: []influxdb.ID{1, 2, 3}},
+		{name: "other org", filter: influxdb.BackfillFilter{OrgID: &otherID}, ids: []influxdb.ID{}},
+		{name: "task", filter: influxdb.BackfillFilter{TaskID: &taskID}, ids: []influxdb.ID{1, 3}},
+		{name: "queued", filter: influxdb.BackfillFilter{TaskID: &taskID, Status: &queued}, ids: []influxdb.ID{1}},
+		{name: "canceled", filter: influxdb.BackfillFilter{Status: &canceled}, ids: []influxdb.ID{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backfills, err := s.FindBackfills(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]influxdb.ID, 0, len(backfills))
+			for _, b := range backfills {
+				ids = append(ids, b.ID)
+			}
+			if diff := cmp.Diff(tt.ids, ids); diff != "" {
+				t.Fatalf("unexpected backfills -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_CancelBackfill(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t, nil, hourly())
+
+	if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: createdAt.Add(-time.Hour), Stop: createdAt}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CancelBackfill(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.FindBackfillByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Status != influxdb.BackfillCanceled || b.FinishedAt == nil {
+		t.Fatalf("expected canceled backfill with finish time, got %+v", b)
+	}
+
+	if err := s.CancelBackfill(ctx, 1); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict canceling a finished backfill, got %v", err)
+	}
+	if err := s.CancelBackfill(ctx, 42); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected not found canceling a missing backfill, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// DefaultProgressInterval is the default interval at which the worker stores the
+// progress of the running backfill and checks whether it was canceled.
+const DefaultProgressInterval = time.Second
+
+// Worker executes the queued backfills of a Service one at a time.
+type Worker struct {
+	log         *zap.Logger
+	backfills   *Service
+	taskService influxdb.TaskService
+
+	ProgressInterval time.Duration
+}
+
+// NewWorker constructs a worker running the backfills of s. The runs are
+// forced with ts, whose ForceRun must return once the run is done, with the
+// error the run failed with if any.
+func NewWorker(log *zap.Logger, s *Service, ts influxdb.TaskService) *Worker {
+	return &Worker{
+		log:              log,
+		backfills:        s,
+		taskService:      ts,
+		ProgressInterval: DefaultProgressInterval,
+	}
+}
+
+// Run executes queued backfills until ctx is done. Backfills left running by
+// a previous process are queued again first, and the backfill running when
+// ctx is done is queued again to be resumed by the next run.
+func (w *Worker) Run(ctx context.Context) error {
+	if err := w.backfills.requeue(ctx); err != nil {
+		return err
+	}
+
+	for {
+		b, err := w.backfills.start(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.log.Error("Failed to start backfill", zap.Error(err))
+		}
+
+		if b == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-w.backfills.queued:
+			}
+			continue
+		}
+
+		w.run(ctx, b)
+	}
+}
+
+// run executes the runs of b not completed yet, storing its progress as it
+// goes and its outcome when done.
+func (w *Worker) run(ctx context.Context, b *influxdb.Backfill) {
+	log := w.log.With(zap.String("backfill_id", b.ID.String()), zap.String("task_id", b.TaskID.String()))
+	log.Info("Backfill started")
+
+	ctx = influxdb.FindTaskWithoutAuth(ctx)
+	progress := b.Progress
+
+	t, err := w.taskService.FindTaskByID(ctx, b.TaskID)
+	if err != nil {
+		w.finish(log, b.ID, progress, err, false, ctx.Err() != nil)
+		return
+	}
+	times, err := scheduledTimes(t, b.Start, b.Stop, progress.Completed)
+	if err != nil {
+		w.finish(log, b.ID, progress, err, false, false)
+		return
+	}
+	// The schedule of the task may have changed since the backfill was created.
+	progress.Total = progress.Succeeded + progress.Failed + int64(len(times))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i   int
+		err error
+	}
+	next := make(chan int)
+	results := make(chan result)
+	go func() {
+		defer close(next)
+		for i := range times {
+			select {
+			case next <- i:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for n := 0; n < b.Concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				_, err := w.taskService.ForceRun(runCtx, b.TaskID, times[i].Unix(), nil)
+				results <- result{i: i, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	ticker := time.NewTicker(w.ProgressInterval)
+	defer ticker.Stop()
+
+	// Only the runs up to the first one not done yet are counted, so the
+	// progress stored is the one the backfill resumes from.
+	done := make([]bool, len(times))
+	failed := make([]bool, len(times))
+	completed := 0
+	var canceled bool
+	for {
+		select {
+		case r, ok := <-results:
+			if !ok {
+				var runErr error
+				if completed == len(times) && progress.Failed > 0 {
+					runErr = fmt.Errorf("%d of %d runs failed", progress.Failed, progress.Total)
+				}
+				unfinished := completed < len(times)
+				w.finish(log, b.ID, progress, runErr, canceled && unfinished, ctx.Err() != nil && unfinished)
+				return
+			}
+			if r.err != nil && runCtx.Err() != nil {
+				// The run was interrupted and is run again when the backfill resumes.
+				continue
+			}
+			if r.err != nil {
+				log.Warn("Backfill run failed", zap.Time("scheduled_for", times[r.i]), zap.Error(r.err))
+			}
+			done[r.i], failed[r.i] = true, r.err != nil
+			for ; completed < len(times) && done[completed]; completed++ {
+				if failed[completed] {
+					progress.Failed++
+				} else {
+					progress.Succeeded++
+				}
+				c := times[completed]
+				progress.Completed = &c
+			}
+		case <-ticker.C:
+			current, err := w.backfills.update(ctx, b.ID, func(b *influxdb.Backfill) error {
+				b.Progress = progress
+				return nil
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warn("Failed to update backfill progress", zap.Error(err))
+				}
+				continue
+			}
+			if current.Status == influxdb.BackfillCanceling && !canceled {
+				canceled = true
+				cancel()
+			}
+		}
+	}
+}
+
+// finish stores the outcome of a backfill. The backfill is queued again if
+// the worker stopped while it was running.
+func (w *Worker) finish(log *zap.Logger, id influxdb.ID, progress influxdb.BackfillProgress, runErr error, canceled, stopped bool) {
+	// The context of the worker may be done, the outcome must be stored anyway.
+	_, err := w.backfills.update(context.Background(), id, func(b *influxdb.Backfill) error {
+		now := w.backfills.Now()
+		b.Progress = progress
+		b.FinishedAt = &now
+
+		switch {
+		case canceled:
+			b.Status = influxdb.BackfillCanceled
+		case stopped:
+			b.Status = influxdb.BackfillQueued
+			b.FinishedAt = nil
+		case runErr != nil:
+			b.Status = influxdb.BackfillFailed
+			b.Error = runErr.Error()
+		default:
+			b.Status = influxdb.BackfillSuccess
+		}
+		log.Info("Backfill finished",
+			zap.String("status", string(b.Status)),
+			zap.Int64("total", progress.Total),
+			zap.Int64("succeeded", progress.Succeeded),
+			zap.Int64("failed", progress.Failed),
+			zap.NamedError("backfill_error", runErr))
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to store backfill outcome", zap.Error(err))
+	}
+}
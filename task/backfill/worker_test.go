@@ -0,0 +1,242 @@
+package backfill_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/backfill"
+	"go.uber.org/zap/zaptest"
+)
+
+// forcedRuns records the runs forced by a worker.
+type forcedRuns struct {
+	mu      sync.Mutex
+	times   []time.Time
+	running int
+	maxRun  int
+}
+
+// taskService returns a task service forcing runs with fn, recording them in r.
+func (r *forcedRuns) taskService(fn func(ctx context.Context, scheduledFor time.Time) error) *mock.TaskService {
+	ts := mock.NewTaskService()
+	ts.ForceRunFn = func(ctx context.Context, id influxdb.ID, scheduledFor int64, params map[string]interface{}) (*influxdb.Run, error) {
+		t := time.Unix(scheduledFor, 0).UTC()
+		r.mu.Lock()
+		r.times = append(r.times, t)
+		if r.running++; r.running > r.maxRun {
+			r.maxRun = r.running
+		}
+		r.mu.Unlock()
+
+		err := fn(ctx, t)
+
+		r.mu.Lock()
+		r.running--
+		r.mu.Unlock()
+		return &influxdb.Run{TaskID: id, ScheduledFor: t}, err
+	}
+	return ts
+}
+
+func (r *forcedRuns) sorted() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	times := append([]time.Time(nil), r.times...)
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// runWorker runs a worker of s forcing runs with ts until the returned function is called.
+func runWorker(t *testing.T, s *backfill.Service, ts influxdb.TaskService) func() {
+	t.Helper()
+
+	w := backfill.NewWorker(zaptest.NewLogger(t), s, ts)
+	w.ProgressInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// waitForStatus waits for the backfill id to reach status and returns it.
+func waitForStatus(t *testing.T, s *backfill.Service, id influxdb.ID, status influxdb.BackfillStatus) *influxdb.Backfill {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		b, err := s.FindBackfillByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Status == status {
+			return b
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backfill %s is %s, expected %s", id, b.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func hours(from time.Time, n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = from.Add(time.Duration(i) * time.Hour)
+	}
+	return times
+}
+
+func TestWorker_Run(t *testing.T) {
+	ctx := context.Background()
+	start := createdAt.Add(-10 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		var runs forcedRuns
+		ts := runs.taskService(func(ctx context.Context, _ time.Time) error {
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		s := newService(t, ts, hourly())
+		stop := runWorker(t, s, ts)
+		defer stop()
+
+		if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt, Concurrency: 3}); err != nil {
+			t.Fatal(err)
+		}
+		b := waitForStatus(t, s, 1, influxdb.BackfillSuccess)
+		exp := influxdb.BackfillProgress{Total: 11, Succeeded: 11, Completed: &createdAt}
+		if diff := cmp.Diff(exp, b.Progress); diff != "" {
+			t.Fatalf("unexpected progress -want/+got:\n%s", diff)
+		}
+		if b.StartedAt == nil || b.FinishedAt == nil {
+			t.Fatalf("expected start and finish times, got %+v", b)
+		}
+		if diff := cmp.Diff(hours(start, 11), runs.sorted()); diff != "" {
+			t.Fatalf("unexpected forced runs -want/+got:\n%s", diff)
+		}
+		if runs.maxRun > 3 {
+			t.Fatalf("expected at most 3 concurrent runs, got %d", runs.maxRun)
+		}
+	})
+
+	t.Run("failed runs", func(t *testing.T) {
+		var runs forcedRuns
+		ts := runs.taskService(func(ctx context.Context, scheduledFor time.Time) error {
+			if scheduledFor.Hour()%2 == 0 {
+				return errors.New("query failed")
+			}
+			return nil
+		})
+		s := newService(t, ts, hourly())
+		stop := runWorker(t, s, ts)
+		defer stop()
+
+		if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: start, Stop: start.Add(3 * time.Hour), Concurrency: 2}); err != nil {
+			t.Fatal(err)
+		}
+		b := waitForStatus(t, s, 1, influxdb.BackfillFailed)
+		if b.Progress.Succeeded != 2 || b.Progress.Failed != 2 {
+			t.Fatalf("unexpected progress %+v", b.Progress)
+		}
+		if b.Error != "2 of 4 runs failed" {
+			t.Fatalf("unexpected backfill error %q", b.Error)
+		}
+	})
+
+	t.Run("cancel running backfill", func(t *testing.T) {
+		var runs forcedRuns
+		ts := runs.taskService(func(ctx context.Context, scheduledFor time.Time) error {
+			if scheduledFor.Equal(start) {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		s := newService(t, ts, hourly())
+		stop := runWorker(t, s, ts)
+		defer stop()
+
+		if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt}); err != nil {
+			t.Fatal(err)
+		}
+		waitForStatus(t, s, 1, influxdb.BackfillRunning)
+		if err := s.CancelBackfill(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		b := waitForStatus(t, s, 1, influxdb.BackfillCanceled)
+		exp := influxdb.BackfillProgress{Total: 11, Succeeded: 1, Completed: &start}
+		if diff := cmp.Diff(exp, b.Progress); diff != "" {
+			t.Fatalf("unexpected progress -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("stop and resume", func(t *testing.T) {
+		var runs forcedRuns
+		third := start.Add(2 * time.Hour)
+		ts := runs.taskService(func(ctx context.Context, scheduledFor time.Time) error {
+			if scheduledFor.Before(third) {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		s := newService(t, ts, hourly())
+		stop := runWorker(t, s, ts)
+
+		if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: start, Stop: start.Add(4 * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+		for len(runs.sorted()) < 3 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		stop()
+		b := waitForStatus(t, s, 1, influxdb.BackfillQueued)
+		if b.Progress.Succeeded != 2 || !b.Progress.Completed.Equal(start.Add(time.Hour)) {
+			t.Fatalf("expected the progress of the stopped backfill, got %+v", b.Progress)
+		}
+
+		var resumed forcedRuns
+		resumedTS := resumed.taskService(func(ctx context.Context, _ time.Time) error { return nil })
+		resumedTS.FindTaskByIDFn = ts.FindTaskByIDFn
+		stop = runWorker(t, s, resumedTS)
+		defer stop()
+
+		b = waitForStatus(t, s, 1, influxdb.BackfillSuccess)
+		if b.Progress.Total != 5 || b.Progress.Succeeded != 5 {
+			t.Fatalf("unexpected progress %+v", b.Progress)
+		}
+		if diff := cmp.Diff(hours(third, 3), resumed.sorted()); diff != "" {
+			t.Fatalf("unexpected resumed runs -want/+got:\n%s", diff)
+		}
+	})
+
+	t.Run("missing task", func(t *testing.T) {
+		s := newService(t, nil, hourly())
+		ts := mock.NewTaskService()
+		ts.FindTaskByIDFn = func(context.Context, influxdb.ID) (*influxdb.Task, error) {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "task not found"}
+		}
+		stop := runWorker(t, s, ts)
+		defer stop()
+
+		if err := s.CreateBackfill(ctx, &influxdb.Backfill{TaskID: taskID, Start: start, Stop: createdAt}); err != nil {
+			t.Fatal(err)
+		}
+		if b := waitForStatus(t, s, 1, influxdb.BackfillFailed); b.Error != "task not found" {
+			t.Fatalf("unexpected backfill error %q", b.Error)
+		}
+	})
+}
@@ -129,6 +129,29 @@ func (t *TaskControlService) ManualRuns(ctx context.Context, taskID influxdb.ID)
 	return []*influxdb.Run{}, nil
 }
 
+// CreateRetryRun adds a manual run retrying run.
+func (t *TaskControlService) CreateRetryRun(_ context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, r := range t.manualRuns {
+		if r.TaskID == run.TaskID && r.ScheduledFor.Equal(run.ScheduledFor) {
+			return nil, influxdb.ErrTaskRunAlreadyQueued
+		}
+	}
+	r := &influxdb.Run{
+		ID:           idgen.ID(),
+		TaskID:       run.TaskID,
+		Status:       influxdb.RunScheduled.String(),
+		ScheduledFor: run.ScheduledFor,
+		RunAt:        runAt,
+		RetryOf:      run.ID,
+		Retry:        run.Retry + 1,
+	}
+	t.manualRuns = append(t.manualRuns, r)
+	return r, nil
+}
+
 // UpdateRunState sets the run state at the respective time.
 func (d *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state influxdb.RunStatus) error {
 	d.mu.Lock()
@@ -141,7 +164,7 @@ func (d *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID i
 	switch state {
 	case influxdb.RunStarted:
 		run.StartedAt = when
-	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled:
+	case influxdb.RunSuccess, influxdb.RunFail, influxdb.RunCanceled, influxdb.RunRetried:
 		run.FinishedAt = when
 	case influxdb.RunScheduled:
 		// nothing
@@ -165,6 +188,33 @@ func (d *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influx
 	return nil
 }
 
+// AddRunLogEntry adds a structured log entry to the run.
+func (d *TaskControlService) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, entry influxdb.Log) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[taskID][runID]
+	if run == nil {
+		panic("cannot add a log to a non existent run")
+	}
+	entry.RunID = runID
+	run.Log = append(run.Log, entry)
+	return nil
+}
+
+// SetRunStats sets the statistics of the query of the run.
+func (d *TaskControlService) SetRunStats(ctx context.Context, taskID, runID influxdb.ID, stats *influxdb.RunStats) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[taskID][runID]
+	if run == nil {
+		panic("cannot set the stats of a non existent run")
+	}
+	run.Stats = stats
+	return nil
+}
+
 func (d *TaskControlService) CreatedFor(taskID influxdb.ID) []*influxdb.Run {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -20,6 +20,17 @@ import (
 const maxConcurrency = 100
 const maxRetry = 10
 
+// The policies of the catchUp option for the runs a task missed, e.g. while
+// influxd was down.
+const (
+	// CatchUpAll executes all the missed runs.
+	CatchUpAll = "all"
+	// CatchUpLatest executes only the latest missed run.
+	CatchUpLatest = "latest"
+	// CatchUpSkip executes none of the missed runs.
+	CatchUpSkip = "skip"
+)
+
 // Options are the task-related options that can be specified in a Flux script.
 type Options struct {
 	// Name is a non optional name designator for each task.
@@ -38,7 +49,21 @@ type Options struct {
 
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
+	// Retry is how many times a failed run is retried. Failed runs are not
+	// retried if it is nil.
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Timezone is the IANA time zone the Cron schedule is evaluated in, UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+
+	// CatchUp is the policy for the runs missed since the task was last
+	// scheduled. If empty, the runs missed while influxd was down are
+	// skipped at startup, and the other missed runs are executed.
+	CatchUp string `json:"catchUp,omitempty"`
+
+	// MaxMissedRuns caps how many of the latest missed runs CatchUpAll
+	// executes. All of them are executed if it is nil.
+	MaxMissedRuns *int64 `json:"maxMissedRuns,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -117,6 +142,9 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Timezone = ""
+	o.CatchUp = ""
+	o.MaxMissedRuns = nil
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -126,7 +154,10 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Timezone == "" &&
+		o.CatchUp == "" &&
+		o.MaxMissedRuns == nil
 }
 
 // All the task option names we accept.
@@ -137,6 +168,9 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optTimezone    = "timezone"
+	optCatchUp     = "catchUp"
+	optMaxMissed   = "maxMissedRuns"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -208,7 +242,7 @@ type FluxLanguageService interface {
 
 // FromScript extracts Options from a Flux script.
 func FromScript(lang FluxLanguageService, script string) (Options, error) {
-	opt := Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}
+	opt := Options{Concurrency: pointer.Int64(1)}
 
 	fluxAST, err := parse(lang, script)
 	if err != nil {
@@ -317,6 +351,27 @@ func FromScript(lang FluxLanguageService, script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if tzVal, ok := optObject.Get(optTimezone); ok {
+		if err := checkNature(tzVal.Type().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Timezone = tzVal.Str()
+	}
+
+	if catchUpVal, ok := optObject.Get(optCatchUp); ok {
+		if err := checkNature(catchUpVal.Type().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.CatchUp = catchUpVal.Str()
+	}
+
+	if maxMissedVal, ok := optObject.Get(optMaxMissed); ok {
+		if err := checkNature(maxMissedVal.Type().Nature(), semantic.Int); err != nil {
+			return opt, err
+		}
+		opt.MaxMissedRuns = pointer.Int64(maxMissedVal.Int())
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -378,6 +433,27 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	if o.Timezone != "" {
+		if !cronPresent {
+			errs = append(errs, "timezone option requires the cron option")
+		} else if _, err := time.LoadLocation(o.Timezone); err != nil {
+			errs = append(errs, "timezone invalid: "+err.Error())
+		}
+	}
+
+	switch o.CatchUp {
+	case "", CatchUpAll, CatchUpLatest, CatchUpSkip:
+	default:
+		errs = append(errs, fmt.Sprintf("catchUp must be one of %s, %s or %s", CatchUpAll, CatchUpLatest, CatchUpSkip))
+	}
+	if o.MaxMissedRuns != nil {
+		if *o.MaxMissedRuns < 1 {
+			errs = append(errs, "maxMissedRuns must be at least 1")
+		} else if o.CatchUp != "" && o.CatchUp != CatchUpAll {
+			errs = append(errs, "maxMissedRuns option requires the catchUp option to be "+CatchUpAll)
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -418,7 +494,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTimezone, optCatchUp, optMaxMissed:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -427,7 +503,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optTimezone, optCatchUp, optMaxMissed}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
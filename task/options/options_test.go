@@ -22,6 +22,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Cron != "" {
 		taskData = fmt.Sprintf("%s  cron: %q,\n", taskData, opt.Cron)
 	}
+	if opt.Timezone != "" {
+		taskData = fmt.Sprintf("%s  timezone: %q,\n", taskData, opt.Timezone)
+	}
 	if !opt.Every.IsZero() {
 		taskData = fmt.Sprintf("%s  every: %s,\n", taskData, opt.Every.String())
 	}
@@ -34,6 +37,12 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.CatchUp != "" {
+		taskData = fmt.Sprintf("%s  catchUp: %q,\n", taskData, opt.CatchUp)
+	}
+	if opt.MaxMissedRuns != nil {
+		taskData = fmt.Sprintf("%s  maxMissedRuns: %d,\n", taskData, *opt.MaxMissedRuns)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -69,8 +78,11 @@ func TestFromScript(t *testing.T) {
 				Concurrency: pointer.Int64(2),
 				Retry:       pointer.Int64(3),
 				Offset:      options.MustParseDuration("-1m")}},
-		{script: scriptGenerator(options.Options{Name: "name1", Every: *(options.MustParseDuration("5s"))}, ""), exp: options.Options{Name: "name1", Every: *(options.MustParseDuration("5s")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
-		{script: scriptGenerator(options.Options{Name: "name2", Cron: "* * * * *"}, ""), exp: options.Options{Name: "name2", Cron: "* * * * *", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name1", Every: *(options.MustParseDuration("5s"))}, ""), exp: options.Options{Name: "name1", Every: *(options.MustParseDuration("5s")), Concurrency: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name2", Cron: "* * * * *"}, ""), exp: options.Options{Name: "name2", Cron: "* * * * *", Concurrency: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name2b", Cron: "0 2 * * *", Timezone: "Europe/Berlin"}, ""), exp: options.Options{Name: "name2b", Cron: "0 2 * * *", Timezone: "Europe/Berlin", Concurrency: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name2c", Every: *(options.MustParseDuration("1h")), CatchUp: options.CatchUpAll, MaxMissedRuns: pointer.Int64(24)}, ""), exp: options.Options{Name: "name2c", Every: *(options.MustParseDuration("1h")), CatchUp: options.CatchUpAll, MaxMissedRuns: pointer.Int64(24), Concurrency: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name2d", Every: *(options.MustParseDuration("1h")), CatchUp: "none"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name3", Every: *(options.MustParseDuration("1h")), Cron: "* * * * *"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name4", Concurrency: pointer.Int64(1000), Every: *(options.MustParseDuration("1h"))}, ""), shouldErr: true},
 		{script: "option task = {\n  name: \"name5\",\n  concurrency: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
@@ -87,7 +99,7 @@ func TestFromScript(t *testing.T) {
 			from(bucket: "metrics")
 			|> range(start: now(), stop: 8w)
 		`,
-			exp: options.Options{Name: "name10", Every: *(options.MustParseDuration("1d")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Offset: options.MustParseDuration("1m")},
+			exp: options.Options{Name: "name10", Every: *(options.MustParseDuration("1d")), Concurrency: pointer.Int64(1), Offset: options.MustParseDuration("1m")},
 		},
 		{script: `option task = {
 			name: "name11",
@@ -98,10 +110,10 @@ func TestFromScript(t *testing.T) {
 			|> range(start: now(), stop: 8w)
 
 		`,
-			exp: options.Options{Name: "name11", Every: *(options.MustParseDuration("1m")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Offset: options.MustParseDuration("1d")},
+			exp: options.Options{Name: "name11", Every: *(options.MustParseDuration("1m")), Concurrency: pointer.Int64(1), Offset: options.MustParseDuration("1d")},
 		},
 		{script: "option task = {name:\"test_task_smoke_name\", every:30s} from(bucket:\"test_tasks_smoke_bucket_source\") |> range(start: -1h) |> map(fn: (r) => ({r with _time: r._time, _value:r._value, t : \"quality_rocks\"}))|> to(bucket:\"test_tasks_smoke_bucket_dest\", orgID:\"3e73e749495d37d5\")",
-			exp: options.Options{Name: "test_task_smoke_name", Every: *(options.MustParseDuration("30s")), Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}, shouldErr: false}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
+			exp: options.Options{Name: "test_task_smoke_name", Every: *(options.MustParseDuration("30s")), Concurrency: pointer.Int64(1)}, shouldErr: false}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
 
 	} {
 		o, err := options.FromScript(fluxlang.DefaultService, c.script)
@@ -147,7 +159,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "timezone", "every", "offset", "concurrency", "retry", "catchUp", "maxMissedRuns"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -156,7 +168,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 }
 
 func TestValidate(t *testing.T) {
-	good := options.Options{Name: "x", Cron: "* * * * *", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}
+	good := options.Options{Name: "x", Cron: "* * * * *", Concurrency: pointer.Int64(1)}
 	if err := good.Validate(); err != nil {
 		t.Fatal(err)
 	}
@@ -223,6 +235,39 @@ func TestValidate(t *testing.T) {
 		t.Error("expected error for retry too large")
 	}
 
+	*bad = good
+	bad.Timezone = "Mars/Olympus_Mons"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+
+	*bad = good
+	bad.Cron = ""
+	bad.Every = *options.MustParseDuration("1h")
+	bad.Timezone = "Europe/Berlin"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for timezone without cron")
+	}
+
+	*bad = good
+	bad.CatchUp = "some"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for unknown catchUp")
+	}
+
+	*bad = good
+	bad.MaxMissedRuns = pointer.Int64(0)
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for 0 maxMissedRuns")
+	}
+
+	*bad = good
+	bad.CatchUp = options.CatchUpSkip
+	bad.MaxMissedRuns = pointer.Int64(5)
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for maxMissedRuns when skipping missed runs")
+	}
+
 	notbad := new(options.Options)
 	*notbad = good
 	notbad.Cron = ""
@@ -231,6 +276,24 @@ func TestValidate(t *testing.T) {
 		t.Error("expected no error for days every")
 	}
 
+	*notbad = good
+	notbad.Timezone = "Europe/Berlin"
+	if err := notbad.Validate(); err != nil {
+		t.Errorf("expected no error for cron with timezone, got %v", err)
+	}
+
+	*notbad = good
+	notbad.MaxMissedRuns = pointer.Int64(5)
+	if err := notbad.Validate(); err != nil {
+		t.Errorf("expected no error for maxMissedRuns, got %v", err)
+	}
+
+	*notbad = good
+	notbad.CatchUp = options.CatchUpLatest
+	if err := notbad.Validate(); err != nil {
+		t.Errorf("expected no error for latest catchUp, got %v", err)
+	}
+
 }
 
 func TestEffectiveCronString(t *testing.T) {
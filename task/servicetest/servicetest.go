@@ -1003,7 +1003,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 		}
 
 		const scheduledFor = 77
-		r, err := sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor)
+		r, err := sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1015,7 +1015,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 		// TODO(lh): Once we have moved over to kv we can list runs and see the manual queue in the list
 
 		// Forcing the same run before it's executed should be rejected.
-		if _, err = sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor); err == nil {
+		if _, err = sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor, nil); err == nil {
 			t.Fatalf("subsequent force should have been rejected; failed to error: %s", task.ID)
 		}
 	})
@@ -1293,7 +1293,7 @@ func testManualRun(t *testing.T, s *System) {
 	}
 
 	scheduledFor := int64(77)
-	run, err := s.TaskService.ForceRun(authorizedCtx, tsk.ID, scheduledFor)
+	run, err := s.TaskService.ForceRun(authorizedCtx, tsk.ID, scheduledFor, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1467,6 +1467,24 @@ func testRunStorage(t *testing.T, sys *System) {
 		t.Fatalf("unexpected FinishedAt; want %s, got %s", exp, runs[2].FinishedAt)
 	}
 
+	// The runs are filtered by the time they are scheduled for.
+	for _, tt := range []struct {
+		filter influxdb.RunFilter
+		want   int
+	}{
+		{filter: influxdb.RunFilter{Task: task.ID, AfterTime: requestedAt.Add(-time.Minute).Format(time.RFC3339), BeforeTime: requestedAt.Add(time.Minute).Format(time.RFC3339)}, want: 3},
+		{filter: influxdb.RunFilter{Task: task.ID, BeforeTime: requestedAt.Add(-time.Minute).Format(time.RFC3339)}, want: 0},
+		{filter: influxdb.RunFilter{Task: task.ID, AfterTime: requestedAt.Add(time.Minute).Format(time.RFC3339)}, want: 0},
+	} {
+		runs, _, err := sys.TaskService.FindRuns(sys.Ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) != tt.want {
+			t.Fatalf("expected %d runs scheduled after %q and before %q, got %v", tt.want, tt.filter.AfterTime, tt.filter.BeforeTime, runs)
+		}
+	}
+
 	// Look for a run that doesn't exist.
 	_, err = sys.TaskService.FindRunByID(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64))
 	// TODO(lh): use kv.ErrRunNotFound in the future. Our error's are not exact
@@ -1556,6 +1574,10 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 		t.Fatalf("wrong scheduledFor on task: got %s, want %s", m.ScheduledFor, rc.ScheduledFor)
 	}
 
+	if m.RetryOf != rc.ID || m.Retry != 1 {
+		t.Fatalf("expected first retry of run %s, got retry %d of run %s", rc.ID, m.Retry, m.RetryOf)
+	}
+
 	exp := influxdb.RequestStillQueuedError{Start: rc.ScheduledFor.Unix(), End: rc.ScheduledFor.Unix()}
 
 	// Retrying a run which has been queued but not started, should be rejected.
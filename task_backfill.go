@@ -0,0 +1,81 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// BackfillStatus is the status of a task backfill.
+type BackfillStatus string
+
+// Statuses of a backfill. A backfill is queued until the worker picks it up
+// and ends in one of success, failed or canceled.
+const (
+	BackfillQueued    BackfillStatus = "queued"
+	BackfillRunning   BackfillStatus = "running"
+	BackfillCanceling BackfillStatus = "canceling"
+	BackfillSuccess   BackfillStatus = "success"
+	BackfillFailed    BackfillStatus = "failed"
+	BackfillCanceled  BackfillStatus = "canceled"
+)
+
+// Finished returns true if the backfill will not run anymore.
+func (s BackfillStatus) Finished() bool {
+	return s == BackfillSuccess || s == BackfillFailed || s == BackfillCanceled
+}
+
+// Backfill runs a task for every time it is scheduled for in [Start, Stop],
+// e.g. to have a newly created task cover data written before it existed.
+// Runs are forced through the manual run queue, at most Concurrency at a time.
+type Backfill struct {
+	ID          ID               `json:"id"`
+	TaskID      ID               `json:"taskID"`
+	OrgID       ID               `json:"orgID"`
+	Start       time.Time        `json:"start"`
+	Stop        time.Time        `json:"stop"`
+	Concurrency int              `json:"concurrency"`
+	Status      BackfillStatus   `json:"status"`
+	Progress    BackfillProgress `json:"progress"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	StartedAt   *time.Time       `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+}
+
+// BackfillProgress counts the runs of a backfill.
+type BackfillProgress struct {
+	// Total is the number of times the task is scheduled for in the range.
+	Total int64 `json:"total"`
+	// Succeeded is the number of runs that succeeded.
+	Succeeded int64 `json:"succeeded"`
+	// Failed is the number of runs that failed or could not be queued.
+	Failed int64 `json:"failed"`
+	// Completed is the latest scheduled time up to which every run is done.
+	// An interrupted backfill resumes after it.
+	Completed *time.Time `json:"completed,omitempty"`
+}
+
+// BackfillFilter represents a set of filters that restrict the returned backfills.
+type BackfillFilter struct {
+	TaskID *ID
+	OrgID  *ID
+	Status *BackfillStatus
+}
+
+// BackfillService manages task backfills.
+type BackfillService interface {
+	// CreateBackfill queues a backfill of the task of b, setting its ID,
+	// organization, status and creation time. Stop is moved back to the
+	// latest time the task could have already run for.
+	CreateBackfill(ctx context.Context, b *Backfill) error
+
+	// FindBackfillByID returns a single backfill by ID.
+	FindBackfillByID(ctx context.Context, id ID) (*Backfill, error)
+
+	// FindBackfills returns the backfills matching filter, oldest first.
+	FindBackfills(ctx context.Context, filter BackfillFilter) ([]*Backfill, error)
+
+	// CancelBackfill cancels a queued or running backfill. Runs already
+	// started by a running backfill are not canceled.
+	CancelBackfill(ctx context.Context, id ID) error
+}
@@ -0,0 +1,44 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// TaskDryRun executes a task for a time it is scheduled for without writing
+// data: the points its script writes with to() are returned in Tables
+// instead, e.g. to check what a task does before enabling it. Truncated is
+// true if the script wrote more points than Tables hold. A dry run creates no
+// run.
+type TaskDryRun struct {
+	TaskID       ID             `json:"taskID"`
+	OrgID        ID             `json:"orgID"`
+	TaskVersion  int            `json:"taskVersion,omitempty"`
+	ScheduledFor time.Time      `json:"scheduledFor"`
+	Tables       []*DryRunTable `json:"tables"`
+	Truncated    bool           `json:"truncated,omitempty"`
+	Stats        *RunStats      `json:"stats,omitempty"`
+}
+
+// DryRunTable is the points a dry run would have written to a series of a
+// bucket, one row by time.
+type DryRunTable struct {
+	BucketID    ID                `json:"bucketID"`
+	Measurement string            `json:"measurement"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Rows        []DryRunRow       `json:"rows"`
+}
+
+// DryRunRow is the fields a dry run would have written to a series at a time.
+type DryRunRow struct {
+	Time   time.Time              `json:"time"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// TaskDryRunService executes tasks without writing data.
+type TaskDryRunService interface {
+	// DryRunTask executes the task of d for d.ScheduledFor, setting the
+	// version of the task executed, the tables it would have written and
+	// the statistics of its query.
+	DryRunTask(ctx context.Context, d *TaskDryRun) error
+}
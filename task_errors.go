@@ -2,6 +2,8 @@ package influxdb
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 var (
@@ -41,6 +43,19 @@ var (
 		Msg:  "task not found",
 	}
 
+	// ErrTaskVersionNotFound is returned when searching for a version of a task that doesn't exist.
+	ErrTaskVersionNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "task version not found",
+	}
+
+	// ErrTaskDryRunNotificationRule is returned when dry running the task of a
+	// notification rule, whose query sends the notifications.
+	ErrTaskDryRunNotificationRule = &Error{
+		Code: EInvalid,
+		Msg:  "the task of a notification rule cannot be tested, as it sends notifications",
+	}
+
 	// ErrRunNotFound is returned when searching for a single run that doesn't exist.
 	ErrRunNotFound = &Error{
 		Code: ENotFound,
@@ -178,3 +193,56 @@ func ErrTaskConcurrencyLimitReached(runsInFront int) *Error {
 		Op:   "taskExecutor",
 	}
 }
+
+// ErrInvalidTaskDependency is returned when a task can not depend on the upstream task.
+func ErrInvalidTaskDependency(upstreamID ID, reason string) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid dependency on task %s: %s", upstreamID, reason),
+		Op:   "taskDependencies",
+	}
+}
+
+// ErrTaskDependencyCycle is returned when the upstream tasks of a task depend on it,
+// cycle being the tasks of the cycle starting and ending with the task.
+func ErrTaskDependencyCycle(cycle []ID) *Error {
+	ids := make([]string, 0, len(cycle))
+	for _, id := range cycle {
+		ids = append(ids, id.String())
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("task dependency cycle: %s", strings.Join(ids, " -> ")),
+		Op:   "taskDependencies",
+	}
+}
+
+// ErrInvalidTaskParam is returned when a parameter of a task, or the value
+// given to it, is not valid.
+func ErrInvalidTaskParam(name, reason string) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid task parameter %q: %s", name, reason),
+		Op:   "taskParams",
+	}
+}
+
+// ErrTaskUpstreamPending is returned when a run waits for an upstream task to
+// complete its runs up to the time the run is scheduled for.
+func ErrTaskUpstreamPending(upstreamID ID, scheduledFor time.Time) *Error {
+	return &Error{
+		Code: ETooManyRequests,
+		Msg:  fmt.Sprintf("waiting for upstream task %s to complete runs scheduled for %s", upstreamID, scheduledFor.UTC().Format(time.RFC3339)),
+		Op:   "taskExecutor",
+	}
+}
+
+// ErrTaskUpstreamFailed is returned when a run can not execute because its
+// upstream task did not succeed for the time the run is scheduled for.
+func ErrTaskUpstreamFailed(upstreamID ID, scheduledFor time.Time, reason string) *Error {
+	return &Error{
+		Code: EConflict,
+		Msg:  fmt.Sprintf("upstream task %s did not succeed for %s: %s", upstreamID, scheduledFor.UTC().Format(time.RFC3339), reason),
+		Op:   "taskExecutor",
+	}
+}
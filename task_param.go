@@ -0,0 +1,201 @@
+package influxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/v2/task/options"
+)
+
+// Types of task parameters.
+const (
+	TaskParamString   = "string"
+	TaskParamInt      = "int"
+	TaskParamFloat    = "float"
+	TaskParamBool     = "bool"
+	TaskParamDuration = "duration"
+	TaskParamTime     = "time"
+	TaskParamRegexp   = "regexp"
+)
+
+// TaskParamsIdent is the identifier of the record of parameters in scripts.
+const TaskParamsIdent = "params"
+
+var taskParamName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// TaskParam is a named, typed parameter of the script of a task. The script
+// reads its value as params.<Name>, the executor sets the params record when
+// a run executes.
+type TaskParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // Type is one of the TaskParam type constants
+	// Value is the value of the parameter for the runs of the task, a run
+	// forced with a value of its own uses that instead. A parameter without
+	// value must be given one by every run.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ParseValue returns v as a value of the type of p: an int64, a float64, a
+// bool, or a string for the other types, durations like 1h30m, times in
+// RFC3339 and regular expressions without slashes.
+func (p TaskParam) ParseValue(v interface{}) (interface{}, error) {
+	switch p.Type {
+	case TaskParamString, TaskParamDuration, TaskParamTime, TaskParamRegexp:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a %s as a string, got %T", p.Type, v)
+		}
+		switch p.Type {
+		case TaskParamDuration:
+			if _, err := options.ParseSignedDuration(s); err != nil {
+				return nil, err
+			}
+		case TaskParamTime:
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, err
+			}
+			s = t.UTC().Format(time.RFC3339Nano)
+		case TaskParamRegexp:
+			if _, err := regexp.Compile(s); err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	case TaskParamBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool, got %T", v)
+		}
+		return b, nil
+	case TaskParamInt, TaskParamFloat:
+		var f float64
+		switch n := v.(type) {
+		case int:
+			f = float64(n)
+		case int64:
+			if p.Type == TaskParamInt {
+				return n, nil
+			}
+			f = float64(n)
+		case float64:
+			f = n
+		case json.Number:
+			if i, err := n.Int64(); err == nil && p.Type == TaskParamInt {
+				return i, nil
+			}
+			var err error
+			if f, err = n.Float64(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("expected a number, got %T", v)
+		}
+		if p.Type == TaskParamFloat {
+			return f, nil
+		}
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, fmt.Errorf("expected an integer, got %v", f)
+		}
+		return int64(f), nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", p.Type)
+	}
+}
+
+// ValidateTaskParams returns an error if params are not valid parameters of
+// the script flux: their names must be unique identifiers, their types known
+// and their values of their type, and the script must not read parameters
+// that are not declared, unless it defines the params record itself.
+func ValidateTaskParams(lang FluxLanguageService, flux string, params []TaskParam) error {
+	names := make(map[string]bool, len(params))
+	for _, p := range params {
+		if !taskParamName.MatchString(p.Name) {
+			return ErrInvalidTaskParam(p.Name, "name must be an identifier")
+		}
+		if names[p.Name] {
+			return ErrInvalidTaskParam(p.Name, "declared more than once")
+		}
+		names[p.Name] = true
+
+		switch p.Type {
+		case TaskParamString, TaskParamInt, TaskParamFloat, TaskParamBool, TaskParamDuration, TaskParamTime, TaskParamRegexp:
+		default:
+			return ErrInvalidTaskParam(p.Name, fmt.Sprintf("unknown type %q", p.Type))
+		}
+		if p.Value != nil {
+			if _, err := p.ParseValue(p.Value); err != nil {
+				return ErrInvalidTaskParam(p.Name, err.Error())
+			}
+		}
+	}
+
+	pkg, err := safeParseSource(lang, flux)
+	if err != nil {
+		return err
+	}
+	if ast.Check(pkg) > 0 {
+		return ErrFluxParseError(ast.GetError(pkg))
+	}
+
+	var undeclared string
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.OptionStatement:
+			if a, ok := n.Assignment.(*ast.VariableAssignment); ok && a.ID.Name == TaskParamsIdent {
+				names = nil
+			}
+		case *ast.VariableAssignment:
+			if n.ID.Name == TaskParamsIdent {
+				names = nil
+			}
+		case *ast.MemberExpression:
+			obj, ok := n.Object.(*ast.Identifier)
+			if !ok || obj.Name != TaskParamsIdent || undeclared != "" {
+				return
+			}
+			if name := n.Property.Key(); !names[name] {
+				undeclared = name
+			}
+		}
+	}), pkg)
+	if names != nil && undeclared != "" {
+		return ErrInvalidTaskParam(undeclared, "read by the script but not declared")
+	}
+	return nil
+}
+
+// ParseTaskParamValues returns values as values of the parameters params of
+// a task, of their types.
+func ParseTaskParamValues(params []TaskParam, values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		p, ok := findTaskParam(params, name)
+		if !ok {
+			return nil, ErrInvalidTaskParam(name, "not declared by the task")
+		}
+		pv, err := p.ParseValue(v)
+		if err != nil {
+			return nil, ErrInvalidTaskParam(name, err.Error())
+		}
+		parsed[name] = pv
+	}
+	return parsed, nil
+}
+
+func findTaskParam(params []TaskParam, name string) (TaskParam, bool) {
+	for _, p := range params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return TaskParam{}, false
+}
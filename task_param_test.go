@@ -0,0 +1,136 @@
+package influxdb_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+)
+
+func TestTaskParam_ParseValue(t *testing.T) {
+	tests := []struct {
+		typ     string
+		v       interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{typ: platform.TaskParamString, v: "telegraf", want: "telegraf"},
+		{typ: platform.TaskParamString, v: 1.0, wantErr: true},
+		{typ: platform.TaskParamInt, v: float64(3), want: int64(3)},
+		{typ: platform.TaskParamInt, v: json.Number("-4"), want: int64(-4)},
+		{typ: platform.TaskParamInt, v: 3.5, wantErr: true},
+		{typ: platform.TaskParamInt, v: json.Number("9223372036854775807"), want: int64(math.MaxInt64)},
+		{typ: platform.TaskParamInt, v: float64(math.MaxInt64), wantErr: true},
+		{typ: platform.TaskParamInt, v: float64(math.MinInt64), want: int64(math.MinInt64)},
+		{typ: platform.TaskParamFloat, v: 3, want: 3.0},
+		{typ: platform.TaskParamFloat, v: "3", wantErr: true},
+		{typ: platform.TaskParamBool, v: true, want: true},
+		{typ: platform.TaskParamDuration, v: "1h30m", want: "1h30m"},
+		{typ: platform.TaskParamDuration, v: "an hour", wantErr: true},
+		{typ: platform.TaskParamTime, v: "2020-06-01T02:00:00+02:00", want: "2020-06-01T00:00:00Z"},
+		{typ: platform.TaskParamTime, v: "yesterday", wantErr: true},
+		{typ: platform.TaskParamRegexp, v: "^web-[0-9]+$", want: "^web-[0-9]+$"},
+		{typ: platform.TaskParamRegexp, v: "web-[", wantErr: true},
+		{typ: "bytes", v: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		p := platform.TaskParam{Name: "p", Type: tt.typ}
+		got, err := p.ParseValue(tt.v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseValue(%v) as %s: unexpected error %v", tt.v, tt.typ, err)
+			continue
+		}
+		if !cmp.Equal(got, tt.want) {
+			t.Errorf("ParseValue(%v) as %s = %#v, want %#v", tt.v, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestParseTaskParamValues(t *testing.T) {
+	params := []platform.TaskParam{
+		{Name: "bucket", Type: platform.TaskParamString, Value: "telegraf"},
+		{Name: "limit", Type: platform.TaskParamInt},
+	}
+
+	got, err := platform.ParseTaskParamValues(params, map[string]interface{}{"limit": float64(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"limit": int64(5)}; !cmp.Equal(got, want) {
+		t.Fatalf("unexpected values -want/+got\n%s", cmp.Diff(want, got))
+	}
+
+	if _, err := platform.ParseTaskParamValues(params, map[string]interface{}{"host": "a"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error for an undeclared parameter, got %v", err)
+	}
+	if _, err := platform.ParseTaskParamValues(params, map[string]interface{}{"limit": "5"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid error for a value of the wrong type, got %v", err)
+	}
+}
+
+func TestValidateTaskParams(t *testing.T) {
+	const script = `option task = {name: "t", every: 1h}
+from(bucket: params.bucket) |> range(start: -1h) |> filter(fn: (r) => r._value > params.threshold)`
+
+	tests := []struct {
+		name    string
+		flux    string
+		params  []platform.TaskParam
+		wantErr bool
+	}{
+		{
+			name: "declared",
+			flux: script,
+			params: []platform.TaskParam{
+				{Name: "bucket", Type: platform.TaskParamString, Value: "telegraf"},
+				{Name: "threshold", Type: platform.TaskParamFloat},
+			},
+		},
+		{
+			name:    "undeclared",
+			flux:    script,
+			params:  []platform.TaskParam{{Name: "bucket", Type: platform.TaskParamString}},
+			wantErr: true,
+		},
+		{
+			name: "defined by the script",
+			flux: `option params = {bucket: "telegraf"}
+` + `from(bucket: params.bucket) |> range(start: -1h)`,
+		},
+		{
+			name:    "duplicate",
+			flux:    `from(bucket: "b") |> range(start: -1h)`,
+			params:  []platform.TaskParam{{Name: "b", Type: platform.TaskParamString}, {Name: "b", Type: platform.TaskParamInt}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			flux:    `from(bucket: "b") |> range(start: -1h)`,
+			params:  []platform.TaskParam{{Name: "my bucket", Type: platform.TaskParamString}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			flux:    `from(bucket: "b") |> range(start: -1h)`,
+			params:  []platform.TaskParam{{Name: "b", Type: "bytes"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			flux:    `from(bucket: "b") |> range(start: -1h)`,
+			params:  []platform.TaskParam{{Name: "b", Type: platform.TaskParamBool, Value: "yes"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := platform.ValidateTaskParams(fluxlang.DefaultService, tt.flux, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/pkg/pointer"
 	_ "github.com/influxdata/influxdb/v2/query/builtin"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/task/options"
@@ -23,6 +24,14 @@ func TestUpdateValidate(t *testing.T) {
 	if tu.Options.Offset.String() != "1h" {
 		t.Fatalf("option.every not properly unmarshaled, expected 1h got %s", tu.Options.Offset)
 	}
+
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"cron":"0 2 * * *", "timezone":"Europe/Berlin"}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.Options.Timezone != "Europe/Berlin" {
+		t.Fatalf("option.timezone not properly unmarshaled, expected Europe/Berlin got %s", tu.Options.Timezone)
+	}
 	if err := tu.Validate(); err != nil {
 		t.Fatalf("expected task update to be valid but it was not: %s", err)
 	}
@@ -131,6 +140,66 @@ from(bucket: "x")
 			t.Fatalf("expected Cron to be \"\" but was %s", op.Cron)
 		}
 	})
+	t.Run("set timezone", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Timezone = "Europe/Berlin"
+		if err := tu.UpdateFlux(fluxlang.DefaultService, `option task = {cron: "0 2 * * *", name: "foo", timezone: "UTC"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(fluxlang.DefaultService, *tu.Flux)
+		if err != nil {
+			t.Error(err)
+		}
+		if op.Timezone != "Europe/Berlin" {
+			t.Fatalf("expected Timezone to be \"Europe/Berlin\" but was %s", op.Timezone)
+		}
+	})
+	t.Run("switching from cron with timezone to every", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Every = *(options.MustParseDuration("10s"))
+		if err := tu.UpdateFlux(fluxlang.DefaultService, `option task = {cron: "0 2 * * *", name: "foo", timezone: "Europe/Berlin"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(fluxlang.DefaultService, *tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Timezone != "" {
+			t.Fatalf("expected Timezone to be \"\" but was %s", op.Timezone)
+		}
+	})
+	t.Run("set catchUp and maxMissedRuns", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.CatchUp = options.CatchUpAll
+		tu.Options.MaxMissedRuns = pointer.Int64(12)
+		if err := tu.UpdateFlux(fluxlang.DefaultService, `option task = {every: 1h, name: "foo", catchUp: "skip"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(fluxlang.DefaultService, *tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.CatchUp != options.CatchUpAll {
+			t.Fatalf("expected CatchUp to be %q but was %q", options.CatchUpAll, op.CatchUp)
+		}
+		if op.MaxMissedRuns == nil || *op.MaxMissedRuns != 12 {
+			t.Fatalf("expected MaxMissedRuns to be 12 but was %v", op.MaxMissedRuns)
+		}
+	})
+	t.Run("switching to latest catchUp deletes maxMissedRuns", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.CatchUp = options.CatchUpLatest
+		if err := tu.UpdateFlux(fluxlang.DefaultService, `option task = {every: 1h, name: "foo", maxMissedRuns: 12} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(fluxlang.DefaultService, *tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.CatchUp != options.CatchUpLatest || op.MaxMissedRuns != nil {
+			t.Fatalf("expected only the latest CatchUp but got %q and %v", op.CatchUp, op.MaxMissedRuns)
+		}
+	})
 	t.Run("delete deletable option", func(t *testing.T) {
 		tu := &platform.TaskUpdate{}
 		tu.Options.Offset = &options.Duration{}
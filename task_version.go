@@ -0,0 +1,57 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// TaskVersion is an immutable version of the script of a task, with the
+// options it sets. A task gets a new version every time its script changes;
+// rolling a task back to a version updates the task to the script of that
+// version, as a new version.
+type TaskVersion struct {
+	TaskID        ID        `json:"taskID"`
+	OrgID         ID        `json:"orgID"`
+	Version       int       `json:"version"`
+	Flux          string    `json:"flux"`
+	Name          string    `json:"name"`
+	Every         string    `json:"every,omitempty"`
+	Cron          string    `json:"cron,omitempty"`
+	Timezone      string    `json:"timezone,omitempty"`
+	Offset        Duration  `json:"offset,omitempty"`
+	Retry         int64     `json:"retry,omitempty"`
+	CatchUp       string    `json:"catchUp,omitempty"`
+	MaxMissedRuns int64     `json:"maxMissedRuns,omitempty"`
+	CreatedBy     ID        `json:"createdBy,omitempty"` // CreatedBy is the user who changed the script, if known
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// NewTaskVersion returns the version version of the current script of t,
+// created by userID at createdAt.
+func NewTaskVersion(t *Task, version int, userID ID, createdAt time.Time) *TaskVersion {
+	return &TaskVersion{
+		TaskID:        t.ID,
+		OrgID:         t.OrganizationID,
+		Version:       version,
+		Flux:          t.Flux,
+		Name:          t.Name,
+		Every:         t.Every,
+		Cron:          t.Cron,
+		Timezone:      t.Timezone,
+		Offset:        Duration{Duration: t.Offset},
+		Retry:         t.Retry,
+		CatchUp:       t.CatchUp,
+		MaxMissedRuns: t.MaxMissedRuns,
+		CreatedBy:     userID,
+		CreatedAt:     createdAt,
+	}
+}
+
+// TaskVersionService finds the versions of tasks.
+type TaskVersionService interface {
+	// FindTaskVersions returns the versions of the task taskID, oldest first.
+	FindTaskVersions(ctx context.Context, taskID ID) ([]*TaskVersion, error)
+
+	// FindTaskVersion returns a single version of the task taskID.
+	FindTaskVersion(ctx context.Context, taskID ID, version int) (*TaskVersion, error)
+}
@@ -7,8 +7,10 @@ import (
 	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
 	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/tsdb"
@@ -19,6 +21,9 @@ import (
 // DeletePrefixRange removes all TSM data belonging to a bucket, and removes all index
 // and series file data associated with the bucket. The provided time range ensures
 // that only bucket data for that range is removed.
+//
+// The delete stops with the context error if ctx is done while tombstones are written
+// to the TSM files, and reports its progress to the influxdb.DeleteProgress of ctx.
 func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, max int64, pred Predicate) error {
 	span, ctx := tracing.StartSpanFromContext(rootCtx)
 	span.LogKV("name_prefix", fmt.Sprintf("%x", name),
@@ -80,7 +85,16 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 	}
 	possiblyDead.keys = make(map[string]struct{})
 
+	progress := influxdb.DeleteProgressFromContext(rootCtx)
+	if progress == nil {
+		progress = new(influxdb.DeleteProgress)
+	}
+
 	if err := e.FileStore.Apply(func(r TSMFile) error {
+		if err := rootCtx.Err(); err != nil {
+			return err
+		}
+
 		var predClone Predicate // Apply executes concurrently across files.
 		if pred != nil {
 			predClone = pred.Clone()
@@ -92,11 +106,16 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 		span.LogKV("file_path", r.Path())
 		defer span.Finish()
 
-		return r.DeletePrefix(name, min, max, predClone, func(key []byte) {
+		deleted, err := r.DeletePrefix(name, min, max, predClone, func(key []byte) {
 			possiblyDead.Lock()
 			possiblyDead.keys[string(key)] = struct{}{}
 			possiblyDead.Unlock()
 		})
+		atomic.AddInt64(&progress.FilesScanned, 1)
+		if deleted {
+			atomic.AddInt64(&progress.FilesRewritten, 1)
+		}
+		return err
 	}); err != nil {
 		return err
 	}
@@ -251,6 +270,7 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 			if err = e.sfile.DeleteSeriesIDs(ids); err != nil {
 				return err
 			}
+			atomic.AddInt64(&progress.SeriesRemoved, int64(len(ids)))
 			span.Finish()
 			return err
 		}
@@ -295,6 +315,7 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 			} else if err := e.sfile.DeleteSeriesIDs(ids); err != nil {
 				return err
 			}
+			atomic.AddInt64(&progress.SeriesRemoved, int64(len(ids)))
 		}
 		span.Finish()
 	}
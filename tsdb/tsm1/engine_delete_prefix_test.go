@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
 )
@@ -121,6 +122,47 @@ func TestEngine_DeletePrefix(t *testing.T) {
 	}
 }
 
+func TestEngine_DeletePrefix_Progress(t *testing.T) {
+	e, err := NewEngine(tsm1.NewConfig(), t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=1.1 1", "mm0"),
+		MustParsePointString("cpu,host=B value=1.2 2", "mm0"),
+		MustParsePointString("mem,host=C value=1.3 1", "mm1"),
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := e.DeletePrefixRange(ctx, []byte("mm1"), 0, 9, nil); err != context.Canceled {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if exp, got := 3, len(e.FileStore.Keys()); exp != got {
+		t.Fatalf("series count mismatch after canceled delete: exp %v, got %v", exp, got)
+	}
+
+	var progress influxdb.DeleteProgress
+	ctx = influxdb.NewContextWithDeleteProgress(context.Background(), &progress)
+	if err := e.DeletePrefixRange(ctx, []byte("mm0"), 0, 9, nil); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+	exp := influxdb.DeleteProgress{FilesScanned: 1, FilesRewritten: 1, SeriesRemoved: 2}
+	if got := progress.Load(); got != exp {
+		t.Fatalf("unexpected progress: got %+v, exp %+v", got, exp)
+	}
+}
+
 func BenchmarkEngine_DeletePrefixRange(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
@@ -0,0 +1,53 @@
+package tsm1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+// Import writes a new TSM file with fn and adds it to the file store under the
+// next generation. Nothing is added if fn does not write any blocks, and the
+// file is removed if fn fails.
+func (e *Engine) Import(ctx context.Context, fn func(w TSMWriter) error) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	path := filepath.Join(e.path, e.formatFileName(e.FileStore.NextGeneration(), 1)+"."+TSMFileExtension+"."+TmpTSMFileExtension)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	if err := fn(w); err != nil {
+		w.Remove()
+		return err
+	}
+
+	if err := w.WriteIndex(); err == ErrNoValues {
+		return w.Remove()
+	} else if err != nil {
+		w.Remove()
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	if err := e.FileStore.Replace(nil, []string{path}); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
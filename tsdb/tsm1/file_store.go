@@ -116,8 +116,9 @@ type TSMFile interface {
 	DeleteRange(keys [][]byte, min, max int64) error
 
 	// DeletePrefix removes the values for keys beginning with prefix. It calls dead with
-	// any keys that became dead as a result of this call.
-	DeletePrefix(prefix []byte, min, max int64, pred Predicate, dead func([]byte)) error
+	// any keys that became dead as a result of this call. It returns true if any values
+	// were removed.
+	DeletePrefix(prefix []byte, min, max int64, pred Predicate, dead func([]byte)) (bool, error)
 
 	// HasTombstones returns true if file contains values that have been deleted.
 	HasTombstones() bool
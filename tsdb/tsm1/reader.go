@@ -331,9 +331,10 @@ func (t *TSMReader) DeleteRange(keys [][]byte, minTime, maxTime int64) error {
 }
 
 // DeletePrefix removes the given points for keys beginning with prefix. It calls dead with
-// any keys that became dead as a result of this call.
+// any keys that became dead as a result of this call. It returns true if any points were
+// removed, in which case a tombstone was written for the file.
 func (t *TSMReader) DeletePrefix(prefix []byte, minTime, maxTime int64,
-	pred Predicate, dead func([]byte)) error {
+	pred Predicate, dead func([]byte)) (bool, error) {
 
 	// Marshal the predicate if passed for adding to the tombstone.
 	var predData []byte
@@ -341,20 +342,20 @@ func (t *TSMReader) DeletePrefix(prefix []byte, minTime, maxTime int64,
 		var err error
 		predData, err = pred.Marshal()
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
 	if !t.index.DeletePrefix(prefix, minTime, maxTime, pred, dead) {
-		return nil
+		return false, nil
 	}
 	if err := t.tombstoner.AddPrefixRange(prefix, minTime, maxTime, predData); err != nil {
-		return err
+		return false, err
 	}
 	if err := t.tombstoner.Flush(); err != nil {
-		return err
+		return false, err
 	}
-	return nil
+	return true, nil
 }
 
 // Iterator returns an iterator over the keys starting at the provided key. You must
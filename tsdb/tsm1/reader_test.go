@@ -1581,8 +1581,17 @@ func TestTSMReader_DeletePrefix(t *testing.T) {
 	r, err := NewTSMReader(f)
 	fatalIfErr(t, "creating reader", err)
 
-	err = r.DeletePrefix([]byte("c"), 0, 5, nil, nil)
+	deleted, err := r.DeletePrefix([]byte("c"), 0, 5, nil, nil)
 	fatalIfErr(t, "deleting prefix", err)
+	if !deleted {
+		t.Fatal("expected values to be deleted")
+	}
+
+	deleted, err = r.DeletePrefix([]byte("d"), 0, 5, nil, nil)
+	fatalIfErr(t, "deleting missing prefix", err)
+	if deleted {
+		t.Fatal("expected no values to be deleted")
+	}
 
 	values, err := r.ReadAll([]byte("cpu"))
 	fatalIfErr(t, "reading values", err)